@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	authService "github.com/cortezaproject/corteza-server/auth"
+	authKeys "github.com/cortezaproject/corteza-server/auth/keys"
 	authSettings "github.com/cortezaproject/corteza-server/auth/settings"
 	autService "github.com/cortezaproject/corteza-server/automation/service"
 	cmpService "github.com/cortezaproject/corteza-server/compose/service"
@@ -14,12 +15,14 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/corredor"
+	envoyStore "github.com/cortezaproject/corteza-server/pkg/envoy/store"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/healthcheck"
 	"github.com/cortezaproject/corteza-server/pkg/http"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/mail"
 	"github.com/cortezaproject/corteza-server/pkg/monitor"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
 	"github.com/cortezaproject/corteza-server/pkg/provision"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/pkg/scheduler"
@@ -252,6 +255,12 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 		rbac.Global().Reload(ctx)
 	}
 
+	// Load (or generate) keys for signing JWTs
+	// when asymmetric signing algorithm is configured
+	if err = authKeys.Setup(ctx, app.Log, app.Store, app.Opt.Auth); err != nil {
+		return
+	}
+
 	// Initializes system services
 	//
 	// Note: this is a legacy approach, all services from all 3 apps
@@ -287,6 +296,9 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 	err = cmpService.Initialize(ctx, app.Log, app.Store, cmpService.Config{
 		ActionLog: app.Opt.ActionLog,
 		Storage:   app.Opt.ObjStore,
+		Search:    app.Opt.Search,
+
+		RecordImportEncoder: envoyStore.ImportComposeRecords,
 	})
 
 	if err != nil {
@@ -359,6 +371,10 @@ func (app *CortezaApp) Activate(ctx context.Context) (err error) {
 
 	// Start scheduler
 	if app.Opt.Eventbus.SchedulerEnabled {
+		if app.Opt.Eventbus.SchedulerLeaseEnabled {
+			scheduler.Service().UseLease(app.Store, app.Opt.Eventbus.SchedulerLeaseHolder)
+		}
+
 		scheduler.Service().Start(ctx)
 	}
 
@@ -378,6 +394,10 @@ func (app *CortezaApp) Activate(ctx context.Context) (err error) {
 
 	rbac.Global().Watch(ctx)
 
+	if authKeys.DefaultManager != nil {
+		authKeys.DefaultManager.Watch(ctx)
+	}
+
 	if err = sysService.Activate(ctx); err != nil {
 		return err
 	}
@@ -446,6 +466,11 @@ func updateAuthSettings(svc authServicer, current *types.AppSettings) {
 	as.MultiFactor.TOTP.Issuer = cas.MultiFactor.TOTP.Issuer
 	as.MultiFactor.EmailOTP.Enabled = cas.MultiFactor.EmailOTP.Enabled
 	as.MultiFactor.EmailOTP.Enforced = cas.MultiFactor.EmailOTP.Enforced
+	as.MultiFactor.WebAuthn.Enabled = cas.MultiFactor.WebAuthn.Enabled
+	as.MultiFactor.WebAuthn.EnabledRoles = payload.ParseUint64s(cas.MultiFactor.WebAuthn.EnabledRoles)
+	as.MultiFactor.WebAuthn.Enforced = cas.MultiFactor.WebAuthn.Enforced
+	as.MultiFactor.WebAuthn.EnforcedRoles = payload.ParseUint64s(cas.MultiFactor.WebAuthn.EnforcedRoles)
+	as.MultiFactor.WebAuthn.RelyingPartyName = cas.MultiFactor.WebAuthn.RelyingPartyName
 
 	svc.UpdateSettings(as)
 }
@@ -25,6 +25,7 @@ type (
 		Federation  options.FederationOpt
 		SCIM        options.SCIMOpt
 		Workflow    options.WorkflowOpt
+		Search      options.SearchOpt
 	}
 )
 
@@ -49,5 +50,6 @@ func NewOptions() *Options {
 		Federation:  *options.Federation(),
 		SCIM:        *options.SCIM(),
 		Workflow:    *options.Workflow(),
+		Search:      *options.Search(),
 	}
 }
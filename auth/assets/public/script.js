@@ -12,3 +12,82 @@ $(function () {
 
   $('input.mfa-code-mask').mask('000 000')
 })
+
+// Security keys (WebAuthn)
+//
+// Options are prepared by the server (binary values are base64url encoded),
+// response of the authenticator is put into the hidden "response" input
+// and submitted with the form
+$(function () {
+  function decode (s) {
+    s = s.replace(/-/g, '+').replace(/_/g, '/')
+    s += '==='.slice((s.length + 3) % 4)
+    return Uint8Array.from(atob(s), function (c) { return c.charCodeAt(0) }).buffer
+  }
+
+  function encode (buf) {
+    return btoa(String.fromCharCode.apply(null, new Uint8Array(buf)))
+      .replace(/\+/g, '-')
+      .replace(/\//g, '_')
+      .replace(/=+$/, '')
+  }
+
+  function descriptors (dd) {
+    return (dd || []).map(function (d) { return $.extend({}, d, { id: decode(d.id) }) })
+  }
+
+  $('button[data-webauthn-ceremony]').on('click', function () {
+    let btn = this
+    let form = btn.form
+    let create = btn.dataset.webauthnCeremony === 'create'
+    let opt = JSON.parse(btn.dataset.webauthnOptions)
+    let $error = $('.webauthn-error', form)
+
+    if (!window.PublicKeyCredential) {
+      $error.text('Security keys are not supported by your browser').removeClass('d-none')
+      return
+    }
+
+    opt.challenge = decode(opt.challenge)
+
+    let ceremony
+    if (create) {
+      opt.user.id = decode(opt.user.id)
+      opt.excludeCredentials = descriptors(opt.excludeCredentials)
+      ceremony = navigator.credentials.create({ publicKey: opt })
+    } else {
+      opt.allowCredentials = descriptors(opt.allowCredentials)
+      ceremony = navigator.credentials.get({ publicKey: opt })
+    }
+
+    btn.disabled = true
+    $error.addClass('d-none')
+
+    ceremony.then(function (cred) {
+      let r = cred.response
+      let response = { clientDataJSON: encode(r.clientDataJSON) }
+
+      if (create) {
+        response.attestationObject = encode(r.attestationObject)
+      } else {
+        response.authenticatorData = encode(r.authenticatorData)
+        response.signature = encode(r.signature)
+        if (r.userHandle) {
+          response.userHandle = encode(r.userHandle)
+        }
+      }
+
+      form.elements.response.value = JSON.stringify({
+        id: cred.id,
+        rawId: encode(cred.rawId),
+        type: cred.type,
+        response: response,
+      })
+
+      form.submit()
+    }).catch(function (err) {
+      btn.disabled = false
+      $error.text(err.message).removeClass('d-none')
+    })
+  })
+})
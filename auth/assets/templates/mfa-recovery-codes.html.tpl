@@ -0,0 +1,29 @@
+{{ template "inc_header.html.tpl"  set . "hideNav" true }}
+<div class="card-body p-0">
+	<h4 class="card-title p-3 border-bottom">Recovery codes</h4>
+
+	<div class="p-3">
+		<p>
+			Keep these codes somewhere safe. If you lose access to your TOTP application,
+			each of them can be used once instead of the code from the application.
+		</p>
+
+		<p class="text-danger font-weight-bold">
+			Codes will not be displayed again. Previously generated codes are no longer valid.
+		</p>
+
+		<ul class="list-unstyled text-center my-4" style="font-family:monospace;font-size:20px;letter-spacing:2px;">
+			{{ range .codes }}
+			<li>{{ . }}</li>
+			{{ end }}
+		</ul>
+
+		<a
+			href="{{ links.Security }}"
+			class="btn btn-primary btn-block btn-lg"
+		>
+			I have saved my recovery codes
+		</a>
+	</div>
+</div>
+{{ template "inc_footer.html.tpl" . }}
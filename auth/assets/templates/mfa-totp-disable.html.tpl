@@ -40,5 +40,36 @@
 			Remove
 		</button>
 	</form>
+
+	<form
+		class="p-3"
+		method="POST"
+		action="{{ links.MfaTotpDisable }}"
+	>
+		Lost your device? Disable by entering one of your recovery codes.
+
+		{{ .csrfField }}
+		<div class="input-group my-3">
+			<input
+				type="text"
+				required
+				class="form-control lg text-center"
+				name="recoveryCode"
+				maxlength="16"
+				aria-required="true"
+				placeholder="xxxxx-xxxxx"
+				autocomplete="off"
+				aria-label="Recovery code">
+		</div>
+
+		<button
+			class="btn btn-light btn-block btn-lg text-dark"
+			name="keep-session"
+			value="true"
+			type="submit"
+		>
+			Remove with recovery code
+		</button>
+	</form>
 </div>
 {{ template "inc_footer.html.tpl" . }}
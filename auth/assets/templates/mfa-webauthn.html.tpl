@@ -0,0 +1,54 @@
+{{ template "inc_header.html.tpl"  set . "hideNav" true }}
+<div class="card-body p-0">
+	<h4 class="card-title p-3 border-bottom">Configure two-factor authentication with security key</h4>
+
+	{{ if .enforced }}
+	<p class="p-3 text-danger mb-0 font-weight-bold">
+		Security key multi factor authentication is enforced by Corteza administrator.
+		Please register your security key right away.
+	</p>
+	{{ end }}
+
+	<form
+		class="p-3"
+		method="POST"
+		action="{{ links.MfaWebAuthnSetup }}"
+	>
+		<p>
+			Insert your security key (or use the authenticator built into your device)
+			and follow the instructions of your browser.
+		</p>
+
+		{{ if .form.error }}
+		<div class="alert alert-danger" role="alert">
+			{{ .form.error }}
+		</div>
+		{{ end }}
+
+		<div class="alert alert-danger d-none webauthn-error" role="alert"></div>
+
+		{{ .csrfField }}
+		<input type="hidden" name="response">
+
+		<div class="input-group my-3">
+			<input
+				type="text"
+				class="form-control"
+				name="label"
+				maxlength="64"
+				placeholder="Security key name (optional)"
+				autocomplete="off"
+				aria-label="Security key name">
+		</div>
+
+		<button
+			class="btn btn-primary btn-block btn-lg"
+			type="button"
+			data-webauthn-ceremony="create"
+			data-webauthn-options="{{ .options }}"
+		>
+			Register security key
+		</button>
+	</form>
+</div>
+{{ template "inc_footer.html.tpl" . }}
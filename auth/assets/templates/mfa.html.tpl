@@ -97,10 +97,90 @@
 			Verify
 		</button>
 	</form>
+
+	<form
+		class="p-3"
+		method="POST"
+		action="{{ links.Mfa }}"
+	>
+		<h6>Lost your device? Enter one of your recovery codes</h6>
+
+		{{ if .form.recoveryCodeError }}
+		<div class="alert alert-danger" role="alert">
+			{{ .form.recoveryCodeError }}
+		</div>
+		{{ end }}
+		{{ .csrfField }}
+
+		<div class="input-group my-3">
+			<input
+				type="text"
+				required
+				class="form-control text-center"
+				name="recoveryCode"
+				maxlength="16"
+				aria-required="true"
+				placeholder="xxxxx-xxxxx"
+				autocomplete="off"
+				aria-label="Recovery code">
+		</div>
+
+		<button
+			class="btn btn-light btn-block btn-lg text-dark"
+			type="submit"
+			name="action"
+			value="verifyRecoveryCode"
+		>
+			Use recovery code
+		</button>
+	</form>
 	{{ else if not .totpDisabled }}
 		<p class="p-3 mb-0">
 			<i class="bi bi-check-circle text-success h5 mr-1"></i> TOTP confirmed
 		</p>
 	{{ end }}
+
+	{{ if .webauthnPending }}
+	<form
+		class="p-3"
+		method="POST"
+		action="{{ links.Mfa }}"
+	>
+		<h5>Insert your security key and confirm your identity</h5>
+
+		{{ if .form.webauthnError }}
+		<div class="alert alert-danger" role="alert">
+			{{ .form.webauthnError }}
+		</div>
+		{{ end }}
+
+		{{ if .webauthnOptionsError }}
+		<div class="alert alert-danger" role="alert">
+			{{ .webauthnOptionsError }}
+		</div>
+		{{ end }}
+
+		<div class="alert alert-danger d-none webauthn-error" role="alert"></div>
+
+		{{ .csrfField }}
+		<input type="hidden" name="action" value="verifyWebAuthn">
+		<input type="hidden" name="response">
+
+		{{ if .webauthnOptions }}
+		<button
+			class="btn btn-primary btn-block btn-lg mt-3"
+			type="button"
+			data-webauthn-ceremony="get"
+			data-webauthn-options="{{ .webauthnOptions }}"
+		>
+			Use security key
+		</button>
+		{{ end }}
+	</form>
+	{{ else if not .webauthnDisabled }}
+		<p class="p-3 mb-0">
+			<i class="bi bi-check-circle text-success h5 mr-1"></i> Security key confirmed
+		</p>
+	{{ end }}
 </div>
 {{ template "inc_footer.html.tpl" . }}
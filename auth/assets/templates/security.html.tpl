@@ -17,7 +17,7 @@
 	<div>
 		{{ .csrfField }}
 		<h5>Multi-factor authentication</h5>
-		{{ if or .settings.MultiFactor.TOTP.Enabled .settings.MultiFactor.EmailOTP.Enabled }}
+		{{ if or .settings.MultiFactor.TOTP.Enabled .settings.MultiFactor.EmailOTP.Enabled .webauthnEnabled }}
 			{{ if .settings.MultiFactor.TOTP.Enabled }}
 			<div class="py-4">
 				<h6>Additional security with mobile app (time-based one-time-password)</h6>
@@ -44,6 +44,17 @@
 						{{ end }}
 					</div>
 				</div>
+				{{ if .totpEnforced }}
+				<div class="row pt-2">
+					<div class="col-10 pt-2">
+						<i class="bi bi-file-earmark-lock mr-1"></i>
+						Recovery codes can be used when you lose access to your TOTP application.
+					</div>
+					<div class="col-md-2 col-sm-12">
+						<button name="action" value="generateRecoveryCodes" class="btn btn-light float-right">Regenerate</button>
+					</div>
+				</div>
+				{{ end }}
 			</div>
 			{{ end }}
 
@@ -76,6 +87,49 @@
 				</div>
 			</div>
 			{{ end }}
+
+			{{ if .webauthnEnabled }}
+			<div class="py-4">
+				<h6>Additional security with security keys (WebAuthn)</h6>
+				<div class="row">
+					<div class="col-10 pt-2">
+						{{ if .webauthnKeys }}
+						<i class="bi bi-check-circle text-success h5 mr-1"></i>
+						Registered and required on login.
+						{{ else }}
+						<i class="bi bi-exclamation-circle-fill text-danger h5 mr-1"></i>
+						Currently disabled.
+						{{ end }}
+					</div>
+					<div class="col-md-2 col-sm-12">
+						<button name="action" value="configureWebAuthn" class="btn btn-primary float-right">Add</button>
+					</div>
+				</div>
+				{{ range .webauthnKeys }}
+				<div class="row pt-2">
+					<div class="col-10 pt-2">
+						<i class="bi bi-key mr-1"></i>
+						{{ .Label }}
+						<small class="text-muted">
+							added {{ .CreatedAt.Format "2006-01-02" }}{{ if .LastUsedAt }}, last used {{ .LastUsedAt.Format "2006-01-02" }}{{ end }}
+						</small>
+					</div>
+					<div class="col-md-2 col-sm-12">
+						{{ if or (not $.webauthnEnforced) (gt (len $.webauthnKeys) 1) }}
+						<button
+							formaction="{{ links.MfaWebAuthnRemove }}"
+							name="credentialsID"
+							value="{{ .ID }}"
+							class="btn btn-danger float-right"
+						>
+							Remove
+						</button>
+						{{ end }}
+					</div>
+				</div>
+				{{ end }}
+			</div>
+			{{ end }}
 		{{ else }}
 			<div class="mb-4 font-italic" role="alert">
 				All MFA methods are currently disabled. Ask your administrator to enable them.
@@ -22,6 +22,7 @@ import (
 	"go.uber.org/zap"
 	"html/template"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -68,6 +69,7 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 
 	oauth2Manager := oauth2.NewManager(
 		opt,
+		auth.DefaultKeyring,
 		&oauth2.ContextClientStore{},
 		&oauth2.CortezaTokenStore{Store: s},
 	)
@@ -132,6 +134,10 @@ func New(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthO
 			if !auth.CheckScope(client.Scope, scope) {
 				return false, fmt.Errorf("client does not allow use of '%s' scope", scope)
 			}
+
+			if scope == "openid" && !handlers.OpenIDSupported() {
+				return false, fmt.Errorf("use of 'openid' scope requires RS256 or ES256 signing key")
+			}
 		}
 
 		return true, nil
@@ -232,7 +238,7 @@ func (svc *service) UpdateSettings(s *settings.Settings) {
 		svc.log.Debug("setting changed", zap.Bool("externalEnabled", s.ExternalEnabled))
 	}
 
-	if svc.settings.MultiFactor != s.MultiFactor {
+	if !reflect.DeepEqual(svc.settings.MultiFactor, s.MultiFactor) {
 		svc.log.Debug("setting changed", zap.Any("mfa", s.MultiFactor))
 	}
 
@@ -301,19 +307,3 @@ func (svc service) MountHttpRoutes(r chi.Router) {
 		r.Handle(uriRoot+"/*", http.StripPrefix(uriRoot, http.FileServer(http.Dir(root))))
 	}
 }
-
-//func (svc service) WellKnownOpenIDConfiguration() http.HandlerFunc {
-//	return func(w http.ResponseWriter, r *http.Request) {
-//		json.NewEncoder(w).Encode(map[string]interface{}{
-//			"issuer":                                svc.opt.BaseURL,
-//			"authorization_endpoint":                svc.opt.BaseURL + "/oauth2/authorize",
-//			"token_endpoint":                        svc.opt.BaseURL + "/oauth2/token",
-//			"jwks_uri":                              svc.opt.BaseURL + "/oauth2/public-keys", // @todo
-//			"subject_types_supported":               []string{"public"},
-//			"response_types_supported":              []string{"public"},
-//			"id_token_signing_alg_values_supported": []string{"RS256", "HS512"},
-//		})
-//
-//		w.Header().Set("Content-Type", "application/json")
-//	}
-//}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/types"
 )
@@ -11,6 +12,8 @@ type (
 		store interface {
 			store.AuthClients
 			store.AuthConfirmedClients
+			store.Users
+			store.Roles
 		}
 	}
 )
@@ -27,3 +30,30 @@ func (svc clientService) Confirmed(ctx context.Context, userID uint64) (types.Au
 func (svc clientService) Revoke(ctx context.Context, userID, clientID uint64) error {
 	return store.DeleteAuthConfirmedClientByUserIDClientID(ctx, svc.store, userID, clientID)
 }
+
+// ImpersonatedUser loads user (and his roles) impersonated by the client
+//
+// Used with client-credentials grant; user must be valid
+// (not suspended or deleted)
+func (svc clientService) ImpersonatedUser(ctx context.Context, client *types.AuthClient) (*types.User, error) {
+	if client.Security == nil || client.Security.ImpersonateUser == 0 {
+		return nil, fmt.Errorf("client does not impersonate any user")
+	}
+
+	u, err := store.LookupUserByID(ctx, svc.store, client.Security.ImpersonateUser)
+	if err != nil {
+		return nil, fmt.Errorf("could not load impersonated user: %w", err)
+	}
+
+	if !u.Valid() {
+		return nil, fmt.Errorf("impersonated user is not valid")
+	}
+
+	rr, _, err := store.SearchRoles(ctx, svc.store, types.RoleFilter{MemberID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	u.SetRoles(rr.IDs())
+	return u, nil
+}
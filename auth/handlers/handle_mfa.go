@@ -35,6 +35,15 @@ func (h AuthHandlers) mfaForm(req *request.AuthReq) (err error) {
 	req.Data["emailOtpPending"] = req.AuthUser.PendingEmailOTP()
 	req.Data["totpDisabled"] = req.AuthUser.DisabledTOTP()
 	req.Data["totpPending"] = req.AuthUser.PendingTOTP()
+	req.Data["webauthnDisabled"] = req.AuthUser.DisabledWebAuthn()
+	req.Data["webauthnPending"] = req.AuthUser.PendingWebAuthn()
+
+	if req.AuthUser.PendingWebAuthn() {
+		if req.Data["webauthnOptions"], err = h.mfaWebAuthnRequestOptions(req); err != nil {
+			req.Data["webauthnOptionsError"] = err.Error()
+		}
+	}
+
 	return nil
 }
 
@@ -71,6 +80,32 @@ func (h AuthHandlers) mfaProc(req *request.AuthReq) (err error) {
 
 		req.PushAlert("TOTP valid")
 		req.AuthUser.CompleteTOTP()
+
+	case "verifyRecoveryCode":
+		err = h.AuthService.ValidateRecoveryCode(
+			auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+			req.Request.PostFormValue("recoveryCode"),
+		)
+
+		if err != nil {
+			req.SetKV(map[string]string{"recoveryCodeError": err.Error()})
+			return nil
+		}
+
+		// recovery code replaces TOTP code
+		req.PushAlert("Recovery code accepted")
+		req.AuthUser.CompleteTOTP()
+
+	case "verifyWebAuthn":
+		err = h.mfaWebAuthnValidate(req)
+
+		if err != nil {
+			req.SetKV(map[string]string{"webauthnError": err.Error()})
+			return nil
+		}
+
+		req.PushAlert("Security key verified")
+		req.AuthUser.CompleteWebAuthn()
 	}
 
 	// All required MFA's confirmed, proceed to profile
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"go.uber.org/zap"
+)
+
+const (
+	// session key where freshly generated recovery codes
+	// are kept until they are displayed
+	mfaRecoveryCodesKey = "mfaRecoveryCodes"
+)
+
+// Displays freshly generated recovery codes
+//
+// Codes are removed from the session and can not be displayed again
+func (h AuthHandlers) mfaRecoveryCodesForm(req *request.AuthReq) error {
+	codes, has := req.Session.Values[mfaRecoveryCodesKey]
+	if !has {
+		req.RedirectTo = GetLinks().Security
+		return nil
+	}
+
+	delete(req.Session.Values, mfaRecoveryCodesKey)
+
+	req.Data["codes"] = codes
+	req.Template = TmplMfaRecoveryCodes
+	return nil
+}
+
+// Generates new set of recovery codes and redirects
+// to the page where they are displayed
+func (h AuthHandlers) mfaRecoveryCodesGenerate(req *request.AuthReq) {
+	codes, err := h.AuthService.GenerateRecoveryCodes(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+	)
+
+	if err != nil {
+		h.Log.Error("failed to generate recovery codes", zap.Error(err))
+		req.PushDangerAlert("Could not generate recovery codes")
+		return
+	}
+
+	h.Log.Info("recovery codes generated")
+	req.Session.Values[mfaRecoveryCodesKey] = codes
+	req.RedirectTo = GetLinks().MfaRecoveryCodes
+}
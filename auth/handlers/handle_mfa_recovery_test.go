@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mfaRecoveryCodes(t *testing.T) {
+	var (
+		rq   = require.New(t)
+		ctx  = context.Background()
+		user = makeMockUser(ctx)
+		req  = &http.Request{URL: &url.URL{}, Form: url.Values{}, PostForm: url.Values{}}
+
+		codes = []string{"abcde-fghjk", "mnpqr-stuvw"}
+		fail  bool
+
+		authService = &authServiceMocked{
+			generateRecoveryCodes: func(ctx context.Context) ([]string, error) {
+				if fail {
+					return nil, fmt.Errorf("TOTP not configured")
+				}
+
+				return codes, nil
+			},
+		}
+
+		authHandlers = prepareClientAuthHandlers(ctx, authService, &settings.Settings{})
+		authReq      = prepareClientAuthReq(ctx, req, user)
+	)
+
+	// nothing to display, back to security page
+	rq.NoError(authHandlers.mfaRecoveryCodesForm(authReq))
+	rq.Equal(GetLinks().Security, authReq.RedirectTo)
+
+	req.Form.Set("action", "generateRecoveryCodes")
+	rq.NoError(authHandlers.securityProc(authReq))
+	rq.Equal(GetLinks().MfaRecoveryCodes, authReq.RedirectTo)
+
+	// codes are kept in the session until displayed
+	authReq.RedirectTo = ""
+	rq.NoError(authHandlers.mfaRecoveryCodesForm(authReq))
+	rq.Equal(TmplMfaRecoveryCodes, authReq.Template)
+	rq.Equal(codes, authReq.Data["codes"])
+
+	// codes are displayed only once
+	authReq.RedirectTo = ""
+	rq.NoError(authHandlers.mfaRecoveryCodesForm(authReq))
+	rq.Equal(GetLinks().Security, authReq.RedirectTo)
+
+	fail = true
+	authReq = prepareClientAuthReq(ctx, req, user)
+	rq.NoError(authHandlers.securityProc(authReq))
+	rq.Equal(GetLinks().Security, authReq.RedirectTo)
+	rq.Equal([]request.Alert{{Type: "danger", Text: "Could not generate recovery codes"}}, authReq.NewAlerts)
+}
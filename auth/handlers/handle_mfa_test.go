@@ -186,6 +186,38 @@ func Test_mfaProc(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "Recovery code: successful login",
+			payload: map[string]string(nil),
+			alerts:  []request.Alert{{Type: "primary", Text: "Recovery code accepted"}},
+			link:    GetLinks().Mfa,
+			fn: func() {
+				req.Form.Set("action", "verifyRecoveryCode")
+				req.PostForm.Add("recoveryCode", "abcde-fghjk")
+
+				authService = &authServiceMocked{
+					validateRecoveryCode: func(ctx context.Context, code string) (err error) {
+						return nil
+					},
+				}
+			},
+		},
+		{
+			name:    "Recovery code: invalid code",
+			payload: map[string]string{"recoveryCodeError": "invalid recovery code"},
+			alerts:  []request.Alert(nil),
+			link:    GetLinks().Mfa,
+			fn: func() {
+				req.Form.Set("action", "verifyRecoveryCode")
+				req.PostForm.Add("recoveryCode", "abcde-fghjk")
+
+				authService = &authServiceMocked{
+					validateRecoveryCode: func(ctx context.Context, code string) (err error) {
+						return service.AuthErrInvalidRecoveryCode()
+					},
+				}
+			},
+		},
 	}
 
 	for _, tc := range tcc {
@@ -84,6 +84,9 @@ func (h AuthHandlers) mfaTotpConfigProc(req *request.AuthReq) (err error) {
 		h.Log.Info("TOTP code verified")
 		req.RedirectTo = GetLinks().Security
 		delete(req.Session.Values, totpSecretKey)
+
+		// recovery codes are displayed right after TOTP is configured
+		h.mfaRecoveryCodesGenerate(req)
 		return nil
 	}
 
@@ -165,13 +168,21 @@ func (h AuthHandlers) mfaTotpDisableProc(req *request.AuthReq) (err error) {
 	req.RedirectTo = GetLinks().MfaTotpDisable
 	req.SetKV(nil)
 
-	var user *types.User
+	var (
+		user *types.User
+		code = req.Request.PostFormValue("code")
+	)
+
+	if rc := req.Request.PostFormValue("recoveryCode"); rc != "" {
+		// lost device, recovery code is used instead
+		code = rc
+	}
 
 	// Here is where code validation is done and where the secret is stored
 	user, err = h.AuthService.RemoveTOTP(
 		req.Context(),
 		req.AuthUser.User.ID,
-		req.Request.PostFormValue("code"),
+		code,
 	)
 
 	if err == nil {
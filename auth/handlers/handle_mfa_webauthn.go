@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// session keys where challenges are kept between requests
+	webauthnRegistrationChallengeKey = "webauthnRegistrationChallenge"
+	webauthnAssertionChallengeKey    = "webauthnAssertionChallenge"
+)
+
+// Handles security key (WebAuthn) registration form
+//
+// Creation options are passed to the browser that
+// asks the authenticator to create a new credential
+func (h AuthHandlers) mfaWebAuthnConfigForm(req *request.AuthReq) (err error) {
+	var (
+		opt *webauthn.CreationOptions
+		rp  *webauthn.RelyingParty
+	)
+
+	if rp, err = h.relyingParty(); err != nil {
+		return err
+	}
+
+	opt, err = h.AuthService.WebAuthnCreationOptions(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		rp,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	req.Session.Values[webauthnRegistrationChallengeKey] = []byte(opt.Challenge)
+
+	if req.Data["options"], err = webauthnOptionsJSON(opt); err != nil {
+		return err
+	}
+
+	_, req.Data["enforced"] = h.Settings.WebAuthnPolicy(request.GetRoleMemberships(req.Session))
+	req.Data["form"] = req.GetKV()
+	req.Template = TmplMfaWebAuthn
+	req.SetKV(nil)
+	return nil
+}
+
+// Handles security key registration form processing
+func (h AuthHandlers) mfaWebAuthnConfigProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().MfaWebAuthnSetup
+	req.SetKV(nil)
+
+	var (
+		user           *types.User
+		rp             *webauthn.RelyingParty
+		challenge, has = req.Session.Values[webauthnRegistrationChallengeKey]
+	)
+
+	if !has {
+		return fmt.Errorf("no WebAuthn challenge in session")
+	}
+
+	// challenge can be used only once
+	delete(req.Session.Values, webauthnRegistrationChallengeKey)
+
+	if rp, err = h.relyingParty(); err != nil {
+		return err
+	}
+
+	user, err = h.AuthService.ConfigureWebAuthn(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		rp,
+		challenge.([]byte),
+		req.Request.PostFormValue("label"),
+		req.Request.PostFormValue("response"),
+	)
+
+	if err != nil {
+		h.Log.Warn("security key registration failed", zap.Error(err))
+		req.SetKV(map[string]string{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	req.NewAlerts = append(req.NewAlerts, request.Alert{
+		Type: "primary",
+		Text: "Two factor authentication with security key enabled",
+	})
+
+	// Make sure we update User's data in the session
+	user.SetRoles(request.GetRoleMemberships(req.Session))
+	req.AuthUser.User = user
+	req.AuthUser.CompleteWebAuthn()
+	req.AuthUser.Save(req.Session)
+
+	h.Log.Info("security key registered")
+	req.RedirectTo = GetLinks().Security
+	return nil
+}
+
+// Handles removal of the security key
+func (h AuthHandlers) mfaWebAuthnRemoveProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().Security
+
+	var (
+		user          *types.User
+		credentialsID uint64
+	)
+
+	if credentialsID, err = strconv.ParseUint(req.Request.PostFormValue("credentialsID"), 10, 64); err != nil || credentialsID == 0 {
+		req.PushDangerAlert("Invalid security key")
+		return nil
+	}
+
+	user, err = h.AuthService.RemoveWebAuthn(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		req.AuthUser.User.ID,
+		credentialsID,
+	)
+
+	if err != nil {
+		req.PushDangerAlert(err.Error())
+		return nil
+	}
+
+	req.PushAlert("Security key removed")
+
+	// Make sure we update User's data in the session
+	user.SetRoles(request.GetRoleMemberships(req.Session))
+	req.AuthUser.User = user
+	if !user.Meta.SecurityPolicy.MFA.EnforcedWebAuthn {
+		req.AuthUser.ResetWebAuthn(h.Settings)
+	}
+
+	req.AuthUser.Save(req.Session)
+
+	h.Log.Info("security key removed")
+	return nil
+}
+
+// prepares assertion options for MFA form
+// and keeps the challenge in the session
+func (h AuthHandlers) mfaWebAuthnRequestOptions(req *request.AuthReq) (string, error) {
+	rp, err := h.relyingParty()
+	if err != nil {
+		return "", err
+	}
+
+	opt, err := h.AuthService.WebAuthnRequestOptions(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		rp,
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Session.Values[webauthnAssertionChallengeKey] = []byte(opt.Challenge)
+	return webauthnOptionsJSON(opt)
+}
+
+// verifies assertion response submitted with MFA form
+func (h AuthHandlers) mfaWebAuthnValidate(req *request.AuthReq) error {
+	challenge, has := req.Session.Values[webauthnAssertionChallengeKey]
+	if !has {
+		return fmt.Errorf("no WebAuthn challenge in session")
+	}
+
+	// challenge can be used only once
+	delete(req.Session.Values, webauthnAssertionChallengeKey)
+
+	rp, err := h.relyingParty()
+	if err != nil {
+		return err
+	}
+
+	return h.AuthService.ValidateWebAuthn(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		rp,
+		challenge.([]byte),
+		req.Request.PostFormValue("response"),
+	)
+}
+
+// relying party is determinated from auth base URL
+func (h AuthHandlers) relyingParty() (*webauthn.RelyingParty, error) {
+	name := h.Settings.MultiFactor.WebAuthn.RelyingPartyName
+	if len(name) == 0 {
+		name = "Corteza"
+	}
+
+	return webauthn.NewRelyingParty(h.Opt.BaseURL, name)
+}
+
+func webauthnOptionsJSON(opt interface{}) (string, error) {
+	enc, err := json.Marshal(opt)
+	return string(enc), err
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/internal/webauthntest"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mfaWebAuthn(t *testing.T) {
+	var (
+		rq   = require.New(t)
+		ctx  = context.Background()
+		user = makeMockUser(ctx)
+		req  = &http.Request{URL: &url.URL{}, Form: url.Values{}, PostForm: url.Values{}}
+
+		authSettings = &settings.Settings{}
+		credential   *webauthn.Credential
+
+		// mimics system service, verifies responses
+		// with the relying party passed from the handler
+		authService = &authServiceMocked{
+			webAuthnCreationOptions: func(ctx context.Context, rp *webauthn.RelyingParty) (*webauthn.CreationOptions, error) {
+				return rp.CreationOptions(webauthn.Entity{ID: []byte{1}, Name: user.Email})
+			},
+			configureWebAuthn: func(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, label, response string) (*types.User, error) {
+				r, err := webauthn.ParseRegistrationResponse([]byte(response))
+				if err != nil {
+					return nil, err
+				}
+
+				if credential, err = rp.VerifyRegistration(challenge, r); err != nil {
+					return nil, err
+				}
+
+				user.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+				return user, nil
+			},
+			webAuthnRequestOptions: func(ctx context.Context, rp *webauthn.RelyingParty) (*webauthn.RequestOptions, error) {
+				return rp.RequestOptions(credential.ID)
+			},
+			validateWebAuthn: func(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, response string) error {
+				r, err := webauthn.ParseAssertionResponse([]byte(response))
+				if err != nil {
+					return err
+				}
+
+				return rp.VerifyAssertion(challenge, r, credential)
+			},
+		}
+
+		authHandlers = prepareClientAuthHandlers(ctx, authService, authSettings)
+	)
+
+	authHandlers.Opt = options.AuthOpt{BaseURL: "https://corteza.example.tld/auth"}
+	authSettings.MultiFactor.WebAuthn.EnforcedRoles = []uint64{42}
+
+	authenticator, err := webauthntest.NewSoftAuthenticator("https://corteza.example.tld")
+	rq.NoError(err)
+
+	t.Run("enforced for role", func(t *testing.T) {
+		rq := require.New(t)
+
+		user.SetRoles([]uint64{42})
+		authReq := prepareClientAuthReq(ctx, req, user)
+		authReq.AuthUser = request.NewAuthUser(authSettings, user, false, 0)
+
+		rq.True(authReq.AuthUser.UnconfiguredWebAuthn())
+
+		rq.NoError(authOnly(authHandlers.profileForm)(authReq))
+		rq.Equal(GetLinks().MfaWebAuthnSetup, authReq.RedirectTo)
+
+		// not enforced for other roles
+		user.SetRoles([]uint64{1})
+		authReq.AuthUser = request.NewAuthUser(authSettings, user, false, 0)
+		rq.True(authReq.AuthUser.DisabledWebAuthn())
+	})
+
+	t.Run("registration", func(t *testing.T) {
+		rq := require.New(t)
+
+		user.SetRoles([]uint64{42})
+		authReq := prepareClientAuthReq(ctx, req, user)
+		authReq.AuthUser = request.NewAuthUser(authSettings, user, false, 0)
+		request.SetRoleMemberships(authReq.Session, user.Roles())
+
+		rq.NoError(authHandlers.mfaWebAuthnConfigForm(authReq))
+		rq.Equal(TmplMfaWebAuthn, authReq.Template)
+		rq.Equal(true, authReq.Data["enforced"])
+
+		opt := &webauthn.CreationOptions{}
+		rq.NoError(json.Unmarshal([]byte(authReq.Data["options"].(string)), opt))
+		rq.Equal("corteza.example.tld", opt.RP.ID)
+
+		r, err := authenticator.Create(opt)
+		rq.NoError(err)
+		rsp, _ := json.Marshal(r)
+		req.PostForm.Set("response", string(rsp))
+
+		rq.NoError(authHandlers.mfaWebAuthnConfigProc(authReq))
+		rq.Nil(authReq.GetKV())
+		rq.Equal(GetLinks().Security, authReq.RedirectTo)
+		rq.Equal([]request.Alert{{Type: "primary", Text: "Two factor authentication with security key enabled"}}, authReq.NewAlerts)
+		rq.False(authReq.AuthUser.PendingMFA())
+		rq.NotNil(credential)
+
+		// challenge can not be reused
+		rq.Error(authHandlers.mfaWebAuthnConfigProc(authReq))
+	})
+
+	t.Run("verification", func(t *testing.T) {
+		rq := require.New(t)
+
+		authReq := prepareClientAuthReq(ctx, req, user)
+		authReq.AuthUser = request.NewAuthUser(authSettings, user, false, 0)
+		rq.True(authReq.AuthUser.PendingWebAuthn())
+
+		rq.NoError(authHandlers.mfaForm(authReq))
+		rq.Equal(true, authReq.Data["webauthnPending"])
+
+		opt := &webauthn.RequestOptions{}
+		rq.NoError(json.Unmarshal([]byte(authReq.Data["webauthnOptions"].(string)), opt))
+
+		r, err := authenticator.Get(opt)
+		rq.NoError(err)
+		rsp, _ := json.Marshal(r)
+
+		req.Form.Set("action", "verifyWebAuthn")
+		req.PostForm.Set("response", string(rsp))
+
+		rq.NoError(authHandlers.mfaProc(authReq))
+		rq.Nil(authReq.GetKV())
+		rq.Equal([]request.Alert{{Type: "primary", Text: "Security key verified"}}, authReq.NewAlerts)
+		rq.Equal(GetLinks().Profile, authReq.RedirectTo)
+		rq.False(authReq.AuthUser.PendingMFA())
+
+		// replayed response (challenge is removed from the session)
+		authReq = prepareClientAuthReq(ctx, req, user)
+		authReq.AuthUser = request.NewAuthUser(authSettings, user, false, 0)
+		rq.NoError(authHandlers.mfaProc(authReq))
+		rq.Equal(map[string]string{"webauthnError": "no WebAuthn challenge in session"}, authReq.GetKV())
+		rq.True(authReq.AuthUser.PendingWebAuthn())
+
+		// response signed for a different (phishing) origin
+		authenticator.Origin = "https://corteza.example.tld.evil"
+
+		rq.NoError(authHandlers.mfaForm(authReq))
+		rq.NoError(json.Unmarshal([]byte(authReq.Data["webauthnOptions"].(string)), opt))
+		r, err = authenticator.Get(opt)
+		rq.NoError(err)
+		rsp, _ = json.Marshal(r)
+		req.PostForm.Set("response", string(rsp))
+
+		rq.NoError(authHandlers.mfaProc(authReq))
+		rq.Equal(map[string]string{"webauthnError": webauthn.ErrOriginMismatch.Error()}, authReq.GetKV())
+		rq.True(authReq.AuthUser.PendingWebAuthn())
+	})
+}
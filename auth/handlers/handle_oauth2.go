@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"github.com/cortezaproject/corteza-server/auth/oauth2"
@@ -10,7 +11,10 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	systemService "github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/dgrijalva/jwt-go"
 	oauth2def "github.com/go-oauth2/oauth2/v4"
+	oauth2errors "github.com/go-oauth2/oauth2/v4/errors"
+	oauth2models "github.com/go-oauth2/oauth2/v4/models"
 	"go.uber.org/zap"
 	"html/template"
 	"net/http"
@@ -49,6 +53,10 @@ func (h AuthHandlers) oauth2Authorize(req *request.AuthReq) (err error) {
 	// this way we work around the limitations we have with the oauth2 lib.
 	ctx = context.WithValue(req.Context(), &oauth2.ContextClientStore{}, client)
 
+	// OIDC nonce is stored with the authorization code
+	// and included in the issued ID token
+	ctx = oauth2.ContextWithNonce(ctx, req.Request.Form.Get("nonce"))
+
 	if client != nil {
 		// No client validation is done at this point;
 		// first, see if user is able to authenticate.
@@ -172,7 +180,7 @@ func (h AuthHandlers) oauth2Token(req *request.AuthReq) (err error) {
 		r := req.Request.Clone(context.WithValue(req.Context(), &oauth2.ContextClientStore{}, client))
 
 		// handle token request with extended context that now holds client!
-		err = h.OAuth2.HandleTokenRequest(req.Response, r)
+		err = h.oauth2IssueToken(req.Response, r, client)
 	}
 
 	return
@@ -208,6 +216,426 @@ func (h AuthHandlers) oauth2Info(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(data)
 }
 
+// oauth2IssueToken handles token request and writes token response
+//
+// With client-credentials grant, token is issued for the user impersonated by
+// the client; user's roles are filtered with client's security settings.
+//
+// ID token is added to the response when openid scope is granted
+func (h AuthHandlers) oauth2IssueToken(w http.ResponseWriter, r *http.Request, client *types.AuthClient) error {
+	var (
+		ctx   = r.Context()
+		nonce string
+	)
+
+	gt, tgr, err := h.OAuth2.ValidationTokenRequest(r)
+	if err != nil {
+		return h.oauth2Error(w, err)
+	}
+
+	switch gt {
+	case oauth2def.AuthorizationCode:
+		// code is removed when token is issued;
+		// load nonce that was stored with it
+		code, err := h.TokenService.LookupByCode(ctx, tgr.Code)
+		if err != nil && !errors.IsNotFound(err) {
+			return h.oauth2Error(w, err)
+		}
+
+		nonce = oauth2.Nonce(code)
+
+	case oauth2def.ClientCredentials:
+		if client.Secret == "" {
+			// client-credentials grant is allowed only for confidential clients
+			return h.oauth2Error(w, oauth2errors.ErrUnauthorizedClient)
+		}
+
+		u, err := h.ClientService.ImpersonatedUser(ctx, client)
+		if err != nil {
+			h.Log.Warn("client credentials grant refused", zap.Uint64("clientID", client.ID), zap.Error(err))
+			return h.oauth2Error(w, oauth2errors.ErrUnauthorizedClient)
+		}
+
+		roles := u.Roles()
+		if client.Security != nil {
+			roles = client.Security.ProcessRoles(roles...)
+		}
+
+		tgr.UserID = oauth2.UserIDSerializer(u.ID, roles...)
+	}
+
+	ti, err := h.OAuth2.GetAccessToken(ctx, gt, tgr)
+	if err != nil {
+		return h.oauth2Error(w, err)
+	}
+
+	data := h.OAuth2.GetTokenData(ti)
+
+	if auth.CheckScope(ti.GetScope(), "openid") && gt != oauth2def.ClientCredentials {
+		if data["id_token"], err = h.idToken(ctx, ti, nonce); err != nil {
+			return h.oauth2Error(w, err)
+		}
+	}
+
+	return h.oauth2JSON(w, http.StatusOK, data)
+}
+
+// OpenIDSupported reports if ID tokens can be issued
+//
+// ID tokens are signed with the default keyring and that is allowed only
+// with an asymmetric (RS256 or ES256) key; shared secret must not be used
+// because it can be used to forge access tokens as well
+func OpenIDSupported() bool {
+	return auth.DefaultKeyring != nil && auth.IsAsymmetricAlgorithm(auth.DefaultKeyring.Algorithm())
+}
+
+// idToken creates signed OIDC ID token for the user the token was issued to
+func (h AuthHandlers) idToken(ctx context.Context, ti oauth2def.TokenInfo, nonce string) (string, error) {
+	var (
+		iat = ti.GetAccessCreateAt()
+	)
+
+	if !OpenIDSupported() {
+		return "", oauth2errors.ErrInvalidScope
+	}
+
+	claims, err := h.userClaims(ctx, ti)
+	if err != nil {
+		return "", err
+	}
+
+	claims["iss"] = h.Opt.BaseURL
+	claims["aud"] = ti.GetClientID()
+	claims["iat"] = iat.Unix()
+	claims["exp"] = iat.Add(ti.GetAccessExpiresIn()).Unix()
+
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	return auth.DefaultKeyring.Sign(claims)
+}
+
+// userClaims returns standard OIDC claims for the user the token was issued to
+//
+// Profile claims are included with profile scope
+// and email claims with email scope
+func (h AuthHandlers) userClaims(ctx context.Context, ti oauth2def.TokenInfo) (jwt.MapClaims, error) {
+	userID := auth.ExtractUserIDFromSubClaim(ti.GetUserID())
+	if userID == 0 {
+		return nil, fmt.Errorf("invalid user ID in 'sub' claim")
+	}
+
+	claims := jwt.MapClaims{"sub": strconv.FormatUint(userID, 10)}
+
+	if !auth.CheckScope(ti.GetScope(), "profile") && !auth.CheckScope(ti.GetScope(), "email") {
+		return claims, nil
+	}
+
+	user, err := h.UserService.FindByID(
+		// inject ad-hoc identity into context so that user service is aware who is
+		// doing the lookup
+		auth.SetIdentityToContext(ctx, auth.NewIdentity(userID)),
+		userID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.CheckScope(ti.GetScope(), "profile") {
+		claims["name"] = user.Name
+		claims["preferred_username"] = user.Handle
+		claims["handle"] = user.Handle
+	}
+
+	if auth.CheckScope(ti.GetScope(), "email") {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailConfirmed
+	}
+
+	return claims, nil
+}
+
+// oauth2UserInfo handles OIDC userinfo requests
+//
+// Access token must be granted with openid scope
+func (h AuthHandlers) oauth2UserInfo(w http.ResponseWriter, r *http.Request) {
+	ti, err := h.OAuth2.ValidationBearerToken(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		_ = h.oauth2JSON(w, http.StatusUnauthorized, map[string]interface{}{"error": "invalid_token"})
+		return
+	}
+
+	if !auth.CheckScope(ti.GetScope(), "openid") {
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+		_ = h.oauth2JSON(w, http.StatusForbidden, map[string]interface{}{"error": "insufficient_scope"})
+		return
+	}
+
+	claims, err := h.userClaims(r.Context(), ti)
+	if err != nil {
+		h.Log.Error("failed to load user info", zap.Error(err))
+		_ = h.oauth2Error(w, err)
+		return
+	}
+
+	_ = h.oauth2JSON(w, http.StatusOK, claims)
+}
+
+// oauth2Introspect handles token introspection requests (RFC 7662)
+//
+// Caller must authenticate with client credentials;
+// unknown, revoked and expired tokens are reported as inactive
+func (h AuthHandlers) oauth2Introspect(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+	)
+
+	if _, err := h.authenticateClient(r); err != nil {
+		h.Log.Debug("token introspection refused", zap.Error(err))
+		_ = h.oauth2Error(w, oauth2errors.ErrInvalidClient)
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		_ = h.oauth2Error(w, oauth2errors.ErrInvalidRequest)
+		return
+	}
+
+	t, ti, isRefresh, err := h.lookupToken(ctx, token, r.PostFormValue("token_type_hint"))
+	if err != nil {
+		_ = h.oauth2Error(w, err)
+		return
+	}
+
+	if t == nil {
+		_ = h.oauth2JSON(w, http.StatusOK, map[string]interface{}{"active": false})
+		return
+	}
+
+	var (
+		iat = ti.GetAccessCreateAt()
+		exp = iat.Add(ti.GetAccessExpiresIn())
+
+		data = map[string]interface{}{
+			"active":    true,
+			"scope":     ti.GetScope(),
+			"client_id": ti.GetClientID(),
+			"aud":       ti.GetClientID(),
+			"iss":       h.Opt.BaseURL,
+		}
+	)
+
+	if isRefresh {
+		iat = ti.GetRefreshCreateAt()
+		exp = t.ExpiresAt
+	} else {
+		data["token_type"] = "Bearer"
+	}
+
+	if !exp.After(time.Now()) {
+		_ = h.oauth2JSON(w, http.StatusOK, map[string]interface{}{"active": false})
+		return
+	}
+
+	data["iat"] = iat.Unix()
+	data["exp"] = exp.Unix()
+	SubSplit(ti, data)
+
+	_ = h.oauth2JSON(w, http.StatusOK, data)
+}
+
+// oauth2Revoke handles token revocation requests (RFC 7009)
+//
+// Revoking access or refresh token removes both; clients can revoke only their own tokens.
+// Note that JWT access tokens remain valid for the API until they expire
+// but are no longer reported as active by the introspection endpoint
+func (h AuthHandlers) oauth2Revoke(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+	)
+
+	client, err := h.authenticateClient(r)
+	if err != nil {
+		h.Log.Debug("token revocation refused", zap.Error(err))
+		_ = h.oauth2Error(w, oauth2errors.ErrInvalidClient)
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		_ = h.oauth2Error(w, oauth2errors.ErrInvalidRequest)
+		return
+	}
+
+	t, _, _, err := h.lookupToken(ctx, token, r.PostFormValue("token_type_hint"))
+	if err != nil {
+		_ = h.oauth2Error(w, err)
+		return
+	}
+
+	if t != nil {
+		if t.ClientID != client.ID {
+			_ = h.oauth2Error(w, oauth2errors.ErrUnauthorizedClient)
+			return
+		}
+
+		if err = h.TokenService.DeleteByID(ctx, t.ID); err != nil {
+			_ = h.oauth2Error(w, err)
+			return
+		}
+	}
+
+	// invalid tokens do not cause an error response
+	w.WriteHeader(http.StatusOK)
+}
+
+// authenticateClient loads client from the request and verifies its secret
+//
+// Client credentials are read from the basic auth header or from the
+// client_id & client_secret params
+func (h AuthHandlers) authenticateClient(r *http.Request) (*types.AuthClient, error) {
+	id, secret, found := r.BasicAuth()
+	if !found {
+		id, secret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+
+	clientID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || clientID == 0 {
+		return nil, fmt.Errorf("invalid client ID")
+	}
+
+	client, err := h.ClientService.LookupByID(r.Context(), clientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client: %w", err)
+	}
+
+	if err = client.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid client: %w", err)
+	}
+
+	if client.Secret == "" || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(secret)) != 1 {
+		return nil, fmt.Errorf("invalid client secret")
+	}
+
+	return client, nil
+}
+
+// lookupToken loads stored access or refresh token
+//
+// Token type hint only determines the lookup order.
+// Returns nil when token is not found
+func (h AuthHandlers) lookupToken(ctx context.Context, token, hint string) (t *types.AuthOa2token, ti oauth2def.TokenInfo, isRefresh bool, err error) {
+	var (
+		lookups = []func(context.Context, string) (*types.AuthOa2token, error){
+			h.TokenService.LookupByAccess,
+			h.TokenService.LookupByRefresh,
+		}
+	)
+
+	if hint == "refresh_token" {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		t, err = lookup(ctx, token)
+		if errors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		info := &oauth2models.Token{}
+		if err = t.Data.Unmarshal(info); err != nil {
+			return nil, nil, false, err
+		}
+
+		return t, info, t.Refresh == token, nil
+	}
+
+	return nil, nil, false, nil
+}
+
+// oauth2Error writes OAuth2 error response
+func (h AuthHandlers) oauth2Error(w http.ResponseWriter, err error) error {
+	data, code, header := h.OAuth2.GetErrorData(err)
+	for k := range header {
+		w.Header().Set(k, header.Get(k))
+	}
+
+	return h.oauth2JSON(w, code, data)
+}
+
+// oauth2JSON writes JSON response that must not be cached
+func (h AuthHandlers) oauth2JSON(w http.ResponseWriter, code int, data interface{}) error {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(data)
+}
+
+// oauth2PublicKeys writes set of public keys (JWKS) that can be used to verify issued tokens
+//
+// Set is empty when tokens are signed with the shared secret
+func (h AuthHandlers) oauth2PublicKeys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(auth.DefaultKeyring.JWKS())
+}
+
+// openIDConfiguration writes OpenID provider configuration (discovery)
+//
+// openid scope is advertised only when ID tokens can be issued
+func (h AuthHandlers) openIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	var (
+		l = GetLinks()
+
+		clientAuthMethods = []string{"client_secret_basic", "client_secret_post"}
+
+		scopes  = []string{"profile", "email", "api"}
+		idAlgos = []string{}
+
+		// links are relative to the server root
+		// and base URL points to the /auth
+		endpoint = func(link string) string {
+			return strings.TrimRight(h.Opt.BaseURL, "/") + strings.TrimPrefix(link, "/auth")
+		}
+	)
+
+	if OpenIDSupported() {
+		scopes = append([]string{"openid"}, scopes...)
+		idAlgos = append(idAlgos, auth.DefaultKeyring.Algorithm())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                h.Opt.BaseURL,
+		"authorization_endpoint":                endpoint(l.OAuth2Authorize),
+		"token_endpoint":                        endpoint(l.OAuth2Token),
+		"userinfo_endpoint":                     endpoint(l.OAuth2UserInfo),
+		"introspection_endpoint":                endpoint(l.OAuth2Introspect),
+		"revocation_endpoint":                   endpoint(l.OAuth2Revoke),
+		"jwks_uri":                              endpoint(l.OAuth2PublicKeys),
+		"subject_types_supported":               []string{"public"},
+		"response_types_supported":              []string{"code"},
+		"response_modes_supported":              []string{"query"},
+		"scopes_supported":                      scopes,
+		"claims_supported":                      []string{"sub", "iss", "aud", "exp", "iat", "nonce", "name", "preferred_username", "handle", "email", "email_verified"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "client_credentials"},
+		"code_challenge_methods_supported":      []string{"plain", "S256"},
+		"id_token_signing_alg_values_supported": idAlgos,
+
+		"token_endpoint_auth_methods_supported":         clientAuthMethods,
+		"introspection_endpoint_auth_methods_supported": clientAuthMethods,
+		"revocation_endpoint_auth_methods_supported":    clientAuthMethods,
+	})
+}
+
 // oauth2authorizeDefaultClient acts as a proxy for default client
 //
 // Responsibilities:
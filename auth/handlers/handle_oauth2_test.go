@@ -2,13 +2,25 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/dgrijalva/jwt-go"
+	oauth2def "github.com/go-oauth2/oauth2/v4"
+	oauth2models "github.com/go-oauth2/oauth2/v4/models"
+	"github.com/go-oauth2/oauth2/v4/server"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -129,3 +141,628 @@ func Test_oauth2AuthorizeSuccessSetParams(t *testing.T) {
 	rq.Equal(-1, authReq.Status)
 	rq.Equal(nil, authReq.Data["error"])
 }
+
+func Test_openIDConfiguration(t *testing.T) {
+	var (
+		rq = require.New(t)
+		rr = httptest.NewRecorder()
+
+		authHandlers = &AuthHandlers{
+			Log: zap.NewNop(),
+			Opt: options.AuthOpt{BaseURL: "https://corteza.tld/auth"},
+		}
+
+		data = map[string]interface{}{}
+	)
+
+	k, err := auth.GenerateSigningKey("42", "RS256")
+	rq.NoError(err)
+
+	defer func(kr *auth.Keyring) { auth.DefaultKeyring = kr }(auth.DefaultKeyring)
+	auth.DefaultKeyring = auth.NewKeyring()
+	rq.NoError(auth.DefaultKeyring.Set(k))
+
+	authHandlers.openIDConfiguration(rr, httptest.NewRequest(http.MethodGet, "/auth/.well-known/openid-configuration", nil))
+
+	rq.Equal(http.StatusOK, rr.Code)
+	rq.NoError(json.NewDecoder(rr.Body).Decode(&data))
+	rq.Equal("https://corteza.tld/auth", data["issuer"])
+	rq.Equal("https://corteza.tld/auth/oauth2/token", data["token_endpoint"])
+	rq.Equal("https://corteza.tld/auth/oauth2/userinfo", data["userinfo_endpoint"])
+	rq.Contains(data["scopes_supported"], "openid")
+	rq.Equal("https://corteza.tld/auth/oauth2/public-keys", data["jwks_uri"])
+	rq.Equal("https://corteza.tld/auth/oauth2/introspect", data["introspection_endpoint"])
+	rq.Equal("https://corteza.tld/auth/oauth2/revoke", data["revocation_endpoint"])
+	rq.Contains(data["grant_types_supported"], "client_credentials")
+	rq.Equal([]interface{}{"RS256"}, data["id_token_signing_alg_values_supported"])
+
+	// ID tokens are not signed with the shared secret
+	auth.DefaultKeyring = auth.HmacKeyring("secret")
+	rr = httptest.NewRecorder()
+	data = map[string]interface{}{}
+
+	authHandlers.openIDConfiguration(rr, httptest.NewRequest(http.MethodGet, "/auth/.well-known/openid-configuration", nil))
+
+	rq.NoError(json.NewDecoder(rr.Body).Decode(&data))
+	rq.NotContains(data["scopes_supported"], "openid")
+	rq.Empty(data["id_token_signing_alg_values_supported"])
+}
+
+func Test_oauth2PublicKeys(t *testing.T) {
+	var (
+		rq = require.New(t)
+		rr = httptest.NewRecorder()
+
+		authHandlers = &AuthHandlers{Log: zap.NewNop()}
+
+		jwks = auth.JSONWebKeySet{}
+	)
+
+	k, err := auth.GenerateSigningKey("42", "RS256")
+	rq.NoError(err)
+
+	defer func(kr *auth.Keyring) { auth.DefaultKeyring = kr }(auth.DefaultKeyring)
+	auth.DefaultKeyring = auth.NewKeyring()
+	rq.NoError(auth.DefaultKeyring.Set(k))
+
+	authHandlers.oauth2PublicKeys(rr, httptest.NewRequest(http.MethodGet, "/auth/oauth2/public-keys", nil))
+
+	rq.Equal(http.StatusOK, rr.Code)
+	rq.Equal("application/json", rr.Header().Get("Content-Type"))
+	rq.NoError(json.NewDecoder(rr.Body).Decode(&jwks))
+	rq.Len(jwks.Keys, 1)
+	rq.Equal("42", jwks.Keys[0].Kid)
+	rq.Equal("RSA", jwks.Keys[0].Kty)
+	rq.NotEmpty(jwks.Keys[0].N)
+}
+
+func Test_oauth2ClientCredentials(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		client = &types.AuthClient{
+			ID:         42,
+			Secret:     "secret",
+			Enabled:    true,
+			ValidGrant: "client_credentials",
+			Security: &types.AuthClientSecurity{
+				ImpersonateUser: 1,
+				ProhibitedRoles: []string{"3"},
+			},
+		}
+
+		issuedFor string
+	)
+
+	tcc := []struct {
+		name   string
+		user   func(context.Context, *types.AuthClient) (*types.User, error)
+		status int
+		sub    string
+	}{
+		{
+			name: "token issued for impersonated user",
+			user: func(context.Context, *types.AuthClient) (*types.User, error) {
+				u := &types.User{ID: 1}
+				u.SetRoles([]uint64{2, 3})
+				return u, nil
+			},
+			status: http.StatusOK,
+			sub:    "1 2",
+		},
+		{
+			name: "client without impersonated user",
+			user: func(context.Context, *types.AuthClient) (*types.User, error) {
+				return nil, errors.New("client does not impersonate any user")
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rq = require.New(t)
+				rr = httptest.NewRecorder()
+				r  = httptest.NewRequest(http.MethodPost, "/auth/oauth2/token", nil)
+
+				data = map[string]interface{}{}
+			)
+
+			issuedFor = ""
+
+			authHandlers := &AuthHandlers{
+				Log: zap.NewNop(),
+				OAuth2: &oauth2ServiceMocked{
+					validationTokenRequest: func(r *http.Request) (oauth2def.GrantType, *oauth2def.TokenGenerateRequest, error) {
+						return oauth2def.ClientCredentials, &oauth2def.TokenGenerateRequest{ClientID: "42"}, nil
+					},
+					getAccessToken: func(ctx context.Context, gt oauth2def.GrantType, tgr *oauth2def.TokenGenerateRequest) (oauth2def.TokenInfo, error) {
+						issuedFor = tgr.UserID
+						return &oauth2models.Token{Access: "access", UserID: tgr.UserID}, nil
+					},
+					getTokenData: func(ti oauth2def.TokenInfo) map[string]interface{} {
+						return map[string]interface{}{"access_token": ti.GetAccess()}
+					},
+					getErrorData: server.NewDefaultServer(nil).GetErrorData,
+				},
+				ClientService: &clientServiceMocked{impersonatedUser: tc.user},
+			}
+
+			rq.NoError(authHandlers.oauth2IssueToken(rr, r.WithContext(ctx), client))
+			rq.Equal(tc.status, rr.Code)
+			rq.Equal(tc.sub, issuedFor)
+			rq.Equal("no-store", rr.Header().Get("Cache-Control"))
+
+			rq.NoError(json.NewDecoder(rr.Body).Decode(&data))
+			if tc.status == http.StatusOK {
+				rq.Equal("access", data["access_token"])
+			} else {
+				rq.Equal("unauthorized_client", data["error"])
+			}
+		})
+	}
+}
+
+func Test_oauth2Introspect(t *testing.T) {
+	var (
+		client = &types.AuthClient{ID: 42, Secret: "secret", Enabled: true}
+
+		stored = func(access, refresh string, createdAt time.Time) *types.AuthOa2token {
+			ti := &oauth2models.Token{
+				ClientID:         "42",
+				UserID:           "1 2 3",
+				Scope:            "profile api",
+				Access:           access,
+				AccessCreateAt:   createdAt,
+				AccessExpiresIn:  time.Hour,
+				Refresh:          refresh,
+				RefreshCreateAt:  createdAt,
+				RefreshExpiresIn: time.Hour * 24,
+			}
+
+			data, _ := json.Marshal(ti)
+			return &types.AuthOa2token{
+				ID:        1,
+				ClientID:  42,
+				Access:    access,
+				Refresh:   refresh,
+				ExpiresAt: createdAt.Add(ti.RefreshExpiresIn),
+				Data:      data,
+			}
+		}
+
+		tokens = map[string]*types.AuthOa2token{
+			"valid":   stored("valid", "valid-refresh", time.Now()),
+			"expired": stored("expired", "expired-refresh", time.Now().Add(-time.Hour*2)),
+		}
+
+		lookup = func(refresh bool) func(context.Context, string) (*types.AuthOa2token, error) {
+			return func(_ context.Context, token string) (*types.AuthOa2token, error) {
+				for _, t := range tokens {
+					if (!refresh && t.Access == token) || (refresh && t.Refresh == token) {
+						return t, nil
+					}
+				}
+
+				return nil, store.ErrNotFound
+			}
+		}
+
+		authHandlers = &AuthHandlers{
+			Log: zap.NewNop(),
+			Opt: options.AuthOpt{BaseURL: "https://corteza.tld/auth"},
+			OAuth2: &oauth2ServiceMocked{
+				getErrorData: server.NewDefaultServer(nil).GetErrorData,
+			},
+			ClientService: &clientServiceMocked{
+				lookupByID: func(ctx context.Context, ID uint64) (*types.AuthClient, error) {
+					if ID != client.ID {
+						return nil, store.ErrNotFound
+					}
+
+					return client, nil
+				},
+			},
+			TokenService: &tokenServiceMocked{
+				lookupByAccess:  lookup(false),
+				lookupByRefresh: lookup(true),
+			},
+		}
+	)
+
+	tcc := []struct {
+		name   string
+		form   url.Values
+		secret string
+		status int
+		active bool
+		check  func(*require.Assertions, map[string]interface{})
+	}{
+		{
+			name:   "active access token",
+			form:   url.Values{"token": {"valid"}},
+			secret: "secret",
+			status: http.StatusOK,
+			active: true,
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.Equal("Bearer", data["token_type"])
+				rq.Equal("profile api", data["scope"])
+				rq.Equal("42", data["client_id"])
+				rq.Equal("1", data["sub"])
+				rq.Equal("2 3", data["roles"])
+				rq.Equal("https://corteza.tld/auth", data["iss"])
+				rq.NotEmpty(data["exp"])
+			},
+		},
+		{
+			name:   "active refresh token",
+			form:   url.Values{"token": {"valid-refresh"}, "token_type_hint": {"refresh_token"}},
+			secret: "secret",
+			status: http.StatusOK,
+			active: true,
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.NotContains(data, "token_type")
+			},
+		},
+		{
+			name:   "expired access token",
+			form:   url.Values{"token": {"expired"}},
+			secret: "secret",
+			status: http.StatusOK,
+		},
+		{
+			name:   "refresh token outlives access token",
+			form:   url.Values{"token": {"expired-refresh"}},
+			secret: "secret",
+			status: http.StatusOK,
+			active: true,
+		},
+		{
+			name:   "unknown token",
+			form:   url.Values{"token": {"unknown"}},
+			secret: "secret",
+			status: http.StatusOK,
+		},
+		{
+			name:   "missing token",
+			form:   url.Values{},
+			secret: "secret",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid client secret",
+			form:   url.Values{"token": {"valid"}},
+			secret: "invalid",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rq = require.New(t)
+				rr = httptest.NewRecorder()
+				r  = httptest.NewRequest(http.MethodPost, "/auth/oauth2/introspect", strings.NewReader(tc.form.Encode()))
+
+				data = map[string]interface{}{}
+			)
+
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.SetBasicAuth("42", tc.secret)
+
+			authHandlers.oauth2Introspect(rr, r)
+
+			rq.Equal(tc.status, rr.Code)
+			rq.NoError(json.NewDecoder(rr.Body).Decode(&data))
+
+			if tc.status == http.StatusOK {
+				rq.Equal(tc.active, data["active"])
+			}
+
+			if tc.check != nil {
+				tc.check(rq, data)
+			}
+		})
+	}
+}
+
+func Test_oauth2Revoke(t *testing.T) {
+	var (
+		own     = &types.AuthOa2token{ID: 1, ClientID: 42, Access: "own", Refresh: "own-refresh", Data: []byte("{}")}
+		foreign = &types.AuthOa2token{ID: 2, ClientID: 43, Access: "foreign", Data: []byte("{}")}
+
+		deleted []uint64
+
+		lookup = func(_ context.Context, token string) (*types.AuthOa2token, error) {
+			for _, t := range []*types.AuthOa2token{own, foreign} {
+				if t.Access == token || t.Refresh == token {
+					return t, nil
+				}
+			}
+
+			return nil, store.ErrNotFound
+		}
+
+		authHandlers = &AuthHandlers{
+			Log: zap.NewNop(),
+			OAuth2: &oauth2ServiceMocked{
+				getErrorData: server.NewDefaultServer(nil).GetErrorData,
+			},
+			ClientService: &clientServiceMocked{
+				lookupByID: func(ctx context.Context, ID uint64) (*types.AuthClient, error) {
+					return &types.AuthClient{ID: ID, Secret: "secret", Enabled: true}, nil
+				},
+			},
+			TokenService: &tokenServiceMocked{
+				lookupByAccess:  lookup,
+				lookupByRefresh: lookup,
+				deleteByID: func(ctx context.Context, ID uint64) error {
+					deleted = append(deleted, ID)
+					return nil
+				},
+			},
+		}
+	)
+
+	tcc := []struct {
+		name    string
+		token   string
+		status  int
+		deleted []uint64
+	}{
+		{
+			name:    "revoke own refresh token",
+			token:   "own-refresh",
+			status:  http.StatusOK,
+			deleted: []uint64{1},
+		},
+		{
+			name:   "revoke token issued to another client",
+			token:  "foreign",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "revoke unknown token",
+			token:  "unknown",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rq   = require.New(t)
+				rr   = httptest.NewRecorder()
+				form = url.Values{"token": {tc.token}, "client_id": {"42"}, "client_secret": {"secret"}}
+				r    = httptest.NewRequest(http.MethodPost, "/auth/oauth2/revoke", strings.NewReader(form.Encode()))
+			)
+
+			deleted = nil
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			authHandlers.oauth2Revoke(rr, r)
+
+			rq.Equal(tc.status, rr.Code)
+			rq.Equal(tc.deleted, deleted)
+		})
+	}
+}
+
+func Test_oauth2IssueIDToken(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		user = &types.User{ID: 1, Handle: "mock.user", Name: "Mock User", Email: "mockuser@example.tld", EmailConfirmed: true}
+
+		code = &types.AuthOa2token{Code: "code", Data: []byte(`{"Code":"code","Nonce":"n-0S6_WzA2Mj"}`)}
+
+		authHandlers = &AuthHandlers{
+			Log: zap.NewNop(),
+			Opt: options.AuthOpt{BaseURL: "https://corteza.tld/auth"},
+			UserService: &userServiceMocked{
+				findByID: func(ctx context.Context, ID uint64) (*types.User, error) {
+					return user, nil
+				},
+			},
+			TokenService: &tokenServiceMocked{
+				lookupByCode: func(ctx context.Context, c string) (*types.AuthOa2token, error) {
+					if c != code.Code {
+						return nil, store.ErrNotFound
+					}
+
+					return code, nil
+				},
+			},
+		}
+	)
+
+	k, err := auth.GenerateSigningKey("42", "ES256")
+	require.NoError(t, err)
+
+	defer func(kr *auth.Keyring) { auth.DefaultKeyring = kr }(auth.DefaultKeyring)
+	auth.DefaultKeyring = auth.NewKeyring()
+	require.NoError(t, auth.DefaultKeyring.Set(k))
+
+	tcc := []struct {
+		name    string
+		scope   string
+		keyring *auth.Keyring
+		status  int
+		check   func(*require.Assertions, map[string]interface{})
+	}{
+		{
+			name:  "without openid scope",
+			scope: "profile api",
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.NotContains(data, "id_token")
+			},
+		},
+		{
+			name:    "signed with shared secret",
+			scope:   "openid",
+			keyring: auth.HmacKeyring("secret"),
+			status:  http.StatusBadRequest,
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.Equal("invalid_scope", data["error"])
+				rq.NotContains(data, "id_token")
+			},
+		},
+		{
+			name:  "with profile and email",
+			scope: "openid profile email",
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				tkn, err := auth.DefaultKeyring.Parse(data["id_token"].(string))
+				rq.NoError(err)
+
+				claims := tkn.Claims.(jwt.MapClaims)
+				rq.Equal("1", claims["sub"])
+				rq.Equal("42", claims["aud"])
+				rq.Equal("https://corteza.tld/auth", claims["iss"])
+				rq.Equal("n-0S6_WzA2Mj", claims["nonce"])
+				rq.Equal("Mock User", claims["name"])
+				rq.Equal("mock.user", claims["preferred_username"])
+				rq.Equal("mockuser@example.tld", claims["email"])
+				rq.Equal(true, claims["email_verified"])
+			},
+		},
+		{
+			name:  "openid only",
+			scope: "openid",
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				tkn, err := auth.DefaultKeyring.Parse(data["id_token"].(string))
+				rq.NoError(err)
+
+				claims := tkn.Claims.(jwt.MapClaims)
+				rq.Equal("1", claims["sub"])
+				rq.NotContains(claims, "email")
+				rq.NotContains(claims, "name")
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rq = require.New(t)
+				rr = httptest.NewRecorder()
+				r  = httptest.NewRequest(http.MethodPost, "/auth/oauth2/token", nil)
+
+				data = map[string]interface{}{}
+			)
+
+			authHandlers.OAuth2 = &oauth2ServiceMocked{
+				validationTokenRequest: func(r *http.Request) (oauth2def.GrantType, *oauth2def.TokenGenerateRequest, error) {
+					return oauth2def.AuthorizationCode, &oauth2def.TokenGenerateRequest{ClientID: "42", Code: "code"}, nil
+				},
+				getAccessToken: func(ctx context.Context, gt oauth2def.GrantType, tgr *oauth2def.TokenGenerateRequest) (oauth2def.TokenInfo, error) {
+					return &oauth2models.Token{
+						ClientID:        "42",
+						UserID:          "1 2",
+						Scope:           tc.scope,
+						Access:          "access",
+						AccessCreateAt:  time.Now(),
+						AccessExpiresIn: time.Hour,
+					}, nil
+				},
+				getTokenData: func(ti oauth2def.TokenInfo) map[string]interface{} {
+					return map[string]interface{}{"access_token": ti.GetAccess()}
+				},
+				getErrorData: server.NewDefaultServer(nil).GetErrorData,
+			}
+
+			if tc.keyring != nil {
+				defer func(kr *auth.Keyring) { auth.DefaultKeyring = kr }(auth.DefaultKeyring)
+				auth.DefaultKeyring = tc.keyring
+			}
+
+			if tc.status == 0 {
+				tc.status = http.StatusOK
+			}
+
+			rq.NoError(authHandlers.oauth2IssueToken(rr, r.WithContext(ctx), &types.AuthClient{ID: 42}))
+			rq.Equal(tc.status, rr.Code)
+			rq.NoError(json.NewDecoder(rr.Body).Decode(&data))
+			tc.check(rq, data)
+		})
+	}
+}
+
+func Test_oauth2UserInfo(t *testing.T) {
+	var (
+		user = &types.User{ID: 1, Handle: "mock.user", Name: "Mock User", Email: "mockuser@example.tld"}
+
+		authHandlers = &AuthHandlers{
+			Log: zap.NewNop(),
+			UserService: &userServiceMocked{
+				findByID: func(ctx context.Context, ID uint64) (*types.User, error) {
+					return user, nil
+				},
+			},
+		}
+	)
+
+	tcc := []struct {
+		name   string
+		scope  string
+		err    error
+		status int
+		check  func(*require.Assertions, map[string]interface{})
+	}{
+		{
+			name:   "valid token",
+			scope:  "openid profile email",
+			status: http.StatusOK,
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.Equal("1", data["sub"])
+				rq.Equal("mock.user", data["preferred_username"])
+				rq.Equal("mockuser@example.tld", data["email"])
+				rq.Equal(false, data["email_verified"])
+			},
+		},
+		{
+			name:   "token without openid scope",
+			scope:  "profile api",
+			status: http.StatusForbidden,
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.Equal("insufficient_scope", data["error"])
+			},
+		},
+		{
+			name:   "invalid token",
+			err:    errors.New("invalid access token"),
+			status: http.StatusUnauthorized,
+			check: func(rq *require.Assertions, data map[string]interface{}) {
+				rq.Equal("invalid_token", data["error"])
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rq = require.New(t)
+				rr = httptest.NewRecorder()
+				r  = httptest.NewRequest(http.MethodGet, "/auth/oauth2/userinfo", nil)
+
+				data = map[string]interface{}{}
+			)
+
+			authHandlers.OAuth2 = &oauth2ServiceMocked{
+				validationBearerToken: func(r *http.Request) (oauth2def.TokenInfo, error) {
+					if tc.err != nil {
+						return nil, tc.err
+					}
+
+					return &oauth2models.Token{UserID: "1 2", Scope: tc.scope}, nil
+				},
+			}
+
+			authHandlers.oauth2UserInfo(rr, r)
+
+			rq.Equal(tc.status, rr.Code)
+			rq.NoError(json.NewDecoder(rr.Body).Decode(&data))
+			tc.check(rq, data)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"go.uber.org/zap"
 )
 
@@ -14,6 +15,24 @@ func (h *AuthHandlers) securityForm(req *request.AuthReq) error {
 	req.Data["emailOtpEnforced"] = umsp.EnforcedEmailOTP
 	req.Data["totpEnforced"] = umsp.EnforcedTOTP
 
+	// security keys can be enabled and enforced for specific roles
+	enabled, enforced := h.Settings.WebAuthnPolicy(request.GetRoleMemberships(req.Session))
+	req.Data["webauthnEnabled"] = enabled
+	req.Data["webauthnEnforced"] = enforced
+
+	if enabled {
+		cc, err := h.AuthService.WebAuthnCredentials(
+			auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+			req.AuthUser.User.ID,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		req.Data["webauthnKeys"] = cc
+	}
+
 	return nil
 }
 
@@ -30,6 +49,12 @@ func (h *AuthHandlers) securityProc(req *request.AuthReq) error {
 	case "disableTOTP":
 		req.RedirectTo = GetLinks().MfaTotpDisable
 
+	case "generateRecoveryCodes":
+		h.mfaRecoveryCodesGenerate(req)
+
+	case "configureWebAuthn":
+		req.RedirectTo = GetLinks().MfaWebAuthnSetup
+
 	case "disableEmailOTP", "enableEmailOTP":
 		enable := action == "enableEmailOTP"
 		if user, err := h.AuthService.ConfigureEmailOTP(req.Context(), req.AuthUser.User.ID, enable); err != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/cortezaproject/corteza-server/auth/settings"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/server"
@@ -40,12 +41,23 @@ type (
 		ConfigureTOTP(ctx context.Context, secret string, code string) (u *types.User, err error)
 		RemoveTOTP(ctx context.Context, userID uint64, code string) (u *types.User, err error)
 
+		GenerateRecoveryCodes(ctx context.Context) (codes []string, err error)
+		ValidateRecoveryCode(ctx context.Context, code string) (err error)
+
 		SendEmailOTP(ctx context.Context) (err error)
 		ConfigureEmailOTP(ctx context.Context, userID uint64, enable bool) (u *types.User, err error)
 		ValidateEmailOTP(ctx context.Context, code string) (err error)
+
+		WebAuthnCreationOptions(ctx context.Context, rp *webauthn.RelyingParty) (*webauthn.CreationOptions, error)
+		ConfigureWebAuthn(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, label, response string) (u *types.User, err error)
+		WebAuthnRequestOptions(ctx context.Context, rp *webauthn.RelyingParty) (*webauthn.RequestOptions, error)
+		ValidateWebAuthn(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, response string) (err error)
+		RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error)
+		WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error)
 	}
 
 	userService interface {
+		FindByID(context.Context, uint64) (*types.User, error)
 		Update(context.Context, *types.User) (*types.User, error)
 	}
 
@@ -53,6 +65,7 @@ type (
 		LookupByID(context.Context, uint64) (*types.AuthClient, error)
 		Confirmed(context.Context, uint64) (types.AuthConfirmedClientSet, error)
 		Revoke(ctx context.Context, userID, clientID uint64) error
+		ImpersonatedUser(ctx context.Context, client *types.AuthClient) (*types.User, error)
 	}
 
 	// @todo this should probably be a little more decoupled from the store and nicely named
@@ -60,6 +73,9 @@ type (
 		SearchByUserID(ctx context.Context, userID uint64) (types.AuthOa2tokenSet, error)
 		DeleteByID(ctx context.Context, ID uint64) error
 		DeleteByUserID(ctx context.Context, userID uint64) error
+		LookupByCode(ctx context.Context, code string) (*types.AuthOa2token, error)
+		LookupByAccess(ctx context.Context, access string) (*types.AuthOa2token, error)
+		LookupByRefresh(ctx context.Context, refresh string) (*types.AuthOa2token, error)
 	}
 
 	templateExecutor interface {
@@ -119,6 +135,8 @@ const (
 	TmplMfa                      = "mfa.html.tpl"
 	TmplMfaTotp                  = "mfa-totp.html.tpl"
 	TmplMfaTotpDisable           = "mfa-totp-disable.html.tpl"
+	TmplMfaRecoveryCodes         = "mfa-recovery-codes.html.tpl"
+	TmplMfaWebAuthn              = "mfa-webauthn.html.tpl"
 	TmplInternalError            = "error-internal.html.tpl"
 )
 
@@ -329,6 +347,10 @@ func authOnly(fn handlerFn) handlerFn {
 			// authenticated but need to configure MFA
 			req.RedirectTo = GetLinks().MfaTotpNewSecret
 
+		case req.AuthUser.UnconfiguredWebAuthn():
+			// authenticated but need to register a security key
+			req.RedirectTo = GetLinks().MfaWebAuthnSetup
+
 		case req.AuthUser.PendingMFA():
 			// authenticated but MFA pending
 			req.RedirectTo = GetLinks().Mfa
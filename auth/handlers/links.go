@@ -22,7 +22,13 @@ type (
 		OAuth2AuthorizeClient,
 		OAuth2Token,
 		OAuth2Info,
+		OAuth2Introspect,
+		OAuth2Revoke,
+		OAuth2UserInfo,
 		OAuth2DefaultClient,
+		OAuth2PublicKeys,
+
+		OpenIDConfiguration,
 
 		Mfa,
 
@@ -30,6 +36,11 @@ type (
 		MfaTotpQRImage,
 		MfaTotpDisable,
 
+		MfaRecoveryCodes,
+
+		MfaWebAuthnSetup,
+		MfaWebAuthnRemove,
+
 		External,
 
 		Assets string
@@ -56,13 +67,24 @@ func GetLinks() Links {
 		OAuth2AuthorizeClient: "/auth/oauth2/authorize-client",
 		OAuth2Token:           "/auth/oauth2/token",
 		OAuth2Info:            "/auth/oauth2/info",
+		OAuth2Introspect:      "/auth/oauth2/introspect",
+		OAuth2Revoke:          "/auth/oauth2/revoke",
+		OAuth2UserInfo:        "/auth/oauth2/userinfo",
 		OAuth2DefaultClient:   "/auth/oauth2/default-client",
+		OAuth2PublicKeys:      "/auth/oauth2/public-keys",
+
+		OpenIDConfiguration: "/auth/.well-known/openid-configuration",
 
 		Mfa:              "/auth/mfa",
 		MfaTotpNewSecret: "/auth/mfa/totp/setup",
 		MfaTotpQRImage:   "/auth/mfa/totp/qr.png",
 		MfaTotpDisable:   "/auth/mfa/totp/disable",
 
+		MfaRecoveryCodes: "/auth/mfa/recovery-codes",
+
+		MfaWebAuthnSetup:  "/auth/mfa/webauthn/setup",
+		MfaWebAuthnRemove: "/auth/mfa/webauthn/remove",
+
 		External: "/auth/external",
 
 		Assets: "/auth/assets/public",
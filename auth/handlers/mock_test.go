@@ -9,6 +9,7 @@ import (
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"github.com/cortezaproject/corteza-server/auth/settings"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -77,7 +78,24 @@ type (
 	}
 
 	userServiceMocked struct {
-		update func(context.Context, *types.User) (*types.User, error)
+		findByID func(context.Context, uint64) (*types.User, error)
+		update   func(context.Context, *types.User) (*types.User, error)
+	}
+
+	clientServiceMocked struct {
+		clientService
+
+		lookupByID       func(context.Context, uint64) (*types.AuthClient, error)
+		impersonatedUser func(context.Context, *types.AuthClient) (*types.User, error)
+	}
+
+	tokenServiceMocked struct {
+		tokenService
+
+		deleteByID      func(context.Context, uint64) error
+		lookupByCode    func(context.Context, string) (*types.AuthOa2token, error)
+		lookupByAccess  func(context.Context, string) (*types.AuthOa2token, error)
+		lookupByRefresh func(context.Context, string) (*types.AuthOa2token, error)
 	}
 
 	authServiceMocked struct {
@@ -94,19 +112,61 @@ type (
 		validateTOTP                      func(context.Context, string) (err error)
 		configureTOTP                     func(context.Context, string, string) (u *types.User, err error)
 		removeTOTP                        func(context.Context, uint64, string) (u *types.User, err error)
+		generateRecoveryCodes             func(context.Context) (codes []string, err error)
+		validateRecoveryCode              func(context.Context, string) (err error)
 		sendEmailOTP                      func(context.Context) (err error)
 		configureEmailOTP                 func(context.Context, uint64, bool) (u *types.User, err error)
 		validateEmailOTP                  func(context.Context, string) (err error)
+		webAuthnCreationOptions           func(context.Context, *webauthn.RelyingParty) (*webauthn.CreationOptions, error)
+		configureWebAuthn                 func(context.Context, *webauthn.RelyingParty, []byte, string, string) (u *types.User, err error)
+		webAuthnRequestOptions            func(context.Context, *webauthn.RelyingParty) (*webauthn.RequestOptions, error)
+		validateWebAuthn                  func(context.Context, *webauthn.RelyingParty, []byte, string) (err error)
+		removeWebAuthn                    func(context.Context, uint64, uint64) (u *types.User, err error)
+		webAuthnCredentials               func(context.Context, uint64) (types.CredentialsSet, error)
 	}
 )
 
 //
 // Mocking userService
 //
+func (u userServiceMocked) FindByID(ctx context.Context, userID uint64) (*types.User, error) {
+	return u.findByID(ctx, userID)
+}
+
 func (u userServiceMocked) Update(ctx context.Context, user *types.User) (*types.User, error) {
 	return u.update(ctx, user)
 }
 
+//
+// Mocking clientService
+//
+func (s clientServiceMocked) LookupByID(ctx context.Context, clientID uint64) (*types.AuthClient, error) {
+	return s.lookupByID(ctx, clientID)
+}
+
+func (s clientServiceMocked) ImpersonatedUser(ctx context.Context, client *types.AuthClient) (*types.User, error) {
+	return s.impersonatedUser(ctx, client)
+}
+
+//
+// Mocking tokenService
+//
+func (s tokenServiceMocked) DeleteByID(ctx context.Context, ID uint64) error {
+	return s.deleteByID(ctx, ID)
+}
+
+func (s tokenServiceMocked) LookupByCode(ctx context.Context, code string) (*types.AuthOa2token, error) {
+	return s.lookupByCode(ctx, code)
+}
+
+func (s tokenServiceMocked) LookupByAccess(ctx context.Context, access string) (*types.AuthOa2token, error) {
+	return s.lookupByAccess(ctx, access)
+}
+
+func (s tokenServiceMocked) LookupByRefresh(ctx context.Context, refresh string) (*types.AuthOa2token, error) {
+	return s.lookupByRefresh(ctx, refresh)
+}
+
 //
 // Mocking authService
 //
@@ -162,6 +222,38 @@ func (s authServiceMocked) RemoveTOTP(ctx context.Context, userID uint64, code s
 	return s.removeTOTP(ctx, userID, code)
 }
 
+func (s authServiceMocked) GenerateRecoveryCodes(ctx context.Context) (codes []string, err error) {
+	return s.generateRecoveryCodes(ctx)
+}
+
+func (s authServiceMocked) ValidateRecoveryCode(ctx context.Context, code string) (err error) {
+	return s.validateRecoveryCode(ctx, code)
+}
+
+func (s authServiceMocked) WebAuthnCreationOptions(ctx context.Context, rp *webauthn.RelyingParty) (*webauthn.CreationOptions, error) {
+	return s.webAuthnCreationOptions(ctx, rp)
+}
+
+func (s authServiceMocked) ConfigureWebAuthn(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, label, response string) (u *types.User, err error) {
+	return s.configureWebAuthn(ctx, rp, challenge, label, response)
+}
+
+func (s authServiceMocked) WebAuthnRequestOptions(ctx context.Context, rp *webauthn.RelyingParty) (*webauthn.RequestOptions, error) {
+	return s.webAuthnRequestOptions(ctx, rp)
+}
+
+func (s authServiceMocked) ValidateWebAuthn(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, response string) (err error) {
+	return s.validateWebAuthn(ctx, rp, challenge, response)
+}
+
+func (s authServiceMocked) RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error) {
+	return s.removeWebAuthn(ctx, userID, credentialsID)
+}
+
+func (s authServiceMocked) WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error) {
+	return s.webAuthnCredentials(ctx, userID)
+}
+
 func (s authServiceMocked) SendEmailOTP(ctx context.Context) (err error) {
 	return s.sendEmailOTP(ctx)
 }
@@ -268,6 +360,14 @@ func (m mockNotificationService) EmailOTP(ctx context.Context, emailAddress stri
 	return nil
 }
 
+func (m mockNotificationService) RecoveryCodeUsed(ctx context.Context, emailAddress string, remaining int) error {
+	return nil
+}
+
+func (m mockNotificationService) MFAReset(ctx context.Context, emailAddress string) error {
+	return nil
+}
+
 //
 // Mocking gorilla session
 //
@@ -83,6 +83,12 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 			r.Get(l.MfaTotpDisable, h.handle(authOnly(h.mfaTotpDisableForm)))
 			r.Post(l.MfaTotpDisable, h.handle(authOnly(h.mfaTotpDisableProc)))
 
+			r.Get(l.MfaRecoveryCodes, h.handle(partAuthOnly(h.mfaRecoveryCodesForm)))
+
+			r.Get(l.MfaWebAuthnSetup, h.handle(partAuthOnly(h.mfaWebAuthnConfigForm)))
+			r.Post(l.MfaWebAuthnSetup, h.handle(partAuthOnly(h.mfaWebAuthnConfigProc)))
+			r.Post(l.MfaWebAuthnRemove, h.handle(authOnly(h.mfaWebAuthnRemoveProc)))
+
 		})
 
 		r.Group(func(r chi.Router) {
@@ -102,5 +108,10 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 
 		r.HandleFunc("/auth/oauth2/token", h.handle(h.oauth2Token))
 		r.HandleFunc("/auth/oauth2/info", h.oauth2Info)
+		r.Post(l.OAuth2Introspect, h.oauth2Introspect)
+		r.Post(l.OAuth2Revoke, h.oauth2Revoke)
+		r.HandleFunc(l.OAuth2UserInfo, h.oauth2UserInfo)
+		r.Get(l.OAuth2PublicKeys, h.oauth2PublicKeys)
+		r.Get(l.OpenIDConfiguration, h.openIDConfiguration)
 	})
 }
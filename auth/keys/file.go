@@ -0,0 +1,125 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+)
+
+type (
+	// fileStorage keeps PEM encoded keys in a directory
+	//
+	// File name (without .pem extension) is used as kid and
+	// file modification time as the time key was created.
+	//
+	// The newest key is used for signing; each older key is
+	// considered retired when the next one was created
+	fileStorage struct {
+		path  string
+		grace time.Duration
+	}
+)
+
+const (
+	keyFileExt = ".pem"
+)
+
+func (s fileStorage) load(context.Context) (kk []*auth.SigningKey, err error) {
+	ff, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	kk = make([]*auth.SigningKey, 0, len(ff))
+	for _, f := range ff {
+		if f.IsDir() || filepath.Ext(f.Name()) != keyFileExt {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(s.path, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := auth.ParseSigningKey(strings.TrimSuffix(f.Name(), keyFileExt), data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key %s: %w", f.Name(), err)
+		}
+
+		k.CreatedAt = f.ModTime()
+		kk = append(kk, k)
+	}
+
+	// oldest first
+	sort.SliceStable(kk, func(i, j int) bool { return kk[i].CreatedAt.Before(kk[j].CreatedAt) })
+
+	for i := 0; i < len(kk)-1; i++ {
+		var (
+			retiredAt = kk[i+1].CreatedAt
+			expiresAt = retiredAt.Add(s.grace)
+		)
+
+		kk[i].RetiredAt = &retiredAt
+		kk[i].ExpiresAt = &expiresAt
+	}
+
+	return kk, nil
+}
+
+func (s fileStorage) rotate(ctx context.Context, alg string) (k *auth.SigningKey, err error) {
+	var (
+		n   = now()
+		pem []byte
+		kk  []*auth.SigningKey
+	)
+
+	if kk, err = s.load(ctx); err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(s.path, 0700); err != nil {
+		return
+	}
+
+	if k, err = auth.GenerateSigningKey(strconv.FormatUint(nextID(), 10), alg); err != nil {
+		return
+	}
+
+	if pem, err = k.PEM(); err != nil {
+		return
+	}
+
+	k.CreatedAt = n
+	filename := filepath.Join(s.path, k.ID+keyFileExt)
+	if err = ioutil.WriteFile(filename, pem, 0600); err != nil {
+		return
+	}
+
+	if err = os.Chtimes(filename, n, n); err != nil {
+		return
+	}
+
+	// keys that were retired before the previous key
+	// (and their tokens) are no longer valid
+	for _, old := range kk {
+		if old.Expired(n) {
+			if err = os.Remove(filepath.Join(s.path, old.ID+keyFileExt)); err != nil {
+				return
+			}
+		}
+	}
+
+	return k, nil
+}
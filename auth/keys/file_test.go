@@ -0,0 +1,85 @@
+package keys
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFileStorageRotation(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		dir = t.TempDir()
+
+		// keyring validates keys against the current time
+		clock = time.Now().Truncate(time.Second)
+		seq   uint64
+
+		kr  = auth.NewKeyring()
+		stg = &fileStorage{path: dir, grace: time.Hour}
+		m   = Manager(zap.NewNop(), stg, kr, "ES256", time.Hour)
+	)
+
+	defer func(orig func() time.Time) { now = orig }(now)
+	now = func() time.Time { return clock }
+
+	defer func(orig func() uint64) { nextID = orig }(nextID)
+	nextID = func() uint64 { seq++; return seq }
+
+	// generates first key when there are none
+	req.NoError(m.Load(ctx))
+	req.Equal("ES256", kr.Algorithm())
+	req.Len(kr.JWKS().Keys, 1)
+
+	signed, err := kr.Sign(jwt.StandardClaims{})
+	req.NoError(err)
+
+	// rotated key remains valid
+	clock = clock.Add(time.Minute)
+	_, err = m.Rotate(ctx)
+	req.NoError(err)
+
+	kk, err := m.Keys(ctx)
+	req.NoError(err)
+	req.Len(kk, 2)
+	req.Equal("1", kk[0].ID)
+	req.NotNil(kk[0].RetiredAt)
+	req.WithinDuration(clock.Add(time.Hour), *kk[0].ExpiresAt, 0)
+	req.Nil(kk[1].RetiredAt)
+
+	_, err = kr.Parse(signed)
+	req.NoError(err)
+
+	// loading does not rotate when active key exists
+	req.NoError(m.Load(ctx))
+	ff, err := ioutil.ReadDir(dir)
+	req.NoError(err)
+	req.Len(ff, 2)
+
+	// expired keys are removed on next rotation
+	clock = clock.Add(2 * time.Hour)
+	_, err = m.Rotate(ctx)
+	req.NoError(err)
+
+	kk, err = m.Keys(ctx)
+	req.NoError(err)
+	req.Len(kk, 2)
+	req.Equal("2", kk[0].ID)
+	req.Equal("3", kk[1].ID)
+
+	_, err = kr.Parse(signed)
+	req.Error(err)
+
+	// changed algorithm generates new key
+	m.alg = "RS256"
+	clock = clock.Add(time.Minute)
+	req.NoError(m.Load(ctx))
+	req.Equal("RS256", kr.Algorithm())
+}
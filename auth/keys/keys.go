@@ -0,0 +1,195 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap"
+)
+
+type (
+	// manager loads and rotates asymmetric keys used for signing JWTs
+	//
+	// Keys are stored in the database or in a directory on disk
+	manager struct {
+		log     *zap.Logger
+		storage storage
+		keys    *auth.Keyring
+
+		// algorithm used for new keys
+		alg string
+
+		// how long are retired keys valid;
+		// long enough for all tokens signed with them to expire
+		grace time.Duration
+	}
+
+	storage interface {
+		// loads all keys
+		load(ctx context.Context) ([]*auth.SigningKey, error)
+
+		// creates new key, retires all other keys
+		// and removes the expired ones
+		rotate(ctx context.Context, alg string) (*auth.SigningKey, error)
+	}
+)
+
+const (
+	// how often are keys reloaded to pick up
+	// keys rotated on other nodes
+	reloadInterval = time.Minute * 5
+)
+
+var (
+	DefaultManager *manager
+
+	// wrapper around id.Next() that will aid testing
+	nextID = func() uint64 {
+		return id.Next()
+	}
+
+	// wrapper around time.Now() that will aid testing
+	now = func() time.Time {
+		return time.Now()
+	}
+)
+
+// Setup initializes default manager and loads keys into the default keyring
+//
+// Nothing is done when tokens are signed with the shared secret (HS512)
+func Setup(ctx context.Context, log *zap.Logger, s store.Storer, opt options.AuthOpt) (err error) {
+	switch {
+	case auth.IsAsymmetricAlgorithm(opt.JwtAlgorithm):
+	case opt.JwtAlgorithm == jwt.SigningMethodHS512.Alg():
+		return nil
+	default:
+		return fmt.Errorf("unsupported JWT signing algorithm %q", opt.JwtAlgorithm)
+	}
+
+	var (
+		stg storage
+	)
+
+	if opt.JwtKeysPath != "" {
+		stg = &fileStorage{path: opt.JwtKeysPath, grace: opt.Expiry}
+	} else {
+		stg = &storeStorage{store: s, grace: opt.Expiry}
+	}
+
+	m := Manager(log.Named("keys"), stg, auth.DefaultKeyring, opt.JwtAlgorithm, opt.Expiry)
+	if err = m.Load(ctx); err != nil {
+		return fmt.Errorf("could not load JWT signing keys: %w", err)
+	}
+
+	DefaultManager = m
+	return nil
+}
+
+func Manager(log *zap.Logger, stg storage, keys *auth.Keyring, alg string, grace time.Duration) *manager {
+	m := &manager{
+		log:     log,
+		storage: stg,
+		keys:    keys,
+		alg:     alg,
+		grace:   grace,
+	}
+
+	keys.SetReloader(func() error {
+		return m.reload(context.Background())
+	})
+
+	return m
+}
+
+// Load loads keys into the keyring
+//
+// Keys are rotated when there is no valid signing key
+// or when signing key does not use the configured algorithm
+func (m *manager) Load(ctx context.Context) error {
+	kk, err := m.storage.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if signing := current(kk); signing != nil && signing.Method.Alg() == m.alg {
+		return m.keys.Set(kk...)
+	}
+
+	_, err = m.Rotate(ctx)
+	return err
+}
+
+// Rotate creates new signing key
+//
+// Previous keys are retired and remain valid until
+// all tokens signed with them expire
+func (m *manager) Rotate(ctx context.Context) (*auth.SigningKey, error) {
+	k, err := m.storage.rotate(ctx, m.alg)
+	if err != nil {
+		return nil, fmt.Errorf("could not rotate JWT signing keys: %w", err)
+	}
+
+	m.log.Info("JWT signing key rotated", zap.String("kid", k.ID), zap.String("alg", k.Method.Alg()))
+	return k, m.reload(ctx)
+}
+
+// Keys returns all loaded keys
+func (m *manager) Keys(ctx context.Context) ([]*auth.SigningKey, error) {
+	return m.storage.load(ctx)
+}
+
+// Watch reloads keys periodically
+func (m *manager) Watch(ctx context.Context) {
+	go func() {
+		defer sentry.Recover()
+
+		tck := time.NewTicker(reloadInterval)
+		defer tck.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tck.C:
+				if err := m.reload(ctx); err != nil {
+					m.log.Error("could not reload JWT signing keys", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (m *manager) reload(ctx context.Context) error {
+	kk, err := m.storage.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	return m.keys.Set(kk...)
+}
+
+// returns newest valid key that is not retired
+func current(kk []*auth.SigningKey) (k *auth.SigningKey) {
+	var (
+		n = now()
+	)
+
+	for _, c := range kk {
+		if c.RetiredAt != nil || c.Expired(n) {
+			continue
+		}
+
+		if k == nil || c.CreatedAt.After(k.CreatedAt) {
+			k = c
+		}
+	}
+
+	return
+}
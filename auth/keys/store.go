@@ -0,0 +1,87 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	// storeStorage keeps keys in the database
+	//
+	// Key ID is used as kid
+	storeStorage struct {
+		store store.Storer
+		grace time.Duration
+	}
+)
+
+func (s storeStorage) load(ctx context.Context) (kk []*auth.SigningKey, err error) {
+	set, _, err := store.SearchAuthSigningKeys(ctx, s.store, types.AuthSigningKeyFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	kk = make([]*auth.SigningKey, 0, len(set))
+	for _, r := range set {
+		k, err := auth.ParseSigningKey(strconv.FormatUint(r.ID, 10), []byte(r.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key %d: %w", r.ID, err)
+		}
+
+		k.CreatedAt = r.CreatedAt
+		k.RetiredAt = r.RetiredAt
+		k.ExpiresAt = r.ExpiresAt
+		kk = append(kk, k)
+	}
+
+	return kk, nil
+}
+
+func (s storeStorage) rotate(ctx context.Context, alg string) (k *auth.SigningKey, err error) {
+	var (
+		n         = now()
+		expiresAt = n.Add(s.grace)
+		r         = &types.AuthSigningKey{ID: nextID(), Algorithm: alg, CreatedAt: n}
+		pem       []byte
+	)
+
+	if k, err = auth.GenerateSigningKey(strconv.FormatUint(r.ID, 10), alg); err != nil {
+		return
+	}
+
+	if pem, err = k.PEM(); err != nil {
+		return
+	}
+
+	r.PrivateKey = string(pem)
+
+	return k, store.Tx(ctx, s.store, func(ctx context.Context, s store.Storer) error {
+		set, _, err := store.SearchAuthSigningKeys(ctx, s, types.AuthSigningKeyFilter{})
+		if err != nil {
+			return err
+		}
+
+		for _, old := range set {
+			switch {
+			case old.ExpiresAt != nil && !old.ExpiresAt.After(n):
+				err = store.DeleteAuthSigningKey(ctx, s, old)
+			case old.RetiredAt == nil:
+				old.RetiredAt = &n
+				old.ExpiresAt = &expiresAt
+				err = store.UpdateAuthSigningKey(ctx, s, old)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return store.CreateAuthSigningKey(ctx, s, r)
+	})
+}
@@ -24,6 +24,17 @@ type (
 			store.AuthConfirmedClients
 		}
 	}
+
+	// tokenData is stored with the token
+	//
+	// Authorization codes are stored with the nonce from the (OIDC) authorization
+	// request so that it can be included in the ID token issued for the code
+	tokenData struct {
+		oauth2models.Token
+		Nonce string `json:",omitempty"`
+	}
+
+	nonceCtxKey struct{}
 )
 
 var (
@@ -69,7 +80,13 @@ func (c CortezaTokenStore) Create(ctx context.Context, info oauth2.TokenInfo) (e
 		}
 	}
 
-	if oa2t.Data, err = json.Marshal(info); err != nil {
+	if t, is := info.(*oauth2models.Token); is && oa2t.Code != "" {
+		oa2t.Data, err = json.Marshal(tokenData{Token: *t, Nonce: NonceFromContext(ctx)})
+	} else {
+		oa2t.Data, err = json.Marshal(info)
+	}
+
+	if err != nil {
 		return
 	}
 
@@ -157,3 +174,26 @@ func (c CortezaTokenStore) GetByRefresh(ctx context.Context, refresh string) (oa
 
 	return internal, t.Data.Unmarshal(internal)
 }
+
+// ContextWithNonce adds nonce from the authorization request to the context
+//
+// Nonce is stored with the authorization code when code is created (see Create())
+func ContextWithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, nonceCtxKey{}, nonce)
+}
+
+// NonceFromContext returns nonce from the context (if any)
+func NonceFromContext(ctx context.Context) string {
+	nonce, _ := ctx.Value(nonceCtxKey{}).(string)
+	return nonce
+}
+
+// Nonce returns nonce that was stored with the authorization code
+func Nonce(t *types.AuthOa2token) string {
+	var aux = tokenData{}
+	if t == nil || t.Data.Unmarshal(&aux) != nil {
+		return ""
+	}
+
+	return aux.Nonce
+}
@@ -2,27 +2,25 @@ package oauth2
 
 import (
 	"context"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/rand"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-oauth2/oauth2/v4"
-	"github.com/go-oauth2/oauth2/v4/errors"
 	"strings"
 )
 
 // NewJWTAccessGenerate create to generate the jwt access token instance
-func NewJWTAccessGenerate(kid string, key []byte, method jwt.SigningMethod) *JWTAccessGenerate {
+//
+// Tokens are signed with the current signing key from the keyring
+func NewJWTAccessGenerate(keys *auth.Keyring) *JWTAccessGenerate {
 	return &JWTAccessGenerate{
-		SignedKeyID:  kid,
-		SignedKey:    key,
-		SignedMethod: method,
+		Keys: keys,
 	}
 }
 
 // JWTAccessGenerate generate the jwt access token
 type JWTAccessGenerate struct {
-	SignedKeyID  string
-	SignedKey    []byte
-	SignedMethod jwt.SigningMethod
+	Keys *auth.Keyring
 }
 
 // Token based on the UUID generated token
@@ -42,30 +40,7 @@ func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasi
 		"roles": userIdWithRoles[1],
 	}
 
-	token := jwt.NewWithClaims(a.SignedMethod, claims)
-	if a.SignedKeyID != "" {
-		token.Header["kid"] = a.SignedKeyID
-	}
-	var key interface{}
-	if a.isEs() {
-		v, err := jwt.ParseECPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else if a.isRsOrPS() {
-		v, err := jwt.ParseRSAPrivateKeyFromPEM(a.SignedKey)
-		if err != nil {
-			return "", "", err
-		}
-		key = v
-	} else if a.isHs() {
-		key = a.SignedKey
-	} else {
-		return "", "", errors.New("unsupported sign method")
-	}
-
-	access, err := token.SignedString(key)
+	access, err := a.Keys.Sign(claims)
 	if err != nil {
 		return "", "", err
 	}
@@ -77,17 +52,3 @@ func (a *JWTAccessGenerate) Token(ctx context.Context, data *oauth2.GenerateBasi
 
 	return access, refresh, nil
 }
-
-func (a *JWTAccessGenerate) isEs() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "ES")
-}
-
-func (a *JWTAccessGenerate) isRsOrPS() bool {
-	isRs := strings.HasPrefix(a.SignedMethod.Alg(), "RS")
-	isPs := strings.HasPrefix(a.SignedMethod.Alg(), "PS")
-	return isRs || isPs
-}
-
-func (a *JWTAccessGenerate) isHs() bool {
-	return strings.HasPrefix(a.SignedMethod.Alg(), "HS")
-}
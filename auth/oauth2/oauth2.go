@@ -1,9 +1,9 @@
 package oauth2
 
 import (
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/options"
-	"github.com/dgrijalva/jwt-go"
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/errors"
 	"github.com/go-oauth2/oauth2/v4/manage"
@@ -17,7 +17,7 @@ const (
 	RedirectUriSeparator = " "
 )
 
-func NewManager(opt options.AuthOpt, cs oauth2.ClientStore, ts oauth2.TokenStore) *manage.Manager {
+func NewManager(opt options.AuthOpt, keys *auth.Keyring, cs oauth2.ClientStore, ts oauth2.TokenStore) *manage.Manager {
 	manager := manage.NewDefaultManager()
 	manager.SetAuthorizeCodeTokenCfg(manage.DefaultAuthorizeCodeTokenCfg)
 
@@ -25,7 +25,7 @@ func NewManager(opt options.AuthOpt, cs oauth2.ClientStore, ts oauth2.TokenStore
 	manager.MapTokenStorage(ts)
 
 	// generate jwt access token
-	manager.MapAccessGenerate(NewJWTAccessGenerate("", []byte(opt.Secret), jwt.SigningMethodHS512))
+	manager.MapAccessGenerate(NewJWTAccessGenerate(keys))
 	manager.MapClientStorage(cs)
 
 	manager.SetValidateURIHandler(func(baseURI, redirectURI string) (err error) {
@@ -70,10 +70,10 @@ func NewServer(manager *manage.Manager) *server.Server {
 		AllowedGrantTypes: []oauth2.GrantType{
 			oauth2.AuthorizationCode,
 			oauth2.Refreshing,
-			// before enabling ClientCredentials grant type, we need to know how to modify released token
-			// using client's security info; how to enforce impersonated user and his roles.
-			//
-			// oauth2.ClientCredentials,
+
+			// impersonated user and roles are resolved from
+			// client's security info in the token handler
+			oauth2.ClientCredentials,
 		},
 		AllowedCodeChallengeMethods: []oauth2.CodeChallengeMethod{
 			oauth2.CodeChallengePlain,
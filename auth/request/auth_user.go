@@ -46,6 +46,7 @@ const (
 	authByPassword = "password"
 	authByEmailOTP = "email-otp"
 	authByTOTP     = "totp"
+	authByWebAuthn = "webauthn"
 )
 
 func init() {
@@ -78,6 +79,7 @@ func (au *authUser) set(s *settings.Settings, u *types.User) {
 		authByPassword: authStatusOK,
 		authByEmailOTP: authStatusDisabled,
 		authByTOTP:     authStatusDisabled,
+		authByWebAuthn: authStatusDisabled,
 	}
 
 	// determinate mfa status for email OTP
@@ -97,6 +99,17 @@ func (au *authUser) set(s *settings.Settings, u *types.User) {
 		mfaStatus[authByTOTP] = authStatusPending
 	}
 
+	// determinate mfa status for WebAuthn (security keys)
+	// enabled and enforced globally or for one of user's roles
+	if enabled, enforced := s.WebAuthnPolicy(u.Roles()); !enabled {
+		mfaStatus[authByWebAuthn] = authStatusDisabled
+	} else if umsp.EnforcedWebAuthn {
+		mfaStatus[authByWebAuthn] = authStatusPending
+	} else if enforced {
+		// no security keys registered but they are enforced
+		mfaStatus[authByWebAuthn] = authStatusUnconfigured
+	}
+
 	au.MFAStatus = mfaStatus
 }
 
@@ -120,6 +133,18 @@ func (au authUser) PendingTOTP() bool {
 	return au.MFAStatus[authByTOTP] == authStatusPending
 }
 
+func (au authUser) DisabledWebAuthn() bool {
+	return au.MFAStatus[authByWebAuthn] == authStatusDisabled
+}
+
+func (au authUser) UnconfiguredWebAuthn() bool {
+	return au.MFAStatus[authByWebAuthn] == authStatusUnconfigured
+}
+
+func (au authUser) PendingWebAuthn() bool {
+	return au.MFAStatus[authByWebAuthn] == authStatusPending
+}
+
 // PendingMFA Returns true if any of MFAs are pending
 func (au authUser) PendingMFA() bool {
 	for _, st := range au.MFAStatus {
@@ -143,6 +168,21 @@ func (au *authUser) ResetTOTP() {
 	au.MFAStatus[authByTOTP] = authStatusUnconfigured
 }
 
+func (au *authUser) CompleteWebAuthn() {
+	au.MFAStatus[authByWebAuthn] = authStatusOK
+}
+
+// ResetWebAuthn is used when user removes all security keys
+//
+// Security keys need to be registered again if they are enforced
+func (au *authUser) ResetWebAuthn(s *settings.Settings) {
+	if _, enforced := s.WebAuthnPolicy(au.User.Roles()); enforced {
+		au.MFAStatus[authByWebAuthn] = authStatusUnconfigured
+	} else {
+		au.MFAStatus[authByWebAuthn] = authStatusDisabled
+	}
+}
+
 func (au *authUser) Forget(ses *sessions.Session) {
 	delete(ses.Values, keyAuthUser)
 	delete(ses.Values, keyPermanent)
@@ -1,5 +1,9 @@
 package settings
 
+import (
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+)
+
 type (
 	Settings struct {
 		LocalEnabled              bool
@@ -28,6 +32,24 @@ type (
 				// TOTP issuer
 				Issuer string
 			}
+
+			WebAuthn struct {
+				// Can users use security keys for MFA?
+				Enabled bool
+
+				// Roles that can use security keys when not enabled for everyone
+				EnabledRoles []uint64
+
+				// Are security keys enforced?
+				Enforced bool
+
+				// Roles that are required to use security keys
+				// (security keys are enabled for them as well)
+				EnforcedRoles []uint64
+
+				// Relying party name
+				RelyingPartyName string
+			}
 		}
 	}
 
@@ -40,3 +62,15 @@ type (
 		Secret      string
 	}
 )
+
+// WebAuthnPolicy resolves if security keys can be used (enabled)
+// and if they are required (enforced) for a member of the given roles
+func (s Settings) WebAuthnPolicy(roles []uint64) (enabled, enforced bool) {
+	wa := s.MultiFactor.WebAuthn
+	return webauthn.Policy{
+		Enabled:       wa.Enabled,
+		EnabledRoles:  wa.EnabledRoles,
+		Enforced:      wa.Enforced,
+		EnforcedRoles: wa.EnforcedRoles,
+	}.Resolve(roles)
+}
@@ -24,3 +24,15 @@ func (svc tokenService) DeleteByID(ctx context.Context, ID uint64) error {
 func (svc tokenService) DeleteByUserID(ctx context.Context, userID uint64) error {
 	return svc.store.DeleteAuthOA2TokenByUserID(ctx, userID)
 }
+
+func (svc tokenService) LookupByCode(ctx context.Context, code string) (*types.AuthOa2token, error) {
+	return svc.store.LookupAuthOa2tokenByCode(ctx, code)
+}
+
+func (svc tokenService) LookupByAccess(ctx context.Context, access string) (*types.AuthOa2token, error) {
+	return svc.store.LookupAuthOa2tokenByAccess(ctx, access)
+}
+
+func (svc tokenService) LookupByRefresh(ctx context.Context, refresh string) (*types.AuthOa2token, error) {
+	return svc.store.LookupAuthOa2tokenByRefresh(ctx, refresh)
+}
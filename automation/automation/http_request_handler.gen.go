@@ -69,6 +69,9 @@ type (
 		hasForm bool
 		Form    url.Values
 
+		hasFiles bool
+		Files    expr.RVars
+
 		hasBody    bool
 		Body       interface{}
 		bodyString string
@@ -150,6 +153,10 @@ func (h httpRequestHandler) Send() *atypes.Function {
 				Name:  "form",
 				Types: []string{"KVV"},
 			},
+			{
+				Name:  "files",
+				Types: []string{"Vars"},
+			},
 			{
 				Name:  "body",
 				Types: []string{"String", "Reader", "Any"},
@@ -203,6 +210,7 @@ func (h httpRequestHandler) Send() *atypes.Function {
 					hasHeaderContentType:  in.Has("headerContentType"),
 					hasTimeout:            in.Has("timeout"),
 					hasForm:               in.Has("form"),
+					hasFiles:              in.Has("files"),
 					hasBody:               in.Has("body"),
 				}
 			)
@@ -5,10 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/version"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"path"
+	"sort"
 	"strings"
 )
 
@@ -16,6 +23,20 @@ type (
 	httpRequestHandler struct {
 		reg httpRequestHandlerRegistry
 	}
+
+	// httpResponseBody is a response body stream
+	//
+	// It implements automation/types.File so that the body can be
+	// stored as an attachment without being read into memory first
+	httpResponseBody struct {
+		io.ReadCloser
+		name  string
+		ctype string
+	}
+)
+
+var (
+	quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 )
 
 func HttpRequestHandler(reg httpRequestHandlerRegistry) *httpRequestHandler {
@@ -50,7 +71,7 @@ func (h httpRequestHandler) send(ctx context.Context, args *httpRequestSendArgs)
 	r.Headers = rsp.Header
 	r.ContentLength = rsp.ContentLength
 	r.ContentType = rsp.Header.Get("Content-Type")
-	r.Body = rsp.Body
+	r.Body = newHttpResponseBody(rsp)
 
 	return
 }
@@ -70,7 +91,19 @@ func (h httpRequestHandler) makeRequest(ctx context.Context, args *httpRequestSe
 			return nil
 		}
 
-		// @todo handle (multiple) file upload as well
+		if len(args.Files) > 0 {
+			if args.Body != nil {
+				return fmt.Errorf("can not not use files and body parameters at the same time")
+			}
+
+			var ctype string
+			if args.bodyStream, ctype, err = multipartBody(args.Form, args.Files); err != nil {
+				return err
+			}
+
+			args.HeaderContentType = ctype
+			return nil
+		}
 
 		if len(args.Form) > 0 {
 			if args.Body != nil {
@@ -134,6 +167,11 @@ func (h httpRequestHandler) makeRequest(ctx context.Context, args *httpRequestSe
 
 	req, err = http.NewRequestWithContext(ctx, args.Method, args.Url, args.bodyStream)
 	if err != nil {
+		if c, is := args.bodyStream.(io.Closer); is {
+			// stop multipart body writer
+			_ = c.Close()
+		}
+
 		return nil, err
 	}
 
@@ -165,3 +203,106 @@ func (h httpRequestHandler) makeRequest(ctx context.Context, args *httpRequestSe
 
 	return
 }
+
+// multipartBody prepares multipart/form-data body from form values and files
+//
+// Files can be (rendered) documents, attachment contents and other
+// streams or strings; body is streamed and not buffered in memory
+func multipartBody(form url.Values, files expr.RVars) (io.Reader, string, error) {
+	var (
+		pr, pw = io.Pipe()
+		mw     = multipart.NewWriter(pw)
+		fields = make([]string, 0, len(files))
+		rr     = make(map[string]io.Reader, len(files))
+	)
+
+	for field, tv := range files {
+		switch c := expr.UntypedValue(tv).(type) {
+		case io.Reader:
+			rr[field] = c
+		case string:
+			rr[field] = strings.NewReader(c)
+		case []byte:
+			rr[field] = bytes.NewReader(c)
+		default:
+			return nil, "", fmt.Errorf("unsupported file value %T for field %q", c, field)
+		}
+
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	go func() {
+		pw.CloseWithError(func() (err error) {
+			for key, vv := range form {
+				for _, v := range vv {
+					if err = mw.WriteField(key, v); err != nil {
+						return
+					}
+				}
+			}
+
+			for _, field := range fields {
+				if err = writeMultipartFile(mw, field, rr[field]); err != nil {
+					return
+				}
+			}
+
+			return mw.Close()
+		}())
+	}()
+
+	return pr, mw.FormDataContentType(), nil
+}
+
+func writeMultipartFile(mw *multipart.Writer, field string, r io.Reader) error {
+	var (
+		name, ctype = atypes.FileMeta(r, field)
+		h           = make(textproto.MIMEHeader)
+	)
+
+	if s, ok := r.(io.Seeker); ok {
+		// make sure content is read from the start
+		if _, err := s.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	h.Set("Content-Disposition", fmt.Sprintf(
+		`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(field),
+		quoteEscaper.Replace(name),
+	))
+	h.Set("Content-Type", ctype)
+
+	w, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func newHttpResponseBody(rsp *http.Response) io.Reader {
+	b := &httpResponseBody{
+		ReadCloser: rsp.Body,
+		ctype:      rsp.Header.Get("Content-Type"),
+	}
+
+	if _, pp, err := mime.ParseMediaType(rsp.Header.Get("Content-Disposition")); err == nil && pp["filename"] != "" {
+		b.name = pp["filename"]
+	} else if rsp.Request != nil && rsp.Request.URL != nil {
+		b.name = path.Base(rsp.Request.URL.Path)
+	}
+
+	if b.name == "/" || b.name == "." {
+		b.name = ""
+	}
+
+	return b
+}
+
+func (b httpResponseBody) FileName() string    { return b.name }
+func (b httpResponseBody) ContentType() string { return b.ctype }
@@ -2,13 +2,27 @@ package automation
 
 import (
 	"context"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 )
 
+type (
+	testFile struct {
+		*strings.Reader
+		name, ctype string
+	}
+)
+
+func (f testFile) FileName() string    { return f.name }
+func (f testFile) ContentType() string { return f.ctype }
+
 func TestHttpRequestMaker(t *testing.T) {
 	validateBody := func(r *require.Assertions, req *http.Request, expected string) {
 		reader, err := req.GetBody()
@@ -52,4 +66,67 @@ func TestHttpRequestMaker(t *testing.T) {
 		r.Equal("POST", req.Method)
 		validateBody(r, req, "a=a&b=b&b=b&i=42")
 	})
+	t.Run("post multipart", func(t *testing.T) {
+		var (
+			r  = require.New(t)
+			in = &httpRequestSendArgs{
+				Form: url.Values{"a": {"a"}},
+				Files: expr.RVars{
+					"doc":  expr.Must(expr.NewReader(testFile{strings.NewReader("%PDF"), "invoice.pdf", "application/pdf"})),
+					"note": expr.Must(expr.NewString("plain")),
+				},
+			}
+			req, err = httpRequestHandler{}.makeRequest(context.Background(), in)
+		)
+
+		r.NoError(err)
+		r.Equal("POST", req.Method)
+
+		mt, pp, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		r.NoError(err)
+		r.Equal("multipart/form-data", mt)
+
+		form, err := multipart.NewReader(req.Body, pp["boundary"]).ReadForm(1024)
+		r.NoError(err)
+		r.Equal([]string{"a"}, form.Value["a"])
+
+		r.Len(form.File["doc"], 1)
+		r.Equal("invoice.pdf", form.File["doc"][0].Filename)
+		r.Equal("application/pdf", form.File["doc"][0].Header.Get("Content-Type"))
+
+		r.Len(form.File["note"], 1)
+		r.Equal("note", form.File["note"][0].Filename)
+		r.Equal("application/octet-stream", form.File["note"][0].Header.Get("Content-Type"))
+	})
+
+	t.Run("files and body", func(t *testing.T) {
+		_, err := httpRequestHandler{}.makeRequest(context.Background(), &httpRequestSendArgs{
+			Files:      expr.RVars{"f": expr.Must(expr.NewString("x"))},
+			Body:       "body",
+			bodyString: "body",
+		})
+
+		require.EqualError(t, err, "can not not use files and body parameters at the same time")
+	})
+}
+
+func TestHttpResponseBody(t *testing.T) {
+	var (
+		r   = require.New(t)
+		rsp = &http.Response{
+			Header: http.Header{
+				"Content-Type":        {"application/pdf"},
+				"Content-Disposition": {`attachment; filename="report.pdf"`},
+			},
+			Body:    ioutil.NopCloser(strings.NewReader("%PDF")),
+			Request: &http.Request{URL: &url.URL{Path: "/download/123"}},
+		}
+	)
+
+	body := newHttpResponseBody(rsp).(*httpResponseBody)
+	r.Equal("report.pdf", body.FileName())
+	r.Equal("application/pdf", body.ContentType())
+
+	rsp.Header.Del("Content-Disposition")
+	r.Equal("123", newHttpResponseBody(rsp).(*httpResponseBody).FileName())
 }
@@ -23,6 +23,7 @@ type (
 		Read(context.Context, *request.SessionRead) (interface{}, error)
 		Trace(context.Context, *request.SessionTrace) (interface{}, error)
 		Delete(context.Context, *request.SessionDelete) (interface{}, error)
+		Cancel(context.Context, *request.SessionCancel) (interface{}, error)
 		ListPrompts(context.Context, *request.SessionListPrompts) (interface{}, error)
 		ResumeState(context.Context, *request.SessionResumeState) (interface{}, error)
 		DeleteState(context.Context, *request.SessionDeleteState) (interface{}, error)
@@ -34,6 +35,7 @@ type (
 		Read        func(http.ResponseWriter, *http.Request)
 		Trace       func(http.ResponseWriter, *http.Request)
 		Delete      func(http.ResponseWriter, *http.Request)
+		Cancel      func(http.ResponseWriter, *http.Request)
 		ListPrompts func(http.ResponseWriter, *http.Request)
 		ResumeState func(http.ResponseWriter, *http.Request)
 		DeleteState func(http.ResponseWriter, *http.Request)
@@ -106,6 +108,22 @@ func NewSession(h SessionAPI) *Session {
 
 			api.Send(w, r, value)
 		},
+		Cancel: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewSessionCancel()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Cancel(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		ListPrompts: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewSessionListPrompts()
@@ -164,6 +182,7 @@ func (h Session) MountRoutes(r chi.Router, middlewares ...func(http.Handler) htt
 		r.Get("/sessions/{sessionID}", h.Read)
 		r.Get("/sessions/{sessionID}/trace", h.Trace)
 		r.Delete("/sessions/{sessionID}", h.Delete)
+		r.Post("/sessions/{sessionID}/cancel", h.Cancel)
 		r.Get("/sessions/prompts", h.ListPrompts)
 		r.Post("/sessions/{sessionID}/state/{stateID}", h.ResumeState)
 		r.Delete("/sessions/{sessionID}/state/{stateID}", h.DeleteState)
@@ -25,16 +25,18 @@ type (
 		Read(context.Context, *request.TriggerRead) (interface{}, error)
 		Delete(context.Context, *request.TriggerDelete) (interface{}, error)
 		Undelete(context.Context, *request.TriggerUndelete) (interface{}, error)
+		RegenerateWebhookSecret(context.Context, *request.TriggerRegenerateWebhookSecret) (interface{}, error)
 	}
 
 	// HTTP API interface
 	Trigger struct {
-		List     func(http.ResponseWriter, *http.Request)
-		Create   func(http.ResponseWriter, *http.Request)
-		Update   func(http.ResponseWriter, *http.Request)
-		Read     func(http.ResponseWriter, *http.Request)
-		Delete   func(http.ResponseWriter, *http.Request)
-		Undelete func(http.ResponseWriter, *http.Request)
+		List                    func(http.ResponseWriter, *http.Request)
+		Create                  func(http.ResponseWriter, *http.Request)
+		Update                  func(http.ResponseWriter, *http.Request)
+		Read                    func(http.ResponseWriter, *http.Request)
+		Delete                  func(http.ResponseWriter, *http.Request)
+		Undelete                func(http.ResponseWriter, *http.Request)
+		RegenerateWebhookSecret func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -134,6 +136,22 @@ func NewTrigger(h TriggerAPI) *Trigger {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		RegenerateWebhookSecret: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewTriggerRegenerateWebhookSecret()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RegenerateWebhookSecret(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -148,5 +166,6 @@ func (h Trigger) MountRoutes(r chi.Router, middlewares ...func(http.Handler) htt
 		r.Get("/triggers/{triggerID}", h.Read)
 		r.Delete("/triggers/{triggerID}", h.Delete)
 		r.Post("/triggers/{triggerID}/undelete", h.Undelete)
+		r.Post("/triggers/{triggerID}/webhook/secret", h.RegenerateWebhookSecret)
 	})
 }
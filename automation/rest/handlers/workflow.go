@@ -26,17 +26,29 @@ type (
 		Delete(context.Context, *request.WorkflowDelete) (interface{}, error)
 		Undelete(context.Context, *request.WorkflowUndelete) (interface{}, error)
 		Test(context.Context, *request.WorkflowTest) (interface{}, error)
+		Publish(context.Context, *request.WorkflowPublish) (interface{}, error)
+		Revisions(context.Context, *request.WorkflowRevisions) (interface{}, error)
+		RevisionRead(context.Context, *request.WorkflowRevisionRead) (interface{}, error)
+		RevisionDiff(context.Context, *request.WorkflowRevisionDiff) (interface{}, error)
+		Rollback(context.Context, *request.WorkflowRollback) (interface{}, error)
+		Stats(context.Context, *request.WorkflowStats) (interface{}, error)
 	}
 
 	// HTTP API interface
 	Workflow struct {
-		List     func(http.ResponseWriter, *http.Request)
-		Create   func(http.ResponseWriter, *http.Request)
-		Update   func(http.ResponseWriter, *http.Request)
-		Read     func(http.ResponseWriter, *http.Request)
-		Delete   func(http.ResponseWriter, *http.Request)
-		Undelete func(http.ResponseWriter, *http.Request)
-		Test     func(http.ResponseWriter, *http.Request)
+		List         func(http.ResponseWriter, *http.Request)
+		Create       func(http.ResponseWriter, *http.Request)
+		Update       func(http.ResponseWriter, *http.Request)
+		Read         func(http.ResponseWriter, *http.Request)
+		Delete       func(http.ResponseWriter, *http.Request)
+		Undelete     func(http.ResponseWriter, *http.Request)
+		Test         func(http.ResponseWriter, *http.Request)
+		Publish      func(http.ResponseWriter, *http.Request)
+		Revisions    func(http.ResponseWriter, *http.Request)
+		RevisionRead func(http.ResponseWriter, *http.Request)
+		RevisionDiff func(http.ResponseWriter, *http.Request)
+		Rollback     func(http.ResponseWriter, *http.Request)
+		Stats        func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -152,6 +164,102 @@ func NewWorkflow(h WorkflowAPI) *Workflow {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		Publish: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewWorkflowPublish()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Publish(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Revisions: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewWorkflowRevisions()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Revisions(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		RevisionRead: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewWorkflowRevisionRead()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RevisionRead(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		RevisionDiff: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewWorkflowRevisionDiff()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RevisionDiff(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Rollback: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewWorkflowRollback()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Rollback(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Stats: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewWorkflowStats()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Stats(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -167,5 +275,11 @@ func (h Workflow) MountRoutes(r chi.Router, middlewares ...func(http.Handler) ht
 		r.Delete("/workflows/{workflowID}", h.Delete)
 		r.Post("/workflows/{workflowID}/undelete", h.Undelete)
 		r.Post("/workflows/{workflowID}/test", h.Test)
+		r.Post("/workflows/{workflowID}/publish", h.Publish)
+		r.Get("/workflows/{workflowID}/revisions", h.Revisions)
+		r.Get("/workflows/{workflowID}/revisions/{revision}", h.RevisionRead)
+		r.Get("/workflows/{workflowID}/revisions/{revision}/diff", h.RevisionDiff)
+		r.Post("/workflows/{workflowID}/revisions/{revision}/rollback", h.Rollback)
+		r.Get("/workflows/{workflowID}/stats", h.Stats)
 	})
 }
@@ -103,6 +103,13 @@ type (
 		SessionID uint64 `json:",string"`
 	}
 
+	SessionCancel struct {
+		// SessionID PATH parameter
+		//
+		// Session ID
+		SessionID uint64 `json:",string"`
+	}
+
 	SessionListPrompts struct {
 	}
 
@@ -387,6 +394,41 @@ func (r *SessionDelete) Fill(req *http.Request) (err error) {
 	return err
 }
 
+// NewSessionCancel request
+func NewSessionCancel() *SessionCancel {
+	return &SessionCancel{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SessionCancel) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"sessionID": r.SessionID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SessionCancel) GetSessionID() uint64 {
+	return r.SessionID
+}
+
+// Fill processes request and fills internal variables
+func (r *SessionCancel) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "sessionID")
+		r.SessionID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
 // NewSessionListPrompts request
 func NewSessionListPrompts() *SessionListPrompts {
 	return &SessionListPrompts{}
@@ -139,6 +139,11 @@ type (
 		// Workflow steps definition
 		Constraints types.TriggerConstraintSet
 
+		// Webhook POST parameter
+		//
+		// Webhook configuration
+		Webhook *types.TriggerWebhook
+
 		// OwnedBy POST parameter
 		//
 		// Owner of the trigger
@@ -196,6 +201,11 @@ type (
 		// Workflow steps definition
 		Constraints types.TriggerConstraintSet
 
+		// Webhook POST parameter
+		//
+		// Webhook configuration
+		Webhook *types.TriggerWebhook
+
 		// OwnedBy POST parameter
 		//
 		// Owner of the trigger
@@ -222,6 +232,13 @@ type (
 		// Trigger ID
 		TriggerID uint64 `json:",string"`
 	}
+
+	TriggerRegenerateWebhookSecret struct {
+		// TriggerID PATH parameter
+		//
+		// Trigger ID
+		TriggerID uint64 `json:",string"`
+	}
 )
 
 // NewTriggerList request
@@ -411,6 +428,7 @@ func (r TriggerCreate) Auditable() map[string]interface{} {
 		"labels":         r.Labels,
 		"meta":           r.Meta,
 		"constraints":    r.Constraints,
+		"webhook":        r.Webhook,
 		"ownedBy":        r.OwnedBy,
 	}
 }
@@ -460,6 +478,11 @@ func (r TriggerCreate) GetConstraints() types.TriggerConstraintSet {
 	return r.Constraints
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r TriggerCreate) GetWebhook() *types.TriggerWebhook {
+	return r.Webhook
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r TriggerCreate) GetOwnedBy() uint64 {
 	return r.OwnedBy
@@ -569,6 +592,18 @@ func (r *TriggerCreate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["webhook[]"]; ok {
+			r.Webhook, err = types.ParseTriggerWebhook(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["webhook"]; ok {
+			r.Webhook, err = types.ParseTriggerWebhook(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["ownedBy"]; ok && len(val) > 0 {
 			r.OwnedBy, err = payload.ParseUint64(val[0]), nil
 			if err != nil {
@@ -598,6 +633,7 @@ func (r TriggerUpdate) Auditable() map[string]interface{} {
 		"labels":         r.Labels,
 		"meta":           r.Meta,
 		"constraints":    r.Constraints,
+		"webhook":        r.Webhook,
 		"ownedBy":        r.OwnedBy,
 	}
 }
@@ -652,6 +688,11 @@ func (r TriggerUpdate) GetConstraints() types.TriggerConstraintSet {
 	return r.Constraints
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r TriggerUpdate) GetWebhook() *types.TriggerWebhook {
+	return r.Webhook
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r TriggerUpdate) GetOwnedBy() uint64 {
 	return r.OwnedBy
@@ -761,6 +802,18 @@ func (r *TriggerUpdate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["webhook[]"]; ok {
+			r.Webhook, err = types.ParseTriggerWebhook(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["webhook"]; ok {
+			r.Webhook, err = types.ParseTriggerWebhook(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["ownedBy"]; ok && len(val) > 0 {
 			r.OwnedBy, err = payload.ParseUint64(val[0]), nil
 			if err != nil {
@@ -888,3 +941,38 @@ func (r *TriggerUndelete) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewTriggerRegenerateWebhookSecret request
+func NewTriggerRegenerateWebhookSecret() *TriggerRegenerateWebhookSecret {
+	return &TriggerRegenerateWebhookSecret{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r TriggerRegenerateWebhookSecret) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"triggerID": r.TriggerID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r TriggerRegenerateWebhookSecret) GetTriggerID() uint64 {
+	return r.TriggerID
+}
+
+// Fill processes request and fills internal variables
+func (r *TriggerRegenerateWebhookSecret) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "triggerID")
+		r.TriggerID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // dummy vars to prevent
@@ -109,6 +110,11 @@ type (
 		// Keep old workflow sessions
 		KeepSessions int
 
+		// Timeout POST parameter
+		//
+		// Max duration of workflow session (in seconds)
+		Timeout int
+
 		// Scope POST parameter
 		//
 		// Workflow meta data
@@ -171,6 +177,11 @@ type (
 		// Keep old workflow sessions
 		KeepSessions int
 
+		// Timeout POST parameter
+		//
+		// Max duration of workflow session (in seconds)
+		Timeout int
+
 		// Scope POST parameter
 		//
 		// Workflow meta data
@@ -226,14 +237,116 @@ type (
 
 		// Scope POST parameter
 		//
-		// Workflow meta data
+		// Input scope
 		Scope *expr.Vars
 
+		// StepID POST parameter
+		//
+		// Starting step; orphan step is used when not set
+		StepID uint64 `json:",string"`
+
+		// Revision POST parameter
+		//
+		// Revision to test; current definition when not set
+		Revision uint
+
+		// Mocks POST parameter
+		//
+		// Mocked results of function, iterator and subprocess steps
+		Mocks types.WorkflowStepMockSet
+
 		// RunAs POST parameter
 		//
-		// Is workflow enabled
+		// Run with workflow's run-as identity
 		RunAs bool
 	}
+
+	WorkflowPublish struct {
+		// WorkflowID PATH parameter
+		//
+		// Workflow ID
+		WorkflowID uint64 `json:",string"`
+	}
+
+	WorkflowRevisions struct {
+		// WorkflowID PATH parameter
+		//
+		// Workflow ID
+		WorkflowID uint64 `json:",string"`
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	WorkflowRevisionRead struct {
+		// WorkflowID PATH parameter
+		//
+		// Workflow ID
+		WorkflowID uint64 `json:",string"`
+
+		// Revision PATH parameter
+		//
+		// Revision
+		Revision uint
+	}
+
+	WorkflowRevisionDiff struct {
+		// WorkflowID PATH parameter
+		//
+		// Workflow ID
+		WorkflowID uint64 `json:",string"`
+
+		// Revision PATH parameter
+		//
+		// Revision
+		Revision uint
+
+		// CompareTo GET parameter
+		//
+		// Revision to compare to (defaults to current workflow definition)
+		CompareTo uint
+	}
+
+	WorkflowRollback struct {
+		// WorkflowID PATH parameter
+		//
+		// Workflow ID
+		WorkflowID uint64 `json:",string"`
+
+		// Revision PATH parameter
+		//
+		// Revision
+		Revision uint
+	}
+
+	WorkflowStats struct {
+		// WorkflowID PATH parameter
+		//
+		// Workflow ID
+		WorkflowID uint64 `json:",string"`
+
+		// From GET parameter
+		//
+		// Start of the time window (defaults to the start of the stats retention period)
+		From *time.Time
+
+		// To GET parameter
+		//
+		// End of the time window (defaults to now)
+		To *time.Time
+	}
 )
 
 // NewWorkflowList request
@@ -379,6 +492,7 @@ func (r WorkflowCreate) Auditable() map[string]interface{} {
 		"enabled":      r.Enabled,
 		"trace":        r.Trace,
 		"keepSessions": r.KeepSessions,
+		"timeout":      r.Timeout,
 		"scope":        r.Scope,
 		"steps":        r.Steps,
 		"paths":        r.Paths,
@@ -417,6 +531,11 @@ func (r WorkflowCreate) GetKeepSessions() int {
 	return r.KeepSessions
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowCreate) GetTimeout() int {
+	return r.Timeout
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r WorkflowCreate) GetScope() *expr.Vars {
 	return r.Scope
@@ -515,6 +634,13 @@ func (r *WorkflowCreate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["timeout"]; ok && len(val) > 0 {
+			r.Timeout, err = payload.ParseInt(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["scope[]"]; ok {
 			r.Scope, err = types.ParseWorkflowVariables(val)
 			if err != nil {
@@ -584,6 +710,7 @@ func (r WorkflowUpdate) Auditable() map[string]interface{} {
 		"enabled":      r.Enabled,
 		"trace":        r.Trace,
 		"keepSessions": r.KeepSessions,
+		"timeout":      r.Timeout,
 		"scope":        r.Scope,
 		"steps":        r.Steps,
 		"paths":        r.Paths,
@@ -627,6 +754,11 @@ func (r WorkflowUpdate) GetKeepSessions() int {
 	return r.KeepSessions
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowUpdate) GetTimeout() int {
+	return r.Timeout
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r WorkflowUpdate) GetScope() *expr.Vars {
 	return r.Scope
@@ -725,6 +857,13 @@ func (r *WorkflowUpdate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["timeout"]; ok && len(val) > 0 {
+			r.Timeout, err = payload.ParseInt(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["scope[]"]; ok {
 			r.Scope, err = types.ParseWorkflowVariables(val)
 			if err != nil {
@@ -906,6 +1045,9 @@ func (r WorkflowTest) Auditable() map[string]interface{} {
 	return map[string]interface{}{
 		"workflowID": r.WorkflowID,
 		"scope":      r.Scope,
+		"stepID":     r.StepID,
+		"revision":   r.Revision,
+		"mocks":      r.Mocks,
 		"runAs":      r.RunAs,
 	}
 }
@@ -920,6 +1062,21 @@ func (r WorkflowTest) GetScope() *expr.Vars {
 	return r.Scope
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowTest) GetStepID() uint64 {
+	return r.StepID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowTest) GetRevision() uint {
+	return r.Revision
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowTest) GetMocks() types.WorkflowStepMockSet {
+	return r.Mocks
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r WorkflowTest) GetRunAs() bool {
 	return r.RunAs
@@ -958,6 +1115,32 @@ func (r *WorkflowTest) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["stepID"]; ok && len(val) > 0 {
+			r.StepID, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["revision"]; ok && len(val) > 0 {
+			r.Revision, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["mocks[]"]; ok {
+			r.Mocks, err = types.ParseWorkflowStepMockSet(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["mocks"]; ok {
+			r.Mocks, err = types.ParseWorkflowStepMockSet(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["runAs"]; ok && len(val) > 0 {
 			r.RunAs, err = payload.ParseBool(val[0]), nil
 			if err != nil {
@@ -980,3 +1163,339 @@ func (r *WorkflowTest) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewWorkflowPublish request
+func NewWorkflowPublish() *WorkflowPublish {
+	return &WorkflowPublish{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowPublish) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"workflowID": r.WorkflowID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowPublish) GetWorkflowID() uint64 {
+	return r.WorkflowID
+}
+
+// Fill processes request and fills internal variables
+func (r *WorkflowPublish) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "workflowID")
+		r.WorkflowID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWorkflowRevisions request
+func NewWorkflowRevisions() *WorkflowRevisions {
+	return &WorkflowRevisions{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisions) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"workflowID": r.WorkflowID,
+		"limit":      r.Limit,
+		"pageCursor": r.PageCursor,
+		"sort":       r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisions) GetWorkflowID() uint64 {
+	return r.WorkflowID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisions) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisions) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisions) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *WorkflowRevisions) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "workflowID")
+		r.WorkflowID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWorkflowRevisionRead request
+func NewWorkflowRevisionRead() *WorkflowRevisionRead {
+	return &WorkflowRevisionRead{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionRead) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"workflowID": r.WorkflowID,
+		"revision":   r.Revision,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionRead) GetWorkflowID() uint64 {
+	return r.WorkflowID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionRead) GetRevision() uint {
+	return r.Revision
+}
+
+// Fill processes request and fills internal variables
+func (r *WorkflowRevisionRead) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "workflowID")
+		r.WorkflowID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "revision")
+		r.Revision, err = payload.ParseUint(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWorkflowRevisionDiff request
+func NewWorkflowRevisionDiff() *WorkflowRevisionDiff {
+	return &WorkflowRevisionDiff{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionDiff) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"workflowID": r.WorkflowID,
+		"revision":   r.Revision,
+		"compareTo":  r.CompareTo,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionDiff) GetWorkflowID() uint64 {
+	return r.WorkflowID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionDiff) GetRevision() uint {
+	return r.Revision
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRevisionDiff) GetCompareTo() uint {
+	return r.CompareTo
+}
+
+// Fill processes request and fills internal variables
+func (r *WorkflowRevisionDiff) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["compareTo"]; ok && len(val) > 0 {
+			r.CompareTo, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "workflowID")
+		r.WorkflowID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "revision")
+		r.Revision, err = payload.ParseUint(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWorkflowRollback request
+func NewWorkflowRollback() *WorkflowRollback {
+	return &WorkflowRollback{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRollback) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"workflowID": r.WorkflowID,
+		"revision":   r.Revision,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRollback) GetWorkflowID() uint64 {
+	return r.WorkflowID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowRollback) GetRevision() uint {
+	return r.Revision
+}
+
+// Fill processes request and fills internal variables
+func (r *WorkflowRollback) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "workflowID")
+		r.WorkflowID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "revision")
+		r.Revision, err = payload.ParseUint(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWorkflowStats request
+func NewWorkflowStats() *WorkflowStats {
+	return &WorkflowStats{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowStats) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"workflowID": r.WorkflowID,
+		"from":       r.From,
+		"to":         r.To,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowStats) GetWorkflowID() uint64 {
+	return r.WorkflowID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowStats) GetFrom() *time.Time {
+	return r.From
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WorkflowStats) GetTo() *time.Time {
+	return r.To
+}
+
+// Fill processes request and fills internal variables
+func (r *WorkflowStats) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["from"]; ok && len(val) > 0 {
+			r.From, err = payload.ParseISODatePtrWithErr(val[0])
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["to"]; ok && len(val) > 0 {
+			r.To, err = payload.ParseISODatePtrWithErr(val[0])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "workflowID")
+		r.WorkflowID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
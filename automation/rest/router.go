@@ -4,10 +4,19 @@ import (
 	"github.com/go-chi/chi"
 
 	"github.com/cortezaproject/corteza-server/automation/rest/handlers"
+	"github.com/cortezaproject/corteza-server/automation/service"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 )
 
 func MountRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		// A special case that, we do not add this through standard request, handlers & controllers
+		// combo but directly -- we need access to r.Body and control over the response
+		r.Handle(service.WebhookBaseURL+"/{triggerID}", &Webhook{
+			svc: service.DefaultWebhook,
+		})
+	})
+
 	// Protect all _private_ routes
 	r.Group(func(r chi.Router) {
 		r.Use(auth.MiddlewareValidOnly)
@@ -23,6 +23,7 @@ type (
 		Search(ctx context.Context, filter types.SessionFilter) (types.SessionSet, types.SessionFilter, error)
 		LookupByID(ctx context.Context, sessionID uint64) (*types.Session, error)
 		Resume(sessionID, stateID uint64, i auth.Identifiable, input *expr.Vars) error
+		Cancel(ctx context.Context, sessionID uint64) error
 		PendingPrompts(context.Context) []*wfexec.PendingPrompt
 	}
 
@@ -71,6 +72,10 @@ func (ctrl Session) Delete(ctx context.Context, r *request.SessionDelete) (inter
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (ctrl Session) Cancel(ctx context.Context, r *request.SessionCancel) (interface{}, error) {
+	return api.OK(), ctrl.svc.Cancel(ctx, r.SessionID)
+}
+
 func (ctrl Session) Trace(ctx context.Context, trace *request.SessionTrace) (interface{}, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -19,6 +19,7 @@ type (
 			Update(ctx context.Context, upd *types.Trigger) (*types.Trigger, error)
 			DeleteByID(ctx context.Context, triggerID uint64) error
 			UndeleteByID(ctx context.Context, triggerID uint64) error
+			RegenerateWebhookSecret(ctx context.Context, triggerID uint64) (string, error)
 		}
 	}
 
@@ -72,6 +73,7 @@ func (ctrl Trigger) Create(ctx context.Context, r *request.TriggerCreate) (inter
 		Labels:       r.Labels,
 		OwnedBy:      r.OwnedBy,
 		Meta:         r.Meta,
+		Webhook:      r.Webhook,
 	}
 
 	return ctrl.svc.Create(ctx, trigger)
@@ -90,6 +92,7 @@ func (ctrl Trigger) Update(ctx context.Context, r *request.TriggerUpdate) (inter
 		Labels:       r.Labels,
 		OwnedBy:      r.OwnedBy,
 		Meta:         r.Meta,
+		Webhook:      r.Webhook,
 	}
 
 	return ctrl.svc.Update(ctx, trigger)
@@ -107,6 +110,10 @@ func (ctrl Trigger) Undelete(ctx context.Context, r *request.TriggerUndelete) (i
 	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.TriggerID)
 }
 
+func (ctrl Trigger) RegenerateWebhookSecret(ctx context.Context, r *request.TriggerRegenerateWebhookSecret) (interface{}, error) {
+	return ctrl.svc.RegenerateWebhookSecret(ctx, r.TriggerID)
+}
+
 func (ctrl Trigger) makeFilterPayload(ctx context.Context, uu types.TriggerSet, f types.TriggerFilter, err error) (*triggerSetPayload, error) {
 	if err != nil {
 		return nil, err
@@ -0,0 +1,26 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+type (
+	Webhook struct {
+		svc interface {
+			ProcessRequest(w http.ResponseWriter, r *http.Request, triggerID uint64)
+		}
+	}
+)
+
+func (ctrl *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	triggerID, err := strconv.ParseUint(chi.URLParam(r, "triggerID"), 10, 64)
+	if err != nil || triggerID == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctrl.svc.ProcessRequest(w, r, triggerID)
+}
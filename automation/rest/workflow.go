@@ -2,7 +2,6 @@ package rest
 
 import (
 	"context"
-	"fmt"
 	"github.com/cortezaproject/corteza-server/automation/rest/request"
 	"github.com/cortezaproject/corteza-server/automation/service"
 	"github.com/cortezaproject/corteza-server/automation/types"
@@ -20,6 +19,16 @@ type (
 			Update(ctx context.Context, upd *types.Workflow) (*types.Workflow, error)
 			DeleteByID(ctx context.Context, workflowID uint64) error
 			UndeleteByID(ctx context.Context, workflowID uint64) error
+
+			Publish(ctx context.Context, workflowID uint64) (*types.Workflow, error)
+			Revisions(ctx context.Context, workflowID uint64, f types.WorkflowRevisionFilter) (types.WorkflowRevisionSet, types.WorkflowRevisionFilter, error)
+			LookupRevision(ctx context.Context, workflowID uint64, revision uint) (*types.WorkflowRevision, error)
+			RevisionDiff(ctx context.Context, workflowID uint64, revision, compareTo uint) (*types.WorkflowRevisionDiff, error)
+			Rollback(ctx context.Context, workflowID uint64, revision uint) (*types.Workflow, error)
+
+			DryRun(ctx context.Context, workflowID uint64, p types.WorkflowDryRunParams) (*types.WorkflowDryRunResult, error)
+
+			Stats(ctx context.Context, workflowID uint64, f types.WorkflowStatsFilter) (*types.WorkflowStats, error)
 		}
 	}
 
@@ -27,6 +36,11 @@ type (
 		Filter types.WorkflowFilter `json:"filter"`
 		Set    types.WorkflowSet    `json:"set"`
 	}
+
+	workflowRevisionSetPayload struct {
+		Filter types.WorkflowRevisionFilter `json:"filter"`
+		Set    types.WorkflowRevisionSet    `json:"set"`
+	}
 )
 
 func (Workflow) New() *Workflow {
@@ -67,6 +81,7 @@ func (ctrl Workflow) Create(ctx context.Context, r *request.WorkflowCreate) (int
 		Enabled:      r.Enabled,
 		Trace:        r.Trace,
 		KeepSessions: r.KeepSessions,
+		Timeout:      r.Timeout,
 		Scope:        r.Scope,
 		Steps:        r.Steps,
 		Paths:        r.Paths,
@@ -86,6 +101,7 @@ func (ctrl Workflow) Update(ctx context.Context, r *request.WorkflowUpdate) (int
 		Enabled:      r.Enabled,
 		Trace:        r.Trace,
 		KeepSessions: r.KeepSessions,
+		Timeout:      r.Timeout,
 		Scope:        r.Scope,
 		Steps:        r.Steps,
 		Paths:        r.Paths,
@@ -101,7 +117,13 @@ func (ctrl Workflow) Read(ctx context.Context, r *request.WorkflowRead) (interfa
 }
 
 func (ctrl Workflow) Test(ctx context.Context, r *request.WorkflowTest) (interface{}, error) {
-	return nil, fmt.Errorf("not implemented")
+	return ctrl.svc.DryRun(ctx, r.WorkflowID, types.WorkflowDryRunParams{
+		Input:    r.Scope,
+		StepID:   r.StepID,
+		Revision: r.Revision,
+		RunAs:    r.RunAs,
+		Mocks:    r.Mocks,
+	})
 }
 
 func (ctrl Workflow) Delete(ctx context.Context, r *request.WorkflowDelete) (interface{}, error) {
@@ -112,6 +134,55 @@ func (ctrl Workflow) Undelete(ctx context.Context, r *request.WorkflowUndelete)
 	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.WorkflowID)
 }
 
+func (ctrl Workflow) Publish(ctx context.Context, r *request.WorkflowPublish) (interface{}, error) {
+	return ctrl.svc.Publish(ctx, r.WorkflowID)
+}
+
+func (ctrl Workflow) Revisions(ctx context.Context, r *request.WorkflowRevisions) (interface{}, error) {
+	var (
+		err error
+		set types.WorkflowRevisionSet
+		f   = types.WorkflowRevisionFilter{}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	if set, f, err = ctrl.svc.Revisions(ctx, r.WorkflowID, f); err != nil {
+		return nil, err
+	}
+
+	if len(set) == 0 {
+		set = make([]*types.WorkflowRevision, 0)
+	}
+
+	return &workflowRevisionSetPayload{Filter: f, Set: set}, nil
+}
+
+func (ctrl Workflow) RevisionRead(ctx context.Context, r *request.WorkflowRevisionRead) (interface{}, error) {
+	return ctrl.svc.LookupRevision(ctx, r.WorkflowID, r.Revision)
+}
+
+func (ctrl Workflow) RevisionDiff(ctx context.Context, r *request.WorkflowRevisionDiff) (interface{}, error) {
+	return ctrl.svc.RevisionDiff(ctx, r.WorkflowID, r.Revision, r.CompareTo)
+}
+
+func (ctrl Workflow) Rollback(ctx context.Context, r *request.WorkflowRollback) (interface{}, error) {
+	return ctrl.svc.Rollback(ctx, r.WorkflowID, r.Revision)
+}
+
+func (ctrl Workflow) Stats(ctx context.Context, r *request.WorkflowStats) (interface{}, error) {
+	return ctrl.svc.Stats(ctx, r.WorkflowID, types.WorkflowStatsFilter{
+		From: r.From,
+		To:   r.To,
+	})
+}
+
 func (ctrl Workflow) makeFilterPayload(ctx context.Context, uu types.WorkflowSet, f types.WorkflowFilter, err error) (*workflowSetPayload, error) {
 	if err != nil {
 		return nil, err
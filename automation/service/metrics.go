@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/slice"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	// metrics collects workflow and step execution counters and durations
+	//
+	// Values are exported as prometheus metrics (per node, labeled by workflow)
+	// and aggregated into stats buckets per workflow and interval.
+	//
+	// Buckets are stored (flushed) periodically so that stats are aggregated
+	// from all nodes and kept after restart for the configured retention period;
+	// buckets that are not stored yet are kept in memory.
+	metrics struct {
+		mux        *sync.Mutex
+		resolution time.Duration
+		retention  time.Duration
+
+		// buckets, ordered by time, for each workflow (key, uint64)
+		buckets map[uint64][]*metricsBucket
+
+		executions *prometheus.CounterVec
+		failures   *prometheus.CounterVec
+		suspended  *prometheus.CounterVec
+		duration   *prometheus.HistogramVec
+
+		stepExecutions *prometheus.CounterVec
+		stepFailures   *prometheus.CounterVec
+		stepSuspended  *prometheus.CounterVec
+		stepDuration   *prometheus.HistogramVec
+	}
+
+	metricsBucket struct {
+		types.WorkflowStatsBucket
+
+		// changed since it was last stored
+		dirty bool
+	}
+)
+
+const (
+	metricsResolution = time.Minute
+
+	// How often are changed stats buckets stored
+	metricsFlushInterval = time.Minute
+)
+
+var (
+	metricsDurationBuckets = []float64{.005, .01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900, 3600}
+)
+
+func Metrics(retention time.Duration) *metrics {
+	var (
+		counter = func(name, help string, ll ...string) *prometheus.CounterVec {
+			return prometheus.NewCounterVec(
+				prometheus.CounterOpts{Namespace: "corteza", Subsystem: "automation", Name: name, Help: help},
+				ll,
+			)
+		}
+
+		histogram = func(name, help string, ll ...string) *prometheus.HistogramVec {
+			return prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{Namespace: "corteza", Subsystem: "automation", Name: name, Help: help, Buckets: metricsDurationBuckets},
+				ll,
+			)
+		}
+	)
+
+	return &metrics{
+		mux:        &sync.Mutex{},
+		resolution: metricsResolution,
+		retention:  retention,
+		buckets:    make(map[uint64][]*metricsBucket),
+
+		executions: counter("workflow_executions_total", "Number of started workflow sessions", "workflowID"),
+		failures:   counter("workflow_failures_total", "Number of failed workflow sessions", "workflowID"),
+		suspended:  counter("workflow_suspended_total", "Number of workflow sessions that were suspended (delayed or prompted)", "workflowID"),
+		duration:   histogram("workflow_duration_seconds", "Duration of completed and failed workflow sessions", "workflowID"),
+
+		// Steps are not labeled to keep the number of series low;
+		// stats of each step are available through workflow stats
+		stepExecutions: counter("step_executions_total", "Number of workflow step executions", "workflowID"),
+		stepFailures:   counter("step_failures_total", "Number of failed workflow step executions", "workflowID"),
+		stepSuspended:  counter("step_suspended_total", "Number of workflow step executions that suspended the session", "workflowID"),
+		stepDuration:   histogram("step_duration_seconds", "Lead time of workflow step executions", "workflowID"),
+	}
+}
+
+// register registers all prometheus collectors
+func (m *metrics) register(r prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{
+		m.executions, m.failures, m.suspended, m.duration,
+		m.stepExecutions, m.stepFailures, m.stepSuspended, m.stepDuration,
+	} {
+		if err := r.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// forget removes prometheus metrics of the (deleted) workflow
+func (m *metrics) forget(workflowID uint64) {
+	var (
+		wl = metricsLabel(workflowID)
+	)
+
+	for _, c := range []*prometheus.CounterVec{m.executions, m.failures, m.suspended, m.stepExecutions, m.stepFailures, m.stepSuspended} {
+		c.DeleteLabelValues(wl)
+	}
+
+	for _, h := range []*prometheus.HistogramVec{m.duration, m.stepDuration} {
+		h.DeleteLabelValues(wl)
+	}
+}
+
+func (m *metrics) sessionStarted(workflowID uint64) {
+	m.executions.WithLabelValues(metricsLabel(workflowID)).Inc()
+	m.record(workflowID, 0, func(s *types.ExecutionStats) { s.Executions++ })
+}
+
+func (m *metrics) sessionSuspended(workflowID uint64) {
+	m.suspended.WithLabelValues(metricsLabel(workflowID)).Inc()
+	m.record(workflowID, 0, func(s *types.ExecutionStats) { s.Suspended++ })
+}
+
+func (m *metrics) sessionFinished(workflowID uint64, failed bool, d time.Duration) {
+	var (
+		wl = metricsLabel(workflowID)
+	)
+
+	if failed {
+		m.failures.WithLabelValues(wl).Inc()
+	}
+
+	m.duration.WithLabelValues(wl).Observe(d.Seconds())
+	m.record(workflowID, 0, func(s *types.ExecutionStats) {
+		if failed {
+			s.Failures++
+		}
+
+		s.Observe(d)
+	})
+}
+
+func (m *metrics) stepExecuted(workflowID, stepID uint64, failed, suspended bool, d time.Duration) {
+	var (
+		wl = metricsLabel(workflowID)
+	)
+
+	m.stepExecutions.WithLabelValues(wl).Inc()
+	if failed {
+		m.stepFailures.WithLabelValues(wl).Inc()
+	}
+
+	if suspended {
+		m.stepSuspended.WithLabelValues(wl).Inc()
+	}
+
+	m.stepDuration.WithLabelValues(wl).Observe(d.Seconds())
+	m.record(workflowID, stepID, func(s *types.ExecutionStats) {
+		s.Executions++
+		if failed {
+			s.Failures++
+		}
+
+		if suspended {
+			s.Suspended++
+		}
+
+		s.Observe(d)
+	})
+}
+
+// record updates workflow (stepID = 0) or step stats in the current bucket
+func (m *metrics) record(workflowID, stepID uint64, fn func(*types.ExecutionStats)) {
+	var (
+		at = now().Truncate(m.resolution)
+	)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	bb := m.buckets[workflowID]
+	if l := len(bb); l == 0 || bb[l-1].StartAt.Before(at) {
+		bb = append(m.prune(bb, at), &metricsBucket{
+			WorkflowStatsBucket: types.WorkflowStatsBucket{
+				ID:         nextID(),
+				WorkflowID: workflowID,
+				StartAt:    at,
+				Steps:      make(types.WorkflowStatsBucketSteps),
+			},
+		})
+
+		m.buckets[workflowID] = bb
+	}
+
+	b := bb[len(bb)-1]
+	b.dirty = true
+
+	if stepID == 0 {
+		fn(&b.Stats)
+		return
+	}
+
+	if b.Steps[stepID] == nil {
+		b.Steps[stepID] = &types.ExecutionStats{}
+	}
+
+	fn(b.Steps[stepID])
+}
+
+// prune removes buckets that ended before the retention period
+func (m *metrics) prune(bb []*metricsBucket, at time.Time) []*metricsBucket {
+	var (
+		i      int
+		cutoff = at.Add(-m.retention)
+	)
+
+	for i < len(bb) && !bb[i].StartAt.Add(m.resolution).After(cutoff) {
+		i++
+	}
+
+	return bb[i:]
+}
+
+// flush stores buckets that changed since they were last stored
+//
+// Stored buckets are removed from memory, except the
+// current bucket of each workflow that can still change
+func (m *metrics) flush(ctx context.Context, s store.Storer) error {
+	var (
+		bb types.WorkflowStatsBucketSet
+	)
+
+	m.mux.Lock()
+	for _, mbb := range m.buckets {
+		for _, b := range mbb {
+			if b.dirty {
+				bb = append(bb, b.copy())
+				b.dirty = false
+			}
+		}
+	}
+	m.mux.Unlock()
+
+	if len(bb) == 0 {
+		return nil
+	}
+
+	err := store.UpsertAutomationWorkflowStatsBucket(ctx, s, bb...)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if err != nil {
+		// try again on the next flush
+		stored := bb.IDs()
+		for _, mbb := range m.buckets {
+			for _, b := range mbb {
+				b.dirty = b.dirty || slice.HasUint64(stored, b.ID)
+			}
+		}
+
+		return err
+	}
+
+	for workflowID, mbb := range m.buckets {
+		kept := mbb[:0]
+		for i, b := range mbb {
+			if b.dirty || i == len(mbb)-1 {
+				kept = append(kept, b)
+			}
+		}
+
+		m.buckets[workflowID] = kept
+	}
+
+	return nil
+}
+
+// cleanup stores changed buckets and removes expired buckets of all workflows
+func (m *metrics) cleanup(ctx context.Context, s store.Storer) error {
+	if err := m.flush(ctx, s); err != nil {
+		return err
+	}
+
+	var (
+		n = *now()
+
+		// bucket holds values until the start of the next one
+		expiredBefore = n.Add(-m.retention - m.resolution)
+	)
+
+	m.mux.Lock()
+	for workflowID, bb := range m.buckets {
+		if bb = m.prune(bb, n); len(bb) == 0 {
+			delete(m.buckets, workflowID)
+		} else {
+			m.buckets[workflowID] = bb
+		}
+	}
+	m.mux.Unlock()
+
+	bb, _, err := store.SearchAutomationWorkflowStatsBuckets(ctx, s, types.WorkflowStatsBucketFilter{To: &expiredBefore})
+	if err != nil || len(bb) == 0 {
+		return err
+	}
+
+	return store.DeleteAutomationWorkflowStatsBucket(ctx, s, bb...)
+}
+
+// stats aggregates workflow and step stats from all buckets in the time window
+//
+// Stored buckets (collected by all nodes) are merged with
+// buckets on this node that were not stored yet
+func (m *metrics) stats(ctx context.Context, s store.Storer, workflowID uint64, from, to time.Time) (*types.WorkflowStats, error) {
+	var (
+		ws = &types.WorkflowStats{
+			WorkflowID: workflowID,
+			From:       from,
+			To:         to,
+			Steps:      make([]*types.WorkflowStepStats, 0),
+		}
+
+		steps = make(map[uint64]*types.WorkflowStepStats)
+		local = make(map[uint64]*types.WorkflowStatsBucket)
+	)
+
+	// bucket holds values from its start time until the next one
+	from = from.Truncate(m.resolution)
+
+	bb, _, err := store.SearchAutomationWorkflowStatsBuckets(ctx, s, types.WorkflowStatsBucketFilter{
+		WorkflowID: workflowID,
+		From:       &from,
+		To:         &to,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.mux.Lock()
+	for _, b := range m.buckets[workflowID] {
+		if !b.StartAt.Before(from) && !b.StartAt.After(to) {
+			local[b.ID] = b.copy()
+		}
+	}
+	m.mux.Unlock()
+
+	// local buckets are up to date, stored ones are replaced
+	for _, b := range bb {
+		if local[b.ID] == nil {
+			local[b.ID] = b
+		}
+	}
+
+	for _, b := range local {
+		ws.Merge(b.Stats)
+
+		for stepID, st := range b.Steps {
+			if steps[stepID] == nil {
+				steps[stepID] = &types.WorkflowStepStats{StepID: stepID}
+				ws.Steps = append(ws.Steps, steps[stepID])
+			}
+
+			steps[stepID].Merge(*st)
+		}
+	}
+
+	sort.Slice(ws.Steps, func(i, j int) bool {
+		return ws.Steps[i].StepID < ws.Steps[j].StepID
+	})
+
+	return ws, nil
+}
+
+// copy returns copy of the bucket that can be used outside of the lock
+func (b metricsBucket) copy() *types.WorkflowStatsBucket {
+	c := b.WorkflowStatsBucket
+	c.Steps = make(types.WorkflowStatsBucketSteps, len(b.Steps))
+	for stepID, st := range b.Steps {
+		cs := *st
+		c.Steps[stepID] = &cs
+	}
+
+	return &c
+}
+
+func metricsLabel(ID uint64) string {
+	return strconv.FormatUint(ID, 10)
+}
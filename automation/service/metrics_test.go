@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMetrics(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		s, err = sqlite3.ConnectInMemory(ctx)
+
+		m = Metrics(time.Hour)
+
+		clock = time.Date(2021, 1, 1, 10, 0, 30, 0, time.UTC)
+
+		at = func(d time.Duration) time.Time { return clock.Add(d) }
+	)
+
+	require.NoError(t, err)
+	require.NoError(t, store.Upgrade(ctx, zap.NewNop(), s))
+	require.NoError(t, store.TruncateAutomationWorkflowStatsBuckets(ctx, s))
+
+	defer func(orig func() *time.Time) { now = orig }(now)
+	now = func() *time.Time { c := clock; return &c }
+
+	// first minute: two sessions, one fails
+	m.sessionStarted(1)
+	m.sessionStarted(1)
+	m.stepExecuted(1, 10, false, false, time.Second)
+	m.stepExecuted(1, 20, true, false, 3*time.Second)
+	m.sessionFinished(1, false, 2*time.Second)
+	m.sessionFinished(1, true, 4*time.Second)
+
+	// other workflow is not included in stats
+	m.sessionStarted(2)
+
+	// next minute: one session suspended on the second step
+	clock = clock.Add(time.Minute)
+	m.sessionStarted(1)
+	m.stepExecuted(1, 10, false, false, 2*time.Second)
+	m.stepExecuted(1, 20, false, true, time.Second)
+	m.sessionSuspended(1)
+
+	t.Run("whole window", func(t *testing.T) {
+		req := require.New(t)
+		ws, err := m.stats(ctx, s, 1, at(-time.Hour), at(0))
+		req.NoError(err)
+		req.Equal(uint(3), ws.Executions)
+		req.Equal(uint(1), ws.Failures)
+		req.Equal(uint(1), ws.Suspended)
+		req.Equal(uint(2), ws.Finished)
+		req.Equal(3*time.Second, ws.DurationAvg)
+		req.Equal(2*time.Second, ws.DurationMin)
+		req.Equal(4*time.Second, ws.DurationMax)
+
+		req.Len(ws.Steps, 2)
+		req.Equal(uint64(10), ws.Steps[0].StepID)
+		req.Equal(uint(2), ws.Steps[0].Executions)
+		req.Equal(3*time.Second, ws.Steps[0].DurationTotal)
+		req.Equal(uint64(20), ws.Steps[1].StepID)
+		req.Equal(uint(1), ws.Steps[1].Failures)
+		req.Equal(uint(1), ws.Steps[1].Suspended)
+		req.Equal(time.Second, ws.Steps[1].DurationMin)
+	})
+
+	t.Run("last minute", func(t *testing.T) {
+		req := require.New(t)
+		ws, err := m.stats(ctx, s, 1, at(-time.Second), at(0))
+		req.NoError(err)
+		req.Equal(uint(1), ws.Executions)
+		req.Equal(uint(0), ws.Failures)
+		req.Equal(uint(0), ws.Finished)
+		req.Equal(time.Duration(0), ws.DurationAvg)
+		req.Len(ws.Steps, 2)
+		req.Equal(uint(1), ws.Steps[0].Executions)
+	})
+
+	t.Run("stored stats", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(m.flush(ctx, s))
+
+		// only the current bucket is kept in memory
+		req.Len(m.buckets[1], 1)
+
+		// other node (or this one after restart) sees stored stats
+		// and adds its own
+		other := Metrics(time.Hour)
+		other.sessionStarted(1)
+
+		ws, err := other.stats(ctx, s, 1, at(-time.Hour), at(0))
+		req.NoError(err)
+		req.Equal(uint(4), ws.Executions)
+		req.Equal(uint(1), ws.Failures)
+		req.Len(ws.Steps, 2)
+		req.Equal(uint(2), ws.Steps[0].Executions)
+
+		// changes after the flush are included
+		m.sessionStarted(1)
+		ws, err = m.stats(ctx, s, 1, at(-time.Hour), at(0))
+		req.NoError(err)
+		req.Equal(uint(4), ws.Executions)
+	})
+
+	t.Run("expired buckets are removed", func(t *testing.T) {
+		req := require.New(t)
+		clock = clock.Add(time.Hour)
+		req.NoError(m.cleanup(ctx, s))
+		req.Len(m.buckets[1], 1)
+		req.Empty(m.buckets[2])
+
+		ws, err := m.stats(ctx, s, 1, at(-2*time.Hour), at(0))
+		req.NoError(err)
+		req.Equal(uint(2), ws.Executions)
+		req.Equal(uint(1), ws.Suspended)
+
+		bb, _, err := store.SearchAutomationWorkflowStatsBuckets(ctx, s, types.WorkflowStatsBucketFilter{})
+		req.NoError(err)
+		req.Len(bb, 1)
+	})
+
+	t.Run("prometheus", func(t *testing.T) {
+		req := require.New(t)
+		reg := prometheus.NewRegistry()
+		req.NoError(m.register(reg))
+
+		req.NoError(testutil.CollectAndCompare(m.executions, strings.NewReader(`
+# HELP corteza_automation_workflow_executions_total Number of started workflow sessions
+# TYPE corteza_automation_workflow_executions_total counter
+corteza_automation_workflow_executions_total{workflowID="1"} 4
+corteza_automation_workflow_executions_total{workflowID="2"} 1
+`)))
+
+		req.NoError(testutil.CollectAndCompare(m.stepFailures, strings.NewReader(`
+# HELP corteza_automation_step_failures_total Number of failed workflow step executions
+# TYPE corteza_automation_step_failures_total counter
+corteza_automation_step_failures_total{workflowID="1"} 1
+`)))
+
+		mfs, err := reg.Gather()
+		req.NoError(err)
+
+		for _, mf := range mfs {
+			if mf.GetName() == "corteza_automation_step_duration_seconds" {
+				req.Len(mf.GetMetric(), 1)
+				req.Equal(uint64(4), mf.GetMetric()[0].GetHistogram().GetSampleCount())
+			}
+		}
+
+		// metrics of deleted workflow are removed
+		m.forget(1)
+		req.NoError(testutil.CollectAndCompare(m.executions, strings.NewReader(`
+# HELP corteza_automation_workflow_executions_total Number of started workflow sessions
+# TYPE corteza_automation_workflow_executions_total counter
+corteza_automation_workflow_executions_total{workflowID="2"} 1
+`)))
+	})
+}
@@ -50,6 +50,7 @@ var (
 	DefaultWorkflow *workflow
 	DefaultTrigger  *trigger
 	DefaultSession  *session
+	DefaultWebhook  *webhook
 
 	// wrapper around time.Now() that will aid service testing
 	now = func() *time.Time {
@@ -90,11 +91,13 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 
 	DefaultAccessControl = AccessControl(rbac.Global())
 
-	DefaultWorkflow = Workflow(DefaultLogger.Named("workflow"))
-	DefaultSession = Session(DefaultLogger.Named("session"))
+	DefaultWorkflow = Workflow(DefaultLogger.Named("workflow"), c.Workflow)
+	DefaultSession = Session(DefaultLogger.Named("session"), c.Workflow)
+	DefaultWebhook = Webhook(DefaultLogger.Named("webhook"))
 	DefaultTrigger = Trigger(DefaultLogger.Named("trigger"), c.Workflow)
 
 	DefaultWorkflow.triggers = DefaultTrigger
+	DefaultWorkflow.session = DefaultSession
 
 	Registry().AddTypes(
 		&expr.Any{},
@@ -124,6 +127,12 @@ func Activate(ctx context.Context) (err error) {
 		return
 	}
 
+	// restore suspended sessions;
+	// this needs to be done after session watcher is started
+	if err = DefaultSession.resumeAll(ctx); err != nil {
+		return
+	}
+
 	return
 }
 
@@ -2,15 +2,21 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"github.com/cortezaproject/corteza-server/automation/types"
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/sentry"
 	"github.com/cortezaproject/corteza-server/pkg/wfexec"
 	"github.com/cortezaproject/corteza-server/store"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"sort"
 	"sync"
 	"time"
 )
@@ -20,16 +26,53 @@ type (
 		store      store.Storer
 		actionlog  actionlog.Recorder
 		ac         sessionAccessController
+		workflow   *workflow
 		log        *zap.Logger
+		opt        options.WorkflowOpt
 		mux        *sync.RWMutex
 		pool       map[uint64]*types.Session
 		spawnQueue chan *spawn
+		metrics    *metrics
+
+		// eventbus handlers registered for sessions that are waiting for events
+		// (session ID => state ID => handler)
+		eventbus  triggerEventTriggerHandler
+		awaits    map[uint64]map[uint64]uintptr
+		awaitsMux *sync.Mutex
+
+		// subprocess calls (subprocess session ID => call);
+		// guarded by awaitsMux
+		subprocesses map[uint64]*subprocessCall
+	}
+
+	// subprocessCall links subprocess session with the awaiting state of the caller
+	subprocessCall struct {
+		callerSessionID uint64
+
+		// set when caller's state is registered as awaiting
+		callerStateID uint64
+
+		// set when subprocess is completed or failed
+		finished bool
+		results  *expr.Vars
+		err      string
 	}
 
 	spawn struct {
 		session chan *wfexec.Session
 		graph   *wfexec.Graph
 		trace   bool
+
+		// max duration of the session
+		timeout time.Duration
+
+		// when set, session timeout is counted from this time
+		// (used when sessions are restored)
+		started time.Time
+
+		// when set, session is spawned with this ID
+		// (used when sessions are restored)
+		sessionID uint64
 	}
 
 	sessionAccessController interface {
@@ -37,18 +80,37 @@ type (
 		CanManageWorkflowSessions(context.Context, *types.Workflow) bool
 	}
 
+	// converted workflow and its retention
+	// used when restoring sessions
+	// workflow ID & revision
+	restoredWorkflowKey [2]uint64
+
+	restoredWorkflow struct {
+		graph   *wfexec.Graph
+		keepFor time.Duration
+		timeout time.Duration
+	}
+
 	WaitFn func(ctx context.Context) (*expr.Vars, wfexec.SessionStatus, error)
 )
 
-func Session(log *zap.Logger) *session {
+func Session(log *zap.Logger, opt options.WorkflowOpt) *session {
 	return &session{
 		log:        log,
+		opt:        opt,
 		actionlog:  DefaultActionlog,
 		store:      DefaultStore,
 		ac:         DefaultAccessControl,
+		workflow:   DefaultWorkflow,
 		mux:        &sync.RWMutex{},
 		pool:       make(map[uint64]*types.Session),
 		spawnQueue: make(chan *spawn),
+		metrics:    Metrics(opt.StatsRetention),
+		eventbus:   eventbus.Service(),
+		awaits:     make(map[uint64]map[uint64]uintptr),
+		awaitsMux:  &sync.Mutex{},
+
+		subprocesses: make(map[uint64]*subprocessCall),
 	}
 }
 
@@ -101,24 +163,209 @@ func (svc *session) LookupByID(ctx context.Context, sessionID uint64) (res *type
 	return res, svc.recordAction(ctx, sap, SessionActionLookup, err)
 }
 
+// resumeAll loads all suspended (delayed and prompted) sessions from the store
+// and restores them into the session pool
+//
+// Sessions are restored in order they were created so that callers
+// are restored before their subprocesses
+//
+// Sessions that can not be restored (workflow removed, disabled, invalid or changed)
+// are marked as failed
 func (svc *session) resumeAll(ctx context.Context) error {
-	// @todo resume active sessions from storage
+	ss, _, err := store.SearchAutomationSessions(ctx, svc.store, types.SessionFilter{
+		Status:    []uint{uint(types.SessionPrompted), uint(types.SessionSuspended)},
+		Completed: filter.StateExcluded,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	var (
+		// cache converted workflows
+		wfs = make(map[restoredWorkflowKey]*restoredWorkflow)
+	)
+
+	sort.Slice(ss, func(i, j int) bool { return ss[i].ID < ss[j].ID })
+
+	for _, ses := range ss {
+		log := svc.log.With(zap.Uint64("sessionID", ses.ID), zap.Uint64("workflowID", ses.WorkflowID))
+
+		if err = svc.restore(ctx, ses, wfs); err != nil {
+			log.Warn("could not restore session", zap.Error(err))
+
+			ses.SuspendedAt = nil
+			ses.CompletedAt = now()
+			ses.Status = types.SessionFailed
+			ses.Error = fmt.Sprintf("could not restore session: %v", err)
+			ses.States = nil
+			ses.KeepFor = svc.opt.SessionRetention
+			ses.ApplyRetention()
+
+			if err = store.UpdateAutomationSession(ctx, svc.store, ses); err != nil {
+				log.Error("failed to update session", zap.Error(err))
+			}
+
+			continue
+		}
+
+		log.Debug("session restored", zap.Int("states", len(ses.States)))
+	}
+
+	svc.resumeOrphanedCallers(ctx)
 	return nil
 }
 
-func (svc *session) suspendAll(ctx context.Context) error {
-	// @todo suspend active sessions to storage
+// resumeOrphanedCallers resumes restored callers that are waiting for a subprocess
+// that was not restored
+//
+// Subprocess could have finished before its caller was suspended or it was still running
+// when sessions were suspended (running sessions can not be restored). Caller is resumed
+// with results of the completed subprocess; in all other cases it is resumed with an error
+// and subprocess that was interrupted is marked as failed
+func (svc *session) resumeOrphanedCallers(ctx context.Context) {
+	var (
+		orphaned = make(map[uint64]*subprocessCall)
+	)
+
+	svc.mux.RLock()
+	svc.awaitsMux.Lock()
+	for sessionID, call := range svc.subprocesses {
+		if svc.pool[sessionID] == nil && !call.finished && call.callerStateID > 0 {
+			orphaned[sessionID] = call
+			delete(svc.subprocesses, sessionID)
+		}
+	}
+	svc.awaitsMux.Unlock()
+	svc.mux.RUnlock()
+
+	for sessionID, call := range orphaned {
+		ses, err := store.LookupAutomationSessionByID(ctx, svc.store, sessionID)
+
+		switch {
+		case err != nil:
+			call.err = fmt.Sprintf("could not restore subprocess: %v", err)
+
+		case ses.Status == types.SessionCompleted:
+			call.results = ses.Output
+
+		case ses.Status == types.SessionFailed:
+			call.err = ses.Error
+
+		default:
+			call.err = "subprocess interrupted"
+
+			ses.SuspendedAt = nil
+			ses.CompletedAt = now()
+			ses.Status = types.SessionFailed
+			ses.Error = call.err
+			ses.States = nil
+			ses.KeepFor = svc.opt.SessionRetention
+			ses.ApplyRetention()
+
+			if err = store.UpdateAutomationSession(ctx, svc.store, ses); err != nil {
+				svc.log.Error("failed to update session", zap.Uint64("sessionID", sessionID), zap.Error(err))
+			}
+		}
+
+		call.finished = true
+		svc.resumeCaller(sessionID, call)
+	}
+}
+
+// restores one session
+func (svc *session) restore(ctx context.Context, ses *types.Session, wfs map[restoredWorkflowKey]*restoredWorkflow) (err error) {
+	if len(ses.States) == 0 {
+		return fmt.Errorf("no suspended states")
+	}
+
+	var (
+		key = restoredWorkflowKey{ses.WorkflowID, uint64(ses.Revision)}
+	)
+
+	rwf, has := wfs[key]
+	if !has {
+		var (
+			wf     *types.Workflow
+			g      *wfexec.Graph
+			issues types.WorkflowIssueSet
+		)
+
+		if wf, err = loadWorkflow(ctx, svc.store, ses.WorkflowID); err != nil {
+			return
+		}
+
+		if !wf.Enabled || wf.DeletedAt != nil {
+			return fmt.Errorf("workflow disabled or deleted")
+		}
+
+		// session is restored on the revision it was started on
+		if wf, err = loadPublishedWorkflow(ctx, svc.store, wf, ses.Revision); err != nil {
+			return
+		}
+
+		if g, issues = Convert(svc.workflow, wf); len(issues) > 0 {
+			return issues
+		}
+
+		rwf = &restoredWorkflow{
+			graph:   g,
+			keepFor: svc.retention(wf.KeepSessions),
+			timeout: time.Duration(wf.Timeout) * time.Second,
+		}
+		wfs[key] = rwf
+	}
+
+	for _, st := range ses.States {
+		if err = st.ResolveTypes(Registry().Type); err != nil {
+			return
+		}
+	}
+
+	// spawned before the lock is acquired;
+	// mux must not be held while waiting for the watcher
+	//
+	// timeout is counted from the time session was created
+	spawned := svc.spawn(rwf.graph, ses.ID, ses.Stacktrace != nil, rwf.timeout, ses.CreatedAt)
+
+	defer svc.mux.Unlock()
+	svc.mux.Lock()
+
+	if err = ses.Restore(spawned); err != nil {
+		return
+	}
+
+	ses.KeepFor = rwf.keepFor
+	svc.restoreCallStack(ses)
+	svc.registerAwaits(ses)
+
+	svc.pool[ses.ID] = ses
 	return nil
 }
 
-type (
-	foo struct {
-		SessionID uint64
-		StateID   uint64
-		CreatedAt time.Time
-		Payload   *expr.Vars
+// suspendAll stores all suspended states of all sessions in the pool
+//
+// Running sessions can not be suspended; callers waiting for
+// such subprocesses are resumed with an error when restored
+func (svc *session) suspendAll(ctx context.Context) error {
+	defer svc.mux.RUnlock()
+	svc.mux.RLock()
+
+	for _, ses := range svc.pool {
+		switch ses.Status {
+		case types.SessionPrompted, types.SessionSuspended:
+		default:
+			continue
+		}
+
+		ses.States = ses.SuspendedStates()
+		if err := store.UpdateAutomationSession(ctx, svc.store, ses); err != nil {
+			return err
+		}
 	}
-)
+
+	return nil
+}
 
 // PendingPrompts returns all prompts on all sessions owned by current user
 func (svc *session) PendingPrompts(ctx context.Context) (pp []*wfexec.PendingPrompt) {
@@ -141,38 +388,54 @@ func (svc *session) PendingPrompts(ctx context.Context) (pp []*wfexec.PendingPro
 	return
 }
 
+// callStack returns call stack of the session (if session is still in the pool)
+func (svc *session) callStack(sessionID uint64) []uint64 {
+	defer svc.mux.RUnlock()
+	svc.mux.RLock()
+
+	if ses := svc.pool[sessionID]; ses != nil {
+		// copy to avoid modifications of the original slice
+		return append([]uint64{}, ses.CallStack...)
+	}
+
+	return nil
+}
+
 // Start new workflow session on a specific step with a given identity and scope
 //
 // Start is an asynchronous operation
 //
 // It does not check user's permissions to execute workflow(s) so it should be used only when !
 func (svc *session) Start(g *wfexec.Graph, i auth.Identifiable, ssp types.SessionStartParams) (wait WaitFn, err error) {
-	var (
-		start wfexec.Step
-	)
+	ses, err := svc.start(g, i, ssp)
+	if err != nil {
+		return
+	}
 
-	if ssp.StepID == 0 {
-		// starting step is not explicitly workflows on trigger, find orphan step
-		switch oo := g.Orphans(); len(oo) {
-		case 1:
-			start = oo[0]
-		case 0:
-			return nil, errors.InvalidData("could not find starting step")
-		default:
-			return nil, errors.InvalidData("can not start workflow session multiple starting steps found")
-		}
-	} else if start = g.StepByID(ssp.StepID); start == nil {
-		return nil, errors.InvalidData("trigger staring step references nonexisting step")
+	return func(ctx context.Context) (*expr.Vars, wfexec.SessionStatus, error) { return ses.WaitResults(ctx) }, nil
+}
+
+// start creates, stores and executes new session
+func (svc *session) start(g *wfexec.Graph, i auth.Identifiable, ssp types.SessionStartParams) (ses *types.Session, err error) {
+	start, err := startingStep(g, ssp.StepID)
+	if err != nil {
+		return
 	}
 
 	var (
 		ctx = auth.SetIdentityToContext(context.Background(), i)
-		ses = svc.spawn(g, ssp.Trace)
 	)
 
+	ses = types.NewSession(svc.spawn(g, 0, ssp.Trace, time.Duration(ssp.Timeout)*time.Second, time.Time{}))
+
+	svc.mux.Lock()
+	svc.pool[ses.ID] = ses
+	svc.mux.Unlock()
+
 	ses.CreatedAt = *now()
 	ses.CreatedBy = i.Identity()
 	ses.Apply(ssp)
+	ses.KeepFor = svc.retention(ssp.KeepFor)
 
 	if err = store.CreateAutomationSession(context.TODO(), svc.store, ses); err != nil {
 		return
@@ -182,7 +445,29 @@ func (svc *session) Start(g *wfexec.Graph, i auth.Identifiable, ssp types.Sessio
 		return
 	}
 
-	return func(ctx context.Context) (*expr.Vars, wfexec.SessionStatus, error) { return ses.WaitResults(ctx) }, nil
+	svc.metrics.sessionStarted(ses.WorkflowID)
+	return ses, nil
+}
+
+// startingStep returns step with the given ID or orphan step when ID is not set
+func startingStep(g *wfexec.Graph, stepID uint64) (start wfexec.Step, err error) {
+	if stepID == 0 {
+		// starting step is not explicitly workflows on trigger, find orphan step
+		switch oo := g.Orphans(); len(oo) {
+		case 1:
+			return oo[0], nil
+		case 0:
+			return nil, errors.InvalidData("could not find starting step")
+		default:
+			return nil, errors.InvalidData("can not start workflow session multiple starting steps found")
+		}
+	}
+
+	if start = g.StepByID(stepID); start == nil {
+		return nil, errors.InvalidData("trigger staring step references nonexisting step")
+	}
+
+	return start, nil
 }
 
 // Resume resumes suspended session/state
@@ -203,26 +488,454 @@ func (svc *session) Resume(sessionID, stateID uint64, i auth.Identifiable, input
 	return ses.Resume(ctx, stateID, input)
 }
 
+// Cancel stops running or suspended session
+//
+// Context of running steps is canceled; session is marked as failed.
+// Subprocesses called by the session are canceled as well
+func (svc *session) Cancel(ctx context.Context, sessionID uint64) (err error) {
+	var (
+		sap = &sessionActionProps{session: &types.Session{ID: sessionID}}
+		ses *types.Session
+		wf  *types.Workflow
+	)
+
+	err = func() (err error) {
+		if ses, err = loadSession(ctx, svc.store, sessionID); err != nil {
+			return err
+		}
+
+		if wf, err = loadWorkflow(ctx, svc.store, ses.WorkflowID); err != nil {
+			return err
+		}
+
+		if !svc.ac.CanManageWorkflowSessions(ctx, wf) {
+			return SessionErrNotAllowedToManage()
+		}
+
+		svc.mux.Lock()
+		if ses = svc.pool[sessionID]; ses == nil || ses.Finished() {
+			svc.mux.Unlock()
+			return SessionErrNotRunning()
+		}
+
+		// caller is canceled before its subprocesses
+		// so that they have nobody to report to
+		canceled := append(types.SessionSet{ses}, svc.subprocessesOf(sessionID)...)
+		for _, c := range canceled {
+			svc.cancel(c)
+		}
+		svc.mux.Unlock()
+
+		for _, c := range canceled {
+			svc.metrics.sessionFinished(c.WorkflowID, true, c.CompletedAt.Sub(c.CreatedAt))
+			svc.unregisterAwaits(c.ID)
+
+			if err = store.UpdateAutomationSession(ctx, svc.store, c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}()
+
+	return svc.recordAction(ctx, sap, SessionActionCancel, err)
+}
+
+// cancel cancels session and marks it as failed
+//
+// Expects session pool (mux) to be locked
+func (svc *session) cancel(ses *types.Session) {
+	ses.Cancel()
+
+	ses.SuspendedAt = nil
+	ses.CompletedAt = now()
+	ses.Error = wfexec.ErrCanceled.Error()
+	ses.Status = types.SessionFailed
+	ses.States = nil
+	ses.ApplyRetention()
+	svc.subprocessFinished(ses, nil)
+}
+
+// subprocessesOf returns all unfinished sessions in the pool that were
+// called (directly or indirectly) by the given session
+//
+// Callers are returned before their subprocesses;
+// expects session pool (mux) to be locked
+func (svc *session) subprocessesOf(sessionID uint64) (ss types.SessionSet) {
+	for _, ses := range svc.pool {
+		if ses.Finished() {
+			continue
+		}
+
+		for _, callerID := range ses.CallStack {
+			if callerID == sessionID {
+				ss = append(ss, ses)
+				break
+			}
+		}
+	}
+
+	sort.Slice(ss, func(i, j int) bool { return len(ss[i].CallStack) < len(ss[j].CallStack) })
+	return
+}
+
+// registerAwaits registers eventbus handlers for all session's states
+// that are waiting for an event and removes handlers for states that are no longer waiting
+//
+// States that are waiting for a subprocess are linked with the subprocess session
+func (svc *session) registerAwaits(ses *types.Session) {
+	defer svc.awaitsMux.Unlock()
+	svc.awaitsMux.Lock()
+
+	var (
+		registered = svc.awaits[ses.ID]
+		pending    = make(map[uint64]bool)
+		log        = svc.log.With(zap.Uint64("sessionID", ses.ID))
+	)
+
+	if registered == nil {
+		registered = make(map[uint64]uintptr)
+	}
+
+	for _, pa := range ses.PendingAwaits() {
+		if pa.Ref == types.SubprocessRef {
+			svc.linkSubprocess(ses.ID, pa)
+			continue
+		}
+
+		if pa.Ref != types.EventWaitRef {
+			continue
+		}
+
+		pending[pa.StateID] = true
+
+		if _, has := registered[pa.StateID]; has {
+			continue
+		}
+
+		ew, err := types.ParseEventWait(pa.Payload)
+		if err != nil {
+			log.Error("could not parse awaited event", zap.Uint64("stateID", pa.StateID), zap.Error(err))
+			continue
+		}
+
+		ops := []eventbus.HandlerRegOp{
+			eventbus.On(ew.EventType),
+			eventbus.For(ew.ResourceType),
+		}
+
+		for name, values := range ew.Constraints {
+			ops = append(ops, eventbus.Constraint(eventbus.MustMakeConstraint(name, "eq", values...)))
+		}
+
+		registered[pa.StateID] = svc.eventbus.Register(svc.makeAwaitHandler(ses, pa.StateID), ops...)
+
+		log.Debug("waiting for event",
+			zap.Uint64("stateID", pa.StateID),
+			zap.String("eventType", ew.EventType),
+			zap.String("resourceType", ew.ResourceType),
+			zap.Any("constraints", ew.Constraints),
+		)
+	}
+
+	for stateID, ptr := range registered {
+		if !pending[stateID] {
+			svc.eventbus.Unregister(ptr)
+			delete(registered, stateID)
+		}
+	}
+
+	if len(registered) > 0 {
+		svc.awaits[ses.ID] = registered
+	} else {
+		delete(svc.awaits, ses.ID)
+	}
+}
+
+// unregisterAwaits removes eventbus handlers for all (or specific) session's states
+//
+// When removing all handlers, links with session's subprocesses are removed as well
+func (svc *session) unregisterAwaits(sessionID uint64, stateIDs ...uint64) {
+	defer svc.awaitsMux.Unlock()
+	svc.awaitsMux.Lock()
+
+	registered := svc.awaits[sessionID]
+	if len(stateIDs) == 0 {
+		for stateID := range registered {
+			stateIDs = append(stateIDs, stateID)
+		}
+
+		for ID, call := range svc.subprocesses {
+			if call.callerSessionID == sessionID {
+				delete(svc.subprocesses, ID)
+			}
+		}
+	}
+
+	for _, stateID := range stateIDs {
+		if ptr, has := registered[stateID]; has {
+			svc.eventbus.Unregister(ptr)
+			delete(registered, stateID)
+		}
+	}
+
+	if len(registered) == 0 {
+		delete(svc.awaits, sessionID)
+	}
+}
+
+// makeAwaitHandler creates eventbus handler that resumes awaiting state
+// with event variables as input
+func (svc *session) makeAwaitHandler(ses *types.Session, stateID uint64) eventbus.HandlerFn {
+	return func(ctx context.Context, ev eventbus.Event) (err error) {
+		var (
+			input = &expr.Vars{}
+			log   = svc.log.With(zap.Uint64("sessionID", ses.ID), zap.Uint64("stateID", stateID))
+		)
+
+		if enc, is := ev.(varsEncoder); is {
+			if input, err = enc.EncodeVars(); err != nil {
+				log.Error("could not encode event variables", zap.Error(err))
+				return nil
+			}
+		}
+
+		_ = input.AssignFieldValue("eventType", expr.Must(expr.NewString(ev.EventType())))
+		_ = input.AssignFieldValue("resourceType", expr.Must(expr.NewString(ev.ResourceType())))
+
+		// handler can not be unregistered while
+		// eventbus is dispatching the event
+		go svc.unregisterAwaits(ses.ID, stateID)
+
+		if err = ses.ResumeAwaiting(ctx, stateID, input); err != nil {
+			// state was most likely resumed with an earlier event or timeout
+			log.Debug("could not resume awaiting state", zap.Error(err))
+		}
+
+		return nil
+	}
+}
+
+// linkSubprocess links caller's awaiting state with the subprocess session
+//
+// Caller is resumed right away if subprocess is already finished;
+// expects awaitsMux to be locked
+func (svc *session) linkSubprocess(callerSessionID uint64, pa *wfexec.PendingAwait) {
+	sessionID, err := types.ParseSubprocess(pa.Payload)
+	if err != nil {
+		svc.log.Error("could not parse awaited subprocess",
+			zap.Uint64("sessionID", callerSessionID),
+			zap.Uint64("stateID", pa.StateID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	call := svc.subprocesses[sessionID]
+	if call == nil {
+		call = &subprocessCall{}
+		svc.subprocesses[sessionID] = call
+	}
+
+	call.callerSessionID = callerSessionID
+	call.callerStateID = pa.StateID
+
+	if call.finished {
+		delete(svc.subprocesses, sessionID)
+		go svc.resumeCaller(sessionID, call)
+	}
+}
+
+// subprocessFinished resumes the caller's state with subprocess results or error
+//
+// When caller's state is not (yet) registered as awaiting, results
+// are kept until it is; expects session pool (mux) to be locked
+func (svc *session) subprocessFinished(ses *types.Session, results *expr.Vars) {
+	if len(ses.CallStack) == 0 {
+		// not a subprocess
+		return
+	}
+
+	var (
+		callerSessionID = ses.CallStack[len(ses.CallStack)-1]
+	)
+
+	if caller := svc.pool[callerSessionID]; caller == nil || caller.Finished() {
+		// nobody to report to
+		return
+	}
+
+	defer svc.awaitsMux.Unlock()
+	svc.awaitsMux.Lock()
+
+	call := svc.subprocesses[ses.ID]
+	if call == nil {
+		call = &subprocessCall{callerSessionID: callerSessionID}
+		svc.subprocesses[ses.ID] = call
+	}
+
+	call.finished = true
+	call.results = results
+	call.err = ses.Error
+
+	if call.callerStateID > 0 {
+		delete(svc.subprocesses, ses.ID)
+		go svc.resumeCaller(ses.ID, call)
+	}
+}
+
+// resumeCaller resumes caller's awaiting state with subprocess results or error
+func (svc *session) resumeCaller(sessionID uint64, call *subprocessCall) {
+	var (
+		input = &expr.Vars{}
+		log   = svc.log.With(
+			zap.Uint64("sessionID", call.callerSessionID),
+			zap.Uint64("stateID", call.callerStateID),
+			zap.Uint64("subprocessID", sessionID),
+		)
+	)
+
+	svc.mux.RLock()
+	caller := svc.pool[call.callerSessionID]
+	svc.mux.RUnlock()
+
+	if caller == nil {
+		log.Warn("could not find caller of the subprocess")
+		return
+	}
+
+	if call.err != "" {
+		_ = input.AssignFieldValue(types.SubprocessError, expr.Must(expr.NewString(call.err)))
+	} else if call.results != nil {
+		_ = input.AssignFieldValue(types.SubprocessResults, call.results)
+	}
+
+	if err := caller.ResumeAwaiting(context.Background(), call.callerStateID, input); err != nil {
+		log.Warn("could not resume caller of the subprocess", zap.Error(err))
+	}
+}
+
+// restoreCallStack restores call stack of the subprocess session from its caller
+//
+// Caller needs to be restored (and its awaiting states registered) before the subprocess;
+// expects session pool (mux) to be locked
+func (svc *session) restoreCallStack(ses *types.Session) {
+	svc.awaitsMux.Lock()
+	call := svc.subprocesses[ses.ID]
+	svc.awaitsMux.Unlock()
+
+	if call == nil {
+		return
+	}
+
+	if caller := svc.pool[call.callerSessionID]; caller != nil {
+		ses.CallStack = append(append([]uint64{}, caller.CallStack...), caller.ID)
+	} else {
+		ses.CallStack = []uint64{call.callerSessionID}
+	}
+}
+
+// retention returns workflow's session retention or default retention
+// when workflow does not have one set
+func (svc *session) retention(keepSessions int) time.Duration {
+	if keepSessions > 0 {
+		return time.Duration(keepSessions) * time.Second
+	}
+
+	return svc.opt.SessionRetention
+}
+
 // spawns a new session
 //
 // We need initial context for the session because we want to catch all cancellations or timeouts from there
 // and not from any potential HTTP requests or similar temporary context that can prematurely destroy a workflow session
-func (svc *session) spawn(g *wfexec.Graph, trace bool) (ses *types.Session) {
-	s := &spawn{make(chan *wfexec.Session, 1), g, trace}
+//
+// When sessionID and started are set, session is spawned with that ID
+// and its timeout is counted from the original start (restoring sessions)
+func (svc *session) spawn(g *wfexec.Graph, sessionID uint64, trace bool, timeout time.Duration, started time.Time) *wfexec.Session {
+	s := &spawn{make(chan *wfexec.Session, 1), g, trace, timeout, started, sessionID}
 
 	// Send new-session request
 	svc.spawnQueue <- s
 
 	// blocks until session is set
-	ses = types.NewSession(<-s.session)
-
-	svc.mux.Lock()
-	svc.pool[ses.ID] = ses
-	svc.mux.Unlock()
-	return ses
+	return <-s.session
 }
 
 func (svc *session) Watch(ctx context.Context) {
+	var (
+		// nil channel (no cleanup) unless interval is set
+		cleanup <-chan time.Time
+		tck     *time.Ticker
+	)
+
+	if svc.opt.SessionCleanupInterval > 0 {
+		tck = time.NewTicker(svc.opt.SessionCleanupInterval)
+		cleanup = tck.C
+	} else {
+		svc.log.Warn("session cleanup interval not set, completed sessions will not be removed")
+	}
+
+	err := prometheus.Register(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "corteza",
+			Subsystem: "automation",
+			Name:      "session_pool_size",
+			Help:      "Number of workflow sessions in memory",
+		},
+		func() float64 { return float64(svc.poolSize()) },
+	))
+
+	if err != nil {
+		svc.log.Warn("could not register session pool size metric", zap.Error(err))
+	}
+
+	if err = svc.metrics.register(prometheus.DefaultRegisterer); err != nil {
+		svc.log.Warn("could not register workflow execution metrics", zap.Error(err))
+	}
+
+	if tck != nil {
+		// cleanup locks the pool and is done outside of the
+		// watcher loop so that it does not block spawning of sessions
+		go func() {
+			defer sentry.Recover()
+			defer tck.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-cleanup:
+					svc.cleanup(ctx)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer sentry.Recover()
+
+		flush := time.NewTicker(metricsFlushInterval)
+		defer flush.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// context is done at this point, we need a new one
+				// to store collected stats
+				if err := svc.metrics.flush(context.Background(), svc.store); err != nil {
+					svc.log.Error("failed to store workflow stats", zap.Error(err))
+				}
+
+				return
+			case <-flush.C:
+				if err := svc.metrics.flush(ctx, svc.store); err != nil {
+					svc.log.Error("failed to store workflow stats", zap.Error(err))
+				}
+			}
+		}
+	}()
+
 	go func() {
 		defer sentry.Recover()
 		defer svc.log.Info("stopped")
@@ -230,26 +943,58 @@ func (svc *session) Watch(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
+				// context is done at this point, we need a new one
+				// to store suspended states
+				if err := svc.suspendAll(context.Background()); err != nil {
+					svc.log.Error("failed to suspend sessions", zap.Error(err))
+				}
+
 				return
 			case s := <-svc.spawnQueue:
-				//
 				s.session <- wfexec.NewSession(ctx,
 					s.graph,
 					wfexec.SetHandler(svc.stateChangeHandler(ctx)),
 					wfexec.SetLogger(svc.log),
+					wfexec.SetSessionID(s.sessionID),
+					wfexec.SetTimeout(s.timeout),
+					wfexec.SetStarted(s.started),
+					wfexec.SetStepTimeout(svc.opt.StepTimeout),
+					wfexec.SetConcurrency(svc.opt.StepConcurrency),
 				)
-				// case time for a pool cleanup
-				// @todo cleanup pool when sessions are complete
 			}
 		}
-
-		// @todo serialize sessions & suspended states
-		//svc.suspendAll(ctx)
 	}()
 
 	svc.log.Debug("watcher initialized")
 }
 
+// cleanup removes completed and failed sessions from the pool
+// and deletes expired sessions and workflow stats from the store
+func (svc *session) cleanup(ctx context.Context) {
+	svc.mux.Lock()
+	for ID, ses := range svc.pool {
+		if ses.Finished() {
+			delete(svc.pool, ID)
+		}
+	}
+	svc.mux.Unlock()
+
+	if err := svc.metrics.cleanup(ctx, svc.store); err != nil {
+		svc.log.Error("failed to clean up workflow stats", zap.Error(err))
+	}
+
+	if err := store.DeleteExpiredAutomationSessions(ctx, svc.store); err != nil {
+		svc.log.Error("failed to delete expired sessions", zap.Error(err))
+	}
+}
+
+// poolSize returns number of sessions in the pool
+func (svc *session) poolSize() int {
+	defer svc.mux.RUnlock()
+	svc.mux.RLock()
+	return len(svc.pool)
+}
+
 func (svc *session) stateChangeHandler(ctx context.Context) wfexec.StateChangeHandler {
 	return func(i wfexec.SessionStatus, state *wfexec.State, s *wfexec.Session) {
 		log := svc.log.With(zap.Uint64("sessionID", s.ID()))
@@ -265,6 +1010,10 @@ func (svc *session) stateChangeHandler(ctx context.Context) wfexec.StateChangeHa
 		var (
 			update = true
 			frame  = state.MakeFrame()
+
+			// status before the change; used to count
+			// suspended and finished sessions only once
+			prev = ses.Status
 		)
 
 		if ses.Stacktrace != nil {
@@ -272,27 +1021,71 @@ func (svc *session) stateChangeHandler(ctx context.Context) wfexec.StateChangeHa
 			ses.Stacktrace = append(ses.Stacktrace, frame)
 		}
 
+		if state.Executed() && frame.StepID > 0 {
+			svc.metrics.stepExecuted(
+				ses.WorkflowID,
+				frame.StepID,
+				frame.Error != "",
+				s.StateSuspended(frame.StateID),
+				frame.LeadTime,
+			)
+		}
+
 		switch i {
 		case wfexec.SessionPrompted:
 			ses.SuspendedAt = now()
 			ses.Status = types.SessionPrompted
+			ses.States = ses.SuspendedStates()
+			svc.registerAwaits(ses)
+
+			if !prev.Suspended() {
+				svc.metrics.sessionSuspended(ses.WorkflowID)
+			}
 
 		case wfexec.SessionDelayed:
 			ses.SuspendedAt = now()
 			ses.Status = types.SessionSuspended
+			ses.States = ses.SuspendedStates()
+			svc.registerAwaits(ses)
+
+			if !prev.Suspended() {
+				svc.metrics.sessionSuspended(ses.WorkflowID)
+			}
 
 		case wfexec.SessionCompleted:
 			ses.SuspendedAt = nil
 			ses.CompletedAt = now()
 			ses.Status = types.SessionCompleted
+			ses.Output = s.Result()
+			ses.States = nil
+			ses.ApplyRetention()
+			svc.unregisterAwaits(ses.ID)
+
+			if !prev.Finished() {
+				svc.metrics.sessionFinished(ses.WorkflowID, false, ses.CompletedAt.Sub(ses.CreatedAt))
+				svc.subprocessFinished(ses, s.Result())
+			}
 
 		case wfexec.SessionFailed:
 			ses.SuspendedAt = nil
 			ses.CompletedAt = now()
 			ses.Error = state.Error()
 			ses.Status = types.SessionFailed
+			ses.States = nil
+			ses.ApplyRetention()
+			svc.unregisterAwaits(ses.ID)
+
+			if !prev.Finished() {
+				svc.metrics.sessionFinished(ses.WorkflowID, true, ses.CompletedAt.Sub(ses.CreatedAt))
+				svc.subprocessFinished(ses, nil)
+			}
 
 		default:
+			if s.StateSuspended(frame.StateID) {
+				// state got suspended while other states are still running
+				svc.registerAwaits(ses)
+			}
+
 			// force update on every 10 new frames but only when stacktrace is not nil
 			update = ses.Stacktrace != nil && len(ses.Stacktrace)%10 == 0
 		}
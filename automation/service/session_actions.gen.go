@@ -343,6 +343,26 @@ func SessionActionUndelete(props ...*sessionActionProps) *sessionAction {
 	return a
 }
 
+// SessionActionCancel returns "automation:session.cancel" action
+//
+// This function is auto-generated.
+//
+func SessionActionCancel(props ...*sessionActionProps) *sessionAction {
+	a := &sessionAction{
+		timestamp: time.Now(),
+		resource:  "automation:session",
+		action:    "cancel",
+		log:       "canceled {session}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -439,6 +459,36 @@ func SessionErrInvalidID(mm ...*sessionActionProps) *errors.Error {
 	return e
 }
 
+// SessionErrNotRunning returns "automation:session.notRunning" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func SessionErrNotRunning(mm ...*sessionActionProps) *errors.Error {
+	var p = &sessionActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("session is not running", nil),
+
+		errors.Meta("type", "notRunning"),
+		errors.Meta("resource", "automation:session"),
+
+		errors.Meta(sessionPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // SessionErrStaleData returns "automation:session.staleData" as *errors.Error
 //
 //
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/sqlite3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_sessionSubprocess(t *testing.T) {
+	const (
+		callerID     = 42
+		subprocessID = 43
+	)
+
+	tcc := []struct {
+		name string
+
+		// subprocess finishes before caller's state is registered as awaiting
+		finishedFirst bool
+
+		// subprocess error; caller should fail with it
+		err string
+	}{
+		{"completed", false, ""},
+		{"completed before registered", true, ""},
+		{"failed", false, "boom"},
+		{"failed before registered", true, "boom"},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+
+				ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+
+				svc = &session{
+					log:          zap.NewNop(),
+					mux:          &sync.RWMutex{},
+					pool:         make(map[uint64]*types.Session),
+					awaits:       make(map[uint64]map[uint64]uintptr),
+					awaitsMux:    &sync.Mutex{},
+					subprocesses: make(map[uint64]*subprocessCall),
+				}
+
+				g    = wfexec.NewGraph()
+				step = types.SubprocessStep("child", nil, nil, func(context.Context, string, uint64, *expr.Vars) (uint64, error) {
+					return subprocessID, nil
+				})
+
+				caller     = types.NewSession(wfexec.NewSession(ctx, g, wfexec.SetSessionID(callerID), wfexec.SetWorkerInterval(time.Millisecond)))
+				subprocess = &types.Session{ID: subprocessID, CallStack: []uint64{callerID}, Error: tc.err}
+			)
+
+			defer cancel()
+
+			step.SetID(1)
+			g.AddStep(step)
+			svc.pool[callerID] = caller
+
+			finish := func() {
+				svc.mux.RLock()
+				defer svc.mux.RUnlock()
+				svc.subprocessFinished(subprocess, &expr.Vars{})
+			}
+
+			req.NoError(caller.Exec(ctx, step, nil))
+			req.Eventually(func() bool { return len(caller.PendingAwaits()) == 1 }, time.Second, time.Millisecond)
+
+			if tc.finishedFirst {
+				finish()
+				req.Len(svc.subprocesses, 1)
+				svc.registerAwaits(caller)
+			} else {
+				svc.registerAwaits(caller)
+				req.Len(svc.subprocesses, 1)
+				finish()
+			}
+
+			_, status, err := caller.WaitResults(ctx)
+			if tc.err != "" {
+				req.EqualError(err, "subprocess failed: "+tc.err)
+			} else {
+				req.NoError(err)
+				req.Equal(wfexec.SessionCompleted, status)
+			}
+
+			req.Empty(svc.subprocesses)
+		})
+	}
+}
+
+func Test_sessionResumeOrphanedCallers(t *testing.T) {
+	tcc := []struct {
+		name string
+
+		// stored subprocess session; nil when missing
+		subprocess *types.Session
+
+		// expected error of the caller
+		err string
+	}{
+		{"completed", &types.Session{Status: types.SessionCompleted, Output: &expr.Vars{}}, ""},
+		{"failed", &types.Session{Status: types.SessionFailed, Error: "boom"}, "subprocess failed: boom"},
+		{"interrupted", &types.Session{Status: types.SessionStarted}, "subprocess failed: subprocess interrupted"},
+		{"missing", nil, "subprocess failed: could not restore subprocess: not found"},
+	}
+
+	for i, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+
+				callerID     = uint64(100 + i*2)
+				subprocessID = callerID + 1
+
+				ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+
+				svc = &session{
+					log:          zap.NewNop(),
+					mux:          &sync.RWMutex{},
+					pool:         make(map[uint64]*types.Session),
+					awaits:       make(map[uint64]map[uint64]uintptr),
+					awaitsMux:    &sync.Mutex{},
+					subprocesses: make(map[uint64]*subprocessCall),
+				}
+
+				g    = wfexec.NewGraph()
+				step = types.SubprocessStep("child", nil, nil, func(context.Context, string, uint64, *expr.Vars) (uint64, error) {
+					return subprocessID, nil
+				})
+
+				caller = types.NewSession(wfexec.NewSession(ctx, g, wfexec.SetSessionID(callerID), wfexec.SetWorkerInterval(time.Millisecond)))
+				err    error
+			)
+
+			defer cancel()
+
+			svc.store, err = sqlite3.ConnectInMemory(ctx)
+			req.NoError(err)
+			req.NoError(store.Upgrade(ctx, zap.NewNop(), svc.store))
+
+			if tc.subprocess != nil {
+				tc.subprocess.ID = subprocessID
+				tc.subprocess.CreatedAt = *now()
+				req.NoError(store.CreateAutomationSession(ctx, svc.store, tc.subprocess))
+			}
+
+			step.SetID(1)
+			g.AddStep(step)
+			svc.pool[callerID] = caller
+
+			req.NoError(caller.Exec(ctx, step, nil))
+			req.Eventually(func() bool { return len(caller.PendingAwaits()) == 1 }, time.Second, time.Millisecond)
+
+			// caller is restored, subprocess is not
+			svc.registerAwaits(caller)
+			svc.resumeOrphanedCallers(ctx)
+
+			_, status, err := caller.WaitResults(ctx)
+			if tc.err != "" {
+				req.EqualError(err, tc.err)
+			} else {
+				req.NoError(err)
+				req.Equal(wfexec.SessionCompleted, status)
+			}
+
+			req.Empty(svc.subprocesses)
+
+			if tc.subprocess != nil {
+				stored, err := store.LookupAutomationSessionByID(ctx, svc.store, subprocessID)
+				req.NoError(err)
+				req.True(stored.Finished())
+			}
+		})
+	}
+}
+
+func Test_sessionCancelSubprocesses(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		svc = &session{
+			log:          zap.NewNop(),
+			mux:          &sync.RWMutex{},
+			pool:         make(map[uint64]*types.Session),
+			awaitsMux:    &sync.Mutex{},
+			subprocesses: make(map[uint64]*subprocessCall),
+		}
+
+		mkSession = func(ID uint64, callStack ...uint64) *types.Session {
+			ses := types.NewSession(wfexec.NewSession(ctx, wfexec.NewGraph(), wfexec.SetSessionID(ID)))
+			ses.CallStack = callStack
+			svc.pool[ID] = ses
+			return ses
+		}
+	)
+
+	defer cancel()
+
+	var (
+		caller     = mkSession(42)
+		subprocess = mkSession(43, 42)
+		nested     = mkSession(44, 42, 43)
+		unrelated  = mkSession(45)
+		finished   = mkSession(46, 42)
+	)
+
+	finished.Status = types.SessionCompleted
+
+	ss := svc.subprocessesOf(caller.ID)
+	req.Equal(types.SessionSet{subprocess, nested}, ss)
+
+	for _, ses := range append(types.SessionSet{caller}, ss...) {
+		svc.cancel(ses)
+	}
+
+	req.Equal(types.SessionFailed, caller.Status)
+	req.Equal(types.SessionFailed, subprocess.Status)
+	req.Equal(types.SessionFailed, nested.Status)
+	req.Equal(types.SessionStarted, unrelated.Status)
+
+	// canceled subprocesses have nobody to report to
+	req.Empty(svc.subprocesses)
+}
+
+func Test_sessionRestoreCallStack(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		svc = &session{
+			mux:          &sync.RWMutex{},
+			pool:         make(map[uint64]*types.Session),
+			awaitsMux:    &sync.Mutex{},
+			subprocesses: make(map[uint64]*subprocessCall),
+		}
+
+		restored = &types.Session{ID: 43}
+	)
+
+	// not a subprocess
+	svc.restoreCallStack(restored)
+	req.Nil(restored.CallStack)
+
+	svc.pool[42] = &types.Session{ID: 42, CallStack: []uint64{41}}
+	svc.subprocesses[43] = &subprocessCall{callerSessionID: 42}
+
+	svc.restoreCallStack(restored)
+	req.Equal([]uint64{41, 42}, restored.CallStack)
+	req.Equal([]uint64{41}, svc.pool[42].CallStack)
+}
+
+func Test_sessionWatchCleanup(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		svc = Session(zap.NewNop(), options.WorkflowOpt{SessionCleanupInterval: time.Millisecond})
+
+		spawned = make(chan struct{})
+		err     error
+	)
+
+	defer cancel()
+
+	svc.store, err = sqlite3.ConnectInMemory(ctx)
+	req.NoError(err)
+
+	svc.Watch(ctx)
+
+	// cleanup is waiting for the pool lock
+	svc.mux.Lock()
+	time.Sleep(10 * time.Millisecond)
+
+	// spawning of sessions must not wait for the cleanup
+	go func() {
+		svc.spawn(wfexec.NewGraph(), 42, false, 0, time.Time{})
+		close(spawned)
+	}()
+
+	select {
+	case <-spawned:
+	case <-time.After(time.Second):
+		t.Fatal("session spawning blocked by cleanup")
+	}
+
+	svc.mux.Unlock()
+}
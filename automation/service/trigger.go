@@ -40,6 +40,7 @@ type (
 
 		workflow *workflow
 		session  *session
+		webhooks *webhook
 
 		mux *sync.RWMutex
 	}
@@ -82,6 +83,7 @@ func Trigger(log *zap.Logger, opt options.WorkflowOpt) *trigger {
 		ac:        DefaultAccessControl,
 		session:   DefaultSession,
 		workflow:  DefaultWorkflow,
+		webhooks:  DefaultWebhook,
 		triggers:  make(map[uint64]uintptr),
 		reg:       make(map[uint64]map[uint64]uintptr),
 		mux:       &sync.RWMutex{},
@@ -124,6 +126,10 @@ func (svc *trigger) Search(ctx context.Context, filter types.TriggerFilter) (rr
 			return err
 		}
 
+		for i := range rr {
+			rr[i] = maskWebhookSecret(rr[i])
+		}
+
 		return nil
 	}()
 
@@ -148,6 +154,7 @@ func (svc *trigger) LookupByID(ctx context.Context, triggerID uint64) (res *type
 			return err
 		}
 
+		res = maskWebhookSecret(res)
 		return nil
 	})
 
@@ -184,6 +191,7 @@ func (svc *trigger) Create(ctx context.Context, new *types.Trigger) (res *types.
 			EventType:    new.EventType,
 			Constraints:  new.Constraints,
 			Input:        new.Input,
+			Webhook:      new.Webhook,
 			Labels:       new.Labels,
 			Meta:         new.Meta,
 			OwnedBy:      cUser,
@@ -191,6 +199,10 @@ func (svc *trigger) Create(ctx context.Context, new *types.Trigger) (res *types.
 			CreatedBy:    cUser,
 		}
 
+		if err = prepareWebhook(res); err != nil {
+			return
+		}
+
 		if err = store.CreateAutomationTrigger(ctx, s, res); err != nil {
 			return
 		}
@@ -210,15 +222,56 @@ func (svc *trigger) Create(ctx context.Context, new *types.Trigger) (res *types.
 }
 
 // Update modifies existing trigger resource in the store
+//
+// Webhook secret is returned only when it was changed
 func (svc *trigger) Update(ctx context.Context, upd *types.Trigger) (*types.Trigger, error) {
-	return svc.updater(ctx, upd.ID, TriggerActionUpdate, func(ctx context.Context, res *types.Trigger) (triggerChanges, error) {
+	var secret string
+
+	res, err := svc.updater(ctx, upd.ID, TriggerActionUpdate, func(ctx context.Context, res *types.Trigger) (triggerChanges, error) {
 		if err := svc.canManageTrigger(ctx, res, TriggerErrNotAllowedToUpdate()); err != nil {
 			return triggerUnchanged, err
 		}
 
+		if res.Webhook != nil {
+			secret = res.Webhook.Secret
+		}
+
 		handler := svc.handleUpdate(upd)
 		return handler(ctx, res)
 	})
+
+	if res != nil && res.Webhook != nil && res.Webhook.Secret == secret {
+		res = maskWebhookSecret(res)
+	}
+
+	return res, err
+}
+
+// RegenerateWebhookSecret generates and returns new secret for the webhook trigger
+func (svc *trigger) RegenerateWebhookSecret(ctx context.Context, triggerID uint64) (secret string, err error) {
+	_, err = svc.updater(ctx, triggerID, TriggerActionRegenerateWebhookSecret, func(ctx context.Context, res *types.Trigger) (triggerChanges, error) {
+		if err := svc.canManageTrigger(ctx, res, TriggerErrNotAllowedToUpdate()); err != nil {
+			return triggerUnchanged, err
+		}
+
+		if !res.IsWebhook() || res.Webhook == nil {
+			return triggerUnchanged, TriggerErrNotWebhook()
+		}
+
+		if err := regenerateWebhookSecret(res.Webhook); err != nil {
+			return triggerUnchanged, err
+		}
+
+		secret = res.Webhook.Secret
+		res.UpdatedAt = now()
+		return triggerChanged, nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
 }
 
 func (svc *trigger) DeleteByID(ctx context.Context, triggerID uint64) error {
@@ -258,6 +311,12 @@ func (svc trigger) updater(ctx context.Context, triggerID uint64, action func(..
 			if err = store.UpdateAutomationTrigger(ctx, svc.store, res); err != nil {
 				return err
 			}
+
+			// make sure changes (event type, webhook config, removal...)
+			// are reflected in the registered handlers
+			if err = svc.updateTriggerRegistration(ctx, res); err != nil {
+				return err
+			}
 		}
 
 		if changes&triggerLabelsChanged > 0 {
@@ -326,6 +385,22 @@ func (svc trigger) handleUpdate(upd *types.Trigger) triggerUpdateHandler {
 			}
 		}
 
+		if upd.Webhook != nil {
+			if upd.Webhook.Secret == "" && res.Webhook != nil {
+				// keep existing secret unless explicitly changed
+				upd.Webhook.Secret = res.Webhook.Secret
+			}
+
+			if !reflect.DeepEqual(upd.Webhook, res.Webhook) {
+				changes |= triggerChanged
+				res.Webhook = upd.Webhook
+			}
+		}
+
+		if err = prepareWebhook(res); err != nil {
+			return triggerUnchanged, err
+		}
+
 		if res.OwnedBy != upd.OwnedBy {
 			// @todo need to check against access control if current user can modify owner
 			changes |= triggerChanged
@@ -437,6 +512,13 @@ func (svc *trigger) registerWorkflow(ctx context.Context, wf *types.Workflow, tt
 		}
 	}
 
+	if wf.PublishedRevision > 0 {
+		// triggers are bound only to the published revision of the workflow
+		if wf, err = loadPublishedWorkflow(ctx, svc.store, wf, wf.PublishedRevision); err != nil {
+			return err
+		}
+	}
+
 	svc.registerTriggers(wf, runAs, tt...)
 	return nil
 }
@@ -454,8 +536,8 @@ func (svc *trigger) registerTriggers(wf *types.Workflow, runAs auth.Identifiable
 				WithOptions(zap.AddStacktrace(zap.DPanicLevel)).
 				With(zap.Uint64("workflowID", wf.ID))
 
-			// register only enabled, undeleted workflows
-		registerWorkflow = wf.Enabled || wf.DeletedAt == nil
+			// register only enabled, undeleted and published workflows
+		registerWorkflow = (wf.Enabled || wf.DeletedAt == nil) && wf.PublishedRevision > 0
 	)
 
 	// convert only registerable and issuless workflwos
@@ -463,6 +545,7 @@ func (svc *trigger) registerTriggers(wf *types.Workflow, runAs auth.Identifiable
 		// Convert workflow only when valid (no issues, enable, not delete)
 		if g, issues = Convert(svc.workflow, wf); len(issues) > 0 {
 			wfLog.Error("failed to convert workflow to graph", zap.Error(issues))
+			wf.Issues = issues
 			g = nil
 		}
 	}
@@ -481,11 +564,24 @@ func (svc *trigger) registerTriggers(wf *types.Workflow, runAs auth.Identifiable
 			svc.eventbus.Unregister(ptr)
 		}
 
+		svc.webhooks.unregister(t.ID)
+		delete(svc.reg[wf.ID], t.ID)
+
 		// do not register disabled or deleted triggers
 		if !registerWorkflow || !t.Enabled || t.DeletedAt != nil {
 			continue
 		}
 
+		if t.IsWebhook() {
+			// webhooks are not dispatched through eventbus but
+			// called directly from the webhook HTTP endpoint
+			svc.webhooks.register(t, wf, g, runAs)
+			svc.reg[wf.ID][t.ID] = 0
+
+			log.Debug("webhook trigger registered")
+			continue
+		}
+
 		var (
 			cnstr eventbus.ConstraintMatcher
 			ops   = make([]eventbus.HandlerRegOp, 0, len(t.Constraints)+2)
@@ -540,6 +636,7 @@ func (svc *trigger) unregisterWorkflows(wwf ...*types.Workflow) {
 	for _, wf := range wwf {
 		for triggerID, ptr := range svc.reg[wf.ID] {
 			svc.eventbus.Unregister(ptr)
+			svc.webhooks.unregister(triggerID)
 			svc.log.Debug("trigger unregistered", zap.Uint64("triggerID", triggerID), zap.Uint64("workflowID", wf.ID))
 			delete(svc.triggers, wf.ID)
 		}
@@ -559,6 +656,7 @@ func (svc *trigger) unregisterTriggers(tt ...*types.Trigger) {
 
 		if ptr, has := svc.reg[t.WorkflowID][t.ID]; has {
 			svc.eventbus.Unregister(ptr)
+			svc.webhooks.unregister(t.ID)
 			svc.log.Debug("trigger unregistered", zap.Uint64("triggerID", t.ID), zap.Uint64("workflowID", t.WorkflowID))
 			delete(svc.triggers, t.ID)
 		}
@@ -596,7 +694,9 @@ func makeWorkflowHandler(s *session, t *types.Trigger, wf *types.Workflow, g *wf
 
 		wait, err = s.Start(g, runAs, types.SessionStartParams{
 			WorkflowID:   wf.ID,
+			Revision:     wf.PublishedRevision,
 			KeepFor:      wf.KeepSessions,
+			Timeout:      wf.Timeout,
 			Trace:        wf.Trace,
 			Input:        scope,
 			StepID:       t.StepID,
@@ -343,6 +343,26 @@ func TriggerActionUndelete(props ...*triggerActionProps) *triggerAction {
 	return a
 }
 
+// TriggerActionRegenerateWebhookSecret returns "automation:trigger.regenerateWebhookSecret" action
+//
+// This function is auto-generated.
+//
+func TriggerActionRegenerateWebhookSecret(props ...*triggerActionProps) *triggerAction {
+	a := &triggerAction{
+		timestamp: time.Now(),
+		resource:  "automation:trigger",
+		action:    "regenerateWebhookSecret",
+		log:       "regenerated webhook secret for {trigger}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -661,6 +681,96 @@ func TriggerErrNotAllowedToUndelete(mm ...*triggerActionProps) *errors.Error {
 	return e
 }
 
+// TriggerErrInvalidWebhookSignature returns "automation:trigger.invalidWebhookSignature" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func TriggerErrInvalidWebhookSignature(mm ...*triggerActionProps) *errors.Error {
+	var p = &triggerActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid webhook signature scheme", nil),
+
+		errors.Meta("type", "invalidWebhookSignature"),
+		errors.Meta("resource", "automation:trigger"),
+
+		errors.Meta(triggerPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// TriggerErrInvalidWebhookAllowedIP returns "automation:trigger.invalidWebhookAllowedIP" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func TriggerErrInvalidWebhookAllowedIP(mm ...*triggerActionProps) *errors.Error {
+	var p = &triggerActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid IP address or network in webhook allow-list", nil),
+
+		errors.Meta("type", "invalidWebhookAllowedIP"),
+		errors.Meta("resource", "automation:trigger"),
+
+		errors.Meta(triggerPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// TriggerErrNotWebhook returns "automation:trigger.notWebhook" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func TriggerErrNotWebhook(mm ...*triggerActionProps) *errors.Error {
+	var p = &triggerActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("trigger is not a webhook", nil),
+
+		errors.Meta("type", "notWebhook"),
+		errors.Meta("resource", "automation:trigger"),
+
+		errors.Meta(triggerPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
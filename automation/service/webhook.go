@@ -0,0 +1,504 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+type (
+	webhook struct {
+		actionlog actionlog.Recorder
+		session   *session
+		log       *zap.Logger
+
+		// registered webhook triggers (trigger ID => webhook)
+		hooks map[uint64]*registeredWebhook
+		mux   *sync.RWMutex
+	}
+
+	registeredWebhook struct {
+		trigger  *atypes.Trigger
+		workflow *atypes.Workflow
+
+		// nil when workflow could not be converted
+		graph *wfexec.Graph
+		runAs auth.Identifiable
+	}
+)
+
+const (
+	// WebhookBaseURL is used when mounting webhook endpoint
+	WebhookBaseURL = "/webhooks"
+
+	webhookGithubHeader = "X-Hub-Signature-256"
+	webhookStripeHeader = "Stripe-Signature"
+
+	// how old can be timestamp in the stripe signature
+	webhookSignatureTolerance = time.Minute * 5
+
+	webhookMaxBodySize = 1 << 20 // 1MB
+
+	webhookSecretLength = 64
+)
+
+func Webhook(log *zap.Logger) *webhook {
+	return &webhook{
+		log:       log,
+		actionlog: DefaultActionlog,
+		session:   DefaultSession,
+		hooks:     make(map[uint64]*registeredWebhook),
+		mux:       &sync.RWMutex{},
+	}
+}
+
+// ProcessRequest function is used directly in the HTTP controller
+//
+// Request is verified against webhook trigger configuration (allowed IPs, signature)
+// and then used as an input for the workflow session
+func (svc *webhook) ProcessRequest(w http.ResponseWriter, r *http.Request, triggerID uint64) {
+	var (
+		ctx = r.Context()
+		wap = &webhookActionProps{
+			trigger:       &atypes.Trigger{ID: triggerID},
+			remoteAddress: r.RemoteAddr,
+			method:        r.Method,
+		}
+	)
+
+	err := func() error {
+		defer r.Body.Close()
+
+		svc.mux.RLock()
+		hook := svc.hooks[triggerID]
+		svc.mux.RUnlock()
+
+		if hook == nil {
+			return WebhookErrNotFound(wap)
+		}
+
+		wap.setTrigger(hook.trigger)
+
+		if !webhookAllowedAddress(hook.trigger.Webhook.AllowedIPs, r.RemoteAddr) {
+			return WebhookErrRemoteAddressNotAllowed(wap)
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBodySize))
+		if err != nil {
+			return WebhookErrContentLengthExceedsMaxAllowedSize(wap)
+		}
+
+		if err = verifyWebhookSignature(hook.trigger.Webhook, r.Header, body, *now(), wap); err != nil {
+			return err
+		}
+
+		return svc.process(w, r, hook, body, wap)
+	}()
+
+	_ = svc.recordAction(ctx, wap, WebhookActionRequest, err)
+	if err != nil {
+		// webhook callers (unlike API clients) rely on HTTP status codes
+		http.Error(w, err.Error(), webhookErrorStatus(err))
+	}
+}
+
+// starts workflow session and (for synchronous webhooks) writes session results to response
+func (svc *webhook) process(w http.ResponseWriter, r *http.Request, hook *registeredWebhook, body []byte, wap *webhookActionProps) (err error) {
+	var (
+		t     = hook.trigger
+		wf    = hook.workflow
+		runAs = hook.runAs
+		wait  WaitFn
+		scope = wf.Scope.Merge(t.Input)
+	)
+
+	if hook.graph == nil {
+		return WebhookErrInvalidWorkflow(wap).Wrap(wf.Issues)
+	}
+
+	_ = scope.AssignFieldValue("eventType", expr.Must(expr.NewString(t.EventType)))
+	_ = scope.AssignFieldValue("resourceType", expr.Must(expr.NewString(t.ResourceType)))
+	_ = scope.AssignFieldValue("request", webhookRequestVars(r, body))
+
+	if runAs == nil {
+		// webhook requests are not authenticated
+		runAs = auth.NewIdentity(0)
+	}
+
+	wait, err = svc.session.Start(hook.graph, runAs, atypes.SessionStartParams{
+		WorkflowID:   wf.ID,
+		Revision:     wf.PublishedRevision,
+		KeepFor:      wf.KeepSessions,
+		Timeout:      wf.Timeout,
+		Trace:        wf.Trace,
+		Input:        scope,
+		StepID:       t.StepID,
+		EventType:    t.EventType,
+		ResourceType: t.ResourceType,
+	})
+
+	if err != nil {
+		return WebhookErrProcessingError(wap).Wrap(err)
+	}
+
+	if !t.Webhook.Sync || wf.CheckDeferred() {
+		// do not wait for the workflow to complete
+		w.WriteHeader(http.StatusAccepted)
+		return nil
+	}
+
+	results, status, err := wait(r.Context())
+	if err != nil {
+		return WebhookErrProcessingError(wap).Wrap(err)
+	}
+
+	if status == wfexec.SessionFailed {
+		return WebhookErrWorkflowFailed(wap)
+	}
+
+	return writeWebhookResponse(w, results)
+}
+
+// registers webhook trigger
+//
+// Graph can be nil when workflow is invalid;
+// requests to such webhooks are then refused
+func (svc *webhook) register(t *atypes.Trigger, wf *atypes.Workflow, g *wfexec.Graph, runAs auth.Identifiable) {
+	defer svc.mux.Unlock()
+	svc.mux.Lock()
+
+	svc.hooks[t.ID] = &registeredWebhook{
+		trigger:  t,
+		workflow: wf,
+		graph:    g,
+		runAs:    runAs,
+	}
+}
+
+func (svc *webhook) unregister(triggerIDs ...uint64) {
+	defer svc.mux.Unlock()
+	svc.mux.Lock()
+
+	for _, triggerID := range triggerIDs {
+		delete(svc.hooks, triggerID)
+	}
+}
+
+// prepares webhook configuration before trigger is stored
+//
+// Secret is generated when not set and allowed IPs are validated
+func prepareWebhook(t *atypes.Trigger) error {
+	if !t.IsWebhook() {
+		t.Webhook = nil
+		return nil
+	}
+
+	if t.Webhook == nil {
+		t.Webhook = &atypes.TriggerWebhook{}
+	}
+
+	t.EventType = atypes.WebhookEventType
+
+	switch t.Webhook.Signature {
+	case "":
+		t.Webhook.Signature = atypes.WebhookSignatureGithub
+	case atypes.WebhookSignatureGithub, atypes.WebhookSignatureStripe, atypes.WebhookSignatureNone:
+	default:
+		return TriggerErrInvalidWebhookSignature()
+	}
+
+	for _, ip := range t.Webhook.AllowedIPs {
+		if parseAllowedIP(ip) == nil {
+			return TriggerErrInvalidWebhookAllowedIP()
+		}
+	}
+
+	if t.Webhook.Secret == "" {
+		return regenerateWebhookSecret(t.Webhook)
+	}
+
+	return nil
+}
+
+// generates new random webhook secret
+func regenerateWebhookSecret(wh *atypes.TriggerWebhook) error {
+	var b = make([]byte, webhookSecretLength/2)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("could not generate webhook secret: %w", err)
+	}
+
+	wh.Secret = hex.EncodeToString(b)
+	return nil
+}
+
+// returns copy of the trigger without the webhook secret
+//
+// Secret is exposed only when it is created or regenerated
+func maskWebhookSecret(t *atypes.Trigger) *atypes.Trigger {
+	if t == nil || t.Webhook == nil || t.Webhook.Secret == "" {
+		return t
+	}
+
+	var (
+		c  = *t
+		wh = *t.Webhook
+	)
+
+	wh.Secret = ""
+	c.Webhook = &wh
+	return &c
+}
+
+// verifies request signature with the configured scheme
+//
+// github: "sha256=<hex hmac of body>"
+// stripe: "t=<timestamp>,v1=<hex hmac of timestamp.body>"
+func verifyWebhookSignature(wh *atypes.TriggerWebhook, h http.Header, body []byte, now time.Time, wap *webhookActionProps) error {
+	var (
+		header = wh.Header
+		sig    string
+	)
+
+	if wh.Signature == atypes.WebhookSignatureNone {
+		return nil
+	}
+
+	if header == "" {
+		if wh.Signature == atypes.WebhookSignatureStripe {
+			header = webhookStripeHeader
+		} else {
+			header = webhookGithubHeader
+		}
+	}
+
+	if sig = h.Get(header); sig == "" {
+		return WebhookErrMissingSignature(wap)
+	}
+
+	if wh.Signature != atypes.WebhookSignatureStripe {
+		if !validWebhookHmac(wh.Secret, body, strings.TrimPrefix(sig, "sha256=")) {
+			return WebhookErrInvalidSignature(wap)
+		}
+
+		return nil
+	}
+
+	var (
+		ts         string
+		signatures []string
+	)
+
+	for _, part := range strings.Split(sig, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+
+	if ts == "" || len(signatures) == 0 {
+		return WebhookErrMissingSignature(wap)
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return WebhookErrInvalidSignature(wap)
+	}
+
+	if d := now.Sub(time.Unix(unix, 0)); d > webhookSignatureTolerance || d < -webhookSignatureTolerance {
+		return WebhookErrSignatureExpired(wap)
+	}
+
+	payload := append([]byte(ts+"."), body...)
+	for _, s := range signatures {
+		if validWebhookHmac(wh.Secret, payload, s) {
+			return nil
+		}
+	}
+
+	return WebhookErrInvalidSignature(wap)
+}
+
+func validWebhookHmac(secret string, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// checks if remote address is in the list of allowed IPs and networks
+//
+// Empty list allows all addresses
+func webhookAllowedAddress(allowed []string, remoteAddr string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+
+	ip := net.ParseIP(remoteAddr)
+	if ip == nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		if n := parseAllowedIP(a); n != nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parses IP address or network (CIDR); single address is converted into a network
+func parseAllowedIP(s string) *net.IPNet {
+	if _, n, err := net.ParseCIDR(s); err == nil {
+		return n
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// converts HTTP request into variables that are passed to the workflow as "request"
+func webhookRequestVars(r *http.Request, body []byte) *expr.Vars {
+	return expr.RVars{
+		"method":        expr.Must(expr.NewString(r.Method)),
+		"path":          expr.Must(expr.NewString(r.URL.Path)),
+		"headers":       expr.Must(expr.NewKVV(map[string][]string(r.Header))),
+		"query":         expr.Must(expr.NewKVV(map[string][]string(r.URL.Query()))),
+		"body":          expr.Must(expr.NewString(string(body))),
+		"remoteAddress": expr.Must(expr.NewString(r.RemoteAddr)),
+	}.Vars()
+}
+
+// writes workflow results to HTTP response
+//
+// Results can contain responseStatus, responseHeaders (KV or KVV) and responseBody;
+// strings and readers are written as-is, everything else is encoded as JSON.
+//
+// Errors are returned only when results can not be used for the response;
+// once the response is started, write errors are ignored
+func writeWebhookResponse(w http.ResponseWriter, results *expr.Vars) (err error) {
+	var (
+		status  = http.StatusOK
+		headers map[string][]string
+		body    interface{}
+	)
+
+	if results != nil {
+		if results.Has("responseStatus") {
+			v, _ := results.Select("responseStatus")
+			if status, err = cast.ToIntE(expr.UntypedValue(v)); err != nil {
+				return fmt.Errorf("invalid response status: %w", err)
+			}
+		}
+
+		if results.Has("responseHeaders") {
+			v, _ := results.Select("responseHeaders")
+			if headers, err = cast.ToStringMapStringSliceE(expr.UntypedValue(v)); err != nil {
+				return fmt.Errorf("invalid response headers: %w", err)
+			}
+		}
+
+		if results.Has("responseBody") {
+			v, _ := results.Select("responseBody")
+			body = expr.UntypedValue(v)
+		}
+	}
+
+	for k, vv := range headers {
+		for _, v := range vv {
+			w.Header().Add(k, v)
+		}
+	}
+
+	switch c := body.(type) {
+	case nil:
+		w.WriteHeader(status)
+	case string:
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, c)
+	case io.Reader:
+		if f, ok := c.(atypes.File); ok && w.Header().Get("Content-Type") == "" && f.ContentType() != "" {
+			w.Header().Set("Content-Type", f.ContentType())
+		}
+
+		w.WriteHeader(status)
+		_, _ = io.Copy(w, c)
+	default:
+		var buf []byte
+		if buf, err = json.Marshal(c); err != nil {
+			return fmt.Errorf("could not encode response body: %w", err)
+		}
+
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write(buf)
+	}
+
+	return nil
+}
+
+func webhookErrorStatus(err error) int {
+	var e *errors.Error
+	if !errors.As(err, &e) {
+		return http.StatusInternalServerError
+	}
+
+	switch e.Meta().AsString("type") {
+	case "notFound":
+		return http.StatusNotFound
+	case "remoteAddressNotAllowed":
+		return http.StatusForbidden
+	case "missingSignature", "invalidSignature", "signatureExpired":
+		return http.StatusUnauthorized
+	case "contentLengthExceedsMaxAllowedSize":
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,551 @@
+package service
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// automation/service/webhook_actions.yaml
+
+import (
+	"context"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"strings"
+	"time"
+)
+
+type (
+	webhookActionProps struct {
+		trigger       *types.Trigger
+		remoteAddress string
+		method        string
+	}
+
+	webhookAction struct {
+		timestamp time.Time
+		resource  string
+		action    string
+		log       string
+		severity  actionlog.Severity
+
+		// prefix for error when action fails
+		errorMessage string
+
+		props *webhookActionProps
+	}
+
+	webhookLogMetaKey   struct{}
+	webhookPropsMetaKey struct{}
+)
+
+var (
+	// just a placeholder to cover template cases w/o fmt package use
+	_ = fmt.Println
+)
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Props methods
+// setTrigger updates webhookActionProps's trigger
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *webhookActionProps) setTrigger(trigger *types.Trigger) *webhookActionProps {
+	p.trigger = trigger
+	return p
+}
+
+// setRemoteAddress updates webhookActionProps's remoteAddress
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *webhookActionProps) setRemoteAddress(remoteAddress string) *webhookActionProps {
+	p.remoteAddress = remoteAddress
+	return p
+}
+
+// setMethod updates webhookActionProps's method
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *webhookActionProps) setMethod(method string) *webhookActionProps {
+	p.method = method
+	return p
+}
+
+// Serialize converts webhookActionProps to actionlog.Meta
+//
+// This function is auto-generated.
+//
+func (p webhookActionProps) Serialize() actionlog.Meta {
+	var (
+		m = make(actionlog.Meta)
+	)
+
+	if p.trigger != nil {
+		m.Set("trigger.ID", p.trigger.ID, true)
+		m.Set("trigger.workflowID", p.trigger.WorkflowID, true)
+	}
+	m.Set("remoteAddress", p.remoteAddress, true)
+	m.Set("method", p.method, true)
+
+	return m
+}
+
+// tr translates string and replaces meta value placeholder with values
+//
+// This function is auto-generated.
+//
+func (p webhookActionProps) Format(in string, err error) string {
+	var (
+		pairs = []string{"{err}"}
+		// first non-empty string
+		fns = func(ii ...interface{}) string {
+			for _, i := range ii {
+				if s := fmt.Sprintf("%v", i); len(s) > 0 {
+					return s
+				}
+			}
+
+			return ""
+		}
+	)
+
+	if err != nil {
+		pairs = append(pairs, err.Error())
+	} else {
+		pairs = append(pairs, "nil")
+	}
+
+	if p.trigger != nil {
+		// replacement for "{trigger}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{trigger}",
+			fns(
+				p.trigger.ID,
+				p.trigger.WorkflowID,
+			),
+		)
+		pairs = append(pairs, "{trigger.ID}", fns(p.trigger.ID))
+		pairs = append(pairs, "{trigger.workflowID}", fns(p.trigger.WorkflowID))
+	}
+	pairs = append(pairs, "{remoteAddress}", fns(p.remoteAddress))
+	pairs = append(pairs, "{method}", fns(p.method))
+	return strings.NewReplacer(pairs...).Replace(in)
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action methods
+
+// String returns loggable description as string
+//
+// This function is auto-generated.
+//
+func (a *webhookAction) String() string {
+	var props = &webhookActionProps{}
+
+	if a.props != nil {
+		props = a.props
+	}
+
+	return props.Format(a.log, nil)
+}
+
+func (e *webhookAction) ToAction() *actionlog.Action {
+	return &actionlog.Action{
+		Resource:    e.resource,
+		Action:      e.action,
+		Severity:    e.severity,
+		Description: e.String(),
+		Meta:        e.props.Serialize(),
+	}
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action constructors
+
+// WebhookActionRequest returns "automation:webhook.request" action
+//
+// This function is auto-generated.
+//
+func WebhookActionRequest(props ...*webhookActionProps) *webhookAction {
+	a := &webhookAction{
+		timestamp: time.Now(),
+		resource:  "automation:webhook",
+		action:    "request",
+		log:       "webhook request processed",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Error constructors
+
+// WebhookErrGeneric returns "automation:webhook.generic" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrGeneric(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("failed to complete request due to internal error", nil),
+
+		errors.Meta("type", "generic"),
+		errors.Meta("resource", "automation:webhook"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(webhookLogMetaKey{}, "{err}"),
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrNotFound returns "automation:webhook.notFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrNotFound(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("webhook not found", nil),
+
+		errors.Meta("type", "notFound"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrRemoteAddressNotAllowed returns "automation:webhook.remoteAddressNotAllowed" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrRemoteAddressNotAllowed(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("remote address not allowed", nil),
+
+		errors.Meta("type", "remoteAddressNotAllowed"),
+		errors.Meta("resource", "automation:webhook"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(webhookLogMetaKey{}, "webhook request from {remoteAddress} denied"),
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrMissingSignature returns "automation:webhook.missingSignature" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrMissingSignature(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("missing webhook signature", nil),
+
+		errors.Meta("type", "missingSignature"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrInvalidSignature returns "automation:webhook.invalidSignature" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrInvalidSignature(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid webhook signature", nil),
+
+		errors.Meta("type", "invalidSignature"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrSignatureExpired returns "automation:webhook.signatureExpired" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrSignatureExpired(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("webhook signature expired", nil),
+
+		errors.Meta("type", "signatureExpired"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrContentLengthExceedsMaxAllowedSize returns "automation:webhook.contentLengthExceedsMaxAllowedSize" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrContentLengthExceedsMaxAllowedSize(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("content length exceeds max size limit", nil),
+
+		errors.Meta("type", "contentLengthExceedsMaxAllowedSize"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrInvalidWorkflow returns "automation:webhook.invalidWorkflow" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrInvalidWorkflow(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("webhook workflow is invalid", nil),
+
+		errors.Meta("type", "invalidWorkflow"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrWorkflowFailed returns "automation:webhook.workflowFailed" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrWorkflowFailed(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("webhook workflow failed", nil),
+
+		errors.Meta("type", "workflowFailed"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WebhookErrProcessingError returns "automation:webhook.processingError" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WebhookErrProcessingError(mm ...*webhookActionProps) *errors.Error {
+	var p = &webhookActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("webhook request process error", nil),
+
+		errors.Meta("type", "processingError"),
+		errors.Meta("resource", "automation:webhook"),
+
+		errors.Meta(webhookPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+
+// recordAction is a service helper function wraps function that can return error
+//
+// It will wrap unrecognized/internal errors with generic errors.
+//
+// This function is auto-generated.
+//
+func (svc webhook) recordAction(ctx context.Context, props *webhookActionProps, actionFn func(...*webhookActionProps) *webhookAction, err error) error {
+	if svc.actionlog == nil || actionFn == nil {
+		// action log disabled or no action fn passed, return error as-is
+		return err
+	} else if err == nil {
+		// action completed w/o error, record it
+		svc.actionlog.Record(ctx, actionFn(props).ToAction())
+		return nil
+	}
+
+	a := actionFn(props).ToAction()
+
+	// Extracting error information and recording it as action
+	a.Error = err.Error()
+
+	switch c := err.(type) {
+	case *errors.Error:
+		m := c.Meta()
+
+		a.Error = err.Error()
+		a.Severity = actionlog.Severity(m.AsInt("severity"))
+		a.Description = props.Format(m.AsString(webhookLogMetaKey{}), err)
+
+		if p, has := m[webhookPropsMetaKey{}]; has {
+			a.Meta = p.(*webhookActionProps).Serialize()
+		}
+
+		svc.actionlog.Record(ctx, a)
+	default:
+		svc.actionlog.Record(ctx, a)
+	}
+
+	// Original error is passed on
+	return err
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testWebhookHmac(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func Test_verifyWebhookSignature(t *testing.T) {
+	var (
+		body   = `{"foo":"bar"}`
+		secret = "s3cr3t"
+		now    = time.Unix(1600000000, 0)
+		ts     = fmt.Sprintf("%d", now.Unix())
+
+		github = &types.TriggerWebhook{Secret: secret, Signature: types.WebhookSignatureGithub}
+		stripe = &types.TriggerWebhook{Secret: secret, Signature: types.WebhookSignatureStripe}
+		custom = &types.TriggerWebhook{Secret: secret, Signature: types.WebhookSignatureGithub, Header: "X-Signature"}
+		none   = &types.TriggerWebhook{Signature: types.WebhookSignatureNone}
+
+		tests = []struct {
+			name    string
+			wh      *types.TriggerWebhook
+			header  http.Header
+			wantErr string
+		}{
+			{
+				name:   "github",
+				wh:     github,
+				header: http.Header{"X-Hub-Signature-256": {"sha256=" + testWebhookHmac(secret, body)}},
+			},
+			{
+				name:    "github missing signature",
+				wh:      github,
+				header:  http.Header{},
+				wantErr: "missing webhook signature",
+			},
+			{
+				name:    "github invalid signature",
+				wh:      github,
+				header:  http.Header{"X-Hub-Signature-256": {"sha256=" + testWebhookHmac("foo", body)}},
+				wantErr: "invalid webhook signature",
+			},
+			{
+				name:    "github malformed signature",
+				wh:      github,
+				header:  http.Header{"X-Hub-Signature-256": {"sha256=not-hex"}},
+				wantErr: "invalid webhook signature",
+			},
+			{
+				name:   "custom header",
+				wh:     custom,
+				header: http.Header{"X-Signature": {testWebhookHmac(secret, body)}},
+			},
+			{
+				name:   "stripe",
+				wh:     stripe,
+				header: http.Header{"Stripe-Signature": {"t=" + ts + ",v1=" + testWebhookHmac("foo", ts+"."+body) + ",v1=" + testWebhookHmac(secret, ts+"."+body)}},
+			},
+			{
+				name:    "stripe without timestamp",
+				wh:      stripe,
+				header:  http.Header{"Stripe-Signature": {"v1=" + testWebhookHmac(secret, ts+"."+body)}},
+				wantErr: "missing webhook signature",
+			},
+			{
+				name:    "stripe signed body only",
+				wh:      stripe,
+				header:  http.Header{"Stripe-Signature": {"t=" + ts + ",v1=" + testWebhookHmac(secret, body)}},
+				wantErr: "invalid webhook signature",
+			},
+			{
+				name:    "stripe expired",
+				wh:      stripe,
+				header:  http.Header{"Stripe-Signature": {"t=1500000000,v1=" + testWebhookHmac(secret, "1500000000."+body)}},
+				wantErr: "webhook signature expired",
+			},
+			{
+				name:   "none",
+				wh:     none,
+				header: http.Header{},
+			},
+		}
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyWebhookSignature(tt.wh, tt.header, []byte(body), now, &webhookActionProps{})
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_webhookAllowedAddress(t *testing.T) {
+	var (
+		req     = require.New(t)
+		allowed = []string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"}
+	)
+
+	req.True(webhookAllowedAddress(nil, "1.2.3.4:1234"))
+	req.True(webhookAllowedAddress(allowed, "10.1.2.3:1234"))
+	req.True(webhookAllowedAddress(allowed, "192.168.1.1"))
+	req.True(webhookAllowedAddress(allowed, "[2001:db8::1]:443"))
+	req.False(webhookAllowedAddress(allowed, "192.168.1.2:1234"))
+	req.False(webhookAllowedAddress(allowed, "invalid"))
+}
+
+func Test_prepareWebhook(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			tr  = &types.Trigger{ResourceType: types.WebhookResourceType}
+		)
+
+		req.NoError(prepareWebhook(tr))
+		req.Equal(types.WebhookEventType, tr.EventType)
+		req.Equal(types.WebhookSignatureGithub, tr.Webhook.Signature)
+		req.Len(tr.Webhook.Secret, webhookSecretLength)
+		req.Regexp(`^[0-9a-f]+$`, tr.Webhook.Secret)
+
+		// secrets are not predictable
+		other := &types.Trigger{ResourceType: types.WebhookResourceType}
+		req.NoError(prepareWebhook(other))
+		req.NotEqual(tr.Webhook.Secret, other.Webhook.Secret)
+	})
+
+	t.Run("secret is kept", func(t *testing.T) {
+		tr := &types.Trigger{ResourceType: types.WebhookResourceType, Webhook: &types.TriggerWebhook{Secret: "foo"}}
+		require.NoError(t, prepareWebhook(tr))
+		require.Equal(t, "foo", tr.Webhook.Secret)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tr := &types.Trigger{ResourceType: types.WebhookResourceType, Webhook: &types.TriggerWebhook{Signature: "foo"}}
+		require.EqualError(t, prepareWebhook(tr), "invalid webhook signature scheme")
+
+		tr = &types.Trigger{ResourceType: types.WebhookResourceType, Webhook: &types.TriggerWebhook{AllowedIPs: []string{"10.0.0.0/33"}}}
+		require.EqualError(t, prepareWebhook(tr), "invalid IP address or network in webhook allow-list")
+	})
+
+	t.Run("non webhook", func(t *testing.T) {
+		tr := &types.Trigger{ResourceType: "system", Webhook: &types.TriggerWebhook{}}
+		require.NoError(t, prepareWebhook(tr))
+		require.Nil(t, tr.Webhook)
+	})
+}
+
+func Test_maskWebhookSecret(t *testing.T) {
+	var (
+		req = require.New(t)
+		tr  = &types.Trigger{ID: 42, Webhook: &types.TriggerWebhook{Secret: "foo", Signature: types.WebhookSignatureGithub}}
+	)
+
+	masked := maskWebhookSecret(tr)
+	req.Equal(uint64(42), masked.ID)
+	req.Empty(masked.Webhook.Secret)
+	req.Equal(types.WebhookSignatureGithub, masked.Webhook.Signature)
+
+	// original (registered) trigger is not modified
+	req.Equal("foo", tr.Webhook.Secret)
+
+	req.Nil(maskWebhookSecret(nil))
+	req.Nil(maskWebhookSecret(&types.Trigger{}).Webhook)
+}
+
+func Test_webhookProcessRequest(t *testing.T) {
+	var (
+		svc = Webhook(zap.NewNop())
+
+		tr = &types.Trigger{
+			ID:           42,
+			ResourceType: types.WebhookResourceType,
+			Webhook: &types.TriggerWebhook{
+				Secret:     "s3cr3t",
+				Signature:  types.WebhookSignatureGithub,
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+		}
+
+		tests = []struct {
+			name       string
+			triggerID  uint64
+			remoteAddr string
+			wantStatus int
+		}{
+			{"unknown webhook", 1, "10.0.0.1:1234", http.StatusNotFound},
+			{"remote address not allowed", 42, "1.2.3.4:1234", http.StatusForbidden},
+			{"invalid signature", 42, "10.0.0.1:1234", http.StatusUnauthorized},
+		}
+	)
+
+	svc.register(tr, &types.Workflow{}, nil, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				rec = httptest.NewRecorder()
+				r   = httptest.NewRequest(http.MethodPost, "/webhooks/42", strings.NewReader("{}"))
+			)
+
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set("X-Hub-Signature-256", "sha256=00")
+			svc.ProcessRequest(rec, r, tt.triggerID)
+			require.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+
+	t.Run("invalid workflow", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodPost, "/webhooks/42", strings.NewReader("{}"))
+		)
+
+		r.RemoteAddr = "10.0.0.1:1234"
+		r.Header.Set("X-Hub-Signature-256", "sha256="+testWebhookHmac("s3cr3t", "{}"))
+		svc.ProcessRequest(rec, r, 42)
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	svc.unregister(42)
+	require.Empty(t, svc.hooks)
+}
+
+func Test_writeWebhookResponse(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, writeWebhookResponse(rec, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Empty(t, rec.Body.String())
+	})
+
+	t.Run("status, headers and string body", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			rec = httptest.NewRecorder()
+		)
+
+		req.NoError(writeWebhookResponse(rec, expr.RVars{
+			"responseStatus":  expr.Must(expr.NewInteger(201)),
+			"responseHeaders": expr.Must(expr.NewKV(map[string]string{"Content-Type": "text/plain"})),
+			"responseBody":    expr.Must(expr.NewString("created")),
+		}.Vars()))
+
+		req.Equal(http.StatusCreated, rec.Code)
+		req.Equal("text/plain", rec.Header().Get("Content-Type"))
+		req.Equal("created", rec.Body.String())
+	})
+
+	t.Run("json body", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			rec = httptest.NewRecorder()
+		)
+
+		req.NoError(writeWebhookResponse(rec, expr.RVars{
+			"responseBody": expr.Must(expr.NewKV(map[string]string{"foo": "bar"})),
+		}.Vars()))
+
+		req.Equal("application/json", rec.Header().Get("Content-Type"))
+		req.JSONEq(`{"foo":"bar"}`, rec.Body.String())
+	})
+
+	t.Run("reader body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		require.NoError(t, writeWebhookResponse(rec, expr.RVars{
+			"responseBody": expr.Must(expr.NewReader(strings.NewReader("stream"))),
+		}.Vars()))
+
+		require.Equal(t, "stream", rec.Body.String())
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		err := writeWebhookResponse(httptest.NewRecorder(), expr.RVars{
+			"responseStatus": expr.Must(expr.NewString("foo")),
+		}.Vars())
+
+		require.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"github.com/cortezaproject/corteza-server/automation/types"
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	intAuth "github.com/cortezaproject/corteza-server/pkg/auth"
@@ -11,11 +12,13 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
 	"github.com/cortezaproject/corteza-server/pkg/label"
+	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/pkg/wfexec"
 	"github.com/cortezaproject/corteza-server/store"
 	"go.uber.org/zap"
 	"reflect"
+	"strconv"
 	"sync"
 )
 
@@ -26,6 +29,9 @@ type (
 		actionlog actionlog.Recorder
 		ac        workflowAccessController
 		triggers  *trigger
+		session   *session
+
+		opt options.WorkflowOpt
 
 		log *zap.Logger
 
@@ -47,6 +53,7 @@ type (
 		CanUpdateWorkflow(context.Context, *types.Workflow) bool
 		CanDeleteWorkflow(context.Context, *types.Workflow) bool
 		CanUndeleteWorkflow(context.Context, *types.Workflow) bool
+		CanExecuteWorkflow(context.Context, *types.Workflow) bool
 
 		Grant(ctx context.Context, rr ...*rbac.Rule) error
 	}
@@ -56,7 +63,7 @@ type (
 		Unregister(ptrs ...uintptr)
 	}
 
-	workflowUpdateHandler func(ctx context.Context, ns *types.Workflow) (workflowChanges, error)
+	workflowUpdateHandler func(ctx context.Context, s store.Storer, ns *types.Workflow) (workflowChanges, error)
 	workflowChanges       uint8
 )
 
@@ -65,15 +72,21 @@ const (
 	workflowChanged       workflowChanges = 1
 	workflowLabelsChanged workflowChanges = 2
 	workflowDefChanged    workflowChanges = 4
+
+	// triggers need to be re-registered
+	// (workflow enabled/disabled, published, run-as changed...)
+	workflowRegChanged workflowChanges = 8
 )
 
-func Workflow(log *zap.Logger) *workflow {
+func Workflow(log *zap.Logger, opt options.WorkflowOpt) *workflow {
 	return &workflow{
 		log:       log,
+		opt:       opt,
 		actionlog: DefaultActionlog,
 		store:     DefaultStore,
 		ac:        DefaultAccessControl,
 		triggers:  DefaultTrigger,
+		session:   DefaultSession,
 		eventbus:  eventbus.Service(),
 		wfgs:      make(map[uint64]*wfexec.Graph),
 		mux:       &sync.RWMutex{},
@@ -193,6 +206,7 @@ func (svc *workflow) Create(ctx context.Context, new *types.Workflow) (wf *types
 			Enabled:      new.Enabled,
 			Trace:        new.Trace,
 			KeepSessions: new.KeepSessions,
+			Timeout:      new.Timeout,
 
 			Scope: new.Scope,
 			Steps: new.Steps,
@@ -207,6 +221,11 @@ func (svc *workflow) Create(ctx context.Context, new *types.Workflow) (wf *types
 
 		_, wf.Issues = Convert(svc, wf)
 
+		// initial definition is published right away
+		if err = svc.publish(ctx, s, wf); err != nil {
+			return
+		}
+
 		if err = store.CreateAutomationWorkflow(ctx, s, wf); err != nil {
 			return
 		}
@@ -223,18 +242,25 @@ func (svc *workflow) Create(ctx context.Context, new *types.Workflow) (wf *types
 
 // Update modifies existing workflow resource in the store
 func (svc *workflow) Update(ctx context.Context, upd *types.Workflow) (*types.Workflow, error) {
-	return svc.updater(ctx, upd.ID, WorkflowActionUpdate, func(ctx context.Context, res *types.Workflow) (workflowChanges, error) {
+	return svc.updater(ctx, upd.ID, WorkflowActionUpdate, func(ctx context.Context, s store.Storer, res *types.Workflow) (workflowChanges, error) {
 		if !svc.ac.CanUpdateWorkflow(ctx, res) {
 			return workflowUnchanged, WorkflowErrNotAllowedToUpdate()
 		}
 
 		handler := svc.handleUpdate(upd)
-		return handler(ctx, res)
+		return handler(ctx, s, res)
 	})
 }
 
 func (svc *workflow) DeleteByID(ctx context.Context, workflowID uint64) error {
-	return trim1st(svc.updater(ctx, workflowID, WorkflowActionDelete, svc.handleDelete))
+	if _, err := svc.updater(ctx, workflowID, WorkflowActionDelete, svc.handleDelete); err != nil {
+		return err
+	}
+
+	// Metrics of deleted workflow are no longer exported;
+	// stats are kept until they expire
+	svc.session.metrics.forget(workflowID)
+	return nil
 }
 
 func (svc *workflow) UndeleteByID(ctx context.Context, workflowID uint64) error {
@@ -250,6 +276,98 @@ func (svc *workflow) Start(ctx context.Context, workflowID uint64, scope *expr.V
 	return errors.Internal("pending implementation")
 }
 
+// execSubprocess starts workflow as a subprocess of the calling session and returns ID of the started session
+//
+// Workflow is referenced by ID or handle. Workflow must be enabled and executable
+// by the identity of the calling session. Subprocess runs with workflow's run-as identity
+// (when set) or with the identity of the caller.
+//
+// Subprocess does not block the caller; calling state is awaiting
+// and is resumed by the session service when subprocess completes or fails
+func (svc *workflow) execSubprocess(ctx context.Context, ref string, callerSessionID uint64, input *expr.Vars) (uint64, error) {
+	var (
+		wf        *types.Workflow
+		g         *wfexec.Graph
+		issues    types.WorkflowIssueSet
+		runAs     intAuth.Identifiable
+		ses       *types.Session
+		callStack []uint64
+		err       error
+
+		wap = &workflowActionProps{}
+	)
+
+	if wf, err = svc.lookupByRef(ctx, ref); err != nil {
+		return 0, err
+	}
+
+	wap.setWorkflow(wf)
+
+	if !wf.Enabled || wf.DeletedAt != nil {
+		return 0, WorkflowErrDisabled(wap)
+	}
+
+	if !svc.ac.CanExecuteWorkflow(ctx, wf) {
+		return 0, WorkflowErrNotAllowedToExecute(wap)
+	}
+
+	callStack = append(svc.session.callStack(callerSessionID), callerSessionID)
+	if svc.opt.CallStackSize > 0 && len(callStack) > svc.opt.CallStackSize {
+		return 0, WorkflowErrCallStackSizeExceeded(wap)
+	}
+
+	if wf, err = loadPublishedWorkflow(ctx, svc.store, wf, wf.PublishedRevision); err != nil {
+		return 0, err
+	}
+
+	if g, issues = Convert(svc, wf); len(issues) > 0 {
+		return 0, issues
+	}
+
+	if wf.RunAs > 0 {
+		if runAs, err = DefaultUser.FindByID(ctx, wf.RunAs); err != nil {
+			return 0, fmt.Errorf("failed to load run-as user %d: %w", wf.RunAs, err)
+		} else if !runAs.Valid() {
+			return 0, fmt.Errorf("invalid user %d used for workflow run-as", wf.RunAs)
+		}
+	} else {
+		runAs = intAuth.GetIdentityFromContext(ctx)
+	}
+
+	ses, err = svc.session.start(g, runAs, types.SessionStartParams{
+		WorkflowID: wf.ID,
+		Revision:   wf.PublishedRevision,
+		KeepFor:    wf.KeepSessions,
+		Timeout:    wf.Timeout,
+		Trace:      wf.Trace,
+		Input:      wf.Scope.Merge(input),
+		CallStack:  callStack,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return ses.ID, nil
+}
+
+// lookupByRef finds workflow by ID or handle
+func (svc *workflow) lookupByRef(ctx context.Context, ref string) (wf *types.Workflow, err error) {
+	if workflowID, _ := strconv.ParseUint(ref, 10, 64); workflowID > 0 {
+		return loadWorkflow(ctx, svc.store, workflowID)
+	}
+
+	if !handle.IsValid(ref) {
+		return nil, WorkflowErrInvalidHandle()
+	}
+
+	if wf, err = store.LookupAutomationWorkflowByHandle(ctx, svc.store, ref); errors.IsNotFound(err) {
+		return nil, WorkflowErrNotFound()
+	}
+
+	return
+}
+
 func (svc workflow) uniqueCheck(ctx context.Context, res *types.Workflow) (err error) {
 	if res.Handle != "" {
 		if e, _ := store.LookupAutomationWorkflowByHandle(ctx, svc.store, res.Handle); e != nil && e.ID != res.ID {
@@ -274,27 +392,31 @@ func (svc *workflow) updater(ctx context.Context, workflowID uint64, action func
 			return
 		}
 
-		if err = label.Load(ctx, svc.store, res); err != nil {
+		if err = label.Load(ctx, s, res); err != nil {
 			return err
 		}
 
 		aProps.setWorkflow(res)
 		aProps.setUpdate(res)
 
-		if changes, err = fn(ctx, res); err != nil {
+		if changes, err = fn(ctx, s, res); err != nil {
 			return err
 		}
 
 		if changes&workflowDefChanged > 0 {
-			if _, res.Issues = Convert(svc, res); len(res.Issues) == 0 {
-				if err = svc.triggers.registerWorkflows(ctx, res); err != nil {
-					return err
-				}
+			// changes to the definition do not affect published revision;
+			// we only need to refresh issues of the (draft) definition
+			_, res.Issues = Convert(svc, res)
+		}
+
+		if changes&workflowRegChanged > 0 {
+			if err = svc.triggers.registerWorkflows(ctx, res); err != nil {
+				return err
 			}
 		}
 
 		if changes&workflowChanged > 0 {
-			if err = store.UpdateAutomationWorkflow(ctx, svc.store, res); err != nil {
+			if err = store.UpdateAutomationWorkflow(ctx, s, res); err != nil {
 				return err
 			}
 		}
@@ -312,7 +434,7 @@ func (svc *workflow) updater(ctx context.Context, workflowID uint64, action func
 }
 
 func (svc workflow) handleUpdate(upd *types.Workflow) workflowUpdateHandler {
-	return func(ctx context.Context, res *types.Workflow) (changes workflowChanges, err error) {
+	return func(ctx context.Context, s store.Storer, res *types.Workflow) (changes workflowChanges, err error) {
 		if isStale(upd.UpdatedAt, res.UpdatedAt, res.CreatedAt) {
 			return workflowUnchanged, WorkflowErrStaleData()
 		}
@@ -335,7 +457,7 @@ func (svc workflow) handleUpdate(upd *types.Workflow) workflowUpdateHandler {
 		}
 
 		if res.Enabled != upd.Enabled {
-			changes |= workflowChanged | workflowDefChanged
+			changes |= workflowChanged | workflowRegChanged
 			res.Enabled = upd.Enabled
 		}
 
@@ -347,15 +469,20 @@ func (svc workflow) handleUpdate(upd *types.Workflow) workflowUpdateHandler {
 		}
 
 		if res.Trace != upd.Trace {
-			changes |= workflowChanged | workflowDefChanged
+			changes |= workflowChanged | workflowRegChanged
 			res.Trace = upd.Trace
 		}
 
 		if res.KeepSessions != upd.KeepSessions {
-			changes |= workflowChanged | workflowDefChanged
+			changes |= workflowChanged | workflowRegChanged
 			res.KeepSessions = upd.KeepSessions
 		}
 
+		if res.Timeout != upd.Timeout {
+			changes |= workflowChanged | workflowRegChanged
+			res.Timeout = upd.Timeout
+		}
+
 		if upd.Meta != nil {
 			if !reflect.DeepEqual(upd.Meta, res.Meta) {
 				changes |= workflowChanged
@@ -386,7 +513,7 @@ func (svc workflow) handleUpdate(upd *types.Workflow) workflowUpdateHandler {
 
 		if res.RunAs != upd.RunAs {
 			// @todo need to check against access control if current user can modify security descriptor
-			changes |= workflowChanged | workflowDefChanged
+			changes |= workflowChanged | workflowRegChanged
 			res.RunAs = upd.RunAs
 		}
 
@@ -404,7 +531,7 @@ func (svc workflow) handleUpdate(upd *types.Workflow) workflowUpdateHandler {
 	}
 }
 
-func (svc workflow) handleDelete(ctx context.Context, res *types.Workflow) (workflowChanges, error) {
+func (svc workflow) handleDelete(ctx context.Context, s store.Storer, res *types.Workflow) (workflowChanges, error) {
 	if !svc.ac.CanDeleteWorkflow(ctx, res) {
 		return workflowUnchanged, WorkflowErrNotAllowedToDelete()
 	}
@@ -418,7 +545,7 @@ func (svc workflow) handleDelete(ctx context.Context, res *types.Workflow) (work
 	return workflowChanged, nil
 }
 
-func (svc workflow) handleUndelete(ctx context.Context, res *types.Workflow) (workflowChanges, error) {
+func (svc workflow) handleUndelete(ctx context.Context, s store.Storer, res *types.Workflow) (workflowChanges, error) {
 	if !svc.ac.CanDeleteWorkflow(ctx, res) {
 		return workflowUnchanged, WorkflowErrNotAllowedToUndelete()
 	}
@@ -442,6 +569,22 @@ func (svc *workflow) Load(ctx context.Context) error {
 		return err
 	}
 
+	for _, wf := range wwf {
+		if wf.PublishedRevision > 0 {
+			continue
+		}
+
+		// workflows created before versioning was introduced
+		// get their current definition published as the first revision
+		if err = svc.publish(ctx, svc.store, wf); err != nil {
+			return err
+		}
+
+		if err = store.UpdateAutomationWorkflow(ctx, svc.store, wf); err != nil {
+			return err
+		}
+	}
+
 	return svc.triggers.registerWorkflows(ctx, wwf...)
 }
 
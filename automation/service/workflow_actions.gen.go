@@ -24,6 +24,7 @@ type (
 		new      *types.Workflow
 		update   *types.Workflow
 		filter   *types.WorkflowFilter
+		revision *types.WorkflowRevision
 	}
 
 	workflowAction struct {
@@ -95,6 +96,17 @@ func (p *workflowActionProps) setFilter(filter *types.WorkflowFilter) *workflowA
 	return p
 }
 
+// setRevision updates workflowActionProps's revision
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *workflowActionProps) setRevision(revision *types.WorkflowRevision) *workflowActionProps {
+	p.revision = revision
+	return p
+}
+
 // Serialize converts workflowActionProps to actionlog.Meta
 //
 // This function is auto-generated.
@@ -118,6 +130,10 @@ func (p workflowActionProps) Serialize() actionlog.Meta {
 	}
 	if p.filter != nil {
 	}
+	if p.revision != nil {
+		m.Set("revision.ID", p.revision.ID, true)
+		m.Set("revision.revision", p.revision.Revision, true)
+	}
 
 	return m
 }
@@ -197,6 +213,20 @@ func (p workflowActionProps) Format(in string, err error) string {
 			fns(),
 		)
 	}
+
+	if p.revision != nil {
+		// replacement for "{revision}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{revision}",
+			fns(
+				p.revision.ID,
+				p.revision.Revision,
+			),
+		)
+		pairs = append(pairs, "{revision.ID}", fns(p.revision.ID))
+		pairs = append(pairs, "{revision.revision}", fns(p.revision.Revision))
+	}
 	return strings.NewReplacer(pairs...).Replace(in)
 }
 
@@ -352,6 +382,146 @@ func WorkflowActionUndelete(props ...*workflowActionProps) *workflowAction {
 	return a
 }
 
+// WorkflowActionPublish returns "automation:workflow.publish" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionPublish(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "publish",
+		log:       "published {workflow}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// WorkflowActionRevisions returns "automation:workflow.revisions" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionRevisions(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "revisions",
+		log:       "searched for {workflow} revisions",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// WorkflowActionRevisionLookup returns "automation:workflow.revisionLookup" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionRevisionLookup(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "revisionLookup",
+		log:       "looked-up for a {workflow} revision {revision.revision}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// WorkflowActionRevisionDiff returns "automation:workflow.revisionDiff" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionRevisionDiff(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "revisionDiff",
+		log:       "compared {workflow} revisions",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// WorkflowActionRollback returns "automation:workflow.rollback" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionRollback(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "rollback",
+		log:       "rolled back {workflow}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// WorkflowActionDryRun returns "automation:workflow.dryRun" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionDryRun(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "dryRun",
+		log:       "test-ran {workflow}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// WorkflowActionStats returns "automation:workflow.stats" action
+//
+// This function is auto-generated.
+//
+func WorkflowActionStats(props ...*workflowActionProps) *workflowAction {
+	a := &workflowAction{
+		timestamp: time.Now(),
+		resource:  "automation:workflow",
+		action:    "stats",
+		log:       "read {workflow} execution stats",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -478,6 +648,66 @@ func WorkflowErrInvalidHandle(mm ...*workflowActionProps) *errors.Error {
 	return e
 }
 
+// WorkflowErrRevisionNotFound returns "automation:workflow.revisionNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrRevisionNotFound(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("workflow revision not found", nil),
+
+		errors.Meta("type", "revisionNotFound"),
+		errors.Meta("resource", "automation:workflow"),
+
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WorkflowErrInvalidStatsWindow returns "automation:workflow.invalidStatsWindow" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrInvalidStatsWindow(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid stats time window", nil),
+
+		errors.Meta("type", "invalidStatsWindow"),
+		errors.Meta("resource", "automation:workflow"),
+
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // WorkflowErrStaleData returns "automation:workflow.staleData" as *errors.Error
 //
 //
@@ -636,6 +866,38 @@ func WorkflowErrNotAllowedToUpdate(mm ...*workflowActionProps) *errors.Error {
 	return e
 }
 
+// WorkflowErrNotAllowedToPublish returns "automation:workflow.notAllowedToPublish" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrNotAllowedToPublish(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to publish this workflow", nil),
+
+		errors.Meta("type", "notAllowedToPublish"),
+		errors.Meta("resource", "automation:workflow"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(workflowLogMetaKey{}, "failed to publish {workflow}; insufficient permissions"),
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // WorkflowErrNotAllowedToDelete returns "automation:workflow.notAllowedToDelete" as *errors.Error
 //
 //
@@ -732,6 +994,102 @@ func WorkflowErrHandleNotUnique(mm ...*workflowActionProps) *errors.Error {
 	return e
 }
 
+// WorkflowErrNotAllowedToExecute returns "automation:workflow.notAllowedToExecute" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrNotAllowedToExecute(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to execute this workflow", nil),
+
+		errors.Meta("type", "notAllowedToExecute"),
+		errors.Meta("resource", "automation:workflow"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(workflowLogMetaKey{}, "failed to execute {workflow}; insufficient permissions"),
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WorkflowErrDisabled returns "automation:workflow.disabled" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrDisabled(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("workflow disabled", nil),
+
+		errors.Meta("type", "disabled"),
+		errors.Meta("resource", "automation:workflow"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(workflowLogMetaKey{}, "failed to execute disabled or deleted {workflow}"),
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// WorkflowErrCallStackSizeExceeded returns "automation:workflow.callStackSizeExceeded" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrCallStackSizeExceeded(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("max workflow call stack size exceeded", nil),
+
+		errors.Meta("type", "callStackSizeExceeded"),
+		errors.Meta("resource", "automation:workflow"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(workflowLogMetaKey{}, "failed to execute {workflow}; max workflow call stack size exceeded"),
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -10,6 +10,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/wfexec"
 	"go.uber.org/zap"
 	"strings"
+	"time"
 )
 
 type (
@@ -18,14 +19,39 @@ type (
 		reg    *registry
 		parser expr.Parsable
 		log    *zap.Logger
+
+		// handles execution of subprocess steps
+		subprocess types.SubprocessHandler
+
+		// test (dry) run; function, iterator and subprocess
+		// steps return mocked results instead of being executed
+		dryRun bool
+		mocks  types.WorkflowStepMockSet
 	}
 )
 
 func Convert(wfService *workflow, wf *types.Workflow) (*wfexec.Graph, types.WorkflowIssueSet) {
+	conv := &workflowConverter{
+		reg:        wfService.reg,
+		parser:     wfService.parser,
+		log:        wfService.log,
+		subprocess: wfService.execSubprocess,
+	}
+
+	return conv.makeGraph(wf)
+}
+
+// ConvertDryRun converts workflow for a test run
+//
+// Function, iterator and subprocess steps are replaced with mocks;
+// steps without a mock fail when executed
+func ConvertDryRun(wfService *workflow, wf *types.Workflow, mocks types.WorkflowStepMockSet) (*wfexec.Graph, types.WorkflowIssueSet) {
 	conv := &workflowConverter{
 		reg:    wfService.reg,
 		parser: wfService.parser,
 		log:    wfService.log,
+		dryRun: true,
+		mocks:  mocks,
 	}
 
 	return conv.makeGraph(wf)
@@ -192,6 +218,12 @@ func (svc workflowConverter) workflowStepDefConv(g *wfexec.Graph, s *types.Workf
 		case types.WorkflowStepKindContinue:
 			return svc.convContinueStep()
 
+		case types.WorkflowStepKindSubprocess:
+			return svc.convSubprocessStep(s)
+
+		case types.WorkflowStepKindEventWait:
+			return svc.convEventWaitStep(g, s, out)
+
 		default:
 			return nil, errors.Internal("unsupported step kind %q", s.Kind)
 		}
@@ -202,6 +234,8 @@ func (svc workflowConverter) workflowStepDefConv(g *wfexec.Graph, s *types.Workf
 	} else if conv != nil {
 		conv.SetID(s.ID)
 		g.AddStep(conv)
+		g.SetStepTimeout(conv, time.Duration(s.Timeout)*time.Second)
+		g.SetStepRetry(conv, s.Retry.Policy())
 		return true, err
 	} else {
 		// signal caller that we were unable to
@@ -345,6 +379,10 @@ func (svc workflowConverter) convFunctionStep(g *wfexec.Graph, s *types.Workflow
 			return nil, errors.Internal("failed to verify result expressions for %s %s: %s", s.Kind, s.Ref, err).Wrap(err)
 		}
 
+		if svc.dryRun {
+			def = svc.mockFunction(s.ID, def)
+		}
+
 		if isIterator {
 			if len(out) != 2 {
 				return nil, fmt.Errorf("expecting exactly 2 outbound paths for iterator")
@@ -472,6 +510,80 @@ func (svc workflowConverter) convContinueStep() (wfexec.Step, error) {
 
 }
 
+// converts subprocess definition to wfexec.Step
+func (svc workflowConverter) convSubprocessStep(s *types.WorkflowStep) (wfexec.Step, error) {
+	if svc.dryRun {
+		return types.MockedSubprocessStep(s.Ref, s.Arguments, s.Results, svc.mockSubprocess(s.ID)), nil
+	}
+
+	return types.SubprocessStep(s.Ref, s.Arguments, s.Results, svc.subprocess), nil
+}
+
+// mockFunction returns copy of the function definition with mocked handlers
+func (svc workflowConverter) mockFunction(stepID uint64, def *types.Function) *types.Function {
+	var (
+		aux = *def
+	)
+
+	if m := svc.mocks.FindByStepID(stepID); m != nil {
+		aux.Handler = m.Handler()
+		aux.Iterator = m.Iterator()
+	} else {
+		aux.Handler = func(context.Context, *expr.Vars) (*expr.Vars, error) {
+			return nil, errNotMocked(stepID)
+		}
+
+		aux.Iterator = func(context.Context, *expr.Vars) (wfexec.IteratorHandler, error) {
+			return nil, errNotMocked(stepID)
+		}
+	}
+
+	return &aux
+}
+
+// mockSubprocess returns subprocess handler with mocked results
+func (svc workflowConverter) mockSubprocess(stepID uint64) types.FunctionHandler {
+	if m := svc.mocks.FindByStepID(stepID); m != nil {
+		return m.Handler()
+	}
+
+	return func(context.Context, *expr.Vars) (*expr.Vars, error) {
+		return nil, errNotMocked(stepID)
+	}
+}
+
+func errNotMocked(stepID uint64) error {
+	return errors.InvalidData("step %d is not mocked", stepID)
+}
+
+// converts wait-for-event definition to wfexec.Step
+//
+// First outbound path is used when event is received, second (optional) on timeout
+func (svc workflowConverter) convEventWaitStep(g *wfexec.Graph, s *types.WorkflowStep, out []*types.WorkflowPath) (wfexec.Step, error) {
+	var (
+		next, timeout wfexec.Step
+	)
+
+	switch len(out) {
+	case 0:
+	case 2:
+		if timeout = g.StepByID(out[1].ChildID); timeout == nil {
+			// wait for it to be resolved
+			return nil, nil
+		}
+		fallthrough
+	case 1:
+		if next = g.StepByID(out[0].ChildID); next == nil {
+			// wait for it to be resolved
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("max 2 paths out of wait-for-event step")
+	}
+
+	return types.EventWaitStep(s.Arguments, s.Results, next, timeout), nil
+}
+
 func (svc workflowConverter) parseExpressions(ee ...*types.Expr) (err error) {
 	for _, e := range ee {
 
@@ -683,6 +795,21 @@ func verifyStep(s *types.WorkflowStep, in, out types.WorkflowPathSet) types.Work
 			count(0, 1, outbound),
 		)
 
+	case types.WorkflowStepKindSubprocess:
+		checks = append(checks,
+			requiredRef,
+			count(0, 1, outbound),
+		)
+
+	case types.WorkflowStepKindEventWait:
+		checks = append(checks,
+			noRef,
+			requiredArg("resourceType", expr.String{}),
+			requiredArg("eventType", expr.String{}),
+			checkArg("timeout", expr.Duration{}),
+			count(0, 2, outbound),
+		)
+
 	case types.WorkflowStepKindBreak:
 		checks = append(checks,
 			noRef,
@@ -709,6 +836,30 @@ func verifyStep(s *types.WorkflowStep, in, out types.WorkflowPathSet) types.Work
 
 	}
 
+	checks = append(checks, func() error {
+		if s.Timeout < 0 {
+			return errors.Internal("%s step timeout can not be negative", s.Kind)
+		}
+
+		return nil
+	})
+
+	if s.Retry != nil {
+		checks = append(checks, func() error {
+			switch s.Kind {
+			case types.WorkflowStepKindFunction, types.WorkflowStepKindIterator, types.WorkflowStepKindSubprocess:
+			default:
+				return errors.Internal("%s step can not be retried", s.Kind)
+			}
+
+			if err := s.Retry.Validate(); err != nil {
+				return errors.Internal("invalid retry configuration on %s step: %s", s.Kind, err)
+			}
+
+			return nil
+		})
+	}
+
 	for _, check := range checks {
 		if err := check(); err != nil {
 			ii = ii.Append(err, nil)
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	intAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+)
+
+// DryRun executes workflow in an isolated test session
+//
+// Function, iterator and subprocess steps are not executed; they return mocked
+// results (or errors) configured for each step. Session is not stored and
+// does not affect running sessions. Run stops when session completes, fails
+// or gets suspended (delay, prompt) and full stacktrace is returned
+func (svc *workflow) DryRun(ctx context.Context, workflowID uint64, p types.WorkflowDryRunParams) (res *types.WorkflowDryRunResult, err error) {
+	var (
+		wap = &workflowActionProps{workflow: &types.Workflow{ID: workflowID}}
+
+		wf     *types.Workflow
+		g      *wfexec.Graph
+		issues types.WorkflowIssueSet
+		runAs  intAuth.Identifiable
+	)
+
+	err = func() error {
+		if wf, err = loadWorkflow(ctx, svc.store, workflowID); err != nil {
+			return err
+		}
+
+		wap.setWorkflow(wf)
+
+		if !svc.ac.CanExecuteWorkflow(ctx, wf) {
+			return WorkflowErrNotAllowedToExecute(wap)
+		}
+
+		if p.Revision > 0 {
+			if wf, err = loadPublishedWorkflow(ctx, svc.store, wf, p.Revision); err != nil {
+				return err
+			}
+		}
+
+		if err = p.Mocks.ResolveTypes(svc.reg.Type); err != nil {
+			return err
+		}
+
+		if p.Input != nil {
+			if err = p.Input.ResolveTypes(svc.reg.Type); err != nil {
+				return err
+			}
+		}
+
+		if g, issues = ConvertDryRun(svc, wf, p.Mocks); len(issues) > 0 {
+			return issues
+		}
+
+		if p.RunAs && wf.RunAs > 0 {
+			if runAs, err = DefaultUser.FindByID(ctx, wf.RunAs); err != nil {
+				return fmt.Errorf("failed to load run-as user %d: %w", wf.RunAs, err)
+			} else if !runAs.Valid() {
+				return fmt.Errorf("invalid user %d used for workflow run-as", wf.RunAs)
+			}
+
+			ctx = intAuth.SetIdentityToContext(ctx, runAs)
+		}
+
+		res, err = svc.dryRun(ctx, g, wf, p)
+		return err
+	}()
+
+	return res, svc.recordAction(ctx, wap, WorkflowActionDryRun, err)
+}
+
+// dryRun runs converted workflow graph in a new session that is not added to the session pool
+func (svc *workflow) dryRun(ctx context.Context, g *wfexec.Graph, wf *types.Workflow, p types.WorkflowDryRunParams) (*types.WorkflowDryRunResult, error) {
+	start, err := startingStep(g, p.StepID)
+	if err != nil {
+		return nil, err
+	}
+
+	if svc.opt.DryRunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, svc.opt.DryRunTimeout)
+		defer cancel()
+	}
+
+	// worker of the session is stopped when test run is done
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var (
+		mux = &sync.Mutex{}
+		res = &types.WorkflowDryRunResult{Stacktrace: types.Stacktrace{}}
+
+		ses = wfexec.NewSession(ctx, g,
+			wfexec.SetLogger(svc.log),
+			wfexec.SetTimeout(time.Duration(wf.Timeout)*time.Second),
+			wfexec.SetStepTimeout(svc.opt.StepTimeout),
+			wfexec.SetConcurrency(svc.opt.StepConcurrency),
+			wfexec.SetHandler(func(_ wfexec.SessionStatus, state *wfexec.State, _ *wfexec.Session) {
+				defer mux.Unlock()
+				mux.Lock()
+				res.Stacktrace = append(res.Stacktrace, state.MakeFrame())
+			}),
+		)
+	)
+
+	if err = ses.Exec(ctx, start, wf.Scope.Merge(p.Input)); err != nil {
+		return nil, err
+	}
+
+	err = ses.WaitUntil(ctx,
+		wfexec.SessionPrompted,
+		wfexec.SessionDelayed,
+		wfexec.SessionFailed,
+		wfexec.SessionCompleted,
+	)
+
+	defer mux.Unlock()
+	mux.Lock()
+
+	switch status := ses.Status(); {
+	case status == wfexec.SessionCompleted:
+		res.Status = types.SessionCompleted
+		res.Scope = ses.Result()
+
+	case err != nil, status == wfexec.SessionFailed:
+		res.Status = types.SessionFailed
+
+	case status == wfexec.SessionPrompted:
+		res.Status = types.SessionPrompted
+
+	case status == wfexec.SessionDelayed:
+		res.Status = types.SessionSuspended
+
+	default:
+		res.Status = types.SessionFailed
+		err = fmt.Errorf("test run did not complete in time: %w", ctx.Err())
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	if res.Scope == nil && len(res.Stacktrace) > 0 {
+		// use scope of the last executed step
+		res.Scope = res.Stacktrace[len(res.Stacktrace)-1].Scope
+	}
+
+	return res, nil
+}
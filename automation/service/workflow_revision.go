@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+	intAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// Publish creates new immutable revision from the current workflow definition
+//
+// Workflow triggers are re-registered and bound to the new revision;
+// sessions that are already running continue on their original revision
+func (svc *workflow) Publish(ctx context.Context, workflowID uint64) (*types.Workflow, error) {
+	return svc.updater(ctx, workflowID, WorkflowActionPublish, svc.handlePublish)
+}
+
+// Revisions returns published revisions of a workflow
+func (svc *workflow) Revisions(ctx context.Context, workflowID uint64, f types.WorkflowRevisionFilter) (set types.WorkflowRevisionSet, _ types.WorkflowRevisionFilter, err error) {
+	var (
+		wap = &workflowActionProps{workflow: &types.Workflow{ID: workflowID}}
+	)
+
+	err = func() error {
+		if _, err = svc.loadReadableWorkflow(ctx, wap, workflowID); err != nil {
+			return err
+		}
+
+		f.WorkflowID = workflowID
+
+		if len(f.Sort) == 0 {
+			f.Sort = filter.SortExprSet{&filter.SortExpr{Column: "revision", Descending: true}}
+		}
+
+		set, f, err = store.SearchAutomationWorkflowRevisions(ctx, svc.store, f)
+		return err
+	}()
+
+	return set, f, svc.recordAction(ctx, wap, WorkflowActionRevisions, err)
+}
+
+// LookupRevision returns one workflow revision
+func (svc *workflow) LookupRevision(ctx context.Context, workflowID uint64, revision uint) (rev *types.WorkflowRevision, err error) {
+	var (
+		wap = &workflowActionProps{workflow: &types.Workflow{ID: workflowID}}
+	)
+
+	err = func() error {
+		if _, err = svc.loadReadableWorkflow(ctx, wap, workflowID); err != nil {
+			return err
+		}
+
+		if rev, err = loadWorkflowRevision(ctx, svc.store, workflowID, revision); err != nil {
+			return err
+		}
+
+		wap.setRevision(rev)
+		return nil
+	}()
+
+	return rev, svc.recordAction(ctx, wap, WorkflowActionRevisionLookup, err)
+}
+
+// RevisionDiff compares definitions of two workflow revisions
+//
+// When compareTo is 0, revision is compared to the current (draft) workflow definition
+func (svc *workflow) RevisionDiff(ctx context.Context, workflowID uint64, revision, compareTo uint) (diff *types.WorkflowRevisionDiff, err error) {
+	var (
+		wap = &workflowActionProps{workflow: &types.Workflow{ID: workflowID}}
+
+		wf       *types.Workflow
+		rev, cmp *types.WorkflowRevision
+	)
+
+	err = func() error {
+		if wf, err = svc.loadReadableWorkflow(ctx, wap, workflowID); err != nil {
+			return err
+		}
+
+		if rev, err = loadWorkflowRevision(ctx, svc.store, workflowID, revision); err != nil {
+			return err
+		}
+
+		wap.setRevision(rev)
+
+		if compareTo > 0 {
+			if cmp, err = loadWorkflowRevision(ctx, svc.store, workflowID, compareTo); err != nil {
+				return err
+			}
+		} else {
+			cmp = types.MakeWorkflowRevision(wf)
+		}
+
+		diff = types.DiffWorkflowRevisions(rev, cmp)
+		return nil
+	}()
+
+	return diff, svc.recordAction(ctx, wap, WorkflowActionRevisionDiff, err)
+}
+
+// Rollback publishes the given revision again
+//
+// No new revision is created; workflow triggers are re-bound to the restored revision.
+// Draft workflow definition is kept as it is; use RevisionDiff to compare it with the
+// restored revision
+func (svc *workflow) Rollback(ctx context.Context, workflowID uint64, revision uint) (*types.Workflow, error) {
+	return svc.updater(ctx, workflowID, WorkflowActionRollback, func(ctx context.Context, s store.Storer, res *types.Workflow) (workflowChanges, error) {
+		if !svc.ac.CanUpdateWorkflow(ctx, res) {
+			return workflowUnchanged, WorkflowErrNotAllowedToUpdate()
+		}
+
+		rev, err := loadWorkflowRevision(ctx, s, res.ID, revision)
+		if err != nil {
+			return workflowUnchanged, err
+		}
+
+		res.PublishedRevision = rev.Revision
+		res.UpdatedAt = now()
+
+		return workflowChanged | workflowRegChanged, nil
+	})
+}
+
+func (svc *workflow) handlePublish(ctx context.Context, s store.Storer, res *types.Workflow) (workflowChanges, error) {
+	if !svc.ac.CanUpdateWorkflow(ctx, res) {
+		return workflowUnchanged, WorkflowErrNotAllowedToPublish()
+	}
+
+	if _, issues := Convert(svc, res); len(issues) > 0 {
+		return workflowUnchanged, issues
+	}
+
+	if err := svc.publish(ctx, s, res); err != nil {
+		return workflowUnchanged, err
+	}
+
+	res.UpdatedAt = now()
+	return workflowChanged | workflowRegChanged, nil
+}
+
+// publish stores snapshot of the workflow definition as a new revision
+// and marks it as published
+//
+// Revision number is sequential for each workflow; when the same revision
+// is published concurrently, unique index rejects all but the first one
+func (svc workflow) publish(ctx context.Context, s store.Storer, wf *types.Workflow) error {
+	var (
+		rev = types.MakeWorkflowRevision(wf)
+		f   = types.WorkflowRevisionFilter{WorkflowID: wf.ID}
+	)
+
+	f.Sort = filter.SortExprSet{&filter.SortExpr{Column: "revision", Descending: true}}
+	f.Limit = 1
+
+	last, _, err := store.SearchAutomationWorkflowRevisions(ctx, s, f)
+	if err != nil {
+		return err
+	}
+
+	rev.Revision = 1
+	if len(last) > 0 {
+		rev.Revision = last[0].Revision + 1
+	}
+
+	rev.ID = nextID()
+	rev.CreatedAt = *now()
+	rev.CreatedBy = intAuth.GetIdentityFromContext(ctx).Identity()
+
+	if err = store.CreateAutomationWorkflowRevision(ctx, s, rev); errors.Is(err, store.ErrNotUnique) {
+		return WorkflowErrStaleData()
+	} else if err != nil {
+		return err
+	}
+
+	wf.PublishedRevision = rev.Revision
+	return nil
+}
+
+// loads workflow and checks if it can be read
+func (svc workflow) loadReadableWorkflow(ctx context.Context, wap *workflowActionProps, workflowID uint64) (wf *types.Workflow, err error) {
+	if wf, err = loadWorkflow(ctx, svc.store, workflowID); err != nil {
+		return
+	}
+
+	wap.setWorkflow(wf)
+
+	if !svc.ac.CanReadWorkflow(ctx, wf) {
+		return nil, WorkflowErrNotAllowedToRead()
+	}
+
+	return
+}
+
+func loadWorkflowRevision(ctx context.Context, s store.Storer, workflowID uint64, revision uint) (*types.WorkflowRevision, error) {
+	if revision == 0 {
+		return nil, WorkflowErrRevisionNotFound()
+	}
+
+	set, _, err := store.SearchAutomationWorkflowRevisions(ctx, s, types.WorkflowRevisionFilter{
+		WorkflowID: workflowID,
+		Revision:   revision,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(set) == 0 {
+		return nil, WorkflowErrRevisionNotFound()
+	}
+
+	return set[0], nil
+}
+
+// loadPublishedWorkflow returns workflow with definition of the given revision
+//
+// Revision 0 denotes sessions and workflows from before workflows were versioned;
+// current workflow definition is used for them
+func loadPublishedWorkflow(ctx context.Context, s store.Storer, wf *types.Workflow, revision uint) (*types.Workflow, error) {
+	if revision == 0 {
+		return wf, nil
+	}
+
+	rev, err := loadWorkflowRevision(ctx, s, wf.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return rev.Apply(wf), nil
+}
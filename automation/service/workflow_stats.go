@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/automation/types"
+)
+
+// Stats returns aggregated execution stats of a workflow and its steps
+//
+// When time window is not set, stats for the whole retention period are returned
+func (svc *workflow) Stats(ctx context.Context, workflowID uint64, f types.WorkflowStatsFilter) (ws *types.WorkflowStats, err error) {
+	var (
+		wap = &workflowActionProps{workflow: &types.Workflow{ID: workflowID}}
+
+		to   = *now()
+		from = to.Add(-svc.opt.StatsRetention)
+	)
+
+	err = func() error {
+		if _, err = svc.loadReadableWorkflow(ctx, wap, workflowID); err != nil {
+			return err
+		}
+
+		if f.From != nil {
+			from = *f.From
+		}
+
+		if f.To != nil {
+			to = *f.To
+		}
+
+		if !from.Before(to) {
+			return WorkflowErrInvalidStatsWindow()
+		}
+
+		ws, err = svc.session.metrics.stats(ctx, svc.store, workflowID, from, to)
+		return err
+	}()
+
+	return ws, svc.recordAction(ctx, wap, WorkflowActionStats, err)
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+)
+
+type (
+	// WorkflowDryRunParams configures test (dry) run of a workflow
+	WorkflowDryRunParams struct {
+		// Input scope, merged with workflow's scope
+		Input *expr.Vars `json:"input"`
+
+		// Starting step; when not set, orphan step is used
+		StepID uint64 `json:"stepID,string"`
+
+		// Revision to run; current (draft) definition is used when not set
+		Revision uint `json:"revision"`
+
+		// Run with workflow's run-as identity
+		RunAs bool `json:"runAs"`
+
+		// Mocked results of function, iterator and subprocess steps
+		Mocks WorkflowStepMockSet `json:"mocks"`
+	}
+
+	// WorkflowStepMock replaces function, iterator or subprocess call
+	// in a test run with predefined results or error
+	WorkflowStepMock struct {
+		StepID uint64 `json:"stepID,string"`
+
+		// Results returned by the function or subprocess
+		Results *expr.Vars `json:"results,omitempty"`
+
+		// Results returned by the iterator, one per iteration
+		Iterations []*expr.Vars `json:"iterations,omitempty"`
+
+		// When set, step fails with this error
+		Error string `json:"error,omitempty"`
+	}
+
+	WorkflowStepMockSet []*WorkflowStepMock
+
+	// WorkflowDryRunResult holds outcome of a workflow test run
+	WorkflowDryRunResult struct {
+		Status     SessionStatus `json:"status"`
+		Scope      *expr.Vars    `json:"scope"`
+		Stacktrace Stacktrace    `json:"stacktrace"`
+		Error      string        `json:"error,omitempty"`
+	}
+
+	// iterates over mocked iterations
+	mockedIterator struct {
+		ii []*expr.Vars
+		i  int
+	}
+)
+
+// FindByStepID returns mock for the given step
+func (set WorkflowStepMockSet) FindByStepID(stepID uint64) *WorkflowStepMock {
+	for _, m := range set {
+		if m.StepID == stepID {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// ResolveTypes resolves types of all mocked results
+func (set WorkflowStepMockSet) ResolveTypes(res func(typ string) expr.Type) (err error) {
+	for _, m := range set {
+		if m.Results != nil {
+			if err = m.Results.ResolveTypes(res); err != nil {
+				return
+			}
+		}
+
+		for _, i := range m.Iterations {
+			if i == nil {
+				continue
+			}
+
+			if err = i.ResolveTypes(res); err != nil {
+				return
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m WorkflowStepMock) err() error {
+	if m.Error != "" {
+		return errors.New(m.Error)
+	}
+
+	return nil
+}
+
+func (m WorkflowStepMock) results() *expr.Vars {
+	if m.Results == nil {
+		return &expr.Vars{}
+	}
+
+	return m.Results
+}
+
+// Handler returns function handler that returns mocked results
+//
+// Used for function and subprocess steps
+func (m WorkflowStepMock) Handler() FunctionHandler {
+	return func(context.Context, *expr.Vars) (*expr.Vars, error) {
+		if err := m.err(); err != nil {
+			return nil, err
+		}
+
+		return m.results(), nil
+	}
+}
+
+// Iterator returns iterator handler that iterates over mocked iterations
+func (m WorkflowStepMock) Iterator() IteratorHandler {
+	return func(context.Context, *expr.Vars) (wfexec.IteratorHandler, error) {
+		if err := m.err(); err != nil {
+			return nil, err
+		}
+
+		return &mockedIterator{ii: m.Iterations}, nil
+	}
+}
+
+func (i *mockedIterator) Start(context.Context, *expr.Vars) error { i.i = 0; return nil }
+
+func (i *mockedIterator) More(context.Context, *expr.Vars) (bool, error) {
+	return i.i < len(i.ii), nil
+}
+
+func (i *mockedIterator) Next(context.Context, *expr.Vars) (out *expr.Vars, err error) {
+	if out = i.ii[i.i]; out == nil {
+		out = &expr.Vars{}
+	}
+
+	i.i++
+	return
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWorkflowStepMockSet(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		set WorkflowStepMockSet
+	)
+
+	req.NoError(json.Unmarshal([]byte(`[
+		{"stepID":"1","results":{"out":{"@type":"String","@value":"mocked"}}},
+		{"stepID":"2","error":"mocked failure"},
+		{"stepID":"3","iterations":[{"i":{"@type":"Integer","@value":1}},{"i":{"@type":"Integer","@value":2}}]}
+	]`), &set))
+
+	req.NoError(set.ResolveTypes(func(typ string) expr.Type {
+		switch typ {
+		case "String":
+			return &expr.String{}
+		case "Integer":
+			return &expr.Integer{}
+		}
+
+		return nil
+	}))
+
+	req.Nil(set.FindByStepID(4))
+
+	out, err := set.FindByStepID(1).Handler()(ctx, nil)
+	req.NoError(err)
+	req.Equal("mocked", expr.Must(out.Select("out")).Get())
+
+	_, err = set.FindByStepID(2).Handler()(ctx, nil)
+	req.EqualError(err, "mocked failure")
+
+	ih, err := set.FindByStepID(3).Iterator()(ctx, nil)
+	req.NoError(err)
+	req.NoError(ih.Start(ctx, nil))
+
+	var ii []interface{}
+	for {
+		more, err := ih.More(ctx, nil)
+		req.NoError(err)
+		if !more {
+			break
+		}
+
+		out, err = ih.Next(ctx, nil)
+		req.NoError(err)
+		ii = append(ii, expr.Must(out.Select("i")).Get())
+	}
+
+	req.Equal([]interface{}{int64(1), int64(2)}, ii)
+}
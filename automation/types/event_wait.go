@@ -0,0 +1,164 @@
+package types
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"time"
+)
+
+type (
+	eventWaitStep struct {
+		wfexec.StepIdentifier
+		arguments ExprSet
+		results   ExprSet
+
+		// step to continue with when event is received
+		next wfexec.Step
+
+		// step to continue with on timeout
+		timeout wfexec.Step
+
+		now func() time.Time
+	}
+
+	// EventWait describes event that awaiting state is waiting for
+	EventWait struct {
+		ResourceType string
+		EventType    string
+		Constraints  map[string][]string
+	}
+)
+
+const (
+	// EventWaitRef is used as a reference on awaiting states
+	// that are waiting for an eventbus event
+	EventWaitRef = "eventbus"
+
+	eventWaitArgResourceType = "resourceType"
+	eventWaitArgEventType    = "eventType"
+	eventWaitArgTimeout      = "timeout"
+	eventWaitArgConstraints  = "constraints"
+)
+
+// EventWaitStep initializes new step that suspends the session until matching event is fired
+//
+// Event is described with resourceType, eventType and (optional) constraints arguments;
+// constraints (KVV) map constraint names to values that event must match
+func EventWaitStep(arguments, results ExprSet, next, timeout wfexec.Step) *eventWaitStep {
+	return &eventWaitStep{
+		arguments: arguments,
+		results:   results,
+		next:      next,
+		timeout:   timeout,
+		now:       func() time.Time { return time.Now() },
+	}
+}
+
+// Exec executes event wait step
+//
+// On first execution, arguments are evaluated and session is suspended.
+// When session is resumed with the event, configured results are evaluated with
+// scope and event variables; without results, event variables are merged into the scope
+func (s eventWaitStep) Exec(ctx context.Context, r *wfexec.ExecRequest) (wfexec.ExecResponse, error) {
+	if r.Input == nil || !r.Input.Has("resumed") {
+		return s.await(ctx, r.Scope)
+	}
+
+	if r.Input.Has("timeout") {
+		if s.timeout == nil {
+			return nil, errors.Automation("timed out while waiting for an event")
+		}
+
+		return wfexec.ContinueWith(s.timeout, nil), nil
+	}
+
+	var (
+		results = r.Input
+		err     error
+	)
+
+	if len(s.results) > 0 {
+		if results, err = s.results.Eval(ctx, r.Scope.Merge(r.Input)); err != nil {
+			return nil, err
+		}
+	}
+
+	return wfexec.ContinueWith(s.next, results), nil
+}
+
+func (s eventWaitStep) await(ctx context.Context, scope *expr.Vars) (wfexec.ExecResponse, error) {
+	var (
+		timeoutAt *time.Time
+		ew        = EventWait{Constraints: make(map[string][]string)}
+
+		args, err = s.arguments.Eval(ctx, scope)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = args.Each(func(k string, v expr.TypedValue) error {
+		switch k {
+		case eventWaitArgResourceType:
+			ew.ResourceType, err = expr.CastToString(v)
+		case eventWaitArgEventType:
+			ew.EventType, err = expr.CastToString(v)
+		case eventWaitArgTimeout:
+			var d time.Duration
+			if d, err = expr.CastToDuration(v); err == nil && d > 0 {
+				at := s.now().Add(d)
+				timeoutAt = &at
+			}
+		case eventWaitArgConstraints:
+			ew.Constraints, err = expr.CastToKVV(v)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ew.ResourceType == "" || ew.EventType == "" {
+		return nil, errors.InvalidData("event wait step requires resource and event type")
+	}
+
+	payload := expr.RVars{
+		eventWaitArgResourceType: expr.Must(expr.NewString(ew.ResourceType)),
+		eventWaitArgEventType:    expr.Must(expr.NewString(ew.EventType)),
+		eventWaitArgConstraints:  expr.Must(expr.NewKVV(ew.Constraints)),
+	}.Vars()
+
+	return wfexec.Await(EventWaitRef, payload, timeoutAt), nil
+}
+
+// ParseEventWait decodes awaiting state payload
+func ParseEventWait(payload *expr.Vars) (ew *EventWait, err error) {
+	var v expr.TypedValue
+	ew = &EventWait{}
+
+	if v, err = payload.Select(eventWaitArgResourceType); err != nil {
+		return
+	} else if ew.ResourceType, err = expr.CastToString(v); err != nil {
+		return
+	}
+
+	if v, err = payload.Select(eventWaitArgEventType); err != nil {
+		return
+	} else if ew.EventType, err = expr.CastToString(v); err != nil {
+		return
+	}
+
+	if payload.Has(eventWaitArgConstraints) {
+		v, _ = payload.Select(eventWaitArgConstraints)
+		if ew.Constraints, err = expr.CastToKVV(v); err != nil {
+			return
+		}
+	}
+
+	return
+}
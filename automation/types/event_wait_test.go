@@ -0,0 +1,72 @@
+package types
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEventWaitStep_Exec(t *testing.T) {
+	var (
+		ctx = auth.SetIdentityToContext(context.Background(), auth.NewIdentity(42))
+		req = require.New(t)
+
+		args = ExprSet{
+			&Expr{Target: "resourceType", Value: "compose:record", typ: &expr.String{}},
+			&Expr{Target: "eventType", Value: "afterUpdate", typ: &expr.String{}},
+			&Expr{Target: "constraints", Value: map[string][]string{"record.values.status": {"approved"}}, typ: &expr.KVV{}},
+		}
+
+		done    = ExpressionsStep(&Expr{Target: "path", Value: "done", typ: &expr.String{}})
+		timeout = ExpressionsStep(&Expr{Target: "path", Value: "timeout", typ: &expr.String{}})
+
+		run = func(step *eventWaitStep) *wfexec.Session {
+			g := wfexec.NewGraph()
+			step.SetID(1)
+			done.SetID(2)
+			timeout.SetID(3)
+			g.AddStep(step, done, timeout)
+			g.AddStep(done)
+			g.AddStep(timeout)
+
+			ses := wfexec.NewSession(ctx, g, wfexec.SetWorkerInterval(time.Millisecond))
+			req.NoError(ses.Exec(ctx, step, nil))
+			req.NoError(ses.WaitUntil(ctx, wfexec.SessionDelayed, wfexec.SessionCompleted, wfexec.SessionFailed))
+			req.NoError(ses.Error())
+			return ses
+		}
+	)
+
+	t.Run("resumed with event", func(t *testing.T) {
+		ses := run(EventWaitStep(args, nil, done, timeout))
+
+		pending := ses.PendingAwaits()
+		req.Len(pending, 1)
+		req.Equal(EventWaitRef, pending[0].Ref)
+		req.Nil(pending[0].TimeoutAt)
+
+		ew, err := ParseEventWait(pending[0].Payload)
+		req.NoError(err)
+		req.Equal("compose:record", ew.ResourceType)
+		req.Equal("afterUpdate", ew.EventType)
+		req.Equal(map[string][]string{"record.values.status": {"approved"}}, ew.Constraints)
+
+		input := expr.RVars{"foo": expr.Must(expr.NewString("bar"))}.Vars()
+		req.NoError(ses.ResumeAwaiting(ctx, pending[0].StateID, input))
+		req.NoError(ses.WaitUntil(ctx, wfexec.SessionCompleted))
+		req.Equal("bar", expr.Must(ses.Result().Select("foo")).Get())
+		req.Equal("done", expr.Must(ses.Result().Select("path")).Get())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		step := EventWaitStep(append(args, &Expr{Target: "timeout", Value: "1ms", typ: &expr.Duration{}}), nil, done, timeout)
+		ses := run(step)
+		req.NoError(ses.WaitUntil(ctx, wfexec.SessionCompleted))
+		req.Equal("timeout", expr.Must(ses.Result().Select("path")).Get())
+		req.False(ses.Result().Has("foo"))
+	})
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"io"
+	"mime"
+	"path"
+)
+
+type (
+	// File is a reader that knows its file name and content type
+	//
+	// Rendered documents, compose attachment contents and HTTP response bodies
+	// implement it so that workflow functions can upload, attach or store them
+	// without loosing (or guessing) metadata
+	File interface {
+		io.Reader
+		FileName() string
+		ContentType() string
+	}
+)
+
+// FileMeta returns file name and content type of the reader
+//
+// When reader does not implement File (or metadata is missing), given default
+// name is used and content type is resolved from the file name extension
+func FileMeta(r io.Reader, name string) (string, string) {
+	var ctype string
+
+	if f, ok := r.(File); ok {
+		if f.FileName() != "" {
+			name = f.FileName()
+		}
+
+		ctype = f.ContentType()
+	}
+
+	if ctype == "" {
+		ctype = mime.TypeByExtension(path.Ext(name))
+	}
+
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+
+	return name, ctype
+}
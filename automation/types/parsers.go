@@ -25,6 +25,11 @@ func ParseWorkflowPathSet(ss []string) (p WorkflowPathSet, err error) {
 	return p, parseStringsInput(ss, &p)
 }
 
+func ParseWorkflowStepMockSet(ss []string) (p WorkflowStepMockSet, err error) {
+	p = WorkflowStepMockSet{}
+	return p, parseStringsInput(ss, &p)
+}
+
 func parseStringsInput(ss []string, p interface{}) (err error) {
 	if len(ss) == 0 {
 		return
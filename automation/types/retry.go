@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+)
+
+type (
+	// WorkflowStepRetry configures retrying of failed function, iterator and subprocess steps
+	WorkflowStepRetry struct {
+		// max number of attempts (including the first one)
+		MaxAttempts int `json:"maxAttempts"`
+
+		// delay before the first retry and max delay between attempts (in sec)
+		Delay    int `json:"delay"`
+		MaxDelay int `json:"maxDelay,omitempty"`
+
+		// constant (default), linear or exponential
+		Backoff string `json:"backoff,omitempty"`
+
+		// random portion (0-1) of the delay that is added to it
+		Jitter float64 `json:"jitter,omitempty"`
+
+		// retryable error kinds (failure, automation, timeout);
+		// all errors are retried when empty
+		ErrorKinds []string `json:"errorKinds,omitempty"`
+	}
+)
+
+const (
+	// upper limit for number of attempts of a single step
+	maxRetryAttempts = 100
+)
+
+// Validate checks retry configuration
+func (r WorkflowStepRetry) Validate() error {
+	switch {
+	case r.MaxAttempts < 1:
+		return fmt.Errorf("max attempts must be at least 1")
+	case r.MaxAttempts > maxRetryAttempts:
+		return fmt.Errorf("max attempts can not be more than %d", maxRetryAttempts)
+	case r.Delay < 0 || r.MaxDelay < 0:
+		return fmt.Errorf("delay can not be negative")
+	case r.Jitter < 0 || r.Jitter > 1:
+		return fmt.Errorf("jitter must be between 0 and 1")
+	case !wfexec.Backoff(r.Backoff).Valid():
+		return fmt.Errorf("unknown backoff strategy %q", r.Backoff)
+	}
+
+	for _, k := range r.ErrorKinds {
+		switch k {
+		case wfexec.ErrorKindFailure, wfexec.ErrorKindAutomation, wfexec.ErrorKindTimeout:
+		default:
+			return fmt.Errorf("unknown or non-retryable error kind %q", k)
+		}
+	}
+
+	return nil
+}
+
+// Policy converts retry configuration to wfexec.RetryPolicy
+func (r *WorkflowStepRetry) Policy() *wfexec.RetryPolicy {
+	if r == nil {
+		return nil
+	}
+
+	return &wfexec.RetryPolicy{
+		MaxAttempts: r.MaxAttempts,
+		Delay:       time.Duration(r.Delay) * time.Second,
+		MaxDelay:    time.Duration(r.MaxDelay) * time.Second,
+		Backoff:     wfexec.Backoff(r.Backoff),
+		Jitter:      r.Jitter,
+		ErrorKinds:  r.ErrorKinds,
+	}
+}
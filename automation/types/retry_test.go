@@ -0,0 +1,50 @@
+package types
+
+import (
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWorkflowStepRetry_Validate(t *testing.T) {
+	tcc := []struct {
+		name  string
+		retry WorkflowStepRetry
+		err   string
+	}{
+		{"valid", WorkflowStepRetry{MaxAttempts: 3, Delay: 1, Backoff: "exponential", Jitter: 0.2, ErrorKinds: []string{"timeout"}}, ""},
+		{"no attempts", WorkflowStepRetry{}, "max attempts must be at least 1"},
+		{"too many attempts", WorkflowStepRetry{MaxAttempts: 101}, "max attempts can not be more than 100"},
+		{"negative delay", WorkflowStepRetry{MaxAttempts: 2, Delay: -1}, "delay can not be negative"},
+		{"jitter", WorkflowStepRetry{MaxAttempts: 2, Jitter: 2}, "jitter must be between 0 and 1"},
+		{"backoff", WorkflowStepRetry{MaxAttempts: 2, Backoff: "random"}, `unknown backoff strategy "random"`},
+		{"error kind", WorkflowStepRetry{MaxAttempts: 2, ErrorKinds: []string{"sessionTimeout"}}, `unknown or non-retryable error kind "sessionTimeout"`},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.retry.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWorkflowStepRetry_Policy(t *testing.T) {
+	var (
+		req = require.New(t)
+		r   = &WorkflowStepRetry{MaxAttempts: 3, Delay: 2, MaxDelay: 10, Backoff: "linear"}
+	)
+
+	req.Nil((*WorkflowStepRetry)(nil).Policy())
+	req.Equal(&wfexec.RetryPolicy{
+		MaxAttempts: 3,
+		Delay:       time.Second * 2,
+		MaxDelay:    time.Second * 10,
+		Backoff:     wfexec.BackoffLinear,
+	}, r.Policy())
+}
@@ -17,6 +17,9 @@ type (
 		ID         uint64 `json:"sessionID,string"`
 		WorkflowID uint64 `json:"workflowID,string"`
 
+		// Workflow revision the session was started on
+		Revision uint `json:"revision"`
+
 		Status SessionStatus `json:"status,string"`
 
 		EventType    string `json:"eventType"`
@@ -27,6 +30,18 @@ type (
 
 		Stacktrace Stacktrace `json:"stacktrace"`
 
+		// IDs of all sessions that (directly or indirectly) called this session
+		// as a subprocess; first item is the root session
+		CallStack []uint64 `json:"-"`
+
+		// Delayed and prompted states;
+		// used to restore the session after restart
+		States StateSet `json:"-"`
+
+		// How long is session kept after it is completed (or failed);
+		// used to calculate PurgeAt
+		KeepFor time.Duration `json:"-"`
+
 		CreatedAt time.Time  `json:"createdAt,omitempty"`
 		CreatedBy uint64     `json:"createdBy,string"`
 		PurgeAt   *time.Time `json:"purgeAt,omitempty"`
@@ -42,12 +57,17 @@ type (
 
 	SessionStartParams struct {
 		WorkflowID   uint64
+		Revision     uint
 		KeepFor      int
+		Timeout      int
 		Trace        bool
 		Input        *expr.Vars
 		StepID       uint64
 		EventType    string
 		ResourceType string
+
+		// IDs of caller sessions when workflow is started as a subprocess
+		CallStack []uint64
 	}
 
 	SessionFilter struct {
@@ -90,10 +110,35 @@ func NewSession(s *wfexec.Session) *Session {
 	}
 }
 
+// Restore binds wfexec session and restores all suspended states on it
+func (s *Session) Restore(es *wfexec.Session) error {
+	if s.ID != es.ID() {
+		return fmt.Errorf("session ID mismatch (%d != %d)", s.ID, es.ID())
+	}
+
+	ss := make([]*wfexec.SuspendedState, len(s.States))
+	for i, st := range s.States {
+		ss[i] = st.Suspended()
+	}
+
+	s.session = es
+	return es.Restore(ss...)
+}
+
+// SuspendedStates returns all delayed and prompted states of the underlying wfexec session
+func (s Session) SuspendedStates() StateSet {
+	return MakeStateSet(s.ID, s.session.SuspendedStates()...)
+}
+
 func (s Session) Exec(ctx context.Context, step wfexec.Step, input *expr.Vars) error {
 	return s.session.Exec(ctx, step, input)
 }
 
+// Cancel stops the underlying wfexec session and all its running steps
+func (s Session) Cancel() {
+	s.session.Cancel()
+}
+
 func (s Session) Resume(ctx context.Context, stateID uint64, input *expr.Vars) error {
 	return s.session.Resume(ctx, stateID, input)
 }
@@ -102,6 +147,14 @@ func (s Session) PendingPrompts(ownerId uint64) []*wfexec.PendingPrompt {
 	return s.session.PendingPrompts(ownerId)
 }
 
+func (s Session) PendingAwaits() []*wfexec.PendingAwait {
+	return s.session.PendingAwaits()
+}
+
+func (s Session) ResumeAwaiting(ctx context.Context, stateID uint64, input *expr.Vars) error {
+	return s.session.ResumeAwaiting(ctx, stateID, input)
+}
+
 // Wait blocks until workflow session is completed or fails (or context is canceled) and returns resuts
 func (s Session) WaitResults(ctx context.Context) (*expr.Vars, wfexec.SessionStatus, error) {
 	if err := s.session.WaitUntil(ctx, wfexec.SessionFailed, wfexec.SessionCompleted); err != nil {
@@ -113,13 +166,14 @@ func (s Session) WaitResults(ctx context.Context) (*expr.Vars, wfexec.SessionSta
 
 func (s *Session) Apply(ssp SessionStartParams) {
 	s.WorkflowID = ssp.WorkflowID
+	s.Revision = ssp.Revision
 	s.EventType = ssp.EventType
 	s.ResourceType = ssp.ResourceType
 	s.Input = ssp.Input
+	s.CallStack = ssp.CallStack
 
 	if ssp.KeepFor > 0 {
-		at := time.Now().Add(time.Duration(ssp.KeepFor) * time.Second)
-		s.PurgeAt = &at
+		s.KeepFor = time.Duration(ssp.KeepFor) * time.Second
 	}
 
 	if ssp.Trace {
@@ -128,6 +182,23 @@ func (s *Session) Apply(ssp SessionStartParams) {
 	}
 }
 
+// ApplyRetention calculates purge time from session's completion time and retention
+//
+// Sessions without retention are never purged
+func (s *Session) ApplyRetention() {
+	if s.KeepFor <= 0 || s.CompletedAt == nil {
+		return
+	}
+
+	at := s.CompletedAt.Add(s.KeepFor)
+	s.PurgeAt = &at
+}
+
+// Finished returns true if session is completed or failed
+func (s Session) Finished() bool {
+	return s.Status.Finished()
+}
+
 func (set *Stacktrace) Scan(value interface{}) error {
 	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
 	switch value.(type) {
@@ -163,6 +234,16 @@ func (s SessionStatus) String() string {
 	return "unknown"
 }
 
+// Suspended reports if status is prompted or suspended
+func (s SessionStatus) Suspended() bool {
+	return s == SessionPrompted || s == SessionSuspended
+}
+
+// Finished reports if status is completed or failed
+func (s SessionStatus) Finished() bool {
+	return s == SessionCompleted || s == SessionFailed
+}
+
 func (s SessionStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.String())
 }
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSession_ApplyRetention(t *testing.T) {
+	var (
+		req = require.New(t)
+		at  = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+		ses = &Session{}
+	)
+
+	ses.Apply(SessionStartParams{KeepFor: 60})
+	req.Equal(time.Minute, ses.KeepFor)
+	req.Nil(ses.PurgeAt, "purge time should not be set before session is completed")
+
+	ses.ApplyRetention()
+	req.Nil(ses.PurgeAt)
+
+	ses.CompletedAt = &at
+	ses.ApplyRetention()
+	req.NotNil(ses.PurgeAt)
+	req.Equal(at.Add(time.Minute), *ses.PurgeAt)
+
+	ses = &Session{CompletedAt: &at}
+	ses.ApplyRetention()
+	req.Nil(ses.PurgeAt, "sessions without retention should not be purged")
+}
@@ -1,7 +1,11 @@
 package types
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
 	"time"
 )
 
@@ -18,8 +22,117 @@ type (
 		CreatedAt time.Time `json:"createdAt,omitempty"`
 		CreatedBy uint64    `json:"createdBy,string"`
 
-		CallerID uint64     `json:"callerID,string"`
-		StepID   uint64     `json:"stepID,string"`
-		Scope    *expr.Vars `json:"scope"`
+		// roles of the state owner (CreatedBy)
+		Roles []uint64 `json:"roles,omitempty"`
+
+		CallerID     uint64     `json:"callerID,string"`
+		StepID       uint64     `json:"stepID,string"`
+		ErrHandlerID uint64     `json:"errHandlerID,string,omitempty"`
+		Scope        *expr.Vars `json:"scope"`
+		Input        *expr.Vars `json:"input,omitempty"`
+
+		// set when failed step is waiting to be retried
+		Retry   bool `json:"retry,omitempty"`
+		Attempt int  `json:"attempt,omitempty"`
+
+		// prompt reference and payload; only set when waiting for input
+		PromptRef     string     `json:"promptRef,omitempty"`
+		PromptPayload *expr.Vars `json:"promptPayload,omitempty"`
+
+		// await reference and payload; only set when waiting for event
+		WaitingForEvent bool       `json:"waitingForEvent,omitempty"`
+		AwaitRef        string     `json:"awaitRef,omitempty"`
+		AwaitPayload    *expr.Vars `json:"awaitPayload,omitempty"`
 	}
 )
+
+// MakeStateSet converts suspended wfexec states into a set of (storable) states
+func MakeStateSet(sessionID uint64, ss ...*wfexec.SuspendedState) StateSet {
+	set := make(StateSet, len(ss))
+	for i, s := range ss {
+		set[i] = &State{
+			ID:              s.StateID,
+			SessionID:       sessionID,
+			ResumeAt:        s.ResumeAt,
+			WaitingForInput: s.Prompted,
+			CreatedAt:       s.CreatedAt,
+			CreatedBy:       s.OwnerID,
+			Roles:           s.OwnerRoles,
+			CallerID:        s.ParentID,
+			StepID:          s.StepID,
+			ErrHandlerID:    s.ErrHandlerID,
+			Scope:           s.Scope,
+			Input:           s.Input,
+			Retry:           s.Retry,
+			Attempt:         s.Attempt,
+			PromptRef:       s.PromptRef,
+			PromptPayload:   s.PromptPayload,
+			WaitingForEvent: s.Awaiting,
+			AwaitRef:        s.AwaitRef,
+			AwaitPayload:    s.AwaitPayload,
+		}
+	}
+
+	return set
+}
+
+// Suspended converts state back to wfexec.SuspendedState
+func (s State) Suspended() *wfexec.SuspendedState {
+	return &wfexec.SuspendedState{
+		StateID:       s.ID,
+		CreatedAt:     s.CreatedAt,
+		OwnerID:       s.CreatedBy,
+		OwnerRoles:    s.Roles,
+		ParentID:      s.CallerID,
+		StepID:        s.StepID,
+		ErrHandlerID:  s.ErrHandlerID,
+		Scope:         s.Scope,
+		Input:         s.Input,
+		Retry:         s.Retry,
+		Attempt:       s.Attempt,
+		ResumeAt:      s.ResumeAt,
+		Prompted:      s.WaitingForInput,
+		PromptRef:     s.PromptRef,
+		PromptPayload: s.PromptPayload,
+		Awaiting:      s.WaitingForEvent,
+		AwaitRef:      s.AwaitRef,
+		AwaitPayload:  s.AwaitPayload,
+	}
+}
+
+// ResolveTypes resolves types on all state variables
+//
+// Variables are unresolved after they are decoded from JSON
+func (s *State) ResolveTypes(res func(typ string) expr.Type) (err error) {
+	for _, vars := range []*expr.Vars{s.Scope, s.Input, s.PromptPayload, s.AwaitPayload} {
+		if vars == nil {
+			continue
+		}
+
+		if err = vars.ResolveTypes(res); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+func (set *StateSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*set = StateSet{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, set); err != nil {
+			return fmt.Errorf("can not scan '%v' into StateSet: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+// Scan on StateSet gracefully handles conversion from NULL
+func (set StateSet) Value() (driver.Value, error) {
+	return json.Marshal(set)
+}
@@ -27,6 +27,12 @@ type (
 		Meta WorkflowStepMeta `json:"meta,omitempty"`
 
 		Labels map[string]string `json:"labels,omitempty"`
+
+		// max duration of step execution (in sec)
+		Timeout int `json:"timeout,omitempty"`
+
+		// retrying of failed function, iterator and subprocess steps
+		Retry *WorkflowStepRetry `json:"retry,omitempty"`
 	}
 
 	WorkflowStepMeta struct {
@@ -57,33 +63,37 @@ type (
 )
 
 const (
-	WorkflowStepKindExpressions WorkflowStepKind = "expressions"   // no ref
-	WorkflowStepKindGateway     WorkflowStepKind = "gateway"       // ref = join|fork|excl|incl
-	WorkflowStepKindFunction    WorkflowStepKind = "function"      // ref = <function ref>
-	WorkflowStepKindIterator    WorkflowStepKind = "iterator"      // ref = <iterator function ref>
-	WorkflowStepKindError       WorkflowStepKind = "error"         // no ref
-	WorkflowStepKindTermination WorkflowStepKind = "termination"   // no ref
-	WorkflowStepKindPrompt      WorkflowStepKind = "prompt"        // ref = <client function>
-	WorkflowStepKindDelay       WorkflowStepKind = "delay"         // no ref
-	WorkflowStepKindErrHandler  WorkflowStepKind = "error-handler" // no ref
-	WorkflowStepKindVisual      WorkflowStepKind = "visual"        // ref = <*>
-	WorkflowStepKindDebug       WorkflowStepKind = "debug"         // ref = <*>
-	WorkflowStepKindBreak       WorkflowStepKind = "break"         // ref = <*>
-	WorkflowStepKindContinue    WorkflowStepKind = "continue"      // ref = <*>
+	WorkflowStepKindExpressions WorkflowStepKind = "expressions"    // no ref
+	WorkflowStepKindGateway     WorkflowStepKind = "gateway"        // ref = join|fork|excl|incl
+	WorkflowStepKindFunction    WorkflowStepKind = "function"       // ref = <function ref>
+	WorkflowStepKindIterator    WorkflowStepKind = "iterator"       // ref = <iterator function ref>
+	WorkflowStepKindError       WorkflowStepKind = "error"          // no ref
+	WorkflowStepKindTermination WorkflowStepKind = "termination"    // no ref
+	WorkflowStepKindPrompt      WorkflowStepKind = "prompt"         // ref = <client function>
+	WorkflowStepKindDelay       WorkflowStepKind = "delay"          // no ref
+	WorkflowStepKindErrHandler  WorkflowStepKind = "error-handler"  // no ref
+	WorkflowStepKindVisual      WorkflowStepKind = "visual"         // ref = <*>
+	WorkflowStepKindDebug       WorkflowStepKind = "debug"          // ref = <*>
+	WorkflowStepKindBreak       WorkflowStepKind = "break"          // ref = <*>
+	WorkflowStepKindContinue    WorkflowStepKind = "continue"       // ref = <*>
+	WorkflowStepKindSubprocess  WorkflowStepKind = "subprocess"     // ref = <workflow ID or handle>
+	WorkflowStepKindEventWait   WorkflowStepKind = "wait-for-event" // no ref
 )
 
-// IsDeferred fn returns true if type of step is delay or prompt
+// IsDeferred fn returns true if type of step is delay, prompt or wait-for-event
 func (s WorkflowStep) IsDeferred() (is bool) {
 	switch s.Kind {
 	case WorkflowStepKindPrompt:
 		return true
 	case WorkflowStepKindDelay:
 		return true
+	case WorkflowStepKindEventWait:
+		return true
 	}
 	return false
 }
 
-// HasDeferred fn returns true if type of any of workflow's steps is delay or prompt
+// HasDeferred fn returns true if type of any of workflow's steps is deferred
 func (vv WorkflowStepSet) HasDeferred() bool {
 	for _, s := range vv {
 		if s.IsDeferred() {
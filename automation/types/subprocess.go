@@ -0,0 +1,130 @@
+package types
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+)
+
+type (
+	// SubprocessHandler starts workflow (referenced by ID or handle) as a subprocess
+	// of the calling session and returns ID of the started session
+	//
+	// Subprocess is executed asynchronously; calling state is resumed when it completes or fails
+	SubprocessHandler func(ctx context.Context, ref string, callerSessionID uint64, input *expr.Vars) (uint64, error)
+
+	subprocessStep struct {
+		wfexec.StepIdentifier
+		ref       string
+		arguments ExprSet
+		results   ExprSet
+		handler   SubprocessHandler
+
+		// when set, subprocess is not started and
+		// mocked results are used instead (dry run)
+		mock FunctionHandler
+	}
+)
+
+const (
+	// SubprocessRef is used as a reference on awaiting states
+	// that are waiting for subprocess to complete
+	SubprocessRef = "subprocess"
+
+	subprocessArgSessionID = "sessionID"
+
+	// input variables of the resumed state
+	SubprocessResults = "results"
+	SubprocessError   = "error"
+)
+
+// SubprocessStep initializes new step that executes another workflow
+func SubprocessStep(ref string, arguments, results ExprSet, h SubprocessHandler) *subprocessStep {
+	return &subprocessStep{ref: ref, arguments: arguments, results: results, handler: h}
+}
+
+// MockedSubprocessStep initializes subprocess step that does not start another workflow
+// but uses results of the given (mocked) function handler
+func MockedSubprocessStep(ref string, arguments, results ExprSet, mock FunctionHandler) *subprocessStep {
+	return &subprocessStep{ref: ref, arguments: arguments, results: results, mock: mock}
+}
+
+// Exec executes subprocess step
+//
+// Configured arguments are evaluated with the step's scope and input and passed
+// to the subprocess as its input scope. Subprocess is started and the calling
+// state is suspended (awaiting) until subprocess completes or fails.
+//
+// When state is resumed, configured results are evaluated with the final scope of the subprocess
+func (s subprocessStep) Exec(ctx context.Context, r *wfexec.ExecRequest) (wfexec.ExecResponse, error) {
+	var (
+		args, results *expr.Vars
+		err           error
+	)
+
+	if r.Input != nil && r.Input.Has("resumed") {
+		return s.resumed(ctx, r.Input)
+	}
+
+	if len(s.arguments) > 0 {
+		args, err = s.arguments.Eval(ctx, r.Scope.Merge(r.Input))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.mock != nil {
+		if results, err = s.mock(ctx, args); err != nil {
+			return nil, err
+		}
+
+		return s.evalResults(ctx, results)
+	}
+
+	sessionID, err := s.handler(ctx, s.ref, r.SessionID, args)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := expr.RVars{
+		subprocessArgSessionID: expr.Must(expr.NewID(sessionID)),
+	}.Vars()
+
+	return wfexec.Await(SubprocessRef, payload, nil), nil
+}
+
+func (s subprocessStep) resumed(ctx context.Context, input *expr.Vars) (wfexec.ExecResponse, error) {
+	if input.Has(SubprocessError) {
+		v, _ := input.Select(SubprocessError)
+		msg, _ := expr.CastToString(v)
+		return nil, errors.Automation("subprocess failed: %s", msg)
+	}
+
+	var results *expr.Vars
+	if input.Has(SubprocessResults) {
+		v, _ := input.Select(SubprocessResults)
+		results, _ = v.(*expr.Vars)
+	}
+
+	return s.evalResults(ctx, results)
+}
+
+func (s subprocessStep) evalResults(ctx context.Context, results *expr.Vars) (wfexec.ExecResponse, error) {
+	if len(s.results) == 0 || results == nil {
+		// No results defined, nothing to return
+		return expr.NewVars(nil)
+	}
+
+	return s.results.Eval(ctx, results)
+}
+
+// ParseSubprocess returns ID of the subprocess session from the awaiting state payload
+func ParseSubprocess(payload *expr.Vars) (uint64, error) {
+	v, err := payload.Select(subprocessArgSessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return expr.CastToID(v)
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSubprocessStep_Exec(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		step = SubprocessStep(
+			"child",
+			ExprSet{&Expr{Target: "in", Source: "foo", typ: &expr.String{}}},
+			ExprSet{&Expr{Target: "bar", Source: "out", typ: &expr.String{}}},
+			func(ctx context.Context, ref string, callerSessionID uint64, input *expr.Vars) (uint64, error) {
+				req.Equal("child", ref)
+				req.Equal(uint64(42), callerSessionID)
+				req.Equal("foo", expr.Must(input.Select("in")).Get())
+
+				return 43, nil
+			},
+		)
+
+		scope = expr.RVars{"foo": expr.Must(expr.NewString("foo"))}.Vars()
+	)
+
+	// subprocess is started and state is suspended
+	rsp, err := step.Exec(ctx, &wfexec.ExecRequest{SessionID: 42, Scope: scope})
+	req.NoError(err)
+	req.IsType(wfexec.Await("", nil, nil), rsp)
+
+	// state is resumed with subprocess results
+	input := expr.RVars{
+		"resumed": expr.Must(expr.NewBoolean(true)),
+		SubprocessResults: expr.RVars{
+			"out":    expr.Must(expr.NewString("from child")),
+			"hidden": expr.Must(expr.NewString("not mapped")),
+		}.Vars(),
+	}.Vars()
+
+	rsp, err = step.Exec(ctx, &wfexec.ExecRequest{SessionID: 42, Scope: scope, Input: input})
+	req.NoError(err)
+	req.IsType(&expr.Vars{}, rsp)
+
+	results := rsp.(*expr.Vars)
+	req.Equal("from child", expr.Must(results.Select("bar")).Get())
+	req.False(results.Has("hidden"))
+
+	// state is resumed with subprocess error
+	input = expr.RVars{
+		"resumed":       expr.Must(expr.NewBoolean(true)),
+		SubprocessError: expr.Must(expr.NewString("child failed")),
+	}.Vars()
+
+	_, err = step.Exec(ctx, &wfexec.ExecRequest{SessionID: 42, Scope: scope, Input: input})
+	req.EqualError(err, "subprocess failed: child failed")
+	req.Equal(wfexec.ErrorKindAutomation, wfexec.ErrorKind(err))
+}
+
+func TestSubprocessStep_ExecFailedStart(t *testing.T) {
+	step := SubprocessStep("child", nil, nil, func(context.Context, string, uint64, *expr.Vars) (uint64, error) {
+		return 0, fmt.Errorf("workflow disabled")
+	})
+
+	_, err := step.Exec(context.Background(), &wfexec.ExecRequest{SessionID: 42})
+	require.EqualError(t, err, "workflow disabled")
+}
+
+func TestSubprocessStep_ExecMocked(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		step = MockedSubprocessStep(
+			"child",
+			nil,
+			ExprSet{&Expr{Target: "bar", Source: "out", typ: &expr.String{}}},
+			func(context.Context, *expr.Vars) (*expr.Vars, error) {
+				return expr.RVars{"out": expr.Must(expr.NewString("mocked"))}.Vars(), nil
+			},
+		)
+	)
+
+	rsp, err := step.Exec(ctx, &wfexec.ExecRequest{SessionID: 42})
+	req.NoError(err)
+	req.Equal("mocked", expr.Must(rsp.(*expr.Vars).Select("bar")).Get())
+}
+
+func TestParseSubprocess(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	ID, err := ParseSubprocess(expr.RVars{subprocessArgSessionID: expr.Must(expr.NewID(uint64(43)))}.Vars())
+	req.NoError(err)
+	req.Equal(uint64(43), ID)
+
+	_, err = ParseSubprocess(&expr.Vars{})
+	req.Error(err)
+}
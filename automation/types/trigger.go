@@ -31,6 +31,10 @@ type (
 		// will be merged merged with workflow variables
 		Input *expr.Vars `json:"input"`
 
+		// Webhook configuration,
+		// used only by triggers with webhook resource type
+		Webhook *TriggerWebhook `json:"webhook,omitempty"`
+
 		Labels map[string]string `json:"labels,omitempty"`
 		Meta   *TriggerMeta      `json:"meta,omitempty"`
 
@@ -54,6 +58,31 @@ type (
 		Visual      map[string]interface{} `json:"visual"`
 	}
 
+	TriggerWebhook struct {
+		// Secret for verifying request signatures;
+		// generated when not set
+		//
+		// Returned only when the trigger is created or the secret is
+		// changed or regenerated, omitted from all other responses
+		Secret string `json:"secret,omitempty"`
+
+		// Signature scheme used by the caller:
+		// github (default), stripe or none
+		Signature string `json:"signature,omitempty"`
+
+		// Name of the header with the signature
+		// when it differs from the scheme's default
+		Header string `json:"header,omitempty"`
+
+		// When set, only requests from these IP addresses
+		// or networks (CIDR) are accepted
+		AllowedIPs []string `json:"allowedIPs,omitempty"`
+
+		// Wait for the workflow to complete and use
+		// its results for the HTTP response
+		Sync bool `json:"sync"`
+	}
+
 	TriggerFilter struct {
 		TriggerID  []uint64 `json:"triggerID"`
 		WorkflowID []uint64 `json:"workflowID"`
@@ -79,11 +108,32 @@ type (
 	}
 )
 
+const (
+	// WebhookResourceType is used by triggers that start
+	// workflows on incoming webhook requests
+	WebhookResourceType = "automation:webhook"
+	WebhookEventType    = "onRequest"
+
+	WebhookSignatureGithub = "github"
+	WebhookSignatureStripe = "stripe"
+	WebhookSignatureNone   = "none"
+)
+
+// IsWebhook returns true if trigger starts workflow on incoming webhook requests
+func (t Trigger) IsWebhook() bool {
+	return t.ResourceType == WebhookResourceType
+}
+
 func ParseTriggerMeta(ss []string) (p *TriggerMeta, err error) {
 	p = &TriggerMeta{}
 	return p, parseStringsInput(ss, p)
 }
 
+func ParseTriggerWebhook(ss []string) (p *TriggerWebhook, err error) {
+	p = &TriggerWebhook{}
+	return p, parseStringsInput(ss, p)
+}
+
 func ParseTriggerConstraintSet(ss []string) (p TriggerConstraintSet, err error) {
 	p = TriggerConstraintSet{}
 	return p, parseStringsInput(ss, &p)
@@ -128,6 +178,30 @@ func (vv *TriggerMeta) Value() (driver.Value, error) {
 	return json.Marshal(vv)
 }
 
+func (vv *TriggerWebhook) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*vv = TriggerWebhook{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, vv); err != nil {
+			return fmt.Errorf("can not scan '%v' into TriggerWebhook: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+// Value on TriggerWebhook gracefully handles conversion from NULL
+func (vv *TriggerWebhook) Value() (driver.Value, error) {
+	if vv == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(vv)
+}
+
 // Scan on TriggerConstraintSet gracefully handles conversion from NULL
 func (vv TriggerConstraintSet) Value() (driver.Value, error) {
 	return json.Marshal(vv)
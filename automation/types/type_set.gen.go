@@ -45,6 +45,16 @@ type (
 	// This type is auto-generated.
 	WorkflowPathSet []*WorkflowPath
 
+	// WorkflowRevisionSet slice of WorkflowRevision
+	//
+	// This type is auto-generated.
+	WorkflowRevisionSet []*WorkflowRevision
+
+	// WorkflowStatsBucketSet slice of WorkflowStatsBucket
+	//
+	// This type is auto-generated.
+	WorkflowStatsBucketSet []*WorkflowStatsBucket
+
 	// WorkflowStepSet slice of WorkflowStep
 	//
 	// This type is auto-generated.
@@ -365,6 +375,118 @@ func (set WorkflowPathSet) Filter(f func(*WorkflowPath) (bool, error)) (out Work
 	return
 }
 
+// Walk iterates through every slice item and calls w(WorkflowRevision) err
+//
+// This function is auto-generated.
+func (set WorkflowRevisionSet) Walk(w func(*WorkflowRevision) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(WorkflowRevision) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set WorkflowRevisionSet) Filter(f func(*WorkflowRevision) (bool, error)) (out WorkflowRevisionSet, err error) {
+	var ok bool
+	out = WorkflowRevisionSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set WorkflowRevisionSet) FindByID(ID uint64) *WorkflowRevision {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set WorkflowRevisionSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
+// Walk iterates through every slice item and calls w(WorkflowStatsBucket) err
+//
+// This function is auto-generated.
+func (set WorkflowStatsBucketSet) Walk(w func(*WorkflowStatsBucket) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(WorkflowStatsBucket) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set WorkflowStatsBucketSet) Filter(f func(*WorkflowStatsBucket) (bool, error)) (out WorkflowStatsBucketSet, err error) {
+	var ok bool
+	out = WorkflowStatsBucketSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set WorkflowStatsBucketSet) FindByID(ID uint64) *WorkflowStatsBucket {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set WorkflowStatsBucketSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(WorkflowStep) err
 //
 // This function is auto-generated.
@@ -542,6 +542,186 @@ func TestWorkflowPathSetFilter(t *testing.T) {
 	}
 }
 
+func TestWorkflowRevisionSetWalk(t *testing.T) {
+	var (
+		value = make(WorkflowRevisionSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*WorkflowRevision) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*WorkflowRevision) error { return fmt.Errorf("walk error") }))
+}
+
+func TestWorkflowRevisionSetFilter(t *testing.T) {
+	var (
+		value = make(WorkflowRevisionSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*WorkflowRevision) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*WorkflowRevision) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*WorkflowRevision) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestWorkflowRevisionSetIDs(t *testing.T) {
+	var (
+		value = make(WorkflowRevisionSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(WorkflowRevision)
+	value[1] = new(WorkflowRevision)
+	value[2] = new(WorkflowRevision)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
+func TestWorkflowStatsBucketSetWalk(t *testing.T) {
+	var (
+		value = make(WorkflowStatsBucketSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*WorkflowStatsBucket) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*WorkflowStatsBucket) error { return fmt.Errorf("walk error") }))
+}
+
+func TestWorkflowStatsBucketSetFilter(t *testing.T) {
+	var (
+		value = make(WorkflowStatsBucketSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*WorkflowStatsBucket) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*WorkflowStatsBucket) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*WorkflowStatsBucket) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestWorkflowStatsBucketSetIDs(t *testing.T) {
+	var (
+		value = make(WorkflowStatsBucketSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(WorkflowStatsBucket)
+	value[1] = new(WorkflowStatsBucket)
+	value[2] = new(WorkflowStatsBucket)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
 func TestWorkflowStepSetWalk(t *testing.T) {
 	var (
 		value = make(WorkflowStepSet, 3)
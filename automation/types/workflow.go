@@ -24,12 +24,19 @@ type (
 		// how much time do we keep completed sessions (in sec)
 		KeepSessions int `json:"keepSessions"`
 
+		// max duration of workflow session (in sec)
+		Timeout int `json:"timeout"`
+
 		// Initial input scope
 		Scope *expr.Vars `json:"scope"`
 
 		Steps WorkflowStepSet `json:"steps"`
 		Paths WorkflowPathSet `json:"paths"`
 
+		// Revision that triggers are bound to;
+		// scope, steps and paths above are a draft that can differ from it
+		PublishedRevision uint `json:"publishedRevision"`
+
 		// Collection of issues from the last parse
 		Issues WorkflowIssueSet `json:"issues,omitempty"`
 
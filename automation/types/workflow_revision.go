@@ -0,0 +1,196 @@
+package types
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// WorkflowRevision holds immutable snapshot of workflow definition
+	//
+	// Revisions are created when workflow is published; triggers
+	// are bound only to the published revision of the workflow
+	WorkflowRevision struct {
+		ID         uint64 `json:"revisionID,string"`
+		WorkflowID uint64 `json:"workflowID,string"`
+
+		// Sequential revision number (per workflow)
+		Revision uint `json:"revision"`
+
+		Scope *expr.Vars      `json:"scope"`
+		Steps WorkflowStepSet `json:"steps"`
+		Paths WorkflowPathSet `json:"paths"`
+
+		CreatedAt time.Time `json:"createdAt,omitempty"`
+		CreatedBy uint64    `json:"createdBy,string"`
+	}
+
+	WorkflowRevisionFilter struct {
+		WorkflowID uint64 `json:"workflowID,string"`
+		Revision   uint   `json:"revision"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*WorkflowRevision) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+
+	// WorkflowRevisionDiff lists differences between two workflow definitions
+	WorkflowRevisionDiff struct {
+		From uint `json:"from"`
+		To   uint `json:"to"`
+
+		// Names of scope variables that were added, removed or modified
+		Scope []string `json:"scope"`
+
+		Steps WorkflowStepDiffSet `json:"steps"`
+		Paths WorkflowPathDiffSet `json:"paths"`
+	}
+
+	WorkflowStepDiff struct {
+		StepID uint64         `json:"stepID,string"`
+		Change WorkflowChange `json:"change"`
+		Old    *WorkflowStep  `json:"old,omitempty"`
+		New    *WorkflowStep  `json:"new,omitempty"`
+	}
+
+	WorkflowStepDiffSet []*WorkflowStepDiff
+
+	WorkflowPathDiff struct {
+		ParentID uint64         `json:"parentID,string"`
+		ChildID  uint64         `json:"childID,string"`
+		Change   WorkflowChange `json:"change"`
+		Old      *WorkflowPath  `json:"old,omitempty"`
+		New      *WorkflowPath  `json:"new,omitempty"`
+	}
+
+	WorkflowPathDiffSet []*WorkflowPathDiff
+
+	WorkflowChange string
+)
+
+const (
+	WorkflowChangeAdded    WorkflowChange = "added"
+	WorkflowChangeRemoved  WorkflowChange = "removed"
+	WorkflowChangeModified WorkflowChange = "modified"
+)
+
+// MakeWorkflowRevision creates new revision from the current workflow definition
+func MakeWorkflowRevision(wf *Workflow) *WorkflowRevision {
+	return &WorkflowRevision{
+		WorkflowID: wf.ID,
+		Scope:      wf.Scope,
+		Steps:      wf.Steps,
+		Paths:      wf.Paths,
+	}
+}
+
+// Apply returns copy of the workflow with definition from the revision
+//
+// Everything but scope, steps and paths is copied from the given workflow
+func (rev WorkflowRevision) Apply(wf *Workflow) *Workflow {
+	var c = *wf
+	c.Scope = rev.Scope
+	c.Steps = rev.Steps
+	c.Paths = rev.Paths
+	c.Issues = nil
+	return &c
+}
+
+// DiffWorkflowRevisions compares definitions of two revisions
+//
+// Steps are compared by their ID and paths by their parent & child step IDs
+func DiffWorkflowRevisions(old, new *WorkflowRevision) *WorkflowRevisionDiff {
+	var (
+		d = &WorkflowRevisionDiff{
+			From:  old.Revision,
+			To:    new.Revision,
+			Scope: diffScope(old.Scope, new.Scope),
+			Steps: WorkflowStepDiffSet{},
+			Paths: WorkflowPathDiffSet{},
+		}
+
+		oldSteps = make(map[uint64]*WorkflowStep)
+		newSteps = make(map[uint64]*WorkflowStep)
+		oldPaths = make(map[[2]uint64]*WorkflowPath)
+		newPaths = make(map[[2]uint64]*WorkflowPath)
+	)
+
+	for _, s := range old.Steps {
+		oldSteps[s.ID] = s
+	}
+
+	for _, s := range new.Steps {
+		newSteps[s.ID] = s
+
+		switch o := oldSteps[s.ID]; {
+		case o == nil:
+			d.Steps = append(d.Steps, &WorkflowStepDiff{StepID: s.ID, Change: WorkflowChangeAdded, New: s})
+		case !reflect.DeepEqual(o, s):
+			d.Steps = append(d.Steps, &WorkflowStepDiff{StepID: s.ID, Change: WorkflowChangeModified, Old: o, New: s})
+		}
+	}
+
+	for _, s := range old.Steps {
+		if newSteps[s.ID] == nil {
+			d.Steps = append(d.Steps, &WorkflowStepDiff{StepID: s.ID, Change: WorkflowChangeRemoved, Old: s})
+		}
+	}
+
+	for _, p := range old.Paths {
+		oldPaths[[2]uint64{p.ParentID, p.ChildID}] = p
+	}
+
+	for _, p := range new.Paths {
+		key := [2]uint64{p.ParentID, p.ChildID}
+		newPaths[key] = p
+
+		switch o := oldPaths[key]; {
+		case o == nil:
+			d.Paths = append(d.Paths, &WorkflowPathDiff{ParentID: p.ParentID, ChildID: p.ChildID, Change: WorkflowChangeAdded, New: p})
+		case o.Expr != p.Expr || !reflect.DeepEqual(o.Meta, p.Meta):
+			d.Paths = append(d.Paths, &WorkflowPathDiff{ParentID: p.ParentID, ChildID: p.ChildID, Change: WorkflowChangeModified, Old: o, New: p})
+		}
+	}
+
+	for _, p := range old.Paths {
+		if newPaths[[2]uint64{p.ParentID, p.ChildID}] == nil {
+			d.Paths = append(d.Paths, &WorkflowPathDiff{ParentID: p.ParentID, ChildID: p.ChildID, Change: WorkflowChangeRemoved, Old: p})
+		}
+	}
+
+	return d
+}
+
+// returns names of all variables that differ between two scopes
+func diffScope(old, new *expr.Vars) (out []string) {
+	var (
+		oldVars = old.Dict()
+		newVars = new.Dict()
+	)
+
+	out = make([]string, 0)
+	for k, v := range newVars {
+		if o, has := oldVars[k]; !has || !reflect.DeepEqual(o, v) {
+			out = append(out, k)
+		}
+	}
+
+	for k := range oldVars {
+		if _, has := newVars[k]; !has {
+			out = append(out, k)
+		}
+	}
+
+	sort.Strings(out)
+	return
+}
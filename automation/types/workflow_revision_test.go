@@ -0,0 +1,96 @@
+package types
+
+import (
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDiffWorkflowRevisions(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		old = &WorkflowRevision{
+			Revision: 1,
+			Scope: expr.RVars{
+				"kept":    expr.Must(expr.NewString("same")),
+				"changed": expr.Must(expr.NewString("old")),
+				"removed": expr.Must(expr.NewString("old")),
+			}.Vars(),
+			Steps: WorkflowStepSet{
+				{ID: 1, Kind: WorkflowStepKindExpressions},
+				{ID: 2, Kind: WorkflowStepKindFunction, Ref: "old"},
+				{ID: 3, Kind: WorkflowStepKindTermination},
+			},
+			Paths: WorkflowPathSet{
+				{ParentID: 1, ChildID: 2},
+				{ParentID: 2, ChildID: 3, Expr: "true"},
+			},
+		}
+
+		new = &WorkflowRevision{
+			Revision: 2,
+			Scope: expr.RVars{
+				"kept":    expr.Must(expr.NewString("same")),
+				"changed": expr.Must(expr.NewString("new")),
+				"added":   expr.Must(expr.NewString("new")),
+			}.Vars(),
+			Steps: WorkflowStepSet{
+				{ID: 1, Kind: WorkflowStepKindExpressions},
+				{ID: 2, Kind: WorkflowStepKindFunction, Ref: "new"},
+				{ID: 4, Kind: WorkflowStepKindTermination},
+			},
+			Paths: WorkflowPathSet{
+				{ParentID: 1, ChildID: 2},
+				{ParentID: 2, ChildID: 4},
+			},
+		}
+
+		diff = DiffWorkflowRevisions(old, new)
+	)
+
+	req.Equal(uint(1), diff.From)
+	req.Equal(uint(2), diff.To)
+	req.Equal([]string{"added", "changed", "removed"}, diff.Scope)
+
+	req.Len(diff.Steps, 3)
+	req.Equal(uint64(2), diff.Steps[0].StepID)
+	req.Equal(WorkflowChangeModified, diff.Steps[0].Change)
+	req.Equal(uint64(4), diff.Steps[1].StepID)
+	req.Equal(WorkflowChangeAdded, diff.Steps[1].Change)
+	req.Equal(uint64(3), diff.Steps[2].StepID)
+	req.Equal(WorkflowChangeRemoved, diff.Steps[2].Change)
+
+	req.Len(diff.Paths, 2)
+	req.Equal(WorkflowChangeAdded, diff.Paths[0].Change)
+	req.Equal(uint64(4), diff.Paths[0].ChildID)
+	req.Equal(WorkflowChangeRemoved, diff.Paths[1].Change)
+	req.Equal(uint64(3), diff.Paths[1].ChildID)
+
+	req.Empty(DiffWorkflowRevisions(new, new).Steps)
+}
+
+func TestWorkflowRevision_Apply(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		wf = &Workflow{
+			ID:     42,
+			Handle: "wf",
+			Steps:  WorkflowStepSet{{ID: 1}},
+			Issues: WorkflowIssueSet{{Description: "draft issue"}},
+		}
+
+		rev = &WorkflowRevision{Steps: WorkflowStepSet{{ID: 2}, {ID: 3}}}
+
+		published = rev.Apply(wf)
+	)
+
+	req.Equal("wf", published.Handle)
+	req.Len(published.Steps, 2)
+	req.Empty(published.Issues)
+
+	// original workflow is left intact
+	req.Len(wf.Steps, 1)
+	req.Len(wf.Issues, 1)
+}
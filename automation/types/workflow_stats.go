@@ -0,0 +1,155 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// WorkflowStats holds aggregated execution stats of a workflow
+	// and its steps for the given time window
+	WorkflowStats struct {
+		WorkflowID uint64    `json:"workflowID,string"`
+		From       time.Time `json:"from"`
+		To         time.Time `json:"to"`
+
+		ExecutionStats
+
+		Steps []*WorkflowStepStats `json:"steps"`
+	}
+
+	WorkflowStepStats struct {
+		StepID uint64 `json:"stepID,string"`
+
+		ExecutionStats
+	}
+
+	// ExecutionStats holds counters and durations of
+	// workflow session or workflow step executions
+	ExecutionStats struct {
+		Executions uint `json:"executions"`
+		Failures   uint `json:"failures"`
+		Suspended  uint `json:"suspended"`
+
+		// Number of finished (completed or failed) executions
+		// durations are calculated from
+		Finished uint `json:"finished"`
+
+		DurationTotal time.Duration `json:"durationTotal"`
+		DurationMin   time.Duration `json:"durationMin"`
+		DurationMax   time.Duration `json:"durationMax"`
+		DurationAvg   time.Duration `json:"durationAvg"`
+	}
+
+	WorkflowStatsFilter struct {
+		From *time.Time `json:"from,omitempty"`
+		To   *time.Time `json:"to,omitempty"`
+	}
+
+	// WorkflowStatsBucket holds execution stats of a workflow and its steps
+	// collected by one node in one interval (bucket)
+	//
+	// Buckets are kept in the store so that stats are aggregated
+	// from all nodes and survive restarts
+	WorkflowStatsBucket struct {
+		ID         uint64 `json:"bucketID,string"`
+		WorkflowID uint64 `json:"workflowID,string"`
+
+		// Start of the interval
+		StartAt time.Time `json:"startAt"`
+
+		Stats ExecutionStats           `json:"stats"`
+		Steps WorkflowStatsBucketSteps `json:"steps"`
+	}
+
+	// WorkflowStatsBucketSteps holds execution stats of each step (by step ID)
+	WorkflowStatsBucketSteps map[uint64]*ExecutionStats
+
+	WorkflowStatsBucketFilter struct {
+		WorkflowID uint64 `json:"workflowID,string"`
+
+		// Filter buckets by start of the interval (inclusive)
+		From *time.Time `json:"from,omitempty"`
+		To   *time.Time `json:"to,omitempty"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*WorkflowStatsBucket) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+// Observe adds duration of one finished execution
+func (s *ExecutionStats) Observe(d time.Duration) {
+	s.Merge(ExecutionStats{Finished: 1, DurationTotal: d, DurationMin: d, DurationMax: d})
+}
+
+// Merge adds counters and durations from another set of stats
+func (s *ExecutionStats) Merge(o ExecutionStats) {
+	s.Executions += o.Executions
+	s.Failures += o.Failures
+	s.Suspended += o.Suspended
+
+	if o.Finished == 0 {
+		return
+	}
+
+	if s.Finished == 0 || o.DurationMin < s.DurationMin {
+		s.DurationMin = o.DurationMin
+	}
+
+	if o.DurationMax > s.DurationMax {
+		s.DurationMax = o.DurationMax
+	}
+
+	s.Finished += o.Finished
+	s.DurationTotal += o.DurationTotal
+	s.DurationAvg = s.DurationTotal / time.Duration(s.Finished)
+}
+
+func (s *ExecutionStats) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*s = ExecutionStats{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, s); err != nil {
+			return fmt.Errorf("can not scan '%v' into ExecutionStats: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (s ExecutionStats) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (ss *WorkflowStatsBucketSteps) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*ss = WorkflowStatsBucketSteps{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, ss); err != nil {
+			return fmt.Errorf("can not scan '%v' into WorkflowStatsBucketSteps: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (ss WorkflowStatsBucketSteps) Value() (driver.Value, error) {
+	return json.Marshal(ss)
+}
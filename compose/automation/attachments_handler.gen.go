@@ -0,0 +1,412 @@
+package automation
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// compose/automation/attachments_handler.yaml
+
+import (
+	"context"
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"io"
+)
+
+var _ wfexec.ExecResponse
+
+type (
+	attachmentsHandlerRegistry interface {
+		AddFunctions(ff ...*atypes.Function)
+		Type(ref string) expr.Type
+	}
+)
+
+func (h attachmentsHandler) register() {
+	h.reg.AddFunctions(
+		h.Lookup(),
+		h.OpenOriginal(),
+		h.OpenPreview(),
+		h.Create(),
+	)
+}
+
+type (
+	attachmentsLookupArgs struct {
+		hasNamespace bool
+		Namespace    uint64
+
+		hasAttachment bool
+		Attachment    uint64
+	}
+
+	attachmentsLookupResults struct {
+		Attachment *types.Attachment
+	}
+)
+
+// Lookup function Compose attachment lookup
+//
+// expects implementation of lookup function:
+// func (h attachmentsHandler) lookup(ctx context.Context, args *attachmentsLookupArgs) (results *attachmentsLookupResults, err error) {
+//    return
+// }
+func (h attachmentsHandler) Lookup() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "composeAttachmentsLookup",
+		Kind:   "function",
+		Labels: map[string]string{"attachment": "step,workflow", "compose": "step,workflow", "lookup": "step"},
+		Meta: &atypes.FunctionMeta{
+			Short:       "Compose attachment lookup",
+			Description: "Find specific attachment by ID",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "namespace",
+				Types: []string{"ID"}, Required: true,
+			},
+			{
+				Name:  "attachment",
+				Types: []string{"ID"}, Required: true,
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "attachment",
+				Types: []string{"ComposeAttachment"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &attachmentsLookupArgs{
+					hasNamespace:  in.Has("namespace"),
+					hasAttachment: in.Has("attachment"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			var results *attachmentsLookupResults
+			if results, err = h.lookup(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Attachment (*types.Attachment) to ComposeAttachment
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("ComposeAttachment").Cast(results.Attachment); err != nil {
+					return
+				} else if err = expr.Assign(out, "attachment", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
+
+type (
+	attachmentsOpenOriginalArgs struct {
+		hasAttachment bool
+		Attachment    *types.Attachment
+	}
+
+	attachmentsOpenOriginalResults struct {
+		Content io.Reader
+	}
+)
+
+// OpenOriginal function Opens original attachment content
+//
+// expects implementation of openOriginal function:
+// func (h attachmentsHandler) openOriginal(ctx context.Context, args *attachmentsOpenOriginalArgs) (results *attachmentsOpenOriginalResults, err error) {
+//    return
+// }
+func (h attachmentsHandler) OpenOriginal() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "composeAttachmentsOpenOriginal",
+		Kind:   "function",
+		Labels: map[string]string{"attachment": "step,workflow", "compose": "step,workflow"},
+		Meta: &atypes.FunctionMeta{
+			Short:       "Opens original attachment content",
+			Description: "Content can be used as an email attachment or as a HTTP request body",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "attachment",
+				Types: []string{"ComposeAttachment"}, Required: true,
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "content",
+				Types: []string{"Reader"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &attachmentsOpenOriginalArgs{
+					hasAttachment: in.Has("attachment"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			var results *attachmentsOpenOriginalResults
+			if results, err = h.openOriginal(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Content (io.Reader) to Reader
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("Reader").Cast(results.Content); err != nil {
+					return
+				} else if err = expr.Assign(out, "content", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
+
+type (
+	attachmentsOpenPreviewArgs struct {
+		hasAttachment bool
+		Attachment    *types.Attachment
+	}
+
+	attachmentsOpenPreviewResults struct {
+		Content io.Reader
+	}
+)
+
+// OpenPreview function Opens attachment preview content
+//
+// expects implementation of openPreview function:
+// func (h attachmentsHandler) openPreview(ctx context.Context, args *attachmentsOpenPreviewArgs) (results *attachmentsOpenPreviewResults, err error) {
+//    return
+// }
+func (h attachmentsHandler) OpenPreview() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "composeAttachmentsOpenPreview",
+		Kind:   "function",
+		Labels: map[string]string{"attachment": "step,workflow", "compose": "step,workflow"},
+		Meta: &atypes.FunctionMeta{
+			Short: "Opens attachment preview content",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "attachment",
+				Types: []string{"ComposeAttachment"}, Required: true,
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "content",
+				Types: []string{"Reader"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &attachmentsOpenPreviewArgs{
+					hasAttachment: in.Has("attachment"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			var results *attachmentsOpenPreviewResults
+			if results, err = h.openPreview(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Content (io.Reader) to Reader
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("Reader").Cast(results.Content); err != nil {
+					return
+				} else if err = expr.Assign(out, "content", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
+
+type (
+	attachmentsCreateArgs struct {
+		hasNamespace bool
+		Namespace    uint64
+
+		hasModule bool
+		Module    uint64
+
+		hasRecord bool
+		Record    uint64
+
+		hasField bool
+		Field    string
+
+		hasName bool
+		Name    string
+
+		hasContent    bool
+		Content       interface{}
+		contentStream io.Reader
+		contentString string
+	}
+
+	attachmentsCreateResults struct {
+		Attachment *types.Attachment
+	}
+)
+
+func (a attachmentsCreateArgs) GetContent() (bool, io.Reader, string) {
+	return a.hasContent, a.contentStream, a.contentString
+}
+
+// Create function Creates record attachment
+//
+// expects implementation of create function:
+// func (h attachmentsHandler) create(ctx context.Context, args *attachmentsCreateArgs) (results *attachmentsCreateResults, err error) {
+//    return
+// }
+func (h attachmentsHandler) Create() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "composeAttachmentsCreate",
+		Kind:   "function",
+		Labels: map[string]string{"attachment": "step,workflow", "compose": "step,workflow", "create": "step"},
+		Meta: &atypes.FunctionMeta{
+			Short:       "Creates record attachment",
+			Description: "Stores content (rendered document, HTTP response body, stream or string) as\nan attachment that can be used as a value of the record's file field\n",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "namespace",
+				Types: []string{"ID"}, Required: true,
+			},
+			{
+				Name:  "module",
+				Types: []string{"ID"}, Required: true,
+			},
+			{
+				Name:  "record",
+				Types: []string{"ID"},
+			},
+			{
+				Name:  "field",
+				Types: []string{"String"}, Required: true,
+			},
+			{
+				Name:  "name",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "content",
+				Types: []string{"Reader", "String"}, Required: true,
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "attachment",
+				Types: []string{"ComposeAttachment"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &attachmentsCreateArgs{
+					hasNamespace: in.Has("namespace"),
+					hasModule:    in.Has("module"),
+					hasRecord:    in.Has("record"),
+					hasField:     in.Has("field"),
+					hasName:      in.Has("name"),
+					hasContent:   in.Has("content"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			// Converting Content argument
+			if args.hasContent {
+				aux := expr.Must(expr.Select(in, "content"))
+				switch aux.Type() {
+				case h.reg.Type("Reader").Type():
+					args.contentStream = aux.Get().(io.Reader)
+				case h.reg.Type("String").Type():
+					args.contentString = aux.Get().(string)
+				}
+			}
+
+			var results *attachmentsCreateResults
+			if results, err = h.create(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Attachment (*types.Attachment) to ComposeAttachment
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("ComposeAttachment").Cast(results.Attachment); err != nil {
+					return
+				} else if err = expr.Assign(out, "attachment", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
@@ -0,0 +1,172 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/compose/types"
+)
+
+type (
+	attachmentService interface {
+		FindByID(ctx context.Context, namespaceID, attachmentID uint64) (*types.Attachment, error)
+		OpenOriginal(ctx context.Context, att *types.Attachment) (io.ReadSeeker, error)
+		OpenPreview(ctx context.Context, att *types.Attachment) (io.ReadSeeker, error)
+		CreateRecordAttachment(ctx context.Context, namespaceID uint64, name string, size int64, fh io.ReadSeeker, moduleID, recordID uint64, fieldName string) (*types.Attachment, error)
+	}
+
+	attachmentsHandler struct {
+		reg attachmentsHandlerRegistry
+		svc attachmentService
+	}
+
+	// attachmentFile is content of the attachment with its metadata
+	//
+	// It implements automation/types.File
+	attachmentFile struct {
+		io.ReadSeeker
+		name  string
+		ctype string
+	}
+)
+
+func AttachmentsHandler(reg attachmentsHandlerRegistry, svc attachmentService) *attachmentsHandler {
+	h := &attachmentsHandler{
+		reg: reg,
+		svc: svc,
+	}
+
+	h.register()
+	return h
+}
+
+func (h attachmentsHandler) lookup(ctx context.Context, args *attachmentsLookupArgs) (results *attachmentsLookupResults, err error) {
+	results = &attachmentsLookupResults{}
+	results.Attachment, err = h.svc.FindByID(ctx, args.Namespace, args.Attachment)
+	return
+}
+
+func (h attachmentsHandler) openOriginal(ctx context.Context, args *attachmentsOpenOriginalArgs) (results *attachmentsOpenOriginalResults, err error) {
+	if args.Attachment == nil {
+		return nil, fmt.Errorf("attachment not set")
+	}
+
+	var rs io.ReadSeeker
+	if rs, err = h.svc.OpenOriginal(ctx, args.Attachment); err != nil {
+		return
+	}
+
+	results = &attachmentsOpenOriginalResults{}
+	results.Content = newAttachmentFile(rs, args.Attachment.Name, args.Attachment.Meta.Original.Mimetype)
+	return
+}
+
+func (h attachmentsHandler) openPreview(ctx context.Context, args *attachmentsOpenPreviewArgs) (results *attachmentsOpenPreviewResults, err error) {
+	if args.Attachment == nil {
+		return nil, fmt.Errorf("attachment not set")
+	}
+
+	var (
+		rs    io.ReadSeeker
+		ctype string
+	)
+
+	if rs, err = h.svc.OpenPreview(ctx, args.Attachment); err != nil {
+		return
+	}
+
+	if args.Attachment.Meta.Preview != nil {
+		ctype = args.Attachment.Meta.Preview.Mimetype
+	}
+
+	results = &attachmentsOpenPreviewResults{}
+	results.Content = newAttachmentFile(rs, args.Attachment.Name, ctype)
+	return
+}
+
+func (h attachmentsHandler) create(ctx context.Context, args *attachmentsCreateArgs) (results *attachmentsCreateResults, err error) {
+	var (
+		fh   io.ReadSeeker
+		size int64
+		name = args.Name
+	)
+
+	if args.contentStream != nil {
+		if name == "" {
+			name, _ = atypes.FileMeta(args.contentStream, "attachment")
+		}
+
+		var cleanup func()
+		if fh, size, cleanup, err = seekableContent(args.contentStream); err != nil {
+			return nil, fmt.Errorf("could not read attachment content: %w", err)
+		}
+
+		defer cleanup()
+	} else {
+		fh = strings.NewReader(args.contentString)
+		size = int64(len(args.contentString))
+	}
+
+	if name == "" {
+		name = "attachment"
+	}
+
+	results = &attachmentsCreateResults{}
+	results.Attachment, err = h.svc.CreateRecordAttachment(ctx, args.Namespace, name, size, fh, args.Module, args.Record, args.Field)
+	return
+}
+
+func newAttachmentFile(rs io.ReadSeeker, name, ctype string) io.Reader {
+	if rs == nil {
+		// attachment without content
+		return nil
+	}
+
+	return &attachmentFile{ReadSeeker: rs, name: name, ctype: ctype}
+}
+
+func (f attachmentFile) FileName() string    { return f.name }
+func (f attachmentFile) ContentType() string { return f.ctype }
+
+// seekableContent prepares content for storing and determinates its size
+//
+// Streams that can not seek (like HTTP response bodies) are
+// spooled to a temporary file instead of being buffered in memory
+func seekableContent(r io.Reader) (fh io.ReadSeeker, size int64, cleanup func(), err error) {
+	cleanup = func() {}
+
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if size, err = rs.Seek(0, io.SeekEnd); err != nil {
+			return
+		}
+
+		_, err = rs.Seek(0, io.SeekStart)
+		return rs, size, cleanup, err
+	}
+
+	tmp, err := ioutil.TempFile("", "corteza-attachment-")
+	if err != nil {
+		return
+	}
+
+	cleanup = func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+
+	if size, err = io.Copy(tmp, r); err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		cleanup()
+		return nil, 0, func() {}, err
+	}
+
+	return tmp, size, cleanup, nil
+}
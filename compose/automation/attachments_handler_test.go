@@ -0,0 +1,63 @@
+package automation
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testAttachmentService struct {
+		attachmentService
+
+		name    string
+		size    int64
+		content string
+	}
+
+	testStream struct {
+		io.Reader
+	}
+)
+
+func (svc *testAttachmentService) CreateRecordAttachment(_ context.Context, _ uint64, name string, size int64, fh io.ReadSeeker, _, _ uint64, _ string) (*types.Attachment, error) {
+	b, err := ioutil.ReadAll(fh)
+	svc.name, svc.size, svc.content = name, size, string(b)
+	return &types.Attachment{Name: name}, err
+}
+
+func (testStream) FileName() string    { return "report.pdf" }
+func (testStream) ContentType() string { return "application/pdf" }
+
+func TestAttachmentsHandler_create(t *testing.T) {
+	var (
+		req = require.New(t)
+		svc = &testAttachmentService{}
+		h   = attachmentsHandler{svc: svc}
+	)
+
+	// non-seekable stream with file metadata
+	_, err := h.create(context.Background(), &attachmentsCreateArgs{
+		contentStream: testStream{strings.NewReader("%PDF-1.4")},
+	})
+
+	req.NoError(err)
+	req.Equal("report.pdf", svc.name)
+	req.Equal(int64(8), svc.size)
+	req.Equal("%PDF-1.4", svc.content)
+
+	// string content with explicit name
+	_, err = h.create(context.Background(), &attachmentsCreateArgs{
+		Name:          "note.txt",
+		contentString: "note",
+	})
+
+	req.NoError(err)
+	req.Equal("note.txt", svc.name)
+	req.Equal(int64(4), svc.size)
+}
@@ -18,6 +18,180 @@ import (
 var _ = context.Background
 var _ = fmt.Errorf
 
+// ComposeAttachment is an expression type, wrapper for *types.Attachment type
+type ComposeAttachment struct{ value *types.Attachment }
+
+// NewComposeAttachment creates new instance of ComposeAttachment expression type
+func NewComposeAttachment(val interface{}) (*ComposeAttachment, error) {
+	if c, err := CastToComposeAttachment(val); err != nil {
+		return nil, fmt.Errorf("unable to create ComposeAttachment: %w", err)
+	} else {
+		return &ComposeAttachment{value: c}, nil
+	}
+}
+
+// Return underlying value on ComposeAttachment
+func (t ComposeAttachment) Get() interface{} { return t.value }
+
+// Return underlying value on ComposeAttachment
+func (t ComposeAttachment) GetValue() *types.Attachment { return t.value }
+
+// Return type name
+func (ComposeAttachment) Type() string { return "ComposeAttachment" }
+
+// Convert value to *types.Attachment
+func (ComposeAttachment) Cast(val interface{}) (TypedValue, error) {
+	return NewComposeAttachment(val)
+}
+
+// Assign new value to ComposeAttachment
+//
+// value is first passed through CastToComposeAttachment
+func (t *ComposeAttachment) Assign(val interface{}) error {
+	if c, err := CastToComposeAttachment(val); err != nil {
+		return err
+	} else {
+		t.value = c
+		return nil
+	}
+}
+
+func (t *ComposeAttachment) AssignFieldValue(key string, val interface{}) error {
+	return assignToComposeAttachment(t.value, key, val)
+}
+
+// SelectGVal implements gval.Selector requirements
+//
+// It allows gval lib to access ComposeAttachment's underlying value (*types.Attachment)
+// and it's fields
+//
+func (t ComposeAttachment) SelectGVal(ctx context.Context, k string) (interface{}, error) {
+	return composeAttachmentGValSelector(t.value, k)
+}
+
+// Select is field accessor for *types.Attachment
+//
+// Similar to SelectGVal but returns typed values
+func (t ComposeAttachment) Select(k string) (TypedValue, error) {
+	return composeAttachmentTypedValueSelector(t.value, k)
+}
+
+func (t ComposeAttachment) Has(k string) bool {
+	switch k {
+	case "ID":
+		return true
+	case "ownerID":
+		return true
+	case "kind":
+		return true
+	case "url":
+		return true
+	case "previewUrl":
+		return true
+	case "name":
+		return true
+	case "namespaceID":
+		return true
+	case "createdAt":
+		return true
+	case "updatedAt":
+		return true
+	case "deletedAt":
+		return true
+	}
+	return false
+}
+
+// composeAttachmentGValSelector is field accessor for *types.Attachment
+func composeAttachmentGValSelector(res *types.Attachment, k string) (interface{}, error) {
+	switch k {
+	case "ID":
+		return res.ID, nil
+	case "ownerID":
+		return res.OwnerID, nil
+	case "kind":
+		return res.Kind, nil
+	case "url":
+		return res.Url, nil
+	case "previewUrl":
+		return res.PreviewUrl, nil
+	case "name":
+		return res.Name, nil
+	case "namespaceID":
+		return res.NamespaceID, nil
+	case "createdAt":
+		return res.CreatedAt, nil
+	case "updatedAt":
+		return res.UpdatedAt, nil
+	case "deletedAt":
+		return res.DeletedAt, nil
+	}
+
+	return nil, fmt.Errorf("unknown field '%s'", k)
+}
+
+// composeAttachmentTypedValueSelector is field accessor for *types.Attachment
+func composeAttachmentTypedValueSelector(res *types.Attachment, k string) (TypedValue, error) {
+	switch k {
+	case "ID":
+		return NewID(res.ID)
+	case "ownerID":
+		return NewID(res.OwnerID)
+	case "kind":
+		return NewString(res.Kind)
+	case "url":
+		return NewString(res.Url)
+	case "previewUrl":
+		return NewString(res.PreviewUrl)
+	case "name":
+		return NewString(res.Name)
+	case "namespaceID":
+		return NewID(res.NamespaceID)
+	case "createdAt":
+		return NewDateTime(res.CreatedAt)
+	case "updatedAt":
+		return NewDateTime(res.UpdatedAt)
+	case "deletedAt":
+		return NewDateTime(res.DeletedAt)
+	}
+
+	return nil, fmt.Errorf("unknown field '%s'", k)
+}
+
+// assignToComposeAttachment is field value setter for *types.Attachment
+func assignToComposeAttachment(res *types.Attachment, k string, val interface{}) error {
+	switch k {
+	case "ID":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "ownerID":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "kind":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "url":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "previewUrl":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "name":
+		aux, err := CastToString(val)
+		if err != nil {
+			return err
+		}
+
+		res.Name = aux
+		return nil
+	case "namespaceID":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "createdAt":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "updatedAt":
+		return fmt.Errorf("field '%s' is read-only", k)
+	case "deletedAt":
+		return fmt.Errorf("field '%s' is read-only", k)
+	}
+
+	return fmt.Errorf("unknown field '%s'", k)
+}
+
 // ComposeModule is an expression type, wrapper for *types.Module type
 type ComposeModule struct{ value *types.Module }
 
@@ -92,6 +92,23 @@ func (t ComposeRecord) SelectGVal(ctx context.Context, k string) (interface{}, e
 	return composeRecordGValSelector(t.value, k)
 }
 
+func CastToComposeAttachment(val interface{}) (out *types.Attachment, err error) {
+	switch val := val.(type) {
+	case expr.Iterator:
+		out = &types.Attachment{}
+		return out, val.Each(func(k string, v expr.TypedValue) error {
+			return assignToComposeAttachment(out, k, v)
+		})
+	}
+
+	switch val := expr.UntypedValue(val).(type) {
+	case *types.Attachment:
+		return val, nil
+	default:
+		return nil, fmt.Errorf("unable to cast type %T to %T", val, out)
+	}
+}
+
 func CastToComposeRecordValues(val interface{}) (out types.RecordValueSet, err error) {
 	out = types.RecordValueSet{}
 
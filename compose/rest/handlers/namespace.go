@@ -26,6 +26,7 @@ type (
 		Delete(context.Context, *request.NamespaceDelete) (interface{}, error)
 		Upload(context.Context, *request.NamespaceUpload) (interface{}, error)
 		TriggerScript(context.Context, *request.NamespaceTriggerScript) (interface{}, error)
+		Search(context.Context, *request.NamespaceSearch) (interface{}, error)
 	}
 
 	// HTTP API interface
@@ -37,6 +38,7 @@ type (
 		Delete        func(http.ResponseWriter, *http.Request)
 		Upload        func(http.ResponseWriter, *http.Request)
 		TriggerScript func(http.ResponseWriter, *http.Request)
+		Search        func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -152,6 +154,22 @@ func NewNamespace(h NamespaceAPI) *Namespace {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		Search: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewNamespaceSearch()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Search(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -167,5 +185,6 @@ func (h Namespace) MountRoutes(r chi.Router, middlewares ...func(http.Handler) h
 		r.Delete("/namespace/{namespaceID}", h.Delete)
 		r.Post("/namespace/upload", h.Upload)
 		r.Post("/namespace/{namespaceID}/trigger", h.TriggerScript)
+		r.Get("/namespace/{namespaceID}/search", h.Search)
 	})
 }
@@ -34,6 +34,9 @@ type (
 		Upload(context.Context, *request.RecordUpload) (interface{}, error)
 		TriggerScript(context.Context, *request.RecordTriggerScript) (interface{}, error)
 		TriggerScriptOnList(context.Context, *request.RecordTriggerScriptOnList) (interface{}, error)
+		Revisions(context.Context, *request.RecordRevisions) (interface{}, error)
+		RevisionDiff(context.Context, *request.RecordRevisionDiff) (interface{}, error)
+		RevisionRestore(context.Context, *request.RecordRevisionRestore) (interface{}, error)
 	}
 
 	// HTTP API interface
@@ -53,6 +56,9 @@ type (
 		Upload              func(http.ResponseWriter, *http.Request)
 		TriggerScript       func(http.ResponseWriter, *http.Request)
 		TriggerScriptOnList func(http.ResponseWriter, *http.Request)
+		Revisions           func(http.ResponseWriter, *http.Request)
+		RevisionDiff        func(http.ResponseWriter, *http.Request)
+		RevisionRestore     func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -296,6 +302,54 @@ func NewRecord(h RecordAPI) *Record {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		Revisions: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRevisions()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Revisions(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		RevisionDiff: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRevisionDiff()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RevisionDiff(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		RevisionRestore: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRevisionRestore()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RevisionRestore(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -319,5 +373,8 @@ func (h Record) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/attachment", h.Upload)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/trigger", h.TriggerScript)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/trigger", h.TriggerScriptOnList)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions", h.Revisions)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions/{revisionID}/diff", h.RevisionDiff)
+		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions/{revisionID}/restore", h.RevisionRestore)
 	})
 }
@@ -106,6 +106,7 @@ func (ctrl *Module) Create(ctx context.Context, r *request.ModuleCreate) (interf
 			Handle:      r.Handle,
 			Fields:      r.Fields,
 			Meta:        r.Meta,
+			Config:      r.Config,
 			Labels:      r.Labels,
 		}
 	)
@@ -124,6 +125,7 @@ func (ctrl *Module) Update(ctx context.Context, r *request.ModuleUpdate) (interf
 			Handle:      r.Handle,
 			Fields:      r.Fields,
 			Meta:        r.Meta,
+			Config:      r.Config,
 			Labels:      r.Labels,
 			UpdatedAt:   r.UpdatedAt,
 		}
@@ -10,6 +10,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/api"
 	"github.com/cortezaproject/corteza-server/pkg/corredor"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
 )
 
 type (
@@ -30,9 +31,15 @@ type (
 		Set    []*namespacePayload   `json:"set"`
 	}
 
+	namespaceSearchPayload struct {
+		Filter types.RecordSearchFilter `json:"filter"`
+		Set    types.RecordSearchHitSet `json:"set"`
+	}
+
 	Namespace struct {
 		namespace  service.NamespaceService
 		attachment service.AttachmentService
+		search     service.RecordSearchService
 		ac         namespaceAccessController
 	}
 
@@ -53,6 +60,7 @@ func (Namespace) New() *Namespace {
 	return &Namespace{
 		namespace:  service.DefaultNamespace,
 		attachment: service.DefaultAttachment,
+		search:     service.DefaultRecordSearch,
 		ac:         service.DefaultAccessControl,
 	}
 }
@@ -166,6 +174,21 @@ func (ctrl *Namespace) TriggerScript(ctx context.Context, r *request.NamespaceTr
 	return ctrl.makePayload(ctx, namespace, err)
 }
 
+func (ctrl Namespace) Search(ctx context.Context, r *request.NamespaceSearch) (interface{}, error) {
+	set, f, err := ctrl.search.Search(ctx, types.RecordSearchFilter{
+		NamespaceID: r.NamespaceID,
+		ModuleID:    payload.ParseUint64s(r.ModuleID),
+		Query:       r.Query,
+		Limit:       r.Limit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &namespaceSearchPayload{Filter: f, Set: set}, nil
+}
+
 func (ctrl Namespace) makePayload(ctx context.Context, ns *types.Namespace, err error) (*namespacePayload, error) {
 	if err != nil || ns == nil {
 		return nil, err
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/rest/request"
 	"github.com/cortezaproject/corteza-server/compose/service"
@@ -20,6 +19,7 @@ import (
 	ejson "github.com/cortezaproject/corteza-server/pkg/envoy/json"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
 	estore "github.com/cortezaproject/corteza-server/pkg/envoy/store"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/xlsx"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/payload"
 	"github.com/cortezaproject/corteza-server/store"
@@ -41,6 +41,11 @@ type (
 		Set    []*recordPayload    `json:"set"`
 	}
 
+	recordRevisionSetPayload struct {
+		Filter types.RecordRevisionFilter `json:"filter"`
+		Set    types.RecordRevisionSet    `json:"set"`
+	}
+
 	Record struct {
 		importSession service.ImportSessionService
 		record        service.RecordService
@@ -305,77 +310,14 @@ func (ctrl *Record) ImportRun(ctx context.Context, r *request.RecordImportRun) (
 		return nil, err
 	}
 
-	// Check if session ok
-	ses, err := ctrl.importSession.FindByID(ctx, r.SessionID)
-	if err != nil {
+	fields := make(map[string]string)
+	if err = json.Unmarshal(r.Fields, &fields); err != nil {
 		return nil, err
 	}
 
-	ses.Fields = make(map[string]string)
-	err = json.Unmarshal(r.Fields, &ses.Fields)
-	if err != nil {
-		return nil, err
-	}
-
-	ses.OnError = r.OnError
-
-	// Errors are presented in the session
-	err = func() (err error) {
-		if ses.Progress.StartedAt != nil {
-			return fmt.Errorf("unable to start import: import session already active")
-		}
-
-		sa := time.Now()
-		ses.Progress.StartedAt = &sa
-
-		// Prepare additional metadata
-		tpl := resource.NewComposeRecordTemplate(
-			strconv.FormatUint(ses.ModuleID, 10),
-			strconv.FormatUint(ses.NamespaceID, 10),
-			ses.Name,
-			resource.MapToMappingTplSet(ses.Fields),
-		)
-
-		// Shape the data
-		ses.Resources = append(ses.Resources, tpl)
-		rt := resource.ComposeRecordShaper()
-		ses.Resources, err = resource.Shape(ses.Resources, rt)
-
-		// Build
-		cfg := &estore.EncoderConfig{
-			// For now the identifier is ignored, so this will never occur
-			OnExisting: resource.Skip,
-			Defer: func() {
-				ses.Progress.Completed++
-			},
-		}
-		if ses.OnError == service.IMPORT_ON_ERROR_SKIP {
-			cfg.DeferNok = func(err error) error {
-				ses.Progress.Failed++
-				ses.Progress.FailReason = err.Error()
-
-				return nil
-			}
-		}
-		se := estore.NewStoreEncoder(service.DefaultStore, cfg)
-		bld := envoy.NewBuilder(se)
-		g, err := bld.Build(ctx, ses.Resources...)
-		if err != nil {
-			return err
-		}
-
-		// Encode
-		err = envoy.Encode(ctx, g, se)
-		now := time.Now()
-		ses.Progress.FinishedAt = &now
-		if err != nil {
-			ses.Progress.FailReason = err.Error()
-			return err
-		}
-
-		return
-	}()
-
+	// Import runs in the background;
+	// errors that occur during the import are presented in the session
+	ses, err := ctrl.importSession.Start(ctx, r.SessionID, fields, r.OnError)
 	return ses, ctrl.record.RecordImport(ctx, err)
 }
 
@@ -448,6 +390,13 @@ func (ctrl *Record) Export(ctx context.Context, r *request.RecordExport) (interf
 				Timezone: r.Timezone,
 			})
 
+		case "xlsx":
+			contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+			encoder = xlsx.NewBulkRecordEncoder(&xlsx.EncoderConfig{
+				Fields:   fx,
+				Timezone: r.Timezone,
+			})
+
 		default:
 			http.Error(w, "unsupported format ("+r.Ext+")", http.StatusBadRequest)
 			return
@@ -528,6 +477,52 @@ func (ctrl *Record) TriggerScriptOnList(ctx context.Context, r *request.RecordTr
 	return api.OK(), err
 }
 
+func (ctrl *Record) Revisions(ctx context.Context, r *request.RecordRevisions) (interface{}, error) {
+	var (
+		err error
+		set types.RecordRevisionSet
+		f   = types.RecordRevisionFilter{}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, f, err = ctrl.record.Revisions(ctx, r.NamespaceID, r.ModuleID, r.RecordID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordRevisionSetPayload{Filter: f, Set: set}, nil
+}
+
+func (ctrl *Record) RevisionDiff(ctx context.Context, r *request.RecordRevisionDiff) (interface{}, error) {
+	return ctrl.record.RevisionDiff(ctx, r.NamespaceID, r.ModuleID, r.RecordID, r.RevisionID, r.CompareTo)
+}
+
+func (ctrl *Record) RevisionRestore(ctx context.Context, r *request.RecordRevisionRestore) (interface{}, error) {
+	var (
+		m   *types.Module
+		err error
+	)
+
+	if m, err = ctrl.module.FindByID(ctx, r.NamespaceID, r.ModuleID); err != nil {
+		return nil, err
+	}
+
+	record, err := ctrl.record.RevisionRestore(ctx, r.NamespaceID, r.ModuleID, r.RecordID, r.RevisionID)
+
+	if rve := types.IsRecordValueErrorSet(err); rve != nil {
+		return ctrl.handleValidationError(rve), nil
+	}
+
+	return ctrl.makePayload(ctx, m, record, err)
+}
+
 func (ctrl Record) makeBulkPayload(ctx context.Context, m *types.Module, err error, rr ...*types.Record) (*recordPayload, error) {
 	if err != nil || rr == nil {
 		return nil, err
@@ -164,6 +164,28 @@ type (
 		// Script to execute
 		Script string
 	}
+
+	NamespaceSearch struct {
+		// NamespaceID PATH parameter
+		//
+		// ID
+		NamespaceID uint64 `json:",string"`
+
+		// Query GET parameter
+		//
+		// Search query
+		Query string
+
+		// ModuleID GET parameter
+		//
+		// Limit search to these modules
+		ModuleID []string
+
+		// Limit GET parameter
+		//
+		// Max number of hits
+		Limit uint
+	}
 )
 
 // NewNamespaceList request
@@ -692,3 +714,85 @@ func (r *NamespaceTriggerScript) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewNamespaceSearch request
+func NewNamespaceSearch() *NamespaceSearch {
+	return &NamespaceSearch{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r NamespaceSearch) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"query":       r.Query,
+		"moduleID":    r.ModuleID,
+		"limit":       r.Limit,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r NamespaceSearch) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r NamespaceSearch) GetQuery() string {
+	return r.Query
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r NamespaceSearch) GetModuleID() []string {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r NamespaceSearch) GetLimit() uint {
+	return r.Limit
+}
+
+// Fill processes request and fills internal variables
+func (r *NamespaceSearch) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["query"]; ok && len(val) > 0 {
+			r.Query, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["moduleID[]"]; ok {
+			r.ModuleID, err = val, nil
+			if err != nil {
+				return err
+			}
+		} else if val, ok := tmp["moduleID"]; ok {
+			r.ModuleID, err = val, nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
@@ -531,3 +531,25 @@ func (svc attachment) processImage(original io.ReadSeeker, att *types.Attachment
 }
 
 var _ AttachmentService = &attachment{}
+
+// automationAttachment wraps attachment service and binds
+// each call to the context of the workflow function
+type automationAttachment struct {
+	svc AttachmentService
+}
+
+func (a automationAttachment) FindByID(ctx context.Context, namespaceID, attachmentID uint64) (*types.Attachment, error) {
+	return a.svc.With(ctx).FindByID(namespaceID, attachmentID)
+}
+
+func (a automationAttachment) OpenOriginal(ctx context.Context, att *types.Attachment) (io.ReadSeeker, error) {
+	return a.svc.With(ctx).OpenOriginal(att)
+}
+
+func (a automationAttachment) OpenPreview(ctx context.Context, att *types.Attachment) (io.ReadSeeker, error) {
+	return a.svc.With(ctx).OpenPreview(att)
+}
+
+func (a automationAttachment) CreateRecordAttachment(ctx context.Context, namespaceID uint64, name string, size int64, fh io.ReadSeeker, moduleID, recordID uint64, fieldName string) (*types.Attachment, error) {
+	return a.svc.With(ctx).CreateRecordAttachment(namespaceID, name, size, fh, moduleID, recordID, fieldName)
+}
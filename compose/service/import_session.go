@@ -1,87 +1,488 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/envoy"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/csv"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/json"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/xlsx"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"github.com/cortezaproject/corteza-server/store"
+	systemTypes "github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
 )
 
 type (
-	recordSet []*recordImportSession
-
 	importSession struct {
+		store   store.Storer
+		log     *zap.Logger
+		ac      importSessionAccessController
+		encoder RecordImportEncoder
+		records importSessionRecordCreator
+
+		// sessions that are being imported on this node
 		l       sync.Mutex
-		records recordSet
+		running map[uint64]bool
+	}
+
+	// RecordImportEncoder encodes shaped records into the store and updates import progress
+	//
+	// New records are created with the given create function
+	//
+	// Envoy store encoder can not be used here directly (it depends on this package)
+	// so it is provided on initialization
+	RecordImportEncoder func(ctx context.Context, s store.Storer, create RecordCreator, skipFailed bool, p *types.RecordImportProgress, rr ...resource.Interface) error
+
+	// RecordCreator creates a new record in the given store
+	RecordCreator func(ctx context.Context, s store.Storer, record *types.Record) (*types.Record, error)
+
+	importSessionAccessController interface {
+		CanCreateRecord(context.Context, *types.Module) bool
+	}
+
+	// imported records are created with the record service
+	// so that they are validated, revisioned and indexed (through events)
+	// the same way as records created through the API
+	importSessionRecordCreator interface {
+		CreateInStore(ctx context.Context, s store.Storer, record *types.Record) (*types.Record, error)
+	}
+
+	// importBatch provides a slice of source entries to the record shaper
+	importBatch struct {
+		fields []string
+		rows   []map[string]string
+		pos    int
 	}
 
 	ImportSessionService interface {
-		Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*recordImportSession, error)
-		FindByID(ctx context.Context, sessionID uint64) (*recordImportSession, error)
+		Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*types.RecordImportSession, error)
+		FindByID(ctx context.Context, sessionID uint64) (*types.RecordImportSession, error)
 		DeleteByID(ctx context.Context, sessionID uint64) error
+		Start(ctx context.Context, sessionID uint64, fields map[string]string, onError string) (*types.RecordImportSession, error)
+		Watch(ctx context.Context)
 	}
 )
 
-func ImportSession() *importSession {
+const (
+	// Number of entries imported in a single transaction;
+	// session progress is stored (checkpointed) after each batch
+	importBatchSize = 100
+
+	// Running import that was not checkpointed for this long
+	// is considered interrupted and resumed by the watcher
+	importSessionStaleAfter = time.Minute * 5
+
+	// How often interrupted sessions are resumed and expired ones removed
+	importSessionWatchInterval = time.Minute
+
+	// Sessions are removed after this period of inactivity
+	importSessionRetention = time.Hour * 24 * 3
+)
+
+var (
+	errImportSessionRemoved = fmt.Errorf("import session removed")
+)
+
+func ImportSession(log *zap.Logger, enc RecordImportEncoder) *importSession {
 	return &importSession{
-		records: recordSet{},
+		store:   DefaultStore,
+		log:     log,
+		ac:      DefaultAccessControl,
+		encoder: enc,
+		records: DefaultRecord,
+		running: make(map[uint64]bool),
+	}
+}
+
+// Create decodes the uploaded file and stores it with the new import session
+//
+// Source is kept in the store so that import can be (re)started on any node
+func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*types.RecordImportSession, error) {
+	src, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the session
+	res := &types.RecordImportSession{
+		ID:          nextID(),
+		NamespaceID: namespaceID,
+		ModuleID:    moduleID,
+		Name:        name,
+		ContentType: contentType,
+		Source:      src,
+		OnError:     IMPORT_ON_ERROR_FAIL,
+		Fields:      make(types.RecordImportFields),
+		OwnedBy:     auth.GetIdentityFromContext(ctx).Identity(),
+		CreatedAt:   *now(),
+		UpdatedAt:   *now(),
+	}
+
+	ds, err := decodeImportSource(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get some metadata
+	res.Progress.EntryCount = ds.P.Count()
+	for _, f := range ds.P.Fields() {
+		res.Fields[f] = ""
+	}
+
+	if err = store.CreateComposeRecordImportSession(ctx, svc.store, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindByID returns import session owned by the current user
+func (svc *importSession) FindByID(ctx context.Context, sessionID uint64) (*types.RecordImportSession, error) {
+	return loadImportSession(ctx, svc.store, sessionID)
+}
+
+// DeleteByID removes import session owned by the current user
+//
+// Running import is stopped before the next batch
+func (svc *importSession) DeleteByID(ctx context.Context, sessionID uint64) error {
+	res, err := loadImportSession(ctx, svc.store, sessionID)
+	if err != nil {
+		// Nothing to remove
+		return nil
 	}
+
+	return store.DeleteComposeRecordImportSession(ctx, svc.store, res)
 }
 
-func (svc *importSession) indexOf(userID, sessionID uint64) int {
-	for i, r := range svc.records {
-		if r.SessionID == sessionID && r.UserID == userID {
-			return i
+// Start sets field mapping and starts the import in the background
+//
+// Progress of the import can be followed through the session
+func (svc *importSession) Start(ctx context.Context, sessionID uint64, fields map[string]string, onError string) (res *types.RecordImportSession, err error) {
+	var (
+		m *types.Module
+	)
+
+	if res, err = loadImportSession(ctx, svc.store, sessionID); err != nil {
+		return nil, err
+	}
+
+	if res.Progress.StartedAt != nil {
+		return nil, RecordErrImportSessionAlreadActive()
+	}
+
+	if _, m, err = loadModuleWithNamespace(ctx, svc.store, res.NamespaceID, res.ModuleID); err != nil {
+		return nil, err
+	}
+
+	if !svc.ac.CanCreateRecord(ctx, m) {
+		return nil, RecordErrNotAllowedToCreate()
+	}
+
+	if svc.encoder == nil || svc.records == nil {
+		return nil, fmt.Errorf("record import not configured")
+	}
+
+	// Prevent concurrent starts of the same session (on any node)
+	if claimed, err := store.ClaimComposeRecordImportSession(ctx, svc.store, res); err != nil {
+		return nil, err
+	} else if !claimed {
+		return nil, RecordErrImportSessionAlreadActive()
+	}
+
+	res.Fields = fields
+	res.OnError = strings.ToUpper(onError)
+	res.Progress.StartedAt = now()
+	res.UpdatedAt = *now()
+
+	if err = store.UpdateComposeRecordImportSession(ctx, svc.store, res); err != nil {
+		return nil, err
+	}
+
+	svc.spawn(auth.GetIdentityFromContext(ctx), res)
+	return res, nil
+}
+
+// Watch periodically resumes interrupted imports and removes expired sessions
+func (svc *importSession) Watch(ctx context.Context) {
+	tck := time.NewTicker(importSessionWatchInterval)
+
+	go func() {
+		defer sentry.Recover()
+		defer tck.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tck.C:
+				if err := svc.resumeInterrupted(ctx); err != nil {
+					svc.log.Error("failed to resume interrupted import sessions", zap.Error(err))
+				}
+
+				if err := svc.purgeExpired(ctx); err != nil {
+					svc.log.Error("failed to remove expired import sessions", zap.Error(err))
+				}
+			}
 		}
+	}()
+}
+
+// resumeInterrupted resumes running imports that were not checkpointed for a while
+//
+// These were interrupted by a crash or shutdown of the node they were running on
+func (svc *importSession) resumeInterrupted(ctx context.Context) error {
+	var (
+		staleBefore = time.Now().Add(-importSessionStaleAfter)
+		f           = types.RecordImportSessionFilter{UpdatedBefore: &staleBefore}
+	)
+
+	f.Check = func(res *types.RecordImportSession) (bool, error) {
+		return res.IsRunning() && !svc.isRunning(res.ID), nil
+	}
+
+	ss, _, err := store.SearchComposeRecordImportSessions(ctx, svc.store, f)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range ss {
+		log := svc.log.With(zap.Uint64("sessionID", res.ID))
+
+		// Another node might have found the same session
+		if claimed, err := store.ClaimComposeRecordImportSession(ctx, svc.store, res); err != nil {
+			return err
+		} else if !claimed {
+			continue
+		}
+
+		i, err := svc.identity(ctx, res.OwnedBy)
+		if err != nil {
+			log.Warn("could not resume import session", zap.Error(err))
+			continue
+		}
+
+		log.Info("resuming interrupted import", zap.Uint64("completed", res.Progress.Completed))
+		svc.spawn(i, res)
 	}
 
-	return -1
+	return nil
+}
+
+// purgeExpired removes sessions that were not updated during the retention period
+func (svc *importSession) purgeExpired(ctx context.Context) error {
+	var (
+		updatedBefore = time.Now().Add(-importSessionRetention)
+		f             = types.RecordImportSessionFilter{UpdatedBefore: &updatedBefore}
+	)
+
+	f.Check = func(res *types.RecordImportSession) (bool, error) {
+		return !svc.isRunning(res.ID), nil
+	}
+
+	ss, _, err := store.SearchComposeRecordImportSessions(ctx, svc.store, f)
+	if err != nil || len(ss) == 0 {
+		return err
+	}
+
+	return store.DeleteComposeRecordImportSession(ctx, svc.store, ss...)
+}
+
+// identity of the session owner with all roles they are member of
+func (svc *importSession) identity(ctx context.Context, userID uint64) (auth.Identifiable, error) {
+	mm, _, err := store.SearchRoleMembers(ctx, svc.store, systemTypes.RoleMemberFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]uint64, len(mm))
+	for i, m := range mm {
+		roles[i] = m.RoleID
+	}
+
+	return auth.NewIdentity(userID, roles...), nil
 }
 
-func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, contentType string, namespaceID, moduleID uint64) (*recordImportSession, error) {
+func (svc *importSession) isRunning(sessionID uint64) bool {
 	svc.l.Lock()
 	defer svc.l.Unlock()
+	return svc.running[sessionID]
+}
 
-	// Prepare the session
-	sh := &recordImportSession{
-		Name:        name,
-		SessionID:   nextID(),
-		UserID:      auth.GetIdentityFromContext(ctx).Identity(),
-		NamespaceID: namespaceID,
-		ModuleID:    moduleID,
+// spawn runs the import in the background with the given identity
+func (svc *importSession) spawn(i auth.Identifiable, res *types.RecordImportSession) {
+	svc.l.Lock()
+	defer svc.l.Unlock()
+
+	if svc.running[res.ID] {
+		return
+	}
 
-		OnError:  IMPORT_ON_ERROR_FAIL,
-		Fields:   make(map[string]string),
-		Progress: &RecordImportProgress{},
+	svc.running[res.ID] = true
 
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	go func() {
+		defer sentry.Recover()
+		defer func() {
+			svc.l.Lock()
+			defer svc.l.Unlock()
+			delete(svc.running, res.ID)
+		}()
+
+		var (
+			log = svc.log.With(zap.Uint64("sessionID", res.ID))
+			ctx = auth.SetIdentityToContext(context.Background(), i)
+		)
+
+		err := svc.run(ctx, res)
+		if err == errImportSessionRemoved {
+			log.Info("import session removed, import stopped")
+			return
+		}
+
+		res.Progress.FinishedAt = now()
+		res.UpdatedAt = *now()
+		if err != nil {
+			log.Warn("import failed", zap.Error(err))
+			res.Progress.FailReason = err.Error()
+		}
+
+		if err = store.UpdateComposeRecordImportSession(ctx, svc.store, res); err != nil {
+			log.Error("failed to update import session", zap.Error(err))
+		}
+	}()
+}
+
+// run imports entries from the session source in batches
+//
+// Each batch is imported in a transaction together with the session progress (checkpoint)
+// so that interrupted import can be resumed without importing the same entries twice.
+// Transaction is required even on stores that have transactions disabled (SQLite).
+//
+// Batches imported before a failure are kept.
+func (svc *importSession) run(ctx context.Context, res *types.RecordImportSession) error {
+	ds, err := decodeImportSource(ctx, res)
+	if err != nil {
+		return err
+	}
+
+	// Skip entries imported before the interruption
+	for n := uint64(0); n < res.Progress.Completed; n++ {
+		if _, err = ds.P.Next(); err != nil {
+			return err
+		}
 	}
 
-	// Decoders; We only need to do csv & yaml here
-	cd := csv.Decoder()
-	jd := json.Decoder()
+	for {
+		batch := &importBatch{fields: ds.P.Fields()}
+		for len(batch.rows) < importBatchSize {
+			row, err := ds.P.Next()
+			if err != nil {
+				return err
+			}
 
-	// This will really be at most 1
-	var err error
-	do := &envoy.DecoderOpts{
-		Name: name,
-		Path: "",
+			if row == nil {
+				break
+			}
+
+			batch.rows = append(batch.rows, row)
+		}
+
+		if len(batch.rows) == 0 {
+			return nil
+		}
+
+		// Context that requires the transaction is not passed on;
+		// other transactions (i.e. in event handlers) are not affected
+		err = store.Tx(store.TxRequired(ctx), svc.store, func(_ context.Context, s store.Storer) error {
+			// Import stops when session is removed
+			if _, err := store.LookupComposeRecordImportSessionByID(ctx, s, res.ID); errors.IsNotFound(err) {
+				return errImportSessionRemoved
+			} else if err != nil {
+				return err
+			}
+
+			// Transaction can be retried, start with a fresh batch & progress
+			batch.pos = 0
+			upd := *res
+
+			if err := svc.importBatch(ctx, s, &upd, batch); err != nil {
+				return err
+			}
+
+			upd.UpdatedAt = *now()
+			if err := store.UpdateComposeRecordImportSession(ctx, s, &upd); err != nil {
+				return err
+			}
+
+			*res = upd
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// importBatch imports entries from the batch and updates session progress
+func (svc *importSession) importBatch(ctx context.Context, s store.Storer, res *types.RecordImportSession, batch *importBatch) (err error) {
+	rr := []resource.Interface{
+		resource.NewResourceDataset(res.Name, batch),
+		resource.NewComposeRecordTemplate(
+			strconv.FormatUint(res.ModuleID, 10),
+			strconv.FormatUint(res.NamespaceID, 10),
+			res.Name,
+			resource.MapToMappingTplSet(res.Fields),
+		),
 	}
 
-	sh.Resources, err = func() ([]resource.Interface, error) {
-		if cd.CanDecodeFile(f) || cd.CanDecodeMime(contentType) {
+	if rr, err = resource.Shape(rr, resource.ComposeRecordShaper()); err != nil {
+		return err
+	}
+
+	return svc.encoder(ctx, s, svc.records.CreateInStore, res.OnError == IMPORT_ON_ERROR_SKIP, &res.Progress, rr...)
+}
+
+// decodeImportSource decodes session source into a generic dataset
+func decodeImportSource(ctx context.Context, res *types.RecordImportSession) (*resource.ResourceDataset, error) {
+	var (
+		f  = bytes.NewReader(res.Source)
+		do = &envoy.DecoderOpts{
+			Name: res.Name,
+			Path: "",
+		}
+
+		// Decoders; We only need to do csv, xlsx & json here
+		cd = csv.Decoder()
+		xd = xlsx.Decoder()
+		jd = json.Decoder()
+	)
+
+	// This will really be at most 1
+	rr, err := func() ([]resource.Interface, error) {
+		if cd.CanDecodeFile(f) || cd.CanDecodeMime(res.ContentType) {
 			f.Seek(0, 0)
 			return cd.Decode(ctx, f, do)
 		}
 
+		f.Seek(0, 0)
+		if xd.CanDecodeFile(f) || xd.CanDecodeMime(res.ContentType) {
+			f.Seek(0, 0)
+			return xd.Decode(ctx, f, do)
+		}
+
 		f.Seek(0, 0)
 		if jd.CanDecodeFile(f) {
 			f.Seek(0, 0)
@@ -95,63 +496,44 @@ func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, con
 		return nil, err
 	}
 
-	// Get some metadata
-	n, ok := (sh.Resources[0]).(*resource.ResourceDataset)
+	ds, ok := rr[0].(*resource.ResourceDataset)
 	if !ok {
 		// @todo move this logic to service and use action/error pattern
 		return nil, fmt.Errorf("compose.service.RecordImportFormatNotSupported")
 	}
 
-	sh.Progress.EntryCount = n.P.Count()
-	for _, f := range n.P.Fields() {
-		sh.Fields[f] = ""
-	}
-
-	// Create it
-	svc.records = append(svc.records, sh)
-	return sh, nil
+	return ds, nil
 }
 
-func (svc *importSession) FindByID(ctx context.Context, sessionID uint64) (*recordImportSession, error) {
-	svc.l.Lock()
-	defer svc.l.Unlock()
+// loadImportSession loads import session owned by the current user
+func loadImportSession(ctx context.Context, s store.ComposeRecordImportSessions, sessionID uint64) (*types.RecordImportSession, error) {
+	res, err := store.LookupComposeRecordImportSessionByID(ctx, s, sessionID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
 
-	userID := auth.GetIdentityFromContext(ctx).Identity()
-	i := svc.indexOf(userID, sessionID)
-	if i >= 0 {
-		return svc.records[i], nil
+	if res == nil || res.OwnedBy != auth.GetIdentityFromContext(ctx).Identity() {
+		return nil, fmt.Errorf("compose.service.RecordImportSessionNotFound")
 	}
-	return nil, fmt.Errorf("compose.service.RecordImportSessionNotFound")
-}
 
-// https://stackoverflow.com/a/37335777
-func remove(s recordSet, i int) recordSet {
-	s[len(s)-1], s[i] = s[i], s[len(s)-1]
-	return s[:len(s)-1]
+	return res, nil
 }
 
-func (svc *importSession) DeleteByID(ctx context.Context, sessionID uint64) error {
-	svc.l.Lock()
-	defer svc.l.Unlock()
-
-	userID := auth.GetIdentityFromContext(ctx).Identity()
-	i := svc.indexOf(userID, sessionID)
+// Fields returns every available field in this batch
+func (b *importBatch) Fields() []string {
+	return b.fields
+}
 
-	if i >= 0 {
-		svc.records = remove(svc.records, i)
+// Next returns the field: value mapping for the next entry in the batch
+func (b *importBatch) Next() (map[string]string, error) {
+	if b.pos >= len(b.rows) {
+		return nil, nil
 	}
-	return nil
-}
 
-// @todo run this in some interval
-func (svc *importSession) clean(ctx context.Context) {
-	svc.l.Lock()
-	defer svc.l.Unlock()
+	b.pos++
+	return b.rows[b.pos-1], nil
+}
 
-	for i := len(svc.records) - 1; i >= 0; i-- {
-		r := svc.records[i]
-		if time.Now().After(r.UpdatedAt.Add(time.Hour * 24 * 3)) {
-			svc.records = remove(svc.records, i)
-		}
-	}
+func (b *importBatch) Count() uint64 {
+	return uint64(len(b.rows))
 }
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/sqlite3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestImportSessionResume(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		req    = require.New(t)
+		s, err = sqlite3.ConnectInMemory(ctx)
+
+		l       sync.Mutex
+		names   []string
+		batches int
+
+		// collects imported names instead of storing records
+		enc = func(ctx context.Context, s store.Storer, create RecordCreator, skipFailed bool, p *types.RecordImportProgress, rr ...resource.Interface) error {
+			l.Lock()
+			defer l.Unlock()
+
+			batches++
+			for _, r := range rr {
+				if cr, ok := r.(*resource.ComposeRecord); ok {
+					err := cr.Walker(func(raw *resource.ComposeRecordRaw) error {
+						names = append(names, raw.Values["name"])
+						p.Completed++
+						return nil
+					})
+
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			return nil
+		}
+
+		makeSession = func(completed uint64, updatedAt time.Time) *types.RecordImportSession {
+			src := &strings.Builder{}
+			src.WriteString("fname\n")
+			for i := 0; i < 250; i++ {
+				fmt.Fprintf(src, "r%d\n", i)
+			}
+
+			return &types.RecordImportSession{
+				ID:          nextID(),
+				NamespaceID: 1,
+				ModuleID:    2,
+				Name:        "import.csv",
+				ContentType: "text/csv",
+				Source:      []byte(src.String()),
+				OnError:     IMPORT_ON_ERROR_FAIL,
+				Fields:      types.RecordImportFields{"fname": "name"},
+				Progress: types.RecordImportProgress{
+					StartedAt:  &updatedAt,
+					EntryCount: 250,
+					Completed:  completed,
+				},
+				OwnedBy:   1,
+				CreatedAt: updatedAt,
+				UpdatedAt: updatedAt.Round(time.Second),
+			}
+		}
+
+		waitFinished = func(sessionID uint64) *types.RecordImportSession {
+			for i := 0; i < 100; i++ {
+				res, err := store.LookupComposeRecordImportSessionByID(ctx, s, sessionID)
+				req.NoError(err)
+				if res.Progress.FinishedAt != nil {
+					return res
+				}
+
+				time.Sleep(time.Millisecond * 10)
+			}
+
+			t.Fatal("import not finished")
+			return nil
+		}
+	)
+
+	req.NoError(err)
+	req.NoError(store.Upgrade(ctx, zap.NewNop(), s))
+	req.NoError(store.TruncateComposeRecordImportSessions(ctx, s))
+
+	svc := ImportSession(zap.NewNop(), enc)
+	svc.store = s
+	svc.records = &record{store: s}
+
+	var (
+		interrupted = makeSession(120, time.Now().Add(-time.Hour))
+		active      = makeSession(10, time.Now())
+	)
+
+	req.NoError(store.CreateComposeRecordImportSession(ctx, s, interrupted, active))
+	req.NoError(svc.resumeInterrupted(ctx))
+
+	res := waitFinished(interrupted.ID)
+	req.Empty(res.Progress.FailReason)
+	req.Equal(uint64(250), res.Progress.Completed)
+
+	l.Lock()
+	req.Equal(2, batches)
+	req.Len(names, 130)
+	req.Equal("r120", names[0])
+	req.Equal("r249", names[129])
+	l.Unlock()
+
+	// Session that is still checkpointed by another node is left alone
+	res, err = store.LookupComposeRecordImportSessionByID(ctx, s, active.ID)
+	req.NoError(err)
+	req.Nil(res.Progress.FinishedAt)
+	req.Equal(uint64(10), res.Progress.Completed)
+}
+
+func TestImportSessionBatchRollback(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		req    = require.New(t)
+		s, err = sqlite3.ConnectInMemory(ctx)
+
+		written = &types.RecordImportSession{ID: nextID(), Source: []byte("-"), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+		// writes to the batch store and fails
+		enc = func(ctx context.Context, s store.Storer, create RecordCreator, skipFailed bool, p *types.RecordImportProgress, rr ...resource.Interface) error {
+			p.Completed++
+			if err := store.CreateComposeRecordImportSession(ctx, s, written); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("failed")
+		}
+
+		res = &types.RecordImportSession{
+			ID:          nextID(),
+			Name:        "import.csv",
+			ContentType: "text/csv",
+			Source:      []byte("fname\nr1\n"),
+			OnError:     IMPORT_ON_ERROR_FAIL,
+			Fields:      types.RecordImportFields{"fname": "name"},
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+	)
+
+	req.NoError(err)
+	req.NoError(store.Upgrade(ctx, zap.NewNop(), s))
+	req.NoError(store.TruncateComposeRecordImportSessions(ctx, s))
+
+	svc := ImportSession(zap.NewNop(), enc)
+	svc.store = s
+	svc.records = &record{store: s}
+
+	req.NoError(store.CreateComposeRecordImportSession(ctx, s, res))
+	req.Error(svc.run(ctx, res))
+
+	// Batch is rolled back together with the progress,
+	// even on SQLite that has transactions disabled
+	_, err = store.LookupComposeRecordImportSessionByID(ctx, s, written.ID)
+	req.True(errors.IsNotFound(err))
+	req.Zero(res.Progress.Completed)
+}
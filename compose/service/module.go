@@ -191,6 +191,10 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 			return ModuleErrInvalidHandle()
 		}
 
+		if err = checkModuleFieldNames(new.Fields, nil); err != nil {
+			return err
+		}
+
 		if ns, err = loadNamespace(ctx, s, new.NamespaceID); err != nil {
 			return err
 		}
@@ -244,6 +248,12 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 		return nil
 	})
 
+	if err == nil {
+		// Partitioned record storage is prepared outside of the transaction
+		// since not all databases support schema changes inside one
+		err = store.UpgradeComposeRecordPartition(ctx, svc.store, new)
+	}
+
 	return new, svc.recordAction(ctx, aProps, ModuleActionCreate, err)
 }
 
@@ -310,7 +320,9 @@ func (svc module) updater(ctx context.Context, namespaceID, moduleID uint64, act
 				set        types.RecordSet
 			)
 
-			if set, _, err = store.SearchComposeRecords(ctx, s, m, types.RecordFilter{Paging: filter.Paging{Limit: 1}}); err != nil {
+			// searching with the old module; record storage might not be prepared
+			// for the updated configuration yet
+			if set, _, err = store.SearchComposeRecords(ctx, s, old, types.RecordFilter{Paging: filter.Paging{Limit: 1}}); err != nil {
 				return err
 			}
 
@@ -336,6 +348,17 @@ func (svc module) updater(ctx context.Context, namespaceID, moduleID uint64, act
 		return err
 	})
 
+	if err == nil && changes&(moduleChanged|moduleFieldsChanged) > 0 && m.DeletedAt == nil {
+		// Partitioned record storage is prepared (or upgraded with new physical columns)
+		// outside of the transaction since not all databases support schema changes inside one
+		err = store.UpgradeComposeRecordPartition(ctx, svc.store, m)
+	}
+
+	if err == nil && changes&moduleChanged > 0 && m.DeletedAt != nil && old.DeletedAt == nil {
+		// Partitioned records are removed together with the module
+		err = store.DropComposeRecordPartition(ctx, svc.store, m)
+	}
+
 	return m, svc.recordAction(ctx, aProps, action, err)
 }
 
@@ -399,6 +422,10 @@ func (svc module) handleUpdate(ctx context.Context, upd *types.Module) moduleUpd
 			return moduleUnchanged, ModuleErrInvalidHandle()
 		}
 
+		if err = checkModuleFieldNames(upd.Fields, res.Fields); err != nil {
+			return moduleUnchanged, err
+		}
+
 		if err = svc.uniqueCheck(ctx, upd); err != nil {
 			return moduleUnchanged, err
 		}
@@ -417,6 +444,20 @@ func (svc module) handleUpdate(ctx context.Context, upd *types.Module) moduleUpd
 			res.Handle = upd.Handle
 		}
 
+		if res.Config != upd.Config {
+			if res.Config.Partitioned != upd.Config.Partitioned {
+				// Records are not migrated between storages
+				if set, _, err := store.SearchComposeRecords(ctx, svc.store, res, types.RecordFilter{Paging: filter.Paging{Limit: 1}}); err != nil {
+					return moduleUnchanged, err
+				} else if len(set) > 0 {
+					return moduleUnchanged, ModuleErrRecordStorageChangeNotAllowed()
+				}
+			}
+
+			changes |= moduleChanged
+			res.Config = upd.Config
+		}
+
 		{
 			oldMeta := res.Meta.String()
 			if oldMeta == "{}" {
@@ -485,6 +526,24 @@ func (svc module) handleUndelete(ctx context.Context, ns *types.Namespace, m *ty
 	return moduleChanged, nil
 }
 
+// checks names of the module fields
+//
+// Names are used as column names and JSON keys (partitioned records) and need to be valid handles;
+// names of existing fields are not checked to keep modules with legacy field names updatable
+func checkModuleFieldNames(ff, existing types.ModuleFieldSet) error {
+	for _, f := range ff {
+		if existing.HasName(f.Name) {
+			continue
+		}
+
+		if f.Name == "" || !handle.IsValid(f.Name) {
+			return ModuleErrInvalidFieldName()
+		}
+	}
+
+	return nil
+}
+
 // updates module fields
 // expecting to receive all module fields, as it deletes the rest
 // also, sort order of the fields is also important as this fn stores and updates field's place as send
@@ -551,6 +551,36 @@ func ModuleErrInvalidHandle(mm ...*moduleActionProps) *errors.Error {
 	return e
 }
 
+// ModuleErrInvalidFieldName returns "compose:module.invalidFieldName" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrInvalidFieldName(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid field name", nil),
+
+		errors.Meta("type", "invalidFieldName"),
+		errors.Meta("resource", "compose:module"),
+
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // ModuleErrHandleNotUnique returns "compose:module.handleNotUnique" as *errors.Error
 //
 //
@@ -645,6 +675,36 @@ func ModuleErrStaleData(mm ...*moduleActionProps) *errors.Error {
 	return e
 }
 
+// ModuleErrRecordStorageChangeNotAllowed returns "compose:module.recordStorageChangeNotAllowed" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrRecordStorageChangeNotAllowed(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("record storage can not be changed on module with records", nil),
+
+		errors.Meta("type", "recordStorageChangeNotAllowed"),
+		errors.Meta("resource", "compose:module"),
+
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // ModuleErrInvalidNamespaceID returns "compose:module.invalidNamespaceID" as *errors.Error
 //
 //
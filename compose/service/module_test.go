@@ -88,6 +88,65 @@ func TestModules(t *testing.T) {
 		req.NotNil(res.DeletedAt)
 	})
 
+	t.Run("field names", func(t *testing.T) {
+		req := require.New(t)
+		svc := module{
+			store:    s,
+			ac:       AccessControl(&rbac.ServiceAllowAll{}),
+			eventbus: eventbus.New(),
+		}
+
+		for _, name := range []string{"", "x", "a'b", `a"b`, "a b", "foo')); DROP TABLE compose_module; --"} {
+			_, err = svc.Create(ctx, &types.Module{Name: "invalid", NamespaceID: namespaceID, Fields: types.ModuleFieldSet{{Name: name, Kind: "String"}}})
+			req.EqualError(err, "invalid field name", "field name %q", name)
+		}
+
+		res, err := svc.Create(ctx, &types.Module{Name: "valid", NamespaceID: namespaceID, Fields: types.ModuleFieldSet{{Name: "first.name", Kind: "String"}}})
+		req.NoError(err)
+
+		res.Fields = append(res.Fields, &types.ModuleField{Name: "a b", Kind: "String"})
+		_, err = svc.Update(ctx, res)
+		req.EqualError(err, "invalid field name")
+
+		req.NoError(svc.DeleteByID(ctx, namespaceID, res.ID))
+	})
+
+	t.Run("partitioned", func(t *testing.T) {
+		req := require.New(t)
+		svc := module{
+			store:    s,
+			ac:       AccessControl(&rbac.ServiceAllowAll{}),
+			eventbus: eventbus.New(),
+		}
+
+		res, err := svc.Create(ctx, &types.Module{
+			Name:        "partitioned",
+			NamespaceID: namespaceID,
+			Config:      types.ModuleConfig{Partitioned: true},
+			Fields: types.ModuleFieldSet{
+				{Name: "firstName", Kind: "String", Options: types.ModuleFieldOptions{"isPhysical": true}},
+				{Name: "last-name", Kind: "String", Options: types.ModuleFieldOptions{"isPhysical": true}},
+			},
+		})
+		req.NoError(err)
+
+		rec := &types.Record{ID: nextID(), ModuleID: res.ID, NamespaceID: namespaceID, CreatedAt: *now()}
+		rec.Values = types.RecordValueSet{
+			{RecordID: rec.ID, Name: "firstName", Value: "Jane"},
+			{RecordID: rec.ID, Name: "last-name", Value: "Doe"},
+		}
+		req.NoError(store.CreateComposeRecord(ctx, s, res, rec))
+
+		set, _, err := store.SearchComposeRecords(ctx, s, res, types.RecordFilter{Query: "firstName = 'Jane'"})
+		req.NoError(err)
+		req.Len(set, 1)
+
+		// records are removed with the module
+		req.NoError(svc.DeleteByID(ctx, namespaceID, res.ID))
+		_, _, err = store.SearchComposeRecords(ctx, s, res, types.RecordFilter{})
+		req.Error(err)
+	})
+
 	t.Run("labels", func(t *testing.T) {
 		t.Run("search", func(t *testing.T) {
 			req := require.New(t)
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
-	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/service/event"
 	"github.com/cortezaproject/corteza-server/compose/service/values"
@@ -13,7 +12,6 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/corredor"
-	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/label"
@@ -81,6 +79,7 @@ type (
 		RecordImport(context.Context, error) error
 
 		Create(ctx context.Context, record *types.Record) (*types.Record, error)
+		CreateInStore(ctx context.Context, s store.Storer, record *types.Record) (*types.Record, error)
 		Update(ctx context.Context, record *types.Record) (*types.Record, error)
 		Bulk(ctx context.Context, oo ...*types.RecordBulkOperation) (types.RecordSet, error)
 
@@ -94,33 +93,11 @@ type (
 
 		TriggerScript(ctx context.Context, namespaceID, moduleID, recordID uint64, rvs types.RecordValueSet, script string) (*types.Module, *types.Record, error)
 
-		EventEmitting(enable bool)
-	}
-
-	recordImportSession struct {
-		Name        string `json:"-"`
-		SessionID   uint64 `json:"sessionID,string"`
-		UserID      uint64 `json:"userID,string"`
-		NamespaceID uint64 `json:"namespaceID,string"`
-		ModuleID    uint64 `json:"moduleID,string"`
+		Revisions(ctx context.Context, namespaceID, moduleID, recordID uint64, f types.RecordRevisionFilter) (types.RecordRevisionSet, types.RecordRevisionFilter, error)
+		RevisionDiff(ctx context.Context, namespaceID, moduleID, recordID, revisionID, compareToID uint64) (types.RecordValueDiffSet, error)
+		RevisionRestore(ctx context.Context, namespaceID, moduleID, recordID, revisionID uint64) (*types.Record, error)
 
-		OnError  string                `json:"onError"`
-		Fields   map[string]string     `json:"fields"`
-		Progress *RecordImportProgress `json:"progress"`
-
-		CreatedAt time.Time `json:"createdAt"`
-		UpdatedAt time.Time `json:"updatedAt"`
-
-		Resources []resource.Interface `json:"-"`
-	}
-
-	RecordImportProgress struct {
-		StartedAt  *time.Time `json:"startedAt"`
-		FinishedAt *time.Time `json:"finishedAt"`
-		EntryCount uint64     `json:"entryCount"`
-		Completed  uint64     `json:"completed"`
-		Failed     uint64     `json:"failed"`
-		FailReason string     `json:"failReason,omitempty"`
+		EventEmitting(enable bool)
 	}
 )
 
@@ -141,6 +118,16 @@ func Record() RecordService {
 			return false, nil
 		}
 
+		// Referenced record belongs to module configured on the field;
+		// we need that module in case its records are partitioned
+		if refModID, _ := strconv.ParseUint(f.Options.String("moduleID"), 10, 64); refModID > 0 && refModID != m.ID {
+			if refMod, err := loadModule(ctx, s, refModID); err == nil {
+				m = refMod
+			} else if !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+
 		r, err := store.LookupComposeRecordByID(ctx, s, m, v.Ref)
 		return r != nil, err
 	})
@@ -376,7 +363,7 @@ func (svc record) Bulk(ctx context.Context, oo ...*types.RecordBulkOperation) (r
 
 			case types.OperationTypeUpdate:
 				action = RecordActionUpdate
-				r, err = svc.update(ctx, r)
+				r, err = svc.update(ctx, r, types.RecordRevisionUpdate)
 
 			case types.OperationTypeDelete:
 				action = RecordActionDelete
@@ -482,7 +469,11 @@ func (svc record) create(ctx context.Context, new *types.Record) (rec *types.Rec
 	}
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-		return store.CreateComposeRecord(ctx, s, m, new)
+		if err = store.CreateComposeRecord(ctx, s, m, new); err != nil {
+			return err
+		}
+
+		return svc.createRevision(ctx, s, new, types.RecordRevisionCreate)
 	})
 
 	if err != nil {
@@ -666,7 +657,9 @@ func RecordValueDefaults(m *types.Module, vv types.RecordValueSet) (out types.Re
 
 // Raw update function that is responsible for value validation, event dispatching
 // and update.
-func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Record, err error) {
+//
+// Operation is stored with the record revision
+func (svc record) update(ctx context.Context, upd *types.Record, op types.RecordRevisionOperation) (rec *types.Record, err error) {
 	var (
 		aProps    = &recordActionProps{changed: upd}
 		invokerID = auth.GetIdentityFromContext(ctx).Identity()
@@ -736,7 +729,11 @@ func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Rec
 			}
 		}
 
-		return store.UpdateComposeRecord(ctx, s, m, upd)
+		if err = store.UpdateComposeRecord(ctx, s, m, upd); err != nil {
+			return err
+		}
+
+		return svc.createRevision(ctx, s, upd, op)
 	})
 
 	if err != nil {
@@ -772,6 +769,15 @@ func (svc record) Create(ctx context.Context, new *types.Record) (rec *types.Rec
 	return rec, svc.recordAction(ctx, aProps, RecordActionCreate, err)
 }
 
+// CreateInStore creates a new record in the given store
+//
+// Used by record import to create records in the transaction
+// of the import batch
+func (svc record) CreateInStore(ctx context.Context, s store.Storer, new *types.Record) (*types.Record, error) {
+	svc.store = s
+	return svc.Create(ctx, new)
+}
+
 // Runs value sanitization, sets values that should be used
 // and validates the final result
 //
@@ -807,7 +813,7 @@ func (svc record) Update(ctx context.Context, upd *types.Record) (rec *types.Rec
 	)
 
 	err = func() error {
-		rec, err = svc.update(ctx, upd)
+		rec, err = svc.update(ctx, upd, types.RecordRevisionUpdate)
 		aProps.setRecord(rec)
 		return err
 	}()
@@ -887,7 +893,11 @@ func (svc record) delete(ctx context.Context, namespaceID, moduleID, recordID ui
 	del.DeletedBy = invokerID
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-		return store.UpdateComposeRecord(ctx, s, m, del)
+		if err = store.UpdateComposeRecord(ctx, s, m, del); err != nil {
+			return err
+		}
+
+		return svc.createRevision(ctx, s, del, types.RecordRevisionDelete)
 	})
 
 	if err != nil {
@@ -1056,6 +1066,10 @@ func (svc record) Organize(ctx context.Context, namespaceID, moduleID, recordID
 		}
 
 		return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+			// records with changed values;
+			// revisions are created after all values are updated
+			var revised types.RecordSet
+
 			if len(recordValues) > 0 {
 				//svc.recordInfoUpdate(r)
 				//if err = store.UpdateComposeRecord(ctx, s, m, r); err != nil {
@@ -1067,6 +1081,14 @@ func (svc record) Organize(ctx context.Context, namespaceID, moduleID, recordID
 				return err
 			}
 
+			if len(recordValues) > 0 {
+				for _, v := range recordValues {
+					r.Values = r.Values.Set(v)
+				}
+
+				revised = append(revised, r)
+			}
+
 			if reorderingRecords {
 				var (
 					set              types.RecordSet
@@ -1101,18 +1123,37 @@ func (svc record) Organize(ctx context.Context, namespaceID, moduleID, recordID
 				var vv = make([]*types.RecordValue, 0, len(set))
 				_ = set.Walk(func(r *types.Record) error {
 					recordOrderPlace++
-					vv = append(vv, &types.RecordValue{
+					v := &types.RecordValue{
 						RecordID: r.ID,
 						Name:     posField,
 						Value:    strconv.FormatUint(recordOrderPlace, 10),
-					})
+					}
 
+					vv = append(vv, v)
+					r.Values = r.Values.Set(v)
 					return nil
 				})
 
 				if err = store.PartialComposeRecordValueUpdate(ctx, s, m, vv...); err != nil {
 					return err
 				}
+
+				// reordered records replace the organized record (when in the set)
+				// so that only one revision is created for it
+				for _, rr := range set {
+					if len(revised) > 0 && revised[0].ID == rr.ID {
+						revised = revised[1:]
+						break
+					}
+				}
+
+				revised = append(revised, set...)
+			}
+
+			for _, rr := range revised {
+				if err = svc.createRevision(ctx, s, rr, types.RecordRevisionUpdate); err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -1263,7 +1304,11 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					}
 
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-						return store.CreateComposeRecord(ctx, s, m, rec)
+						if err = store.CreateComposeRecord(ctx, s, m, rec); err != nil {
+							return err
+						}
+
+						return svc.createRevision(ctx, s, rec, types.RecordRevisionCreate)
 					})
 				case "update":
 					recordableAction = RecordActionIteratorUpdate
@@ -1274,7 +1319,11 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					}
 
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-						return store.UpdateComposeRecord(ctx, s, m, rec)
+						if err = store.UpdateComposeRecord(ctx, s, m, rec); err != nil {
+							return err
+						}
+
+						return svc.createRevision(ctx, s, rec, types.RecordRevisionUpdate)
 					})
 				case "delete":
 					recordableAction = RecordActionIteratorDelete
@@ -1282,7 +1331,11 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
 						rec.DeletedAt = now()
 						rec.DeletedBy = invokerID
-						return store.UpdateComposeRecord(ctx, s, m, rec)
+						if err = store.UpdateComposeRecord(ctx, s, m, rec); err != nil {
+							return err
+						}
+
+						return svc.createRevision(ctx, s, rec, types.RecordRevisionDelete)
 					})
 				}
 
@@ -29,6 +29,7 @@ type (
 		field         string
 		value         string
 		valueErrors   *types.RecordValueErrorSet
+		revision      *types.RecordRevision
 	}
 
 	recordAction struct {
@@ -155,6 +156,17 @@ func (p *recordActionProps) setValueErrors(valueErrors *types.RecordValueErrorSe
 	return p
 }
 
+// setRevision updates recordActionProps's revision
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *recordActionProps) setRevision(revision *types.RecordRevision) *recordActionProps {
+	p.revision = revision
+	return p
+}
+
 // Serialize converts recordActionProps to actionlog.Meta
 //
 // This function is auto-generated.
@@ -201,6 +213,11 @@ func (p recordActionProps) Serialize() actionlog.Meta {
 	if p.valueErrors != nil {
 		m.Set("valueErrors.set", p.valueErrors.Set, true)
 	}
+	if p.revision != nil {
+		m.Set("revision.ID", p.revision.ID, true)
+		m.Set("revision.revision", p.revision.Revision, true)
+		m.Set("revision.recordID", p.revision.RecordID, true)
+	}
 
 	return m
 }
@@ -336,6 +353,22 @@ func (p recordActionProps) Format(in string, err error) string {
 		)
 		pairs = append(pairs, "{valueErrors.set}", fns(p.valueErrors.Set))
 	}
+
+	if p.revision != nil {
+		// replacement for "{revision}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{revision}",
+			fns(
+				p.revision.ID,
+				p.revision.Revision,
+				p.revision.RecordID,
+			),
+		)
+		pairs = append(pairs, "{revision.ID}", fns(p.revision.ID))
+		pairs = append(pairs, "{revision.revision}", fns(p.revision.Revision))
+		pairs = append(pairs, "{revision.recordID}", fns(p.revision.RecordID))
+	}
 	return strings.NewReplacer(pairs...).Replace(in)
 }
 
@@ -531,6 +564,66 @@ func RecordActionUndelete(props ...*recordActionProps) *recordAction {
 	return a
 }
 
+// RecordActionRevisions returns "compose:record.revisions" action
+//
+// This function is auto-generated.
+//
+func RecordActionRevisions(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "revisions",
+		log:       "searched for {record} revisions",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// RecordActionRevisionDiff returns "compose:record.revisionDiff" action
+//
+// This function is auto-generated.
+//
+func RecordActionRevisionDiff(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "revisionDiff",
+		log:       "compared {record} revisions",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// RecordActionRevisionRestore returns "compose:record.revisionRestore" action
+//
+// This function is auto-generated.
+//
+func RecordActionRevisionRestore(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "revisionRestore",
+		log:       "restored {record} to revision {revision.revision}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // RecordActionImport returns "compose:record.import" action
 //
 // This function is auto-generated.
@@ -847,6 +940,66 @@ func RecordErrInvalidID(mm ...*recordActionProps) *errors.Error {
 	return e
 }
 
+// RecordErrRevisionNotFound returns "compose:record.revisionNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrRevisionNotFound(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("record revision not found", nil),
+
+		errors.Meta("type", "revisionNotFound"),
+		errors.Meta("resource", "compose:record"),
+
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// RecordErrSearchDisabled returns "compose:record.searchDisabled" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrSearchDisabled(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("record search is not enabled", nil),
+
+		errors.Meta("type", "searchDisabled"),
+		errors.Meta("resource", "compose:record"),
+
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // RecordErrInvalidNamespaceID returns "compose:record.invalidNamespaceID" as *errors.Error
 //
 //
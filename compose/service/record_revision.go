@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// Revisions returns revisions of a record
+//
+// Values of fields that current user is not allowed to read are removed from all revisions
+func (svc record) Revisions(ctx context.Context, namespaceID, moduleID, recordID uint64, f types.RecordRevisionFilter) (set types.RecordRevisionSet, _ types.RecordRevisionFilter, err error) {
+	var (
+		aProps = &recordActionProps{}
+
+		m *types.Module
+		r *types.Record
+	)
+
+	err = func() error {
+		if m, r, err = svc.loadReadableRecord(ctx, aProps, namespaceID, moduleID, recordID); err != nil {
+			return err
+		}
+
+		f.RecordID = r.ID
+		f.ModuleID = 0
+		f.NamespaceID = 0
+
+		if len(f.Sort) == 0 {
+			f.Sort = filter.SortExprSet{&filter.SortExpr{Column: "revision", Descending: true}}
+		}
+
+		if set, f, err = store.SearchComposeRecordRevisions(ctx, svc.store, f); err != nil {
+			return err
+		}
+
+		trimUnreadableRevisionFields(ctx, svc.ac, m, set...)
+		return nil
+	}()
+
+	return set, f, svc.recordAction(ctx, aProps, RecordActionRevisions, err)
+}
+
+// RevisionDiff compares values of two record revisions
+//
+// When compareToID is 0, revision is compared to the current record values
+func (svc record) RevisionDiff(ctx context.Context, namespaceID, moduleID, recordID, revisionID, compareToID uint64) (diff types.RecordValueDiffSet, err error) {
+	var (
+		aProps = &recordActionProps{}
+
+		m   *types.Module
+		r   *types.Record
+		rev *types.RecordRevision
+		cmp types.RecordValueSet
+	)
+
+	err = func() error {
+		if m, r, err = svc.loadReadableRecord(ctx, aProps, namespaceID, moduleID, recordID); err != nil {
+			return err
+		}
+
+		if rev, err = svc.loadRevision(ctx, r, revisionID); err != nil {
+			return err
+		}
+
+		aProps.setRevision(rev)
+
+		if compareToID > 0 {
+			var cmpRev *types.RecordRevision
+			if cmpRev, err = svc.loadRevision(ctx, r, compareToID); err != nil {
+				return err
+			}
+
+			trimUnreadableRevisionFields(ctx, svc.ac, m, cmpRev)
+			cmp = cmpRev.Values
+		} else {
+			trimUnreadableRecordFields(ctx, svc.ac, m, r)
+			cmp = r.Values
+		}
+
+		trimUnreadableRevisionFields(ctx, svc.ac, m, rev)
+		diff = types.DiffRecordValues(rev.Values, cmp)
+		return nil
+	}()
+
+	return diff, svc.recordAction(ctx, aProps, RecordActionRevisionDiff, err)
+}
+
+// RevisionRestore restores record values from the given revision
+//
+// Restoration is done through a regular update procedure
+// (with all access control checks, validation and events)
+// and results in a new revision
+func (svc record) RevisionRestore(ctx context.Context, namespaceID, moduleID, recordID, revisionID uint64) (rec *types.Record, err error) {
+	var (
+		aProps = &recordActionProps{}
+
+		r   *types.Record
+		rev *types.RecordRevision
+	)
+
+	err = func() error {
+		if _, r, err = svc.loadReadableRecord(ctx, aProps, namespaceID, moduleID, recordID); err != nil {
+			return err
+		}
+
+		if rev, err = svc.loadRevision(ctx, r, revisionID); err != nil {
+			return err
+		}
+
+		aProps.setRevision(rev)
+
+		rec, err = svc.update(ctx, &types.Record{
+			ID:          r.ID,
+			ModuleID:    r.ModuleID,
+			NamespaceID: r.NamespaceID,
+			OwnedBy:     r.OwnedBy,
+			Values:      rev.Values,
+		}, types.RecordRevisionRestore)
+
+		return err
+	}()
+
+	return rec, svc.recordAction(ctx, aProps, RecordActionRevisionRestore, err)
+}
+
+// loads namespace, module & record and checks if record can be read
+func (svc record) loadReadableRecord(ctx context.Context, aProps *recordActionProps, namespaceID, moduleID, recordID uint64) (m *types.Module, r *types.Record, err error) {
+	var ns *types.Namespace
+
+	if recordID == 0 {
+		return nil, nil, RecordErrInvalidID()
+	}
+
+	if ns, m, r, err = loadRecordCombo(ctx, svc.store, namespaceID, moduleID, recordID); errors.IsNotFound(err) {
+		return nil, nil, RecordErrNotFound()
+	} else if err != nil {
+		return
+	}
+
+	aProps.setNamespace(ns)
+	aProps.setModule(m)
+	aProps.setRecord(r)
+
+	if !svc.ac.CanReadRecord(ctx, m) {
+		return nil, nil, RecordErrNotAllowedToRead()
+	}
+
+	return
+}
+
+// loads revision and makes sure it belongs to the record
+func (svc record) loadRevision(ctx context.Context, r *types.Record, revisionID uint64) (rev *types.RecordRevision, err error) {
+	if rev, err = store.LookupComposeRecordRevisionByID(ctx, svc.store, revisionID); errors.IsNotFound(err) {
+		return nil, RecordErrRevisionNotFound()
+	} else if err != nil {
+		return nil, err
+	}
+
+	if rev.RecordID != r.ID {
+		return nil, RecordErrRevisionNotFound()
+	}
+
+	return rev, nil
+}
+
+// createRevision stores snapshot of the record values
+//
+// Revision number is sequential for each record; concurrent writes to the same record
+// would produce duplicate revision numbers and are rejected (unique index) as stale data
+func (svc record) createRevision(ctx context.Context, s store.Storer, r *types.Record, op types.RecordRevisionOperation) error {
+	var (
+		rev = types.MakeRecordRevision(r, op)
+		f   = types.RecordRevisionFilter{RecordID: r.ID}
+	)
+
+	f.Sort = filter.SortExprSet{&filter.SortExpr{Column: "revision", Descending: true}}
+	f.Limit = 1
+
+	last, _, err := store.SearchComposeRecordRevisions(ctx, s, f)
+	if err != nil {
+		return err
+	}
+
+	rev.Revision = 1
+	if len(last) > 0 {
+		rev.Revision = last[0].Revision + 1
+	}
+
+	rev.ID = nextID()
+	rev.CreatedAt = *now()
+	rev.CreatedBy = auth.GetIdentityFromContext(ctx).Identity()
+
+	if err = store.CreateComposeRecordRevision(ctx, s, rev); errors.Is(err, store.ErrNotUnique) {
+		return RecordErrStaleData()
+	}
+
+	return err
+}
+
+func trimUnreadableRevisionFields(ctx context.Context, ac recordValueAccessController, m *types.Module, rr ...*types.RecordRevision) {
+	for _, rev := range rr {
+		r := &types.Record{Values: rev.Values}
+		trimUnreadableRecordFields(ctx, ac, m, r)
+		rev.Values = r.Values
+	}
+}
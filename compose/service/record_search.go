@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/search"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"github.com/cortezaproject/corteza-server/store"
+	"go.uber.org/zap"
+)
+
+type (
+	recordSearch struct {
+		store store.Storer
+		log   *zap.Logger
+		ac    recordSearchAccessController
+
+		// nil when search is not enabled
+		index search.Index
+	}
+
+	recordSearchAccessController interface {
+		CanReadNamespace(context.Context, *types.Namespace) bool
+		CanReadRecord(context.Context, *types.Module) bool
+		CanReadRecordValue(context.Context, *types.ModuleField) bool
+	}
+
+	recordSearchEvent interface {
+		Record() *types.Record
+		OldRecord() *types.Record
+		Module() *types.Module
+	}
+
+	eventRegistry interface {
+		Register(h eventbus.HandlerFn, ops ...eventbus.HandlerRegOp) uintptr
+	}
+
+	// searchModule holds module and what current user can read from its records
+	searchModule struct {
+		module         *types.Module
+		readableFields map[string]bool
+	}
+
+	RecordSearchService interface {
+		Search(ctx context.Context, f types.RecordSearchFilter) (types.RecordSearchHitSet, types.RecordSearchFilter, error)
+		ReindexModule(ctx context.Context, moduleID uint64) error
+		Register(eb eventRegistry)
+		Watch(ctx context.Context)
+	}
+)
+
+const (
+	recordSearchDefaultLimit = 20
+	recordSearchMaxLimit     = 100
+
+	// Number of records loaded from the store at once when (re)indexing
+	recordSearchReindexBatchSize = 500
+)
+
+func RecordSearch(log *zap.Logger, idx search.Index) *recordSearch {
+	return &recordSearch{
+		store: DefaultStore,
+		log:   log,
+		ac:    DefaultAccessControl,
+		index: idx,
+	}
+}
+
+// Search searches for records in the namespace
+//
+// Hits are loaded from the store so that only existing records are returned;
+// record and record-value read permissions are applied: matches on fields
+// that can not be read do not contribute to the score.
+func (svc *recordSearch) Search(ctx context.Context, f types.RecordSearchFilter) (set types.RecordSearchHitSet, _ types.RecordSearchFilter, err error) {
+	if svc.index == nil {
+		return nil, f, RecordErrSearchDisabled()
+	}
+
+	if f.NamespaceID == 0 {
+		return nil, f, RecordErrInvalidNamespaceID()
+	}
+
+	if f.Limit == 0 {
+		f.Limit = recordSearchDefaultLimit
+	} else if f.Limit > recordSearchMaxLimit {
+		f.Limit = recordSearchMaxLimit
+	}
+
+	ns, err := loadNamespace(ctx, svc.store, f.NamespaceID)
+	if err != nil {
+		return nil, f, err
+	}
+
+	if !svc.ac.CanReadNamespace(ctx, ns) {
+		return nil, f, RecordErrNotAllowedToReadNamespace()
+	}
+
+	hits, err := svc.index.Search(ctx, search.Query{
+		NamespaceID: f.NamespaceID,
+		ModuleIDs:   f.ModuleID,
+		Text:        f.Query,
+	})
+
+	if err != nil {
+		return nil, f, err
+	}
+
+	var (
+		modules = make(map[uint64]*searchModule)
+		scored  = make([]*search.Hit, 0, len(hits))
+	)
+
+	for _, h := range hits {
+		sm, has := modules[h.ModuleID]
+		if !has {
+			if sm, err = svc.loadSearchModule(ctx, f.NamespaceID, h.ModuleID); err != nil {
+				return nil, f, err
+			}
+
+			modules[h.ModuleID] = sm
+		}
+
+		if sm == nil {
+			continue
+		}
+
+		// Re-score hit using only readable fields
+		h.Score = 0
+		for name, s := range h.Fields {
+			if sm.readableFields[name] {
+				h.Score += s
+			}
+		}
+
+		if h.Score > 0 {
+			scored = append(scored, h)
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+
+	for _, h := range scored {
+		if uint(len(set)) >= f.Limit {
+			break
+		}
+
+		sm := modules[h.ModuleID]
+		r, err := store.LookupComposeRecordByID(ctx, svc.store, sm.module, h.ID)
+		if errors.IsNotFound(err) {
+			// Index is not in sync with the store
+			continue
+		} else if err != nil {
+			return nil, f, err
+		}
+
+		if r.DeletedAt != nil {
+			continue
+		}
+
+		r.Values, _ = r.Values.Filter(func(v *types.RecordValue) (bool, error) {
+			return sm.readableFields[v.Name], nil
+		})
+
+		r.SetModule(sm.module)
+
+		set = append(set, &types.RecordSearchHit{Score: h.Score, Record: r})
+	}
+
+	return set, f, nil
+}
+
+// loadSearchModule loads module with fields and checks what current user can read
+//
+// Returns nil when module does not exist or its records can not be read
+func (svc *recordSearch) loadSearchModule(ctx context.Context, namespaceID, moduleID uint64) (*searchModule, error) {
+	m, err := loadModule(ctx, svc.store, moduleID)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if m.NamespaceID != namespaceID || m.DeletedAt != nil || !svc.ac.CanReadRecord(ctx, m) {
+		return nil, nil
+	}
+
+	sm := &searchModule{module: m, readableFields: make(map[string]bool)}
+	for _, f := range m.Fields {
+		sm.readableFields[f.Name] = svc.ac.CanReadRecordValue(ctx, f)
+	}
+
+	return sm, nil
+}
+
+// Register registers event handlers that keep the index in sync with records
+func (svc *recordSearch) Register(eb eventRegistry) {
+	if svc.index == nil {
+		return
+	}
+
+	eb.Register(
+		svc.handleRecordEvent,
+		eventbus.On("afterCreate", "afterUpdate", "afterDelete"),
+		eventbus.For("compose:record"),
+	)
+}
+
+func (svc *recordSearch) handleRecordEvent(ctx context.Context, ev eventbus.Event) error {
+	re, ok := ev.(recordSearchEvent)
+	if !ok {
+		return nil
+	}
+
+	var err error
+	switch {
+	case re.Record() != nil && re.Record().DeletedAt == nil:
+		err = svc.index.Index(ctx, recordSearchDocument(re.Module(), re.Record()))
+	case re.Record() != nil:
+		err = svc.index.Remove(ctx, re.Record().ID)
+	case re.OldRecord() != nil:
+		err = svc.index.Remove(ctx, re.OldRecord().ID)
+	}
+
+	if err != nil {
+		// Failed indexing should not affect record operations
+		svc.log.Error("failed to update search index", zap.String("event", ev.EventType()), zap.Error(err))
+	}
+
+	return nil
+}
+
+// Watch indexes all existing records when index is empty
+func (svc *recordSearch) Watch(ctx context.Context) {
+	if svc.index == nil {
+		return
+	}
+
+	go func() {
+		defer sentry.Recover()
+
+		if c, err := svc.index.Count(ctx); err != nil || c > 0 {
+			return
+		}
+
+		svc.log.Info("search index is empty, indexing records")
+		if err := svc.reindexAll(ctx); err != nil {
+			svc.log.Error("failed to index records", zap.Error(err))
+		}
+	}()
+}
+
+func (svc *recordSearch) reindexAll(ctx context.Context) error {
+	nn, _, err := store.SearchComposeNamespaces(ctx, svc.store, types.NamespaceFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range nn {
+		mm, _, err := store.SearchComposeModules(ctx, svc.store, types.ModuleFilter{NamespaceID: ns.ID})
+		if err != nil {
+			return err
+		}
+
+		for _, m := range mm {
+			if err = svc.ReindexModule(ctx, m.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReindexModule (re)indexes all records of the module
+//
+// Used for records that were stored without emitting events (imports)
+func (svc *recordSearch) ReindexModule(ctx context.Context, moduleID uint64) error {
+	if svc.index == nil {
+		return nil
+	}
+
+	m, err := loadModule(ctx, svc.store, moduleID)
+	if err != nil {
+		return err
+	}
+
+	f := types.RecordFilter{
+		NamespaceID: m.NamespaceID,
+		ModuleID:    m.ID,
+	}
+
+	f.Limit = recordSearchReindexBatchSize
+
+	for {
+		rr, next, err := store.SearchComposeRecords(ctx, svc.store, m, f)
+		if err != nil {
+			return err
+		}
+
+		dd := make([]*search.Document, len(rr))
+		for i, r := range rr {
+			dd[i] = recordSearchDocument(m, r)
+		}
+
+		if err = svc.index.Index(ctx, dd...); err != nil {
+			return err
+		}
+
+		if next.NextPage == nil || len(rr) == 0 {
+			return nil
+		}
+
+		f.PageCursor = next.NextPage
+	}
+}
+
+// recordSearchDocument converts record to a search document
+//
+// Only values of textual and numeric fields are indexed
+func recordSearchDocument(m *types.Module, r *types.Record) *search.Document {
+	d := &search.Document{
+		ID:          r.ID,
+		NamespaceID: r.NamespaceID,
+		ModuleID:    r.ModuleID,
+		Fields:      make(map[string][]string),
+	}
+
+	for _, f := range m.Fields {
+		if f.IsRef() || f.IsBoolean() || f.IsDateTime() || f.IsGeometry() {
+			continue
+		}
+
+		for _, v := range r.Values.FilterByName(f.Name) {
+			if v.DeletedAt == nil && v.Value != "" {
+				d.Fields[f.Name] = append(d.Fields[f.Name], v.Value)
+			}
+		}
+	}
+
+	return d
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
+	"github.com/cortezaproject/corteza-server/pkg/search"
+	"github.com/cortezaproject/corteza-server/pkg/search/local"
 	"github.com/cortezaproject/corteza-server/store"
 	"go.uber.org/zap"
 	"strconv"
@@ -33,6 +35,10 @@ type (
 	Config struct {
 		ActionLog options.ActionLogOpt
 		Storage   options.ObjectStoreOpt
+		Search    options.SearchOpt
+
+		// Encodes imported records into the store
+		RecordImportEncoder RecordImportEncoder
 	}
 
 	eventDispatcher interface {
@@ -44,6 +50,9 @@ type (
 var (
 	DefaultObjectStore objstore.Store
 
+	// DefaultSearchIndex is nil when record search is not enabled
+	DefaultSearchIndex search.Index
+
 	// DefaultStore is an interface to storage backend(s)
 	// ng (next-gen) is a temporary prefix
 	// so that we can differentiate between it and the file-only store
@@ -58,6 +67,7 @@ var (
 
 	DefaultNamespace     NamespaceService
 	DefaultImportSession ImportSessionService
+	DefaultRecordSearch  RecordSearchService
 	DefaultRecord        RecordService
 	DefaultModule        ModuleService
 	DefaultChart         ChartService
@@ -141,11 +151,25 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 		}
 	}
 
+	if DefaultSearchIndex == nil && c.Search.Enabled {
+		DefaultSearchIndex, err = local.New(c.Search.Path)
+		log.Info("initializing search index",
+			zap.String("path", c.Search.Path),
+			zap.Error(err))
+
+		if err != nil {
+			return err
+		}
+	}
+
 	DefaultNamespace = Namespace()
 	DefaultModule = Module()
 
-	DefaultImportSession = ImportSession()
+	DefaultRecordSearch = RecordSearch(DefaultLogger.Named("record-search"), DefaultSearchIndex)
+	DefaultRecordSearch.Register(eventbus.Service())
+
 	DefaultRecord = Record()
+	DefaultImportSession = ImportSession(DefaultLogger.Named("import-session"), c.RecordImportEncoder)
 	DefaultPage = Page()
 	DefaultChart = Chart()
 	DefaultNotification = Notification()
@@ -158,6 +182,7 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 		automation.ComposeModule{},
 		automation.ComposeRecord{},
 		automation.ComposeRecordValues{},
+		automation.ComposeAttachment{},
 	)
 
 	automation.RecordsHandler(
@@ -178,6 +203,11 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 		DefaultNamespace,
 	)
 
+	automation.AttachmentsHandler(
+		automationService.Registry(),
+		automationAttachment{DefaultAttachment},
+	)
+
 	return nil
 }
 
@@ -186,7 +216,8 @@ func Activate(ctx context.Context) (err error) {
 }
 
 func Watchers(ctx context.Context) {
-	//
+	DefaultImportSession.Watch(ctx)
+	DefaultRecordSearch.Watch(ctx)
 }
 
 func RegisterIteratorProviders() {
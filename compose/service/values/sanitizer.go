@@ -1,8 +1,10 @@
 package values
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/geo"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"go.uber.org/zap"
 	"strconv"
@@ -127,6 +129,9 @@ func (s sanitizer) Run(m *types.Module, vv types.RecordValueSet) (out types.Reco
 		case "number":
 			v.Value = sNumber(v.Value, f.Options.Precision())
 
+		case "geometry":
+			v.Value = sGeometry(v.Value)
+
 			// Uncomment when they become relevant for sanitization
 			//case "email":
 			//	v = s.sEmail(v, f, m)
@@ -183,6 +188,7 @@ func sDatetime(v interface{}, onlyDate, onlyTime bool) string {
 			"Monday, 02-Jan-06",
 			"Mon, 02 Jan 2006",
 			"2006/_1/_2",
+			time.RFC3339,
 		}
 	} else if onlyTime {
 		internalFormat = datetimeIntenralFormatTime
@@ -196,6 +202,7 @@ func sDatetime(v interface{}, onlyDate, onlyTime bool) string {
 			"15:04Z07:00",
 			"15:04 -0700",
 			time.Kitchen,
+			time.RFC3339,
 		}
 	} else {
 		internalFormat = datetimeInternalFormatFull
@@ -252,6 +259,31 @@ func sNumber(num interface{}, p uint) string {
 	return str
 }
 
+// sGeometry converts supported geometry formats into GeoJSON
+//
+// Invalid values are kept as they are so that validator can report them
+func sGeometry(v interface{}) string {
+	var str string
+	switch c := v.(type) {
+	case string:
+		str = c
+	default:
+		// result of an expression (map)
+		buf, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+
+		str = string(buf)
+	}
+
+	if g, err := geo.Parse(str); err == nil {
+		return g.String()
+	}
+
+	return str
+}
+
 // sanitize casts value to field kind format
 func sanitize(f *types.ModuleField, v interface{}) string {
 	switch strings.ToLower(f.Kind) {
@@ -261,6 +293,8 @@ func sanitize(f *types.ModuleField, v interface{}) string {
 		v = sDatetime(v, f.Options.Bool("onlyDate"), f.Options.Bool("onlyTime"))
 	case "number":
 		v = sNumber(v, f.Options.Precision())
+	case "geometry":
+		v = sGeometry(v)
 	}
 
 	return fmt.Sprintf("%v", v)
@@ -90,6 +90,20 @@ func Test_sanitizer_Run(t *testing.T) {
 			input:  "2020-03-11T11:20:08.471Z",
 			output: "2020-03-11T11:20:08Z",
 		},
+		{
+			name:    "timestamps should be accepted for date only values",
+			kind:    "DateTime",
+			options: map[string]interface{}{"onlyDate": true},
+			input:   "2020-03-11T00:00:00Z",
+			output:  "2020-03-11",
+		},
+		{
+			name:    "timestamps should be accepted for time only values",
+			kind:    "DateTime",
+			options: map[string]interface{}{"onlyTime": true},
+			input:   "1899-12-30T11:20:08Z",
+			output:  "11:20:08",
+		},
 		{
 			name:   "number space trim",
 			kind:   "Number",
@@ -157,6 +171,24 @@ func Test_sanitizer_Run(t *testing.T) {
 			input:   "42.040",
 			output:  "42.04",
 		},
+		{
+			name:   "geometry lat/lng pair should be converted to GeoJSON",
+			kind:   "Geometry",
+			input:  "46.0569, 14.5058",
+			output: `{"type":"Point","coordinates":[14.5058,46.0569],"bbox":[14.5058,46.0569,14.5058,46.0569]}`,
+		},
+		{
+			name:   "geometry GeoJSON should be normalized",
+			kind:   "Geometry",
+			input:  `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 2]}}`,
+			output: `{"type":"Point","coordinates":[1,2],"bbox":[1,2,1,2]}`,
+		},
+		{
+			name:   "invalid geometry should be kept intact",
+			kind:   "Geometry",
+			input:  "somewhere",
+			output: "somewhere",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
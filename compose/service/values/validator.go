@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/geo"
 	"github.com/cortezaproject/corteza-server/pkg/slice"
 	"github.com/cortezaproject/corteza-server/store"
 	"math/big"
@@ -166,6 +167,8 @@ fields:
 				out.Push(vldtr.vEmail(v, f, r, m)...)
 			case "file":
 				out.Push(vldtr.vFile(ctx, s, v, f, r, m)...)
+			case "geometry":
+				out.Push(vldtr.vGeometry(v, f, r, m)...)
 			case "number":
 				out.Push(vldtr.vNumber(v, f, r, m)...)
 			case "record":
@@ -321,6 +324,14 @@ func (vldtr validator) vFile(ctx context.Context, s store.Storer, v *types.Recor
 	return nil
 }
 
+func (vldtr validator) vGeometry(v *types.RecordValue, f *types.ModuleField, r *types.Record, m *types.Module) []types.RecordValueError {
+	if _, err := geo.Parse(v.Value); err != nil {
+		return e2s(makeInvalidValueErr(f, v.Value))
+	}
+
+	return nil
+}
+
 func (vldtr validator) vNumber(v *types.RecordValue, f *types.ModuleField, r *types.Record, m *types.Module) []types.RecordValueError {
 	if _, _, err := big.ParseFloat(v.Value, 0, f.Options.Precision(), big.ToNearestEven); err != nil {
 		return e2s(makeInvalidValueErr(f, v.Value))
@@ -125,6 +125,44 @@ func Test_validator_vNumber(t *testing.T) {
 	}
 }
 
+func Test_validator_vGeometry(t *testing.T) {
+	var (
+		vldtr = validator{}
+		tests = []struct {
+			name string
+			val  string
+			want []types.RecordValueError
+		}{
+			{
+				name: "unparsable",
+				val:  "unparsable",
+				want: e2s(types.RecordValueError{Kind: "invalidValue", Meta: map[string]interface{}{"field": "", "value": "unparsable"}}),
+			},
+			{
+				name: "out of range",
+				val:  `{"type":"Point","coordinates":[200,10]}`,
+				want: e2s(types.RecordValueError{Kind: "invalidValue", Meta: map[string]interface{}{"field": "", "value": `{"type":"Point","coordinates":[200,10]}`}}),
+			},
+			{
+				name: "valid point",
+				val:  `{"type":"Point","coordinates":[14.5058,46.0569]}`,
+			},
+			{
+				name: "valid polygon",
+				val:  `{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,1],[0,0]]]}`,
+			},
+		}
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vldtr.vGeometry(&types.RecordValue{Value: tt.val}, &types.ModuleField{}, nil, nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("vGeometry() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_validator_vUrl(t *testing.T) {
 	var (
 		vldtr = validator{}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/filter"
@@ -14,6 +17,7 @@ type (
 		Handle string         `json:"handle"`
 		Name   string         `json:"name"`
 		Meta   types.JSONText `json:"meta"`
+		Config ModuleConfig   `json:"config"`
 		Fields ModuleFieldSet `json:"fields"`
 
 		Labels map[string]string `json:"labels,omitempty"`
@@ -25,6 +29,12 @@ type (
 		DeletedAt *time.Time `json:"deletedAt,omitempty"`
 	}
 
+	ModuleConfig struct {
+		// Records of a partitioned module are stored in a dedicated table
+		// with values encoded as JSON instead of in the key-value table
+		Partitioned bool `json:"partitioned,omitempty"`
+	}
+
 	ModuleFilter struct {
 		ModuleID    []uint64 `json:"moduleID"`
 		NamespaceID uint64   `json:"namespaceID,string"`
@@ -64,6 +74,42 @@ func (m Module) Clone() *Module {
 	return c
 }
 
+// PhysicalFields returns single-value fields that are stored in physical columns
+//
+// Physical columns are only used on modules with partitioned records
+func (m Module) PhysicalFields() (out ModuleFieldSet) {
+	if !m.Config.Partitioned {
+		return
+	}
+
+	for _, f := range m.Fields {
+		if f.IsPhysical() {
+			out = append(out, f)
+		}
+	}
+
+	return
+}
+
+func (c *ModuleConfig) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*c = ModuleConfig{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, c); err != nil {
+			return fmt.Errorf("can not scan '%v' into ModuleConfig: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (c ModuleConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
 // FindByHandle finds module by it's handle
 func (set ModuleSet) FindByHandle(handle string) *Module {
 	for i := range set {
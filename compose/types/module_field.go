@@ -144,6 +144,16 @@ func (f ModuleField) IsDateTime() bool {
 	return f.Kind == "DateTime"
 }
 
+func (f ModuleField) IsGeometry() bool {
+	return f.Kind == "Geometry"
+}
+
+// IsPhysical tells us if value of this field is stored in a physical column
+// (when records are partitioned)
+func (f ModuleField) IsPhysical() bool {
+	return !f.Multi && f.Options.IsPhysical()
+}
+
 // IsRef tells us if value of this field be a reference to something
 // (another record, file , user)?
 func (f ModuleField) IsRef() bool {
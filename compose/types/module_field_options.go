@@ -17,6 +17,7 @@ const (
 	moduleFieldOptionExpression         = "expression"
 	moduleFieldOptionIsUnique           = "isUnique"
 	moduleFieldOptionIsUniqueMultiValue = "isUniqueMultiValue"
+	moduleFieldOptionIsPhysical         = "isPhysical"
 
 	moduleFieldNumberOptionPrecision         = "precision"
 	moduleFieldNumberOptionPrecisionMin uint = 0
@@ -130,6 +131,18 @@ func (opt ModuleFieldOptions) SetIsUniqueMultiValue(value bool) {
 	opt[moduleFieldOptionIsUniqueMultiValue] = value
 }
 
+// IsPhysical - should value be stored in a physical column?
+//
+// Only applicable to single-value fields on modules with partitioned records
+func (opt ModuleFieldOptions) IsPhysical() bool {
+	return opt.Bool(moduleFieldOptionIsPhysical)
+}
+
+// SetIsPhysical - should value be stored in a physical column?
+func (opt ModuleFieldOptions) SetIsPhysical(value bool) {
+	opt[moduleFieldOptionIsPhysical] = value
+}
+
 func (opt ModuleFieldOptions) Precision() (p uint) {
 	p = uint(opt.Int64(moduleFieldNumberOptionPrecision))
 
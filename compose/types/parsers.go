@@ -0,0 +1,16 @@
+package types
+
+import "encoding/json"
+
+func ParseModuleConfig(ss []string) (p ModuleConfig, err error) {
+	err = parseStringsInput(ss, &p)
+	return
+}
+
+func parseStringsInput(ss []string, p interface{}) (err error) {
+	if len(ss) == 0 {
+		return
+	}
+
+	return json.Unmarshal([]byte(ss[0]), p)
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// RecordImportSession holds uploaded source, field mapping and progress of a record import
+	//
+	// Sessions are kept in the store so that import progress is available on all nodes
+	// and interrupted imports can be resumed from the last checkpoint
+	RecordImportSession struct {
+		ID          uint64 `json:"sessionID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+
+		// Name & content type of the uploaded file
+		Name        string `json:"-"`
+		ContentType string `json:"-"`
+
+		// Uploaded file; decoded again when import is (re)started
+		Source []byte `json:"-"`
+
+		OnError  string               `json:"onError"`
+		Fields   RecordImportFields   `json:"fields"`
+		Progress RecordImportProgress `json:"progress"`
+
+		OwnedBy   uint64    `json:"userID,string"`
+		CreatedAt time.Time `json:"createdAt"`
+
+		// Updated on every checkpoint;
+		// used to detect imports interrupted by a crash or shutdown
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	// RecordImportFields maps source columns to module fields
+	RecordImportFields map[string]string
+
+	RecordImportProgress struct {
+		StartedAt  *time.Time `json:"startedAt"`
+		FinishedAt *time.Time `json:"finishedAt"`
+		EntryCount uint64     `json:"entryCount"`
+
+		// Number of processed entries (including failed ones)
+		//
+		// Import is resumed from this entry
+		Completed  uint64 `json:"completed"`
+		Failed     uint64 `json:"failed"`
+		FailReason string `json:"failReason,omitempty"`
+	}
+
+	RecordImportSessionFilter struct {
+		NamespaceID uint64 `json:"namespaceID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		OwnedBy     uint64 `json:"userID,string"`
+
+		// Filter sessions that were not updated after the given time
+		UpdatedBefore *time.Time `json:"updatedBefore"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*RecordImportSession) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+// IsRunning returns true when import was started but not (yet) finished
+func (s RecordImportSession) IsRunning() bool {
+	return s.Progress.StartedAt != nil && s.Progress.FinishedAt == nil
+}
+
+func (ff *RecordImportFields) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*ff = RecordImportFields{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, ff); err != nil {
+			return fmt.Errorf("can not scan '%v' into RecordImportFields: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (ff RecordImportFields) Value() (driver.Value, error) {
+	return json.Marshal(ff)
+}
+
+func (p *RecordImportProgress) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*p = RecordImportProgress{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, p); err != nil {
+			return fmt.Errorf("can not scan '%v' into RecordImportProgress: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (p RecordImportProgress) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
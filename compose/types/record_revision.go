@@ -0,0 +1,136 @@
+package types
+
+import (
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// RecordRevision holds a snapshot of record values after each change
+	RecordRevision struct {
+		ID          uint64 `json:"revisionID,string"`
+		RecordID    uint64 `json:"recordID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+
+		// Sequential revision number (per record)
+		Revision uint `json:"revision"`
+
+		// Operation that produced this revision
+		Operation RecordRevisionOperation `json:"operation"`
+
+		// Snapshot of all record values
+		Values RecordValueSet `json:"values"`
+
+		OwnedBy   uint64    `json:"ownedBy,string"`
+		CreatedAt time.Time `json:"createdAt,omitempty"`
+		CreatedBy uint64    `json:"createdBy,string"`
+	}
+
+	RecordRevisionFilter struct {
+		RecordID    uint64 `json:"recordID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*RecordRevision) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+
+	// RecordValueDiff holds old and new values of a single record field
+	RecordValueDiff struct {
+		Name string   `json:"name"`
+		Old  []string `json:"old"`
+		New  []string `json:"new"`
+	}
+
+	RecordValueDiffSet []*RecordValueDiff
+
+	RecordRevisionOperation string
+)
+
+const (
+	RecordRevisionCreate  RecordRevisionOperation = "create"
+	RecordRevisionUpdate  RecordRevisionOperation = "update"
+	RecordRevisionDelete  RecordRevisionOperation = "delete"
+	RecordRevisionRestore RecordRevisionOperation = "restore"
+)
+
+// MakeRecordRevision creates a new revision from the record
+//
+// Deleted values are omitted from the snapshot
+func MakeRecordRevision(r *Record, op RecordRevisionOperation) *RecordRevision {
+	return &RecordRevision{
+		RecordID:    r.ID,
+		ModuleID:    r.ModuleID,
+		NamespaceID: r.NamespaceID,
+		Operation:   op,
+		OwnedBy:     r.OwnedBy,
+		Values:      r.Values.GetClean(),
+	}
+}
+
+// DiffRecordValues compares two sets of values and returns
+// old and new values for all fields that differ
+//
+// Multi-value fields are compared by value and place
+func DiffRecordValues(old, new RecordValueSet) (out RecordValueDiffSet) {
+	var (
+		names = make([]string, 0)
+		known = make(map[string]bool)
+	)
+
+	for _, set := range []RecordValueSet{old, new} {
+		for _, v := range set.GetClean() {
+			if !known[v.Name] {
+				known[v.Name] = true
+				names = append(names, v.Name)
+			}
+		}
+	}
+
+	out = RecordValueDiffSet{}
+	for _, name := range names {
+		d := &RecordValueDiff{
+			Name: name,
+			Old:  recordValueStrings(old, name),
+			New:  recordValueStrings(new, name),
+		}
+
+		if !equalStrings(d.Old, d.New) {
+			out = append(out, d)
+		}
+	}
+
+	return
+}
+
+func recordValueStrings(set RecordValueSet, name string) []string {
+	out := make([]string, 0)
+	for _, v := range set.GetClean().FilterByName(name) {
+		out = append(out, v.Value)
+	}
+
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
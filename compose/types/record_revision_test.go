@@ -0,0 +1,57 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffRecordValues(t *testing.T) {
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name string
+		old  RecordValueSet
+		new  RecordValueSet
+		want RecordValueDiffSet
+	}{
+		{
+			name: "no changes",
+			old:  RecordValueSet{{Name: "a", Value: "1"}},
+			new:  RecordValueSet{{Name: "a", Value: "1"}},
+			want: RecordValueDiffSet{},
+		},
+		{
+			name: "changed, added and removed",
+			old:  RecordValueSet{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}},
+			new:  RecordValueSet{{Name: "a", Value: "3"}, {Name: "c", Value: "4"}},
+			want: RecordValueDiffSet{
+				{Name: "a", Old: []string{"1"}, New: []string{"3"}},
+				{Name: "b", Old: []string{"2"}, New: []string{}},
+				{Name: "c", Old: []string{}, New: []string{"4"}},
+			},
+		},
+		{
+			name: "multi-value",
+			old:  RecordValueSet{{Name: "m", Value: "1"}, {Name: "m", Value: "2", Place: 1}},
+			new:  RecordValueSet{{Name: "m", Value: "2"}, {Name: "m", Value: "1", Place: 1}},
+			want: RecordValueDiffSet{
+				{Name: "m", Old: []string{"1", "2"}, New: []string{"2", "1"}},
+			},
+		},
+		{
+			name: "deleted values are ignored",
+			old:  RecordValueSet{{Name: "a", Value: "1"}},
+			new:  RecordValueSet{{Name: "a", Value: "1"}, {Name: "a", Value: "2", Place: 1, DeletedAt: &deletedAt}},
+			want: RecordValueDiffSet{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffRecordValues(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffRecordValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package types
+
+type (
+	RecordSearchFilter struct {
+		NamespaceID uint64   `json:"namespaceID,string"`
+		ModuleID    []uint64 `json:"moduleID"`
+		Query       string   `json:"query"`
+		Limit       uint     `json:"limit"`
+	}
+
+	// RecordSearchHit is a record that matched the full-text search query
+	RecordSearchHit struct {
+		Score  float64 `json:"score"`
+		Record *Record `json:"record"`
+	}
+
+	RecordSearchHitSet []*RecordSearchHit
+)
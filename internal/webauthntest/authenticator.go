@@ -0,0 +1,211 @@
+// Package webauthntest provides software WebAuthn authenticator
+// for testing registration and assertion flows without a hardware key
+package webauthntest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+)
+
+type (
+	// SoftAuthenticator is a software implementation of a WebAuthn authenticator
+	// and the browser API that invokes it
+	//
+	// It holds a single (ES256 or RS256) credential
+	SoftAuthenticator struct {
+		// Origin reported in the client data
+		Origin string
+
+		key          crypto.Signer
+		alg          int
+		credentialID []byte
+		signCount    uint32
+	}
+
+	clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+)
+
+const (
+	credentialType = "public-key"
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+
+	// rpIdHash (32) + flags (1) + signCount (4)
+	authDataLength = 37
+
+	// aaguid (16) + credentialIdLength (2)
+	attestedCredentialLength = 18
+)
+
+// NewSoftAuthenticator creates a software authenticator with a fresh ES256 key pair
+func NewSoftAuthenticator(origin string) (*SoftAuthenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSoftAuthenticator(origin, key, webauthn.AlgES256)
+}
+
+// NewSoftRSAAuthenticator creates a software authenticator with a fresh RS256 key pair
+func NewSoftRSAAuthenticator(origin string) (*SoftAuthenticator, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSoftAuthenticator(origin, key, webauthn.AlgRS256)
+}
+
+func newSoftAuthenticator(origin string, key crypto.Signer, alg int) (*SoftAuthenticator, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &SoftAuthenticator{
+		Origin:       origin,
+		key:          key,
+		alg:          alg,
+		credentialID: id,
+	}, nil
+}
+
+// CredentialID returns ID of the credential held by the authenticator
+func (a *SoftAuthenticator) CredentialID() []byte {
+	return a.credentialID
+}
+
+// Create emulates navigator.credentials.create()
+func (a *SoftAuthenticator) Create(opt *webauthn.CreationOptions) (*webauthn.RegistrationResponse, error) {
+	var supported bool
+	for _, p := range opt.PubKeyCredParams {
+		supported = supported || (p.Type == credentialType && p.Alg == a.alg)
+	}
+
+	if !supported {
+		return nil, fmt.Errorf("no supported algorithm")
+	}
+
+	for _, d := range opt.ExcludeCredentials {
+		if string(d.ID) == string(a.credentialID) {
+			return nil, fmt.Errorf("credential already registered")
+		}
+	}
+
+	pubKey, err := encodePublicKey(a.key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	cd, err := a.clientData(ceremonyCreate, opt.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	// attested credential data: aaguid (zeroes), credential ID length, credential ID, public key
+	acd := make([]byte, attestedCredentialLength)
+	binary.BigEndian.PutUint16(acd[16:], uint16(len(a.credentialID)))
+	acd = append(append(acd, a.credentialID...), pubKey...)
+
+	ad := append(a.authData(opt.RP.ID, flagAttestedCredentialData), acd...)
+
+	attObj, err := cborEncode(map[interface{}]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[interface{}]interface{}{},
+		"authData": ad,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := &webauthn.RegistrationResponse{
+		ID:    webauthn.Base64(a.credentialID).String(),
+		RawID: a.credentialID,
+		Type:  credentialType,
+	}
+
+	r.Response.ClientDataJSON = cd
+	r.Response.AttestationObject = attObj
+	return r, nil
+}
+
+// Get emulates navigator.credentials.get()
+func (a *SoftAuthenticator) Get(opt *webauthn.RequestOptions) (*webauthn.AssertionResponse, error) {
+	var allowed = len(opt.AllowCredentials) == 0
+	for _, d := range opt.AllowCredentials {
+		allowed = allowed || string(d.ID) == string(a.credentialID)
+	}
+
+	if !allowed {
+		return nil, fmt.Errorf("credential not allowed")
+	}
+
+	cd, err := a.clientData(ceremonyGet, opt.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.signCount++
+
+	var (
+		ad             = a.authData(opt.RPID, 0)
+		clientDataHash = sha256.Sum256(cd)
+		digest         = sha256.Sum256(append(append([]byte{}, ad...), clientDataHash[:]...))
+	)
+
+	sig, err := a.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &webauthn.AssertionResponse{
+		ID:    webauthn.Base64(a.credentialID).String(),
+		RawID: a.credentialID,
+		Type:  credentialType,
+	}
+
+	r.Response.ClientDataJSON = cd
+	r.Response.AuthenticatorData = ad
+	r.Response.Signature = sig
+	return r, nil
+}
+
+func (a *SoftAuthenticator) clientData(ceremony string, challenge []byte) ([]byte, error) {
+	return json.Marshal(clientData{
+		Type:      ceremony,
+		Challenge: webauthn.Base64(challenge).String(),
+		Origin:    a.Origin,
+	})
+}
+
+func (a *SoftAuthenticator) authData(rpID string, flags byte) []byte {
+	var (
+		h  = sha256.Sum256([]byte(rpID))
+		ad = make([]byte, authDataLength)
+	)
+
+	copy(ad, h[:])
+	ad[32] = flags | flagUserPresent | flagUserVerified
+	binary.BigEndian.PutUint32(ad[33:], a.signCount)
+	return ad
+}
@@ -0,0 +1,185 @@
+package webauthntest
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+)
+
+// Minimal CBOR (RFC 7049) and COSE (RFC 8152) encoding
+//
+// Counterpart of the decoding in the webauthn package; authenticators
+// encode attestation objects and public keys, relying party only decodes them
+
+const (
+	cborUint byte = iota
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	_ // tags
+	cborSimple
+)
+
+const (
+	coseKeyType      int64 = 1
+	coseKeyAlgorithm int64 = 3
+
+	coseEC2Curve int64 = -1
+	coseEC2X     int64 = -2
+	coseEC2Y     int64 = -3
+
+	coseRSAModulus  int64 = -1
+	coseRSAExponent int64 = -2
+
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256 int64 = 1
+)
+
+// encodePublicKey encodes public key as COSE key
+func encodePublicKey(pub crypto.PublicKey) ([]byte, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+		}
+
+		var x, y [32]byte
+		k.X.FillBytes(x[:])
+		k.Y.FillBytes(y[:])
+
+		return cborEncode(map[interface{}]interface{}{
+			coseKeyType:      coseKeyTypeEC2,
+			coseKeyAlgorithm: int64(webauthn.AlgES256),
+			coseEC2Curve:     coseCurveP256,
+			coseEC2X:         x[:],
+			coseEC2Y:         y[:],
+		})
+
+	case *rsa.PublicKey:
+		return cborEncode(map[interface{}]interface{}{
+			coseKeyType:      coseKeyTypeRSA,
+			coseKeyAlgorithm: int64(webauthn.AlgRS256),
+			coseRSAModulus:   k.N.Bytes(),
+			coseRSAExponent:  big.NewInt(int64(k.E)).Bytes(),
+		})
+	}
+
+	return nil, fmt.Errorf("unsupported public key type %T", pub)
+}
+
+// cborEncode encodes value into CBOR
+//
+// Map keys are sorted using canonical CBOR ordering (shorter keys first)
+func cborEncode(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := cborEncodeTo(buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func cborEncodeTo(buf *bytes.Buffer, v interface{}) (err error) {
+	switch v := v.(type) {
+	case int:
+		cborEncodeInt(buf, int64(v))
+	case int64:
+		cborEncodeInt(buf, v)
+	case []byte:
+		cborHead(buf, cborBytes, uint64(len(v)))
+		buf.Write(v)
+	case string:
+		cborHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case []interface{}:
+		cborHead(buf, cborArray, uint64(len(v)))
+		for _, i := range v {
+			if err = cborEncodeTo(buf, i); err != nil {
+				return
+			}
+		}
+	case map[interface{}]interface{}:
+		type kv struct{ k, v []byte }
+
+		kvs := make([]kv, 0, len(v))
+		for k, val := range v {
+			var ek, ev []byte
+			if ek, err = cborEncode(k); err != nil {
+				return
+			}
+			if ev, err = cborEncode(val); err != nil {
+				return
+			}
+			kvs = append(kvs, kv{ek, ev})
+		}
+
+		sort.Slice(kvs, func(i, j int) bool {
+			if len(kvs[i].k) != len(kvs[j].k) {
+				return len(kvs[i].k) < len(kvs[j].k)
+			}
+			return bytes.Compare(kvs[i].k, kvs[j].k) < 0
+		})
+
+		cborHead(buf, cborMap, uint64(len(kvs)))
+		for _, i := range kvs {
+			buf.Write(i.k)
+			buf.Write(i.v)
+		}
+	case bool:
+		if v {
+			buf.WriteByte(cborSimple<<5 | 21)
+		} else {
+			buf.WriteByte(cborSimple<<5 | 20)
+		}
+	case nil:
+		buf.WriteByte(cborSimple<<5 | 22)
+	default:
+		return fmt.Errorf("cbor: unsupported type %T", v)
+	}
+
+	return nil
+}
+
+func cborEncodeInt(buf *bytes.Buffer, i int64) {
+	if i < 0 {
+		cborHead(buf, cborNegInt, uint64(-1-i))
+	} else {
+		cborHead(buf, cborUint, uint64(i))
+	}
+}
+
+func cborHead(buf *bytes.Buffer, major byte, arg uint64) {
+	var b [8]byte
+
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= 0xff:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= 0xffff:
+		buf.WriteByte(major<<5 | 25)
+		binary.BigEndian.PutUint16(b[:2], uint16(arg))
+		buf.Write(b[:2])
+	case arg <= 0xffffffff:
+		buf.WriteByte(major<<5 | 26)
+		binary.BigEndian.PutUint32(b[:4], uint32(arg))
+		buf.Write(b[:4])
+	default:
+		buf.WriteByte(major<<5 | 27)
+		binary.BigEndian.PutUint64(b[:], arg)
+		buf.Write(b[:])
+	}
+}
@@ -16,8 +16,8 @@ import (
 type (
 	token struct {
 		// Expiration time in minutes
-		expiry    time.Duration
-		tokenAuth *jwtauth.JWTAuth
+		expiry time.Duration
+		keys   *Keyring
 	}
 )
 
@@ -28,8 +28,11 @@ var (
 func SetupDefault(secret string, expiry time.Duration) {
 	// Use JWT secret for hmac signer for now
 	DefaultSigner = HmacSigner(secret)
-	DefaultJwtHandler, _ = JWT(secret, expiry)
 
+	// Keys in the default keyring can be replaced
+	// with asymmetric keys when store is initialized
+	DefaultKeyring = HmacKeyring(secret)
+	DefaultJwtHandler, _ = JWTWithKeyring(DefaultKeyring, expiry)
 }
 
 func JWT(secret string, expiry time.Duration) (tkn *token, err error) {
@@ -37,17 +40,65 @@ func JWT(secret string, expiry time.Duration) (tkn *token, err error) {
 		return nil, errors.New("JWT secret missing")
 	}
 
+	return JWTWithKeyring(HmacKeyring(secret), expiry)
+}
+
+// JWTWithKeyring signs and verifies tokens with keys from the keyring
+func JWTWithKeyring(keys *Keyring, expiry time.Duration) (tkn *token, err error) {
+	if keys == nil {
+		return nil, errors.New("JWT keyring missing")
+	}
+
 	tkn = &token{
-		expiry:    expiry,
-		tokenAuth: jwtauth.New(jwt.SigningMethodHS512.Alg(), []byte(secret), nil),
+		expiry: expiry,
+		keys:   keys,
 	}
 
 	return tkn, nil
 }
 
 // Verifies JWT and stores it into context
+//
+// Token is searched for in the jwt query parameter, authorization
+// header and jwt cookie (in that order)
 func (t *token) HttpVerifier() func(http.Handler) http.Handler {
-	return jwtauth.Verifier(t.tokenAuth)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tkn, err := t.verify(r, jwtauth.TokenFromQuery, jwtauth.TokenFromHeader, jwtauth.TokenFromCookie)
+			next.ServeHTTP(w, r.WithContext(jwtauth.NewContext(r.Context(), tkn, err)))
+		})
+	}
+}
+
+func (t *token) verify(r *http.Request, findTokenFns ...func(r *http.Request) string) (*jwt.Token, error) {
+	var (
+		signed string
+	)
+
+	for _, fn := range findTokenFns {
+		if signed = fn(r); signed != "" {
+			break
+		}
+	}
+
+	if signed == "" {
+		return nil, jwtauth.ErrNoTokenFound
+	}
+
+	tkn, err := t.keys.Parse(signed)
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok && verr.Errors&jwt.ValidationErrorExpired > 0 {
+			return nil, jwtauth.ErrExpired
+		}
+
+		return nil, err
+	}
+
+	if !tkn.Valid {
+		return nil, jwtauth.ErrUnauthorized
+	}
+
+	return tkn, nil
 }
 
 func (t *token) Encode(i Identifiable) string {
@@ -57,7 +108,7 @@ func (t *token) Encode(i Identifiable) string {
 		roles += fmt.Sprintf(" %d", r)
 	}
 
-	_, tkn, _ := t.tokenAuth.Encode(jwt.MapClaims{
+	tkn, _ := t.keys.Sign(jwt.MapClaims{
 		"sub": i.String(),
 		"exp": time.Now().Add(t.expiry).Unix(),
 
@@ -0,0 +1,416 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+type (
+	// SigningKey is used for signing and verifying JWTs
+	SigningKey struct {
+		// Key ID, set as kid header on signed tokens
+		//
+		// Key used with shared secret (HMAC) has no ID
+		ID string
+
+		Method jwt.SigningMethod
+
+		// *rsa.PrivateKey, *ecdsa.PrivateKey or []byte with HMAC secret
+		Key interface{}
+
+		CreatedAt time.Time
+
+		// Retired keys are no longer used for signing
+		// but remain valid until they expire
+		RetiredAt *time.Time
+		ExpiresAt *time.Time
+	}
+
+	// Keyring holds keys used for signing and verifying JWTs
+	//
+	// The newest key that is not retired is used for signing; all keys
+	// that did not expire yet are used for verification
+	Keyring struct {
+		mux *sync.RWMutex
+
+		signing *SigningKey
+		keys    map[string]*SigningKey
+
+		// reloads keys when token signed with an unknown key
+		// is verified (key was rotated on another node)
+		reload     func() error
+		reloadedAt time.Time
+	}
+
+	// JSONWebKey is a public part of the signing key (RFC 7517)
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+
+		// RSA public key
+		N string `json:"n,omitempty"`
+		E string `json:"e,omitempty"`
+
+		// EC public key
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	JSONWebKeySet struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+)
+
+const (
+	// min time between two key reloads
+	keyringReloadInterval = time.Second * 30
+
+	rsaKeyBits = 2048
+)
+
+var (
+	DefaultKeyring *Keyring
+
+	// wrapper around time.Now() that will aid testing
+	now = func() time.Time {
+		return time.Now()
+	}
+)
+
+// NewKeyring creates an empty keyring
+func NewKeyring() *Keyring {
+	return &Keyring{
+		mux:  &sync.RWMutex{},
+		keys: make(map[string]*SigningKey),
+	}
+}
+
+// HmacKeyring creates keyring with a single HS512 key using shared secret
+func HmacKeyring(secret string) *Keyring {
+	kr := NewKeyring()
+	_ = kr.Set(&SigningKey{Method: jwt.SigningMethodHS512, Key: []byte(secret)})
+	return kr
+}
+
+// IsAsymmetricAlgorithm reports if keys for the signing algorithm can be generated
+func IsAsymmetricAlgorithm(alg string) bool {
+	switch alg {
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg():
+		return true
+	}
+
+	return false
+}
+
+// GenerateSigningKey generates new RS256 or ES256 private key
+func GenerateSigningKey(kid, alg string) (k *SigningKey, err error) {
+	k = &SigningKey{ID: kid, CreatedAt: now()}
+
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		k.Method = jwt.SigningMethodRS256
+		k.Key, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case jwt.SigningMethodES256.Alg():
+		k.Method = jwt.SigningMethodES256
+		k.Key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// ParseSigningKey parses PEM encoded RSA or EC private key
+//
+// Signing method is determined from the key type (and curve)
+func ParseSigningKey(kid string, data []byte) (k *SigningKey, err error) {
+	var (
+		block, _ = pem.Decode(data)
+		key      interface{}
+	)
+
+	if block == nil {
+		return nil, errors.New("invalid PEM encoded private key")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key type %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	k = &SigningKey{ID: kid, Key: key}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		k.Method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			k.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			k.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			k.Method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("unsupported elliptic curve")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return k, nil
+}
+
+// PEM encodes private key
+func (k SigningKey) PEM() ([]byte, error) {
+	switch key := k.Key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	}
+
+	return nil, fmt.Errorf("unsupported private key type %T", k.Key)
+}
+
+// Expired reports if key expired at the given time
+func (k SigningKey) Expired(at time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(at)
+}
+
+// verificationKey returns public key (or shared secret) used to verify token signature
+func (k SigningKey) verificationKey() interface{} {
+	switch key := k.Key.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey
+	case *ecdsa.PrivateKey:
+		return &key.PublicKey
+	}
+
+	return k.Key
+}
+
+// JWK returns public part of the signing key
+//
+// Returns nil for keys with shared secrets
+func (k SigningKey) JWK() *JSONWebKey {
+	var (
+		jwk = &JSONWebKey{Use: "sig", Kid: k.ID, Alg: k.Method.Alg()}
+		enc = base64.RawURLEncoding.EncodeToString
+	)
+
+	switch key := k.Key.(type) {
+	case *rsa.PrivateKey:
+		jwk.Kty = "RSA"
+		jwk.N = enc(key.N.Bytes())
+		jwk.E = enc(big.NewInt(int64(key.E)).Bytes())
+
+	case *ecdsa.PrivateKey:
+		var (
+			// coordinates are padded to the curve size
+			size = (key.Curve.Params().BitSize + 7) / 8
+			x    = make([]byte, size)
+			y    = make([]byte, size)
+		)
+
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+
+		jwk.Kty = "EC"
+		jwk.Crv = key.Curve.Params().Name
+		jwk.X = enc(x)
+		jwk.Y = enc(y)
+
+	default:
+		return nil
+	}
+
+	return jwk
+}
+
+// Set replaces all keys in the keyring
+//
+// Expired keys are ignored
+func (kr *Keyring) Set(kk ...*SigningKey) error {
+	var (
+		n       = now()
+		signing *SigningKey
+		keys    = make(map[string]*SigningKey)
+	)
+
+	// newest first
+	sort.SliceStable(kk, func(i, j int) bool { return kk[i].CreatedAt.After(kk[j].CreatedAt) })
+
+	for _, k := range kk {
+		if k.Expired(n) {
+			continue
+		}
+
+		if signing == nil && k.RetiredAt == nil {
+			signing = k
+		}
+
+		keys[k.ID] = k
+	}
+
+	if signing == nil {
+		return errors.New("no valid signing key")
+	}
+
+	kr.mux.Lock()
+	defer kr.mux.Unlock()
+
+	kr.signing = signing
+	kr.keys = keys
+	return nil
+}
+
+// SetReloader sets function that is called to reload keys
+// when token with an unknown key ID is verified
+func (kr *Keyring) SetReloader(fn func() error) {
+	kr.mux.Lock()
+	defer kr.mux.Unlock()
+	kr.reload = fn
+}
+
+// Algorithm returns name of the algorithm used for signing
+func (kr *Keyring) Algorithm() string {
+	kr.mux.RLock()
+	defer kr.mux.RUnlock()
+
+	if kr.signing == nil {
+		return ""
+	}
+
+	return kr.signing.Method.Alg()
+}
+
+// Sign signs claims with the current signing key
+func (kr *Keyring) Sign(claims jwt.Claims) (string, error) {
+	kr.mux.RLock()
+	k := kr.signing
+	kr.mux.RUnlock()
+
+	if k == nil {
+		return "", errors.New("signing key not set")
+	}
+
+	tkn := jwt.NewWithClaims(k.Method, claims)
+	if k.ID != "" {
+		tkn.Header["kid"] = k.ID
+	}
+
+	return tkn.SignedString(k.Key)
+}
+
+// Parse parses and verifies signed token
+func (kr *Keyring) Parse(signed string) (*jwt.Token, error) {
+	return jwt.Parse(signed, kr.keyFunc)
+}
+
+// JWKS returns set of public keys that are used to verify tokens
+func (kr *Keyring) JWKS() *JSONWebKeySet {
+	var (
+		n   = now()
+		set = &JSONWebKeySet{Keys: make([]JSONWebKey, 0)}
+	)
+
+	kr.mux.RLock()
+	defer kr.mux.RUnlock()
+
+	for _, k := range kr.keys {
+		if k.Expired(n) {
+			continue
+		}
+
+		if jwk := k.JWK(); jwk != nil {
+			set.Keys = append(set.Keys, *jwk)
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// keyFunc returns verification key for the token
+//
+// Keys are reloaded (at most once per reload interval) when token
+// references an unknown key
+func (kr *Keyring) keyFunc(t *jwt.Token) (interface{}, error) {
+	var (
+		kid, _ = t.Header["kid"].(string)
+		k      = kr.lookup(kid)
+	)
+
+	if k == nil && kr.reloadable() {
+		if err := kr.reload(); err != nil {
+			return nil, fmt.Errorf("could not reload signing keys: %w", err)
+		}
+
+		k = kr.lookup(kid)
+	}
+
+	if k == nil || k.Expired(now()) {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if t.Method.Alg() != k.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+	}
+
+	return k.verificationKey(), nil
+}
+
+func (kr *Keyring) lookup(kid string) *SigningKey {
+	kr.mux.RLock()
+	defer kr.mux.RUnlock()
+	return kr.keys[kid]
+}
+
+// reports if keys can be reloaded and marks the reload
+func (kr *Keyring) reloadable() bool {
+	kr.mux.Lock()
+	defer kr.mux.Unlock()
+
+	if kr.reload == nil || now().Sub(kr.reloadedAt) < keyringReloadInterval {
+		return false
+	}
+
+	kr.reloadedAt = now()
+	return true
+}
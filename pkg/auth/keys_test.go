@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	var (
+		claims = func() jwt.MapClaims {
+			return jwt.MapClaims{"sub": "42", "exp": now().Add(time.Hour).Unix()}
+		}
+
+		kid = func(signed string) string {
+			tkn, _, err := new(jwt.Parser).ParseUnverified(signed, jwt.MapClaims{})
+			require.NoError(t, err)
+			kid, _ := tkn.Header["kid"].(string)
+			return kid
+		}
+	)
+
+	t.Run("shared secret", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			kr  = HmacKeyring("secret")
+		)
+
+		signed, err := kr.Sign(claims())
+		req.NoError(err)
+		req.Empty(kid(signed))
+		req.Equal("HS512", kr.Algorithm())
+
+		_, err = kr.Parse(signed)
+		req.NoError(err)
+
+		_, err = HmacKeyring("other").Parse(signed)
+		req.Error(err)
+
+		req.Empty(kr.JWKS().Keys)
+	})
+
+	for _, alg := range []string{"RS256", "ES256"} {
+		t.Run(alg, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				kr  = NewKeyring()
+			)
+
+			k, err := GenerateSigningKey("k1", alg)
+			req.NoError(err)
+			req.NoError(kr.Set(k))
+
+			signed, err := kr.Sign(claims())
+			req.NoError(err)
+			req.Equal("k1", kid(signed))
+
+			_, err = kr.Parse(signed)
+			req.NoError(err)
+
+			// encode & parse must preserve the key
+			pem, err := k.PEM()
+			req.NoError(err)
+			parsed, err := ParseSigningKey("k1", pem)
+			req.NoError(err)
+			req.Equal(alg, parsed.Method.Alg())
+
+			jwks := kr.JWKS()
+			req.Len(jwks.Keys, 1)
+			req.Equal("k1", jwks.Keys[0].Kid)
+			req.Equal(alg, jwks.Keys[0].Alg)
+			req.Equal("sig", jwks.Keys[0].Use)
+		})
+	}
+
+	t.Run("rotation", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			kr  = NewKeyring()
+			n   = now()
+			exp = n.Add(time.Hour)
+		)
+
+		k1, err := GenerateSigningKey("k1", "RS256")
+		req.NoError(err)
+		req.NoError(kr.Set(k1))
+
+		signed, err := kr.Sign(claims())
+		req.NoError(err)
+
+		k2, err := GenerateSigningKey("k2", "ES256")
+		req.NoError(err)
+		k2.CreatedAt = n.Add(time.Second)
+		k1.RetiredAt, k1.ExpiresAt = &n, &exp
+		req.NoError(kr.Set(k1, k2))
+
+		// retired key remains valid
+		_, err = kr.Parse(signed)
+		req.NoError(err)
+		req.Len(kr.JWKS().Keys, 2)
+
+		// new tokens are signed with the new key
+		signed2, err := kr.Sign(claims())
+		req.NoError(err)
+		req.Equal("k2", kid(signed2))
+		req.Equal("ES256", kr.Algorithm())
+
+		// expired keys are removed
+		exp = n.Add(-time.Second)
+		req.NoError(kr.Set(k1, k2))
+		_, err = kr.Parse(signed)
+		req.Error(err)
+		req.Len(kr.JWKS().Keys, 1)
+
+		// at least one key must be valid for signing
+		req.Error(kr.Set(k1))
+	})
+
+	t.Run("unknown key reload", func(t *testing.T) {
+		var (
+			req      = require.New(t)
+			kr       = NewKeyring()
+			other    = NewKeyring()
+			reloaded int
+		)
+
+		k1, err := GenerateSigningKey("k1", "ES256")
+		req.NoError(err)
+		k2, err := GenerateSigningKey("k2", "ES256")
+		req.NoError(err)
+
+		req.NoError(kr.Set(k1))
+		req.NoError(other.Set(k2))
+
+		// token signed on another node with a newer key
+		signed, err := other.Sign(claims())
+		req.NoError(err)
+
+		kr.SetReloader(func() error {
+			reloaded++
+			return kr.Set(k1, k2)
+		})
+
+		_, err = kr.Parse(signed)
+		req.NoError(err)
+		req.Equal(1, reloaded)
+
+		// reloads are throttled
+		unknown, err := GenerateSigningKey("k3", "ES256")
+		req.NoError(err)
+		req.NoError(other.Set(unknown))
+		signed, err = other.Sign(claims())
+		req.NoError(err)
+
+		_, err = kr.Parse(signed)
+		req.Error(err)
+		req.Equal(1, reloaded)
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			kr  = NewKeyring()
+		)
+
+		k, err := GenerateSigningKey("k1", "RS256")
+		req.NoError(err)
+		req.NoError(kr.Set(k))
+
+		// token with the same kid, signed with HMAC
+		tkn := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+		tkn.Header["kid"] = "k1"
+		signed, err := tkn.SignedString([]byte("secret"))
+		req.NoError(err)
+
+		_, err = kr.Parse(signed)
+		req.Error(err)
+	})
+}
@@ -172,10 +172,15 @@ func (d *composeDecoder) decodeComposeRecord(ctx context.Context, s store.Storer
 	}
 	relUsers.Add(uu...)
 
+	// Values of multi-value fields are joined with a new line
 	mapValues := func(r *types.Record) map[string]string {
 		rr := make(map[string]string)
 		for _, v := range r.Values {
-			rr[v.Name] = v.Value
+			if _, has := rr[v.Name]; has {
+				rr[v.Name] += "\n" + v.Value
+			} else {
+				rr[v.Name] = v.Value
+			}
 		}
 
 		return rr
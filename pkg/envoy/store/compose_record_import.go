@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// ImportComposeRecords encodes shaped compose records into the store and updates import progress
+//
+// Records are created with the given create function.
+// Failed records are counted and skipped when skipFailed is set,
+// otherwise the first failure stops the import.
+func ImportComposeRecords(ctx context.Context, s store.Storer, create service.RecordCreator, skipFailed bool, p *types.RecordImportProgress, rr ...resource.Interface) error {
+	cfg := &EncoderConfig{
+		// For now the identifier is ignored, so this will never occur
+		OnExisting: resource.Skip,
+		Defer: func() {
+			p.Completed++
+		},
+		CreateComposeRecord: func(ctx context.Context, s store.Storer, rec *types.Record) (*types.Record, error) {
+			rec, err := create(ctx, s, rec)
+			if rve := types.IsRecordValueErrorSet(err); rve != nil {
+				// Value errors describe what is wrong with the entry
+				return nil, rve
+			}
+
+			return rec, err
+		},
+	}
+
+	if skipFailed {
+		cfg.DeferNok = func(err error) error {
+			p.Failed++
+			p.FailReason = err.Error()
+
+			return nil
+		}
+	}
+
+	se := NewStoreEncoder(s, cfg)
+	g, err := envoy.NewBuilder(se).Build(ctx, rr...)
+	if err != nil {
+		return err
+	}
+
+	return envoy.Encode(ctx, g, se)
+}
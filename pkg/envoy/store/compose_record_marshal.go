@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/service"
@@ -288,25 +289,51 @@ func (n *composeRecord) Encode(ctx context.Context, pl *payload) (err error) {
 		}
 
 		rvs := make(types.RecordValueSet, 0, len(r.Values))
-		for k, v := range r.Values {
-			rv := &types.RecordValue{
-				RecordID: rec.ID,
-				Name:     k,
-				Value:    v,
-				Updated:  true,
+		for k, raw := range r.Values {
+			f := mod.Fields.FindByName(k)
+
+			// Values of multi-value fields are separated by a new line
+			vv := []string{raw}
+			if f != nil && f.Multi {
+				vv = strings.Split(raw, "\n")
 			}
 
-			f := mod.Fields.FindByName(k)
-			if f != nil && f.Kind == "User" {
-				uID := ux[v]
-				if uID == 0 {
-					return resource.UserErrUnresolved(resource.MakeIdentifiers(v))
+			place := uint(0)
+			for _, v := range vv {
+				if f != nil && f.Multi && strings.TrimSpace(v) == "" {
+					continue
+				}
+
+				rv := &types.RecordValue{
+					RecordID: rec.ID,
+					Name:     k,
+					Value:    v,
+					Place:    place,
+					Updated:  true,
 				}
-				rv.Value = strconv.FormatUint(uID, 10)
-				rv.Ref = uID
+				place++
+
+				if f != nil && f.Kind == "User" {
+					uID := ux[v]
+					if uID == 0 {
+						return resource.UserErrUnresolved(resource.MakeIdentifiers(v))
+					}
+					rv.Value = strconv.FormatUint(uID, 10)
+					rv.Ref = uID
+				}
+
+				rvs = append(rvs, rv)
+			}
+		}
+
+		if !exists && n.cfg.CreateComposeRecord != nil {
+			rec.Values = rvs
+			if rec, err = n.cfg.CreateComposeRecord(ctx, pl.s, rec); err != nil {
+				return dfr(err)
 			}
 
-			rvs = append(rvs, rv)
+			im[r.ID] = rec.ID
+			return dfr(nil)
 		}
 
 		if err = service.RecordValueSanitazion(mod, rvs); err != nil {
@@ -39,6 +39,10 @@ type (
 		// If you return an error, the encoding will terminate.
 		// If you return nil (ignore the error), the encoding will continue.
 		DeferNok func(error) error
+
+		// CreateComposeRecord is used to create new records when set;
+		// values are then processed and validated by the create function
+		CreateComposeRecord func(context.Context, store.Storer, *types.Record) (*types.Record, error)
 	}
 
 	accessControlRBACServicer interface {
@@ -0,0 +1,207 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+)
+
+type (
+	bulkComposeRecordEncoder struct {
+		encoderConfig *EncoderConfig
+
+		res *resource.ComposeRecord
+	}
+)
+
+func bulkComposeRecordEncoderFromResource(rec *resource.ComposeRecord, cfg *EncoderConfig) *bulkComposeRecordEncoder {
+	return &bulkComposeRecordEncoder{
+		encoderConfig: cfg,
+
+		res: rec,
+	}
+}
+
+// Prepare prepares the composeRecord to be encoded
+//
+// Any validation, additional constraining should be performed here.
+func (n *bulkComposeRecordEncoder) Prepare(ctx context.Context, state *envoy.ResourceState) (err error) {
+	_, ok := state.Res.(*resource.ComposeRecord)
+	if !ok {
+		return encoderErrInvalidResource(resource.COMPOSE_RECORD_RESOURCE_TYPE, state.Res.ResourceType())
+	}
+
+	return nil
+}
+
+// Encode encodes the composeRecord to the document
+//
+// Number, Bool and DateTime values are encoded as typed cells;
+// values of multi-value fields are put in the same cell, each in a new line.
+func (n *bulkComposeRecordEncoder) Encode(ctx context.Context, w io.Writer, state *envoy.ResourceState) (err error) {
+	var (
+		all = len(n.encoderConfig.Fields) == 0
+		tz  = time.UTC
+	)
+
+	if n.encoderConfig.Timezone != "" {
+		if tz, err = time.LoadLocation(n.encoderConfig.Timezone); err != nil {
+			return err
+		}
+	}
+
+	sw, err := newSheetWriter(w)
+	if err != nil {
+		return err
+	}
+
+	// Generate header & cell index
+	hh := make([]cell, 0, 100)
+	hh = append(hh, cell{value: "id", style: styleHeader})
+
+	fx := make(map[string]int)
+	// 1 sys fields
+	sysFields := []string{"ownedBy", "createdAt", "createdBy", "updatedAt", "updatedBy", "deletedAt", "deletedBy"}
+	for _, sf := range sysFields {
+		if all || n.encoderConfig.Fields[sf] {
+			fx[sf] = len(hh)
+			hh = append(hh, cell{value: sf, style: styleHeader})
+		}
+	}
+
+	// 2 module fields
+	for _, f := range n.res.RelMod.Fields {
+		if all || n.encoderConfig.Fields[f.Name] {
+			fx[f.Name] = len(hh)
+			hh = append(hh, cell{value: f.Name, style: styleHeader})
+		}
+	}
+
+	if err = sw.WriteRow(hh); err != nil {
+		return err
+	}
+
+	err = n.res.Walker(func(r *resource.ComposeRecordRaw) error {
+		row := make([]cell, len(hh))
+		row[0] = cell{value: r.ID}
+
+		var (
+			err error
+
+			stringify = func(k string, us *resource.Userstamp) {
+				if err != nil || us == nil || !(all || n.encoderConfig.Fields[k]) {
+					return
+				}
+
+				row[fx[k]].value, err = n.res.UserFlakes.GetByStamp(us).Stringify()
+			}
+
+			timestamp = func(k string, ts *resource.Timestamp) {
+				if ts == nil || ts.T == nil || !(all || n.encoderConfig.Fields[k]) {
+					return
+				}
+
+				row[fx[k]] = dateTimeCell(ts.T.In(tz), styleDateTime)
+			}
+		)
+
+		if r.Us != nil {
+			stringify("ownedBy", r.Us.OwnedBy)
+			stringify("createdBy", r.Us.CreatedBy)
+			stringify("updatedBy", r.Us.UpdatedBy)
+			stringify("deletedBy", r.Us.DeletedBy)
+		}
+
+		if r.Ts != nil {
+			timestamp("createdAt", r.Ts.CreatedAt)
+			timestamp("updatedAt", r.Ts.UpdatedAt)
+			timestamp("deletedAt", r.Ts.DeletedAt)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for k, v := range r.Values {
+			// Skip fields we don't want
+			if !all && !n.encoderConfig.Fields[k] {
+				continue
+			}
+
+			ix, has := fx[k]
+			if !has {
+				return fmt.Errorf("unknown cell %s", k)
+			}
+			f := n.res.RelMod.Fields.FindByName(k)
+			if f == nil {
+				return fmt.Errorf("field %s not found", k)
+			}
+
+			if row[ix], err = n.valueCell(f, v, tz); err != nil {
+				return err
+			}
+		}
+
+		return sw.WriteRow(row)
+	})
+	if err != nil {
+		return err
+	}
+
+	return sw.Close()
+}
+
+// valueCell converts record value into a typed cell
+//
+// Values that can not be converted are encoded as strings
+func (n *bulkComposeRecordEncoder) valueCell(f *types.ModuleField, v string, tz *time.Location) (c cell, err error) {
+	c = cell{value: v}
+
+	if f.Multi {
+		if strings.Contains(v, "\n") {
+			c.style = styleWrap
+		}
+
+		return
+	}
+
+	switch f.Kind {
+	case "User":
+		c.value, err = n.res.UserFlakes.GetByKey(v).Stringify()
+
+	case "Number":
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			c.kind = cellNumber
+		}
+
+	case "Bool":
+		c.kind = cellBool
+		c.value = "0"
+		if v == "1" {
+			c.value = "1"
+		}
+
+	case "DateTime":
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			c = dateTimeCell(t.In(tz), styleDateTime)
+		} else if t, err := time.Parse("2006-01-02", v); err == nil {
+			c = dateTimeCell(t, styleDate)
+		} else if t, err := time.Parse("15:04:05", v); err == nil {
+			// time only values are a fraction of the day
+			c = dateTimeCell(t.AddDate(1899, 11, 29), styleTime)
+		}
+	}
+
+	return
+}
+
+func dateTimeCell(t time.Time, style int) cell {
+	return cell{kind: cellDateTime, value: toSerial(t), style: style}
+}
@@ -0,0 +1,116 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/gabriel-vasile/mimetype"
+)
+
+type (
+	// wrapper struct for xlsx related methods
+	decoder struct{}
+
+	// xlsx decoder wrapper for additional bits
+	reader struct {
+		rows   [][]string
+		header []string
+		pos    int
+	}
+)
+
+const (
+	mimeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Decoder initializes and returns a fresh XLSX decoder
+//
+// Only the first worksheet is decoded; first row is used as a header
+func Decoder() *decoder {
+	return &decoder{}
+}
+
+// CanDecodeFile determines if the file can be decoded by this decoder
+func (y *decoder) CanDecodeFile(f io.Reader) bool {
+	m, err := mimetype.DetectReader(f)
+	if err != nil {
+		return false
+	}
+
+	return y.CanDecodeExt(m.Extension())
+}
+
+func (y *decoder) CanDecodeMime(m string) bool {
+	return m == mimeXlsx
+}
+
+func (y *decoder) CanDecodeExt(ext string) bool {
+	pt := strings.Split(ext, ".")
+	return strings.TrimSpace(pt[len(pt)-1]) == "xlsx"
+}
+
+// Decode decodes the given io.Reader into a generic resource dataset
+//
+// Typed cells are converted to strings that compose record value sanitizers understand;
+// booleans as true/false and dates as RFC3339 timestamps.
+// Multi-value fields are expected to have each value in a new line inside the cell.
+func (y *decoder) Decode(ctx context.Context, r io.Reader, do *envoy.DecoderOpts) ([]resource.Interface, error) {
+	// Archive requires random access so the whole document is read
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+	if err != nil {
+		return nil, err
+	}
+
+	xr := &reader{}
+	if xr.rows, err = readRows(zr); err != nil {
+		return nil, err
+	}
+
+	if len(xr.rows) > 0 {
+		xr.header = xr.rows[0]
+		xr.rows = xr.rows[1:]
+	}
+
+	return []resource.Interface{resource.NewResourceDataset(do.Name, xr)}, nil
+}
+
+// Fields returns every available field in this dataset
+func (xr *reader) Fields() []string {
+	return xr.header
+}
+
+// Next returns the field: value mapping for the next row
+func (xr *reader) Next() (map[string]string, error) {
+	if xr.pos >= len(xr.rows) {
+		return nil, nil
+	}
+
+	mr := make(map[string]string)
+	row := xr.rows[xr.pos]
+	xr.pos++
+
+	for i, h := range xr.header {
+		if i < len(row) {
+			mr[h] = row[i]
+		} else {
+			mr[h] = ""
+		}
+	}
+
+	return mr, nil
+}
+
+func (xr *reader) Count() uint64 {
+	return uint64(len(xr.rows))
+}
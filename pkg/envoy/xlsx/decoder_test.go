@@ -0,0 +1,196 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func makeDocument(t *testing.T, rows ...[]cell) *bytes.Buffer {
+	var (
+		req = require.New(t)
+		buf = &bytes.Buffer{}
+	)
+
+	sw, err := newSheetWriter(buf)
+	req.NoError(err)
+
+	for _, r := range rows {
+		req.NoError(sw.WriteRow(r))
+	}
+
+	req.NoError(sw.Close())
+	return buf
+}
+
+// makeRawDocument packs given parts (path => content) into a workbook
+func makeRawDocument(t *testing.T, parts map[string]string) *bytes.Buffer {
+	var (
+		req = require.New(t)
+		buf = &bytes.Buffer{}
+		zw  = zip.NewWriter(buf)
+	)
+
+	for name, doc := range parts {
+		w, err := zw.Create(name)
+		req.NoError(err)
+		_, err = w.Write([]byte(doc))
+		req.NoError(err)
+	}
+
+	req.NoError(zw.Close())
+	return buf
+}
+
+// makeSheetDocument makes a workbook with a single worksheet with the given sheet data
+func makeSheetDocument(t *testing.T, sheetData string) *bytes.Buffer {
+	return makeRawDocument(t, map[string]string{
+		"xl/workbook.xml":            workbookDoc,
+		"xl/_rels/workbook.xml.rels": workbookRelsDoc,
+		"xl/worksheets/sheet1.xml":   sheetHeader + sheetData + sheetFooter,
+	})
+}
+
+func TestDecoder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("typed cells", func(t *testing.T) {
+		req := require.New(t)
+
+		doc := makeDocument(t,
+			[]cell{{value: "id", style: styleHeader}, {value: "str"}, {value: "num"}, {value: "bool"}, {value: "dt"}, {value: "multi"}},
+			[]cell{
+				{value: "1"},
+				{value: "<foo & bar>"},
+				{kind: cellNumber, value: "42.5"},
+				{kind: cellBool, value: "1"},
+				{kind: cellDateTime, value: "44197.5", style: styleDateTime},
+				{value: "a\nb", style: styleWrap},
+			},
+			[]cell{{value: "2"}, {}, {kind: cellNumber, value: "-1"}, {kind: cellBool, value: "0"}},
+		)
+
+		xd := Decoder()
+		req.True(xd.CanDecodeFile(bytes.NewReader(doc.Bytes())))
+
+		ii, err := xd.Decode(ctx, doc, &envoy.DecoderOpts{Name: "records"})
+		req.NoError(err)
+
+		ds, ok := ii[0].(*resource.ResourceDataset)
+		req.True(ok)
+		req.Equal(resource.MakeIdentifiers("records"), ds.Identifiers())
+		req.Equal([]string{"id", "str", "num", "bool", "dt", "multi"}, ds.P.Fields())
+		req.Equal(uint64(2), ds.P.Count())
+
+		n, err := ds.P.Next()
+		req.NoError(err)
+		req.Equal(map[string]string{
+			"id":    "1",
+			"str":   "<foo & bar>",
+			"num":   "42.5",
+			"bool":  "true",
+			"dt":    "2021-01-01T12:00:00Z",
+			"multi": "a\nb",
+		}, n)
+
+		n, err = ds.P.Next()
+		req.NoError(err)
+		req.Equal(map[string]string{
+			"id":    "2",
+			"str":   "",
+			"num":   "-1",
+			"bool":  "false",
+			"dt":    "",
+			"multi": "",
+		}, n)
+
+		n, err = ds.P.Next()
+		req.Nil(n)
+		req.Nil(err)
+	})
+
+	t.Run("shared strings and custom date formats", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			doc = makeRawDocument(t, map[string]string{
+				"xl/workbook.xml": `<workbook xmlns="` + nsMain + `" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+					`<workbookPr date1904="1"/><sheets><sheet name="Data" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+				"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+					`<Relationship Id="rId3" Type="worksheet" Target="worksheets/data.xml"/></Relationships>`,
+				"xl/sharedStrings.xml": `<sst xmlns="` + nsMain + `"><si><t>name</t></si><si><t>date</t></si><si><r><t>rich </t></r><r><t>text</t></r></si></sst>`,
+				"xl/styles.xml": `<styleSheet xmlns="` + nsMain + `"><numFmts count="2"><numFmt numFmtId="170" formatCode="dd/mm/yyyy"/>` +
+					`<numFmt numFmtId="171" formatCode="&quot;days&quot; 0"/></numFmts>` +
+					`<cellXfs count="3"><xf numFmtId="0"/><xf numFmtId="170"/><xf numFmtId="171"/></cellXfs></styleSheet>`,
+				"xl/worksheets/data.xml": `<worksheet xmlns="` + nsMain + `"><sheetData>` +
+					`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>count</t></is></c></row>` +
+					`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" s="1"><v>0</v></c><c r="C3" s="2"><v>10</v></c></row>` +
+					`<row r="4"></row>` +
+					`</sheetData></worksheet>`,
+			})
+		)
+
+		ii, err := Decoder().Decode(ctx, doc, &envoy.DecoderOpts{Name: "records"})
+		req.NoError(err)
+
+		ds := ii[0].(*resource.ResourceDataset)
+		req.Equal([]string{"name", "date", "count"}, ds.P.Fields())
+		req.Equal(uint64(2), ds.P.Count())
+
+		// empty row
+		n, err := ds.P.Next()
+		req.NoError(err)
+		req.Equal(map[string]string{"name": "", "date": "", "count": ""}, n)
+
+		n, err = ds.P.Next()
+		req.NoError(err)
+		req.Equal(map[string]string{"name": "rich text", "date": "1904-01-01T00:00:00Z", "count": "10"}, n)
+	})
+
+	t.Run("worksheet limits", func(t *testing.T) {
+		for name, sheetData := range map[string]string{
+			"cell reference without column": `<row r="1"><c r="1" t="inlineStr"><is><t>a</t></is></c></row>`,
+			"column reference too long":     `<row r="1"><c r="AAAAAAAAAAAAAAAA1" t="inlineStr"><is><t>a</t></is></c></row>`,
+			"column after XFD":              `<row r="1"><c r="XFE1" t="inlineStr"><is><t>a</t></is></c></row>`,
+			"row index too big":             `<row r="2000000000"><c t="inlineStr"><is><t>a</t></is></c></row>`,
+			"row after the last row":        `<row r="1048577"><c t="inlineStr"><is><t>a</t></is></c></row>`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := Decoder().Decode(ctx, makeSheetDocument(t, sheetData), &envoy.DecoderOpts{Name: "records"})
+				require.Error(t, err)
+			})
+		}
+
+		t.Run("last column and row", func(t *testing.T) {
+			req := require.New(t)
+
+			ii, err := Decoder().Decode(ctx, makeSheetDocument(t,
+				`<row r="1"><c r="A1" t="inlineStr"><is><t>first</t></is></c><c r="XFD1" t="inlineStr"><is><t>last</t></is></c></row>`+
+					`<row r="1048576"><c r="XFD1048576"><v>42</v></c></row>`,
+			), &envoy.DecoderOpts{Name: "records"})
+			req.NoError(err)
+
+			ds := ii[0].(*resource.ResourceDataset)
+			req.Equal(uint64(maxRows-1), ds.P.Count())
+		})
+	})
+}
+
+func TestColumnNames(t *testing.T) {
+	req := require.New(t)
+
+	for i, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		req.Equal(name, columnName(i))
+		req.Equal(i, columnIndex(name+"42"))
+	}
+
+	for _, ref := range []string{"", "42", "a42", "AAAA42", "XFE42"} {
+		req.Equal(-1, columnIndex(ref))
+	}
+
+	req.Equal(maxColumns-1, columnIndex("XFD42"))
+}
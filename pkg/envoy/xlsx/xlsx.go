@@ -0,0 +1,552 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Minimal Office Open XML spreadsheet support
+//
+// Only the first worksheet of the workbook is read and a single worksheet is written;
+// formulas, merged cells and other spreadsheet features are not supported.
+
+type (
+	cellKind int
+
+	// cell is a single value that is written to the worksheet
+	cell struct {
+		kind  cellKind
+		value string
+		style int
+	}
+
+	// sheetWriter streams rows into a new single-sheet workbook
+	sheetWriter struct {
+		zw  *zip.Writer
+		w   io.Writer
+		row int
+	}
+
+	xlsxWorkbook struct {
+		WorkbookPr struct {
+			Date1904 bool `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	xlsxRelationships struct {
+		Relationship []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	xlsxRichText struct {
+		T string `xml:"t"`
+		R []struct {
+			T string `xml:"t"`
+		} `xml:"r"`
+	}
+
+	xlsxSharedStrings struct {
+		SI []xlsxRichText `xml:"si"`
+	}
+
+	xlsxStyles struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+
+	xlsxCell struct {
+		R  string        `xml:"r,attr"`
+		S  int           `xml:"s,attr"`
+		T  string        `xml:"t,attr"`
+		V  string        `xml:"v"`
+		IS *xlsxRichText `xml:"is"`
+	}
+
+	xlsxRow struct {
+		R int        `xml:"r,attr"`
+		C []xlsxCell `xml:"c"`
+	}
+
+	// workbook holds everything needed to resolve cell values of a worksheet
+	workbook struct {
+		date1904 bool
+		strings  []string
+		// style index => is date format
+		dateStyles map[int]bool
+	}
+)
+
+const (
+	cellString cellKind = iota
+	cellNumber
+	cellBool
+	cellDateTime
+)
+
+// cell styles, as defined in stylesDoc
+const (
+	styleDefault = iota
+	styleHeader
+	styleDateTime
+	styleDate
+	styleTime
+	styleWrap
+)
+
+// worksheet limits (as defined by Excel)
+const (
+	maxRows    = 1048576
+	maxColumns = 16384 // XFD
+)
+
+const (
+	nsMain = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+
+	contentTypesDoc = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	relsDoc = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookDoc = xml.Header + `<workbook xmlns="` + nsMain + `" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	workbookRelsDoc = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	stylesDoc = xml.Header + `<styleSheet xmlns="` + nsMain + `">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="6">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="21" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" applyAlignment="1"><alignment wrapText="1"/></xf>` +
+		`</cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+
+	sheetHeader = xml.Header + `<worksheet xmlns="` + nsMain + `"><sheetData>`
+	sheetFooter = `</sheetData></worksheet>`
+)
+
+var (
+	// Excel's epochs; 1900 date system includes the (non-existing) 29th of February 1900
+	epoch1900 = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	epoch1904 = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// quoted literals, escaped characters and bracketed sections (colors, conditions)
+	// are removed from the number format before checking for date & time tokens
+	numFmtLiterals = regexp.MustCompile(`"[^"]*"|\\.|\[[^\]]*\]`)
+	numFmtDateTime = regexp.MustCompile(`[ydhs]`)
+)
+
+// isBuiltinDateFormat checks if the built-in number format represents a date and/or time
+func isBuiltinDateFormat(id int) bool {
+	return (id >= 14 && id <= 22) || (id >= 27 && id <= 36) || (id >= 45 && id <= 47) || (id >= 50 && id <= 58)
+}
+
+// isDateFormatCode checks if the custom number format represents a date and/or time
+func isDateFormatCode(code string) bool {
+	code = strings.ToLower(numFmtLiterals.ReplaceAllString(code, ""))
+	return code != "general" && numFmtDateTime.MatchString(code)
+}
+
+// fromSerial converts Excel's serial date into time
+func fromSerial(serial float64, date1904 bool) time.Time {
+	var (
+		epoch = epoch1900
+		days  = math.Floor(serial)
+		secs  = math.Round((serial - days) * 86400)
+	)
+
+	if date1904 {
+		epoch = epoch1904
+	}
+
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second)
+}
+
+// toSerial converts time into Excel's serial date (1900 date system)
+//
+// Wall clock of the given time is used since Excel does not know about timezones
+func toSerial(t time.Time) string {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return strconv.FormatFloat(wall.Sub(epoch1900).Hours()/24, 'f', -1, 64)
+}
+
+// columnIndex returns zero based column index from the cell reference (eg. AB12)
+//
+// Returns -1 for references without column or with column out of the worksheet limits
+func columnIndex(ref string) int {
+	var col, n int
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+
+		if n++; n > 3 {
+			return -1
+		}
+
+		col = col*26 + int(r-'A') + 1
+	}
+
+	if col == 0 || col > maxColumns {
+		return -1
+	}
+
+	return col - 1
+}
+
+// columnName returns column name (eg. AB) for the zero based column index
+func columnName(i int) string {
+	var name string
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+
+	var sb strings.Builder
+	for _, r := range t.R {
+		sb.WriteString(r.T)
+	}
+
+	return sb.String()
+}
+
+// readRows reads all rows from the first worksheet of the workbook
+//
+// Cell values are converted to strings; booleans as true/false and
+// numbers with date format as RFC3339 timestamps (UTC)
+func readRows(zr *zip.Reader) (rows [][]string, err error) {
+	var (
+		wb = &workbook{
+			dateStyles: make(map[int]bool),
+		}
+
+		files = make(map[string]*zip.File)
+
+		xWorkbook xlsxWorkbook
+		xRels     xlsxRelationships
+		xStrings  xlsxSharedStrings
+		xStyles   xlsxStyles
+
+		sheetPath = "xl/worksheets/sheet1.xml"
+	)
+
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	if err = decodePart(files, "xl/workbook.xml", &xWorkbook); err != nil {
+		return nil, err
+	}
+
+	wb.date1904 = xWorkbook.WorkbookPr.Date1904
+
+	if err = decodePart(files, "xl/_rels/workbook.xml.rels", &xRels); err != nil {
+		return nil, err
+	}
+
+	if len(xWorkbook.Sheets) > 0 {
+		for _, rel := range xRels.Relationship {
+			if rel.ID == xWorkbook.Sheets[0].RID {
+				if strings.HasPrefix(rel.Target, "/") {
+					sheetPath = strings.TrimPrefix(rel.Target, "/")
+				} else {
+					sheetPath = path.Join("xl", rel.Target)
+				}
+			}
+		}
+	}
+
+	if err = decodePart(files, "xl/sharedStrings.xml", &xStrings); err != nil {
+		return nil, err
+	}
+
+	for _, si := range xStrings.SI {
+		wb.strings = append(wb.strings, si.String())
+	}
+
+	if err = decodePart(files, "xl/styles.xml", &xStyles); err != nil {
+		return nil, err
+	}
+
+	customFormats := make(map[int]string)
+	for _, nf := range xStyles.NumFmts {
+		customFormats[nf.ID] = nf.Code
+	}
+
+	for i, xf := range xStyles.CellXfs {
+		if code, has := customFormats[xf.NumFmtID]; has {
+			wb.dateStyles[i] = isDateFormatCode(code)
+		} else {
+			wb.dateStyles[i] = isBuiltinDateFormat(xf.NumFmtID)
+		}
+	}
+
+	sheet, has := files[sheetPath]
+	if !has {
+		return nil, fmt.Errorf("worksheet %s not found", sheetPath)
+	}
+
+	rc, err := sheet.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return wb.readSheet(rc)
+}
+
+// readSheet reads rows from the worksheet document
+func (wb *workbook) readSheet(r io.Reader) (rows [][]string, err error) {
+	var (
+		d   = xml.NewDecoder(r)
+		tkn xml.Token
+	)
+
+	for {
+		if tkn, err = d.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		se, ok := tkn.(xml.StartElement)
+		if !ok || se.Name.Local != "row" {
+			continue
+		}
+
+		xRow := xlsxRow{}
+		if err = d.DecodeElement(&xRow, &se); err != nil {
+			return nil, err
+		}
+
+		// Rows without cells or with explicit index can leave gaps
+		ix := len(rows)
+		if xRow.R > 0 {
+			ix = xRow.R - 1
+		}
+
+		if ix >= maxRows {
+			return nil, fmt.Errorf("row %d out of worksheet limits", ix+1)
+		}
+
+		for len(rows) <= ix {
+			rows = append(rows, nil)
+		}
+
+		row := make([]string, 0, len(xRow.C))
+		for _, c := range xRow.C {
+			col := len(row)
+			if c.R != "" {
+				if col = columnIndex(c.R); col < 0 {
+					return nil, fmt.Errorf("invalid cell reference %q", c.R)
+				}
+			} else if col >= maxColumns {
+				return nil, fmt.Errorf("row %d has too many cells", ix+1)
+			}
+
+			for len(row) <= col {
+				row = append(row, "")
+			}
+
+			row[col] = wb.cellValue(c)
+		}
+
+		rows[ix] = row
+	}
+
+	// Trailing empty rows are not relevant
+	for len(rows) > 0 && isEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+
+	return rows, nil
+}
+
+func (wb *workbook) cellValue(c xlsxCell) string {
+	switch c.T {
+	case "s":
+		i, err := strconv.Atoi(c.V)
+		if err != nil || i < 0 || i >= len(wb.strings) {
+			return ""
+		}
+		return wb.strings[i]
+
+	case "inlineStr":
+		if c.IS == nil {
+			return ""
+		}
+		return c.IS.String()
+
+	case "b":
+		return strconv.FormatBool(c.V == "1")
+
+	case "", "n":
+		if c.V != "" && wb.dateStyles[c.S] {
+			if serial, err := strconv.ParseFloat(c.V, 64); err == nil {
+				return fromSerial(serial, wb.date1904).Format(time.RFC3339)
+			}
+		}
+	}
+
+	return c.V
+}
+
+func isEmptyRow(row []string) bool {
+	for _, v := range row {
+		if v != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodePart decodes XML document from the archive
+//
+// Missing documents are ignored
+func decodePart(files map[string]*zip.File, name string, dst interface{}) error {
+	f, has := files[name]
+	if !has {
+		return nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err = xml.NewDecoder(rc).Decode(dst); err != nil {
+		return fmt.Errorf("could not decode %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// newSheetWriter prepares the workbook and opens the worksheet for writing
+func newSheetWriter(w io.Writer) (sw *sheetWriter, err error) {
+	sw = &sheetWriter{zw: zip.NewWriter(w)}
+
+	parts := []struct{ name, doc string }{
+		{"[Content_Types].xml", contentTypesDoc},
+		{"_rels/.rels", relsDoc},
+		{"xl/workbook.xml", workbookDoc},
+		{"xl/_rels/workbook.xml.rels", workbookRelsDoc},
+		{"xl/styles.xml", stylesDoc},
+	}
+
+	for _, p := range parts {
+		if err = sw.writePart(p.name, p.doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if sw.w, err = sw.zw.Create("xl/worksheets/sheet1.xml"); err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(sw.w, sheetHeader)
+	return sw, err
+}
+
+func (sw *sheetWriter) writePart(name, doc string) error {
+	w, err := sw.zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, doc)
+	return err
+}
+
+// WriteRow writes the next row to the worksheet
+func (sw *sheetWriter) WriteRow(cc []cell) (err error) {
+	sw.row++
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<row r="%d">`, sw.row)
+
+	for i, c := range cc {
+		if c.value == "" {
+			continue
+		}
+
+		ref := columnName(i) + strconv.Itoa(sw.row)
+		fmt.Fprintf(&sb, `<c r="%s"`, ref)
+		if c.style != styleDefault {
+			fmt.Fprintf(&sb, ` s="%d"`, c.style)
+		}
+
+		switch c.kind {
+		case cellNumber, cellDateTime:
+			fmt.Fprintf(&sb, `><v>%s</v></c>`, c.value)
+		case cellBool:
+			fmt.Fprintf(&sb, ` t="b"><v>%s</v></c>`, c.value)
+		default:
+			sb.WriteString(` t="inlineStr"><is><t xml:space="preserve">`)
+			if err = xml.EscapeText(&sb, []byte(c.value)); err != nil {
+				return err
+			}
+			sb.WriteString(`</t></is></c>`)
+		}
+	}
+
+	sb.WriteString(`</row>`)
+
+	_, err = io.WriteString(sw.w, sb.String())
+	return err
+}
+
+// Close finalizes the worksheet and the workbook
+func (sw *sheetWriter) Close() (err error) {
+	if _, err = io.WriteString(sw.w, sheetFooter); err != nil {
+		return err
+	}
+
+	return sw.zw.Close()
+}
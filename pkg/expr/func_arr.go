@@ -0,0 +1,504 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/spf13/cast"
+)
+
+func ArrayFunctions() []gval.Language {
+	return []gval.Language{
+		gval.Function("count", count),
+		gval.Function("push", push),
+		gval.Function("pop", pop),
+		gval.Function("shift", shift),
+		gval.Function("has", has),
+		gval.Function("hasAll", hasAll),
+		gval.Function("find", find),
+		gval.Function("slice", slice),
+		gval.Function("sort", sortArray),
+		gval.Function("reverse", reverse),
+		gval.Function("unique", unique),
+		gval.Function("concat", concat),
+		gval.Function("filter", filter),
+		gval.Function("map", mapArray),
+	}
+}
+
+// count returns number of items in array or map
+func count(arr interface{}) (int, error) {
+	if m, err := toMap(arr); err == nil {
+		return len(m), nil
+	}
+
+	if aa, err := toArray(arr); err != nil {
+		return 0, err
+	} else {
+		return len(aa), nil
+	}
+}
+
+// push returns new array with values appended at the end
+func push(arr interface{}, vv ...interface{}) (*Array, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, len(aa), len(aa)+len(vv))
+	copy(out, aa)
+	for _, v := range vv {
+		out = append(out, typify(v))
+	}
+
+	return &Array{value: out}, nil
+}
+
+// pop returns the last item of the array
+func pop(arr interface{}) (interface{}, error) {
+	aa, err := toArray(arr)
+	if err != nil || len(aa) == 0 {
+		return nil, err
+	}
+
+	return gvalValue(aa[len(aa)-1]), nil
+}
+
+// shift returns the first item of the array
+func shift(arr interface{}) (interface{}, error) {
+	aa, err := toArray(arr)
+	if err != nil || len(aa) == 0 {
+		return nil, err
+	}
+
+	return gvalValue(aa[0]), nil
+}
+
+// has checks if array contains any of the given values
+func has(arr interface{}, vv ...interface{}) (bool, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range vv {
+		if indexOfValue(aa, v) > -1 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// hasAll checks if array contains all of the given values
+func hasAll(arr interface{}, vv ...interface{}) (bool, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range vv {
+		if indexOfValue(aa, v) == -1 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// find returns position of the value in the array or -1 when value is not found
+func find(arr interface{}, v interface{}) (int, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return -1, err
+	}
+
+	return indexOfValue(aa, v), nil
+}
+
+// slice returns part of the array from start up to (but not including) end
+//
+// Negative positions are counted from the end of the array;
+// when end is omitted, array is sliced to the end
+func slice(arr interface{}, from interface{}, to ...interface{}) (*Array, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	start, e, err := sliceBounds(len(aa), from, to...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, e-start)
+	copy(out, aa[start:e])
+	return &Array{value: out}, nil
+}
+
+// sortArray returns sorted copy of the array
+//
+// Numbers, strings, booleans and times can be sorted;
+// pass true as a second argument for descending order
+func sortArray(arr interface{}, desc ...bool) (out *Array, err error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dir    = 1
+		sorted = make([]TypedValue, len(aa))
+	)
+
+	if len(desc) > 0 && desc[0] {
+		dir = -1
+	}
+
+	copy(sorted, aa)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		c, cErr := compareValues(UntypedValue(sorted[i]), UntypedValue(sorted[j]))
+		if cErr != nil && err == nil {
+			err = cErr
+		}
+
+		return c*dir < 0
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Array{value: sorted}, nil
+}
+
+// reverse returns array with items in the reverse order
+func reverse(arr interface{}) (*Array, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, len(aa))
+	for i := range aa {
+		out[len(aa)-1-i] = aa[i]
+	}
+
+	return &Array{value: out}, nil
+}
+
+// unique returns array without duplicated values
+func unique(arr interface{}) (*Array, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, 0, len(aa))
+	for _, v := range aa {
+		if indexOfValue(out, v) == -1 {
+			out = append(out, v)
+		}
+	}
+
+	return &Array{value: out}, nil
+}
+
+// concat joins multiple arrays into one
+func concat(arr interface{}, more ...interface{}) (*Array, error) {
+	out, err := push(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range more {
+		aa, err := toArray(m)
+		if err != nil {
+			return nil, err
+		}
+
+		out.value = append(out.value, aa...)
+	}
+
+	return out, nil
+}
+
+// filter returns items for which the expression evaluates to true
+//
+// Expression can access the current item with "item" and its position with "index"
+func filter(ctx context.Context, arr interface{}, expr string) (*Array, error) {
+	aa, eval, err := prepArrayExpr(arr, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, 0, len(aa))
+	for i, v := range aa {
+		if ok, err := eval.EvalBool(ctx, arrayExprScope(i, v)); err != nil {
+			return nil, err
+		} else if ok {
+			out = append(out, v)
+		}
+	}
+
+	return &Array{value: out}, nil
+}
+
+// mapArray returns array with the results of the expression evaluated for each item
+//
+// Expression can access the current item with "item" and its position with "index"
+func mapArray(ctx context.Context, arr interface{}, expr string) (*Array, error) {
+	aa, eval, err := prepArrayExpr(arr, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, len(aa))
+	for i, v := range aa {
+		if r, err := eval(ctx, arrayExprScope(i, v)); err != nil {
+			return nil, err
+		} else {
+			out[i] = typify(r)
+		}
+	}
+
+	return &Array{value: out}, nil
+}
+
+func prepArrayExpr(arr interface{}, expr string) ([]TypedValue, gval.Evaluable, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	eval, err := Parser().NewEvaluable(expr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+
+	return aa, eval, nil
+}
+
+func arrayExprScope(i int, v TypedValue) map[string]interface{} {
+	return map[string]interface{}{
+		"item":  gvalValue(v),
+		"index": i,
+	}
+}
+
+// toArray converts (typed) arrays and slices into slice of typed values
+func toArray(arr interface{}) ([]TypedValue, error) {
+	switch c := arr.(type) {
+	case nil:
+		return []TypedValue{}, nil
+	case *Array:
+		return c.value, nil
+	case Array:
+		return c.value, nil
+	case []TypedValue:
+		return c, nil
+	case TypedValue:
+		return toArray(c.Get())
+	}
+
+	ref := reflect.ValueOf(arr)
+	if ref.Kind() != reflect.Slice && ref.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unable to use %T as an array", arr)
+	}
+
+	out := make([]TypedValue, ref.Len())
+	for i := 0; i < ref.Len(); i++ {
+		out[i] = typify(ref.Index(i).Interface())
+	}
+
+	return out, nil
+}
+
+// typify wraps raw value with a matching expression type
+func typify(v interface{}) TypedValue {
+	switch c := v.(type) {
+	case TypedValue:
+		return c
+	case string:
+		return &String{value: c}
+	case bool:
+		return &Boolean{value: c}
+	case int, int8, int16, int32, int64:
+		return &Integer{value: cast.ToInt64(c)}
+	case uint, uint8, uint16, uint32, uint64:
+		return &UnsignedInteger{value: cast.ToUint64(c)}
+	case float32, float64:
+		return &Float{value: cast.ToFloat64(c)}
+	case time.Time:
+		return &DateTime{value: &c}
+	case *time.Time:
+		return &DateTime{value: c}
+	case time.Duration:
+		return &Duration{value: c}
+	case []TypedValue:
+		return &Array{value: c}
+	case []interface{}, []string:
+		if aa, err := toArray(c); err == nil {
+			return &Array{value: aa}
+		}
+	}
+
+	return &Any{value: v}
+}
+
+// gvalValue prepares typed value for use inside the expression
+//
+// Same rules as with Vars.Dict() apply
+func gvalValue(v TypedValue) interface{} {
+	switch c := v.(type) {
+	case gval.Selector:
+		return c
+	case Dict:
+		return c.Dict()
+	case nil:
+		return nil
+	default:
+		return c.Get()
+	}
+}
+
+func indexOfValue(aa []TypedValue, v interface{}) int {
+	for i := range aa {
+		if valuesEqual(aa[i], v) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// valuesEqual compares two (typed) values
+//
+// Numbers are compared by their value regardless of their type
+func valuesEqual(a, b interface{}) bool {
+	a, b = UntypedValue(a), UntypedValue(b)
+
+	if isNumber(a) && isNumber(b) {
+		return cast.ToFloat64(a) == cast.ToFloat64(b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func compareValues(a, b interface{}) (int, error) {
+	switch {
+	case isNumber(a) && isNumber(b):
+		fa, fb := cast.ToFloat64(a), cast.ToFloat64(b)
+		switch {
+		case fa < fb:
+			return -1, nil
+		case fa > fb:
+			return 1, nil
+		}
+
+		return 0, nil
+	}
+
+	switch ca := a.(type) {
+	case string:
+		if cb, ok := b.(string); ok {
+			switch {
+			case ca < cb:
+				return -1, nil
+			case ca > cb:
+				return 1, nil
+			}
+
+			return 0, nil
+		}
+
+	case bool:
+		if cb, ok := b.(bool); ok {
+			switch {
+			case ca == cb:
+				return 0, nil
+			case cb:
+				return -1, nil
+			}
+
+			return 1, nil
+		}
+
+	case *time.Time:
+		if ca != nil {
+			return compareValues(*ca, b)
+		}
+
+	case time.Time:
+		switch cb := b.(type) {
+		case *time.Time:
+			if cb != nil {
+				return compareValues(ca, *cb)
+			}
+		case time.Time:
+			switch {
+			case ca.Before(cb):
+				return -1, nil
+			case ca.After(cb):
+				return 1, nil
+			}
+
+			return 0, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unable to compare %T and %T", a, b)
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+
+	return false
+}
+
+// sliceBounds resolves start and (optional) end position for slicing
+//
+// Negative positions are counted from the end and
+// both positions are clamped to the length
+func sliceBounds(l int, from interface{}, to ...interface{}) (start, end int, err error) {
+	end = l
+	if start, err = cast.ToIntE(from); err != nil {
+		return
+	}
+
+	if len(to) > 0 {
+		if end, err = cast.ToIntE(to[0]); err != nil {
+			return
+		}
+	}
+
+	start, end = clampIndex(start, l), clampIndex(end, l)
+	if start > end {
+		start = end
+	}
+
+	return
+}
+
+func clampIndex(i, l int) int {
+	if i < 0 {
+		i += l
+	}
+
+	switch {
+	case i < 0:
+		return 0
+	case i > l:
+		return l
+	}
+
+	return i
+}
@@ -0,0 +1,180 @@
+package expr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	exampleArrayParams = map[string]interface{}{
+		"arr": Must(NewArray([]TypedValue{
+			Must(NewInteger(3)),
+			Must(NewInteger(1)),
+			Must(NewInteger(2)),
+		})),
+		"people": []interface{}{
+			map[string]interface{}{"name": "Ann", "age": 42},
+			map[string]interface{}{"name": "Bob", "age": 17},
+		},
+	}
+)
+
+func Example_count() {
+	eval(`count(arr)`, exampleArrayParams)
+
+	// output:
+	// 3
+}
+
+func Example_push() {
+	eval(`toJSON(push(arr, 4, 5))`, exampleArrayParams)
+
+	// output:
+	// [3,1,2,4,5]
+}
+
+func Example_pop() {
+	eval(`pop(arr)`, exampleArrayParams)
+
+	// output:
+	// 2
+}
+
+func Example_shift() {
+	eval(`shift(arr)`, exampleArrayParams)
+
+	// output:
+	// 3
+}
+
+func Example_has() {
+	eval(`has(arr, 5, 1)`, exampleArrayParams)
+
+	// output:
+	// true
+}
+
+func Example_hasAll() {
+	eval(`hasAll(arr, 5, 1)`, exampleArrayParams)
+
+	// output:
+	// false
+}
+
+func Example_find() {
+	eval(`find(arr, 1)`, exampleArrayParams)
+
+	// output:
+	// 1
+}
+
+func Example_slice() {
+	eval(`toJSON(slice(arr, 1))`, exampleArrayParams)
+
+	// output:
+	// [1,2]
+}
+
+func Example_sort() {
+	eval(`toJSON(sort(arr))`, exampleArrayParams)
+
+	// output:
+	// [1,2,3]
+}
+
+func Example_sortDesc() {
+	eval(`toJSON(sort(["b", "c", "a"], true))`, nil)
+
+	// output:
+	// ["c","b","a"]
+}
+
+func Example_reverse() {
+	eval(`toJSON(reverse(arr))`, exampleArrayParams)
+
+	// output:
+	// [2,1,3]
+}
+
+func Example_unique() {
+	eval(`toJSON(unique([1, 2, 1, "a", "a"]))`, nil)
+
+	// output:
+	// [1,2,"a"]
+}
+
+func Example_concat() {
+	eval(`toJSON(concat(arr, [4], [5, 6]))`, exampleArrayParams)
+
+	// output:
+	// [3,1,2,4,5,6]
+}
+
+func Example_filter() {
+	eval(`toJSON(filter(people, "item.age >= 18"))`, exampleArrayParams)
+
+	// output:
+	// [{"age":42,"name":"Ann"}]
+}
+
+func Example_map() {
+	eval(`join(map(people, "format(\"%s (%d)\", item.name, index)"), ", ")`, exampleArrayParams)
+
+	// output:
+	// Ann (0), Bob (1)
+}
+
+func TestArrayFunctions_typed(t *testing.T) {
+	var (
+		req = require.New(t)
+		arr = Must(NewArray([]TypedValue{
+			Must(NewString("b")),
+			Must(NewString("a")),
+		}))
+	)
+
+	// typed values are kept in the resulting array
+	sorted, err := sortArray(arr)
+	req.NoError(err)
+	req.Equal("Array", sorted.Type())
+	req.IsType(&String{}, sorted.GetValue()[0])
+	req.Equal("a", sorted.GetValue()[0].Get())
+
+	// raw values are converted to typed values
+	pushed, err := push(arr, 42, true)
+	req.NoError(err)
+	req.IsType(&Integer{}, pushed.GetValue()[2])
+	req.IsType(&Boolean{}, pushed.GetValue()[3])
+	req.Len(arr.(*Array).GetValue(), 2)
+
+	_, err = sortArray([]interface{}{1, "a"})
+	req.EqualError(err, "unable to compare string and int64")
+
+	_, err = count("foo")
+	req.EqualError(err, "unable to use string as an array")
+
+	_, err = filter(context.Background(), arr, "item +")
+	req.Error(err)
+}
+
+func TestArrayFunctions_scope(t *testing.T) {
+	var (
+		req  = require.New(t)
+		vars = RVars{
+			"arr": Must(NewArray([]TypedValue{
+				Must(NewInteger(1)),
+				Must(NewInteger(2)),
+				Must(NewInteger(3)),
+			})),
+		}.Vars()
+	)
+
+	eval, err := NewParser().Parse(`count(filter(arr, "item > 1")) == 2 && pop(map(arr, "item * 2")) == 6`)
+	req.NoError(err)
+
+	ok, err := eval.Test(context.Background(), vars)
+	req.NoError(err)
+	req.True(ok)
+}
@@ -0,0 +1,41 @@
+package expr
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+
+	"github.com/PaesslerAG/gval"
+)
+
+func CryptoFunctions() []gval.Language {
+	return []gval.Language{
+		gval.Function("sha1", hashHex(sha1.New)),
+		gval.Function("sha256", hashHex(sha256.New)),
+		gval.Function("sha512", hashHex(sha512.New)),
+		gval.Function("hmacSha1", hmacHex(sha1.New)),
+		gval.Function("hmacSha256", hmacHex(sha256.New)),
+		gval.Function("hmacSha512", hmacHex(sha512.New)),
+	}
+}
+
+// hashHex returns function that calculates hex encoded checksum of the string
+func hashHex(fn func() hash.Hash) func(string) string {
+	return func(s string) string {
+		h := fn()
+		_, _ = h.Write([]byte(s))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
+
+// hmacHex returns function that calculates hex encoded HMAC of the string
+func hmacHex(fn func() hash.Hash) func(string, string) string {
+	return func(s, key string) string {
+		h := hmac.New(fn, []byte(key))
+		_, _ = h.Write([]byte(s))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
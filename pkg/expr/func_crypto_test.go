@@ -0,0 +1,22 @@
+package expr
+
+func Example_sha1() {
+	eval(`sha1("corteza")`, nil)
+
+	// output:
+	// e2e0dae6216bf65d2546f0eec1266fc4b2a91cf4
+}
+
+func Example_sha256() {
+	eval(`sha256("corteza")`, nil)
+
+	// output:
+	// c2e36e9a621e1ddfcf958118d2d588d76a21d112fcc68d16fbc8f5f145b50c37
+}
+
+func Example_hmacSha256() {
+	eval(`hmacSha256("corteza", "secret")`, nil)
+
+	// output:
+	// 80bc096f4795cecd69238281626e49ec9b6ba6b785fbca0a1a38357c8d48f63b
+}
@@ -0,0 +1,154 @@
+package expr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/PaesslerAG/gval"
+)
+
+func EncodingFunctions() []gval.Language {
+	return []gval.Language{
+		gval.Function("toJSON", toJSON),
+		gval.Function("parseJSON", parseJSON),
+		gval.Function("base64Encode", base64Encode),
+		gval.Function("base64Decode", base64Decode),
+		gval.Function("base64URLEncode", base64URLEncode),
+		gval.Function("base64URLDecode", base64URLDecode),
+		gval.Function("hexEncode", hexEncode),
+		gval.Function("hexDecode", hexDecode),
+		gval.Function("urlEncode", url.QueryEscape),
+		gval.Function("urlDecode", url.QueryUnescape),
+	}
+}
+
+// toJSON serializes value into JSON string
+func toJSON(v interface{}) (string, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(deepUntyped(v)); err != nil {
+		return "", err
+	}
+
+	return string(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+// parseJSON parses JSON string
+//
+// Objects are converted into maps and arrays into Array
+func parseJSON(s string) (interface{}, error) {
+	var (
+		aux interface{}
+		dec = json.NewDecoder(bytes.NewBufferString(s))
+	)
+
+	dec.UseNumber()
+	if err := dec.Decode(&aux); err != nil {
+		return nil, err
+	}
+
+	return fromJSON(aux), nil
+}
+
+func base64Encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func base64Decode(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	return string(b), err
+}
+
+func base64URLEncode(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func base64URLDecode(s string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	return string(b), err
+}
+
+func hexEncode(s string) string {
+	return hex.EncodeToString([]byte(s))
+}
+
+func hexDecode(s string) (string, error) {
+	b, err := hex.DecodeString(s)
+	return string(b), err
+}
+
+// deepUntyped removes expression types from the value and all nested values
+func deepUntyped(v interface{}) interface{} {
+	switch c := v.(type) {
+	case *Array:
+		out := make([]interface{}, len(c.value))
+		for i := range c.value {
+			out[i] = deepUntyped(c.value[i])
+		}
+
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(c))
+		for i := range c {
+			out[i] = deepUntyped(c[i])
+		}
+
+		return out
+	case []TypedValue:
+		return deepUntyped(&Array{value: c})
+	case Dict:
+		return deepUntyped(c.Dict())
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(c))
+		for k := range c {
+			out[k] = deepUntyped(c[k])
+		}
+
+		return out
+	case *DateTime:
+		if c.value == nil {
+			return nil
+		}
+
+		return c.value.Format(time.RFC3339)
+	case TypedValue:
+		return deepUntyped(c.Get())
+	}
+
+	return v
+}
+
+// fromJSON converts decoded JSON values into values
+// that can be used with the rest of the functions
+func fromJSON(v interface{}) interface{} {
+	switch c := v.(type) {
+	case []interface{}:
+		out := make([]TypedValue, len(c))
+		for i := range c {
+			out[i] = typify(fromJSON(c[i]))
+		}
+
+		return &Array{value: out}
+	case map[string]interface{}:
+		for k := range c {
+			c[k] = fromJSON(c[k])
+		}
+
+		return c
+	case json.Number:
+		if i, err := c.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := c.Float64()
+		return f
+	}
+
+	return v
+}
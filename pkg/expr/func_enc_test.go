@@ -0,0 +1,57 @@
+package expr
+
+func Example_toJSON() {
+	eval(`toJSON({"list": [1, "two"], "ok": true})`, nil)
+
+	// output:
+	// {"list":[1,"two"],"ok":true}
+}
+
+func Example_parseJSON() {
+	eval(`toJSON(parseJSON("{\"list\": [1, 2.5]}"))`, nil)
+
+	// output:
+	// {"list":[1,2.5]}
+}
+
+func Example_parseJSONArray() {
+	eval(`count(parseJSON("[1, 2, 3]"))`, nil)
+
+	// output:
+	// 3
+}
+
+func Example_base64Encode() {
+	eval(`base64Encode("corteza")`, nil)
+
+	// output:
+	// Y29ydGV6YQ==
+}
+
+func Example_base64Decode() {
+	eval(`base64Decode("Y29ydGV6YQ==")`, nil)
+
+	// output:
+	// corteza
+}
+
+func Example_base64URLEncode() {
+	eval(`base64URLEncode("??>")`, nil)
+
+	// output:
+	// Pz8-
+}
+
+func Example_hexEncode() {
+	eval(`hexEncode("corteza")`, nil)
+
+	// output:
+	// 636f7274657a61
+}
+
+func Example_urlEncode() {
+	eval(`urlEncode("a b&c")`, nil)
+
+	// output:
+	// a+b%26c
+}
@@ -0,0 +1,186 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/spf13/cast"
+)
+
+func KvFunctions() []gval.Language {
+	return []gval.Language{
+		gval.Function("keys", keys),
+		gval.Function("values", values),
+		gval.Function("hasKey", hasKey),
+		gval.Function("set", set),
+		gval.Function("omit", omit),
+		gval.Function("pick", pick),
+		gval.Function("merge", merge),
+	}
+}
+
+// keys returns sorted list of map keys
+func keys(m interface{}) (*Array, error) {
+	mm, err := toMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, 0, len(mm))
+	for _, k := range sortedKeys(mm) {
+		out = append(out, &String{value: k})
+	}
+
+	return &Array{value: out}, nil
+}
+
+// values returns list of map values, sorted by their keys
+func values(m interface{}) (*Array, error) {
+	mm, err := toMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedValue, 0, len(mm))
+	for _, k := range sortedKeys(mm) {
+		out = append(out, typify(mm[k]))
+	}
+
+	return &Array{value: out}, nil
+}
+
+// hasKey checks if map contains any of the given keys
+func hasKey(m interface{}, kk ...string) (bool, error) {
+	mm, err := toMap(m)
+	if err != nil {
+		return false, err
+	}
+
+	for _, k := range kk {
+		if _, has := mm[k]; has {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// set returns copy of the map with the value set under the given key
+func set(m interface{}, k string, v interface{}) (interface{}, error) {
+	mm, err := toMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(mm)+1)
+	for key, val := range mm {
+		out[key] = val
+	}
+
+	out[k] = UntypedValue(v)
+	return sameMapKind(m, out)
+}
+
+// omit returns copy of the map without the given keys
+func omit(m interface{}, kk ...string) (interface{}, error) {
+	mm, err := toMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(mm))
+	for key, val := range mm {
+		out[key] = val
+	}
+
+	for _, k := range kk {
+		delete(out, k)
+	}
+
+	return sameMapKind(m, out)
+}
+
+// pick returns copy of the map with only the given keys
+func pick(m interface{}, kk ...string) (interface{}, error) {
+	mm, err := toMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(kk))
+	for _, k := range kk {
+		if val, has := mm[k]; has {
+			out[k] = val
+		}
+	}
+
+	return sameMapKind(m, out)
+}
+
+// merge combines maps into a new one; values from the later maps take precedence
+func merge(m interface{}, more ...interface{}) (interface{}, error) {
+	out := make(map[string]interface{})
+	for _, src := range append([]interface{}{m}, more...) {
+		mm, err := toMap(src)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, val := range mm {
+			out[key] = val
+		}
+	}
+
+	return sameMapKind(m, out)
+}
+
+// toMap converts (typed) maps into map of untyped values
+func toMap(m interface{}) (map[string]interface{}, error) {
+	switch c := m.(type) {
+	case nil:
+		return map[string]interface{}{}, nil
+	case Dict:
+		return c.Dict(), nil
+	case map[string]interface{}:
+		return c, nil
+	case TypedValue:
+		return toMap(c.Get())
+	}
+
+	ref := reflect.ValueOf(m)
+	if ref.Kind() != reflect.Map || ref.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("unable to use %T as a map", m)
+	}
+
+	out := make(map[string]interface{}, ref.Len())
+	for _, k := range ref.MapKeys() {
+		out[k.String()] = UntypedValue(ref.MapIndex(k).Interface())
+	}
+
+	return out, nil
+}
+
+// sameMapKind makes sure that map functions preserve
+// the kind of the source map (KV, KVV or generic map)
+func sameMapKind(src interface{}, out map[string]interface{}) (interface{}, error) {
+	switch UntypedValue(src).(type) {
+	case map[string]string:
+		return cast.ToStringMapStringE(out)
+	case map[string][]string:
+		return cast.ToStringMapStringSliceE(out)
+	}
+
+	return out, nil
+}
+
+func sortedKeys(mm map[string]interface{}) []string {
+	kk := make([]string, 0, len(mm))
+	for k := range mm {
+		kk = append(kk, k)
+	}
+
+	sort.Strings(kk)
+	return kk
+}
@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	exampleKvParams = map[string]interface{}{
+		"kv":  map[string]string{"b": "2", "a": "1"},
+		"obj": map[string]interface{}{"name": "Ann", "age": 42},
+	}
+)
+
+func Example_keys() {
+	eval(`join(keys(kv), ",")`, exampleKvParams)
+
+	// output:
+	// a,b
+}
+
+func Example_values() {
+	eval(`join(values(kv), ",")`, exampleKvParams)
+
+	// output:
+	// 1,2
+}
+
+func Example_hasKey() {
+	eval(`hasKey(obj, "foo", "name")`, exampleKvParams)
+
+	// output:
+	// true
+}
+
+func Example_set() {
+	eval(`toJSON(set(obj, "active", true))`, exampleKvParams)
+
+	// output:
+	// {"active":true,"age":42,"name":"Ann"}
+}
+
+func Example_omit() {
+	eval(`toJSON(omit(obj, "age"))`, exampleKvParams)
+
+	// output:
+	// {"name":"Ann"}
+}
+
+func Example_pick() {
+	eval(`toJSON(pick(obj, "age", "foo"))`, exampleKvParams)
+
+	// output:
+	// {"age":42}
+}
+
+func Example_merge() {
+	eval(`toJSON(merge(kv, {"b": "3", "c": "4"}))`, exampleKvParams)
+
+	// output:
+	// {"a":"1","b":"3","c":"4"}
+}
+
+func TestKvFunctions_typed(t *testing.T) {
+	var (
+		req = require.New(t)
+		kv  = Must(NewKV(map[string]string{"a": "1"}))
+	)
+
+	// KV stays KV
+	out, err := set(kv, "b", 2)
+	req.NoError(err)
+	req.Equal(map[string]string{"a": "1", "b": "2"}, out)
+
+	// original value is not modified
+	req.Equal(map[string]string{"a": "1"}, kv.Get())
+
+	// Vars are converted to maps
+	out, err = merge(RVars{"a": Must(NewInteger(1))}.Vars(), map[string]interface{}{"b": 2})
+	req.NoError(err)
+	req.Equal(map[string]interface{}{"a": int64(1), "b": 2}, out)
+
+	_, err = keys([]string{"a"})
+	req.EqualError(err, "unable to use []string as a map")
+}
@@ -0,0 +1,186 @@
+package expr
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/spf13/cast"
+)
+
+type (
+	// regexCacheStore holds compiled regular expressions,
+	// least recently used are evicted when the cache is full
+	regexCacheStore struct {
+		mux   sync.Mutex
+		size  int
+		order *list.List
+		items map[string]*list.Element
+	}
+
+	regexCacheItem struct {
+		expr string
+		re   *regexp.Regexp
+	}
+)
+
+const (
+	regexCacheSize = 1000
+)
+
+var (
+	// compiled regular expressions are cached
+	// to avoid recompilation on every evaluation
+	regexCache = newRegexCache(regexCacheSize)
+)
+
+func RegexFunctions() []gval.Language {
+	return []gval.Language{
+		gval.Function("regexMatch", regexMatch),
+		gval.Function("regexFind", regexFind),
+		gval.Function("regexFindAll", regexFindAll),
+		gval.Function("regexSubmatch", regexSubmatch),
+		gval.Function("regexReplace", regexReplace),
+		gval.Function("regexSplit", regexSplit),
+	}
+}
+
+// regexMatch checks if string matches the regular expression
+func regexMatch(s, expr string) (bool, error) {
+	re, err := compileRegex(expr)
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(s), nil
+}
+
+// regexFind returns the first match or an empty string
+func regexFind(s, expr string) (string, error) {
+	re, err := compileRegex(expr)
+	if err != nil {
+		return "", err
+	}
+
+	return re.FindString(s), nil
+}
+
+// regexFindAll returns all matches
+//
+// Optional third argument limits number of returned matches
+func regexFindAll(s, expr string, n ...interface{}) (*Array, error) {
+	re, err := compileRegex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := -1
+	if len(n) > 0 {
+		if limit, err = cast.ToIntE(n[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return stringArray(re.FindAllString(s, limit)), nil
+}
+
+// regexSubmatch returns the first match and its subexpression (group) matches
+func regexSubmatch(s, expr string) (*Array, error) {
+	re, err := compileRegex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return stringArray(re.FindStringSubmatch(s)), nil
+}
+
+// regexReplace replaces all matches with the replacement string
+//
+// Replacement can reference subexpressions ($1, ${name})
+func regexReplace(s, expr, repl string) (string, error) {
+	re, err := compileRegex(expr)
+	if err != nil {
+		return "", err
+	}
+
+	return re.ReplaceAllString(s, repl), nil
+}
+
+// regexSplit splits string around the matches
+func regexSplit(s, expr string) (*Array, error) {
+	re, err := compileRegex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return stringArray(re.Split(s, -1)), nil
+}
+
+func compileRegex(expr string) (*regexp.Regexp, error) {
+	if re := regexCache.get(expr); re != nil {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", expr, err)
+	}
+
+	regexCache.add(expr, re)
+	return re, nil
+}
+
+func newRegexCache(size int) *regexCacheStore {
+	return &regexCacheStore{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *regexCacheStore) get(expr string) *regexp.Regexp {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if e, has := c.items[expr]; has {
+		c.order.MoveToFront(e)
+		return e.Value.(*regexCacheItem).re
+	}
+
+	return nil
+}
+
+func (c *regexCacheStore) add(expr string, re *regexp.Regexp) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if e, has := c.items[expr]; has {
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.items[expr] = c.order.PushFront(&regexCacheItem{expr: expr, re: re})
+
+	for c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*regexCacheItem).expr)
+	}
+}
+
+func (c *regexCacheStore) len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.order.Len()
+}
+
+func stringArray(ss []string) *Array {
+	out := make([]TypedValue, len(ss))
+	for i := range ss {
+		out[i] = &String{value: ss[i]}
+	}
+
+	return &Array{value: out}
+}
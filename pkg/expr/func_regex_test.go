@@ -0,0 +1,75 @@
+package expr
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Example_regexMatch() {
+	eval(`regexMatch("foo@example.tld", "^[^@]+@[^@]+$")`, nil)
+
+	// output:
+	// true
+}
+
+func Example_regexFind() {
+	eval(`regexFind("order #1234 shipped", "[0-9]+")`, nil)
+
+	// output:
+	// 1234
+}
+
+func Example_regexFindAll() {
+	eval(`join(regexFindAll("1, 22, 333", "[0-9]+"), "|")`, nil)
+
+	// output:
+	// 1|22|333
+}
+
+func Example_regexSubmatch() {
+	eval(`pop(regexSubmatch("key=value", "^(\\w+)=(\\w+)$"))`, nil)
+
+	// output:
+	// value
+}
+
+func Example_regexReplace() {
+	eval(`regexReplace("2021-03-15", "(\\d+)-(\\d+)-(\\d+)", "$3.$2.$1")`, nil)
+
+	// output:
+	// 15.03.2021
+}
+
+func Example_regexSplit() {
+	eval(`join(regexSplit("a1b22c", "[0-9]+"), ",")`, nil)
+
+	// output:
+	// a,b,c
+}
+
+func Example_regexInvalid() {
+	eval(`regexMatch("foo", "(")`, nil)
+
+	// output:
+	// error: can not evaluate regexMatch("foo", "("): invalid regular expression "(": error parsing regexp: missing closing ): `(`
+}
+
+func TestRegexCache(t *testing.T) {
+	var (
+		req = require.New(t)
+		c   = newRegexCache(2)
+	)
+
+	c.add("a", regexp.MustCompile("a"))
+	c.add("b", regexp.MustCompile("b"))
+	req.NotNil(c.get("a"))
+
+	// least recently used ("b") is evicted
+	c.add("c", regexp.MustCompile("c"))
+	req.Equal(2, c.len())
+	req.Nil(c.get("b"))
+	req.NotNil(c.get("a"))
+	req.NotNil(c.get("c"))
+}
@@ -5,6 +5,13 @@ import (
 	"strings"
 
 	"github.com/PaesslerAG/gval"
+	"github.com/spf13/cast"
+)
+
+const (
+	// limits for repeat()
+	maxRepeatCount  = 1 << 16
+	maxRepeatLength = 1 << 20
 )
 
 func StringFunctions() []gval.Language {
@@ -18,6 +25,15 @@ func StringFunctions() []gval.Language {
 		gval.Function("shortest", shortest),
 		gval.Function("longest", longest),
 		gval.Function("format", fmt.Sprintf),
+		gval.Function("split", split),
+		gval.Function("join", join),
+		gval.Function("contains", strings.Contains),
+		gval.Function("hasPrefix", strings.HasPrefix),
+		gval.Function("hasSuffix", strings.HasSuffix),
+		gval.Function("replace", strings.ReplaceAll),
+		gval.Function("indexOf", strings.Index),
+		gval.Function("substr", substr),
+		gval.Function("repeat", repeat),
 	}
 }
 
@@ -44,3 +60,68 @@ func longest(f string, aa ...string) string {
 func length(s string) int {
 	return len(s)
 }
+
+// split splits string into array of strings
+//
+// Optional third argument limits number of returned items
+func split(s, sep string, n ...interface{}) (*Array, error) {
+	if len(n) > 0 {
+		limit, err := cast.ToIntE(n[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return stringArray(strings.SplitN(s, sep, limit)), nil
+	}
+
+	return stringArray(strings.Split(s, sep)), nil
+}
+
+// join concatenates array items into a single string
+func join(arr interface{}, sep string) (string, error) {
+	aa, err := toArray(arr)
+	if err != nil {
+		return "", err
+	}
+
+	ss := make([]string, len(aa))
+	for i := range aa {
+		if ss[i], err = cast.ToStringE(UntypedValue(aa[i])); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(ss, sep), nil
+}
+
+// substr returns part of the string from start up to (but not including) end
+//
+// Positions are counted in characters (not bytes); negative positions
+// are counted from the end of the string and when end is omitted,
+// string is cut to the end
+func substr(s string, from interface{}, to ...interface{}) (string, error) {
+	rr := []rune(s)
+	start, end, err := sliceBounds(len(rr), from, to...)
+	if err != nil {
+		return "", err
+	}
+
+	return string(rr[start:end]), nil
+}
+
+// repeat repeats string count times
+//
+// Count and length of the result are limited to prevent
+// expressions from exhausting memory
+func repeat(s string, n interface{}) (string, error) {
+	count, err := cast.ToIntE(n)
+	if err != nil || count < 0 || count > maxRepeatCount {
+		return "", fmt.Errorf("invalid repeat count: %v", n)
+	}
+
+	if len(s) > 0 && count > maxRepeatLength/len(s) {
+		return "", fmt.Errorf("repeated string too long (max %d bytes)", maxRepeatLength)
+	}
+
+	return strings.Repeat(s, count), nil
+}
@@ -42,3 +42,87 @@ func Example_longest() {
 	// output:
 	// foobar
 }
+
+func Example_split() {
+	eval(`count(split("a,b,c", ","))`, nil)
+
+	// output:
+	// 3
+}
+
+func Example_splitN() {
+	eval(`pop(split("a,b,c", ",", 2))`, nil)
+
+	// output:
+	// b,c
+}
+
+func Example_join() {
+	eval(`join(["a", 1, true], "-")`, nil)
+
+	// output:
+	// a-1-true
+}
+
+func Example_contains() {
+	eval(`contains("foobar", "oba")`, nil)
+
+	// output:
+	// true
+}
+
+func Example_hasPrefix() {
+	eval(`hasPrefix("foobar", "foo")`, nil)
+
+	// output:
+	// true
+}
+
+func Example_hasSuffix() {
+	eval(`hasSuffix("foobar", "foo")`, nil)
+
+	// output:
+	// false
+}
+
+func Example_replace() {
+	eval(`replace("foo", "o", "0")`, nil)
+
+	// output:
+	// f00
+}
+
+func Example_indexOf() {
+	eval(`indexOf("foobar", "bar")`, nil)
+
+	// output:
+	// 3
+}
+
+func Example_substr() {
+	eval(`substr("čćžšđ", 1, -1)`, nil)
+
+	// output:
+	// ćžš
+}
+
+func Example_repeat() {
+	eval(`repeat("ab", 3)`, nil)
+
+	// output:
+	// ababab
+}
+
+func Example_repeatTooMany() {
+	eval(`repeat("ab", 100000)`, nil)
+
+	// output:
+	// error: can not evaluate repeat("ab", 100000): invalid repeat count: 100000
+}
+
+func Example_repeatTooLong() {
+	eval(`repeat("abcdefghijklmnopqrstuvwxyz", 50000)`, nil)
+
+	// output:
+	// error: can not evaluate repeat("abcdefghijklmnopqrstuvwxyz", 50000): repeated string too long (max 1048576 bytes)
+}
@@ -84,6 +84,11 @@ func AllFunctions() []gval.Language {
 	ff = append(ff, StringFunctions()...)
 	ff = append(ff, NumericFunctions()...)
 	ff = append(ff, TimeFunctions()...)
+	ff = append(ff, ArrayFunctions()...)
+	ff = append(ff, KvFunctions()...)
+	ff = append(ff, RegexFunctions()...)
+	ff = append(ff, EncodingFunctions()...)
+	ff = append(ff, CryptoFunctions()...)
 
 	return ff
 }
@@ -0,0 +1,279 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+type (
+	// Geometry is a GeoJSON geometry object
+	//
+	// Bounding box is always calculated and included;
+	// it is used when filtering records by distance or area.
+	Geometry struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+		BBox        [4]float64  `json:"bbox"`
+	}
+
+	position []float64
+)
+
+const (
+	Point           = "Point"
+	MultiPoint      = "MultiPoint"
+	LineString      = "LineString"
+	MultiLineString = "MultiLineString"
+	Polygon         = "Polygon"
+	MultiPolygon    = "MultiPolygon"
+
+	// Mean earth radius in kilometers
+	earthRadius = 6371.0088
+)
+
+var (
+	// "lat, lng" pair
+	latLngPair = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)\s*[,;]\s*(-?\d+(?:\.\d+)?)\s*$`)
+)
+
+// Parse parses geometry from the string
+//
+// Supported formats:
+//  - "lat, lng" pair (point)
+//  - GeoJSON geometry object (Point, MultiPoint, LineString, MultiLineString, Polygon or MultiPolygon)
+//  - GeoJSON feature object with one of the above as geometry
+func Parse(s string) (*Geometry, error) {
+	if m := latLngPair.FindStringSubmatch(s); m != nil {
+		lat, _ := strconv.ParseFloat(m[1], 64)
+		lng, _ := strconv.ParseFloat(m[2], 64)
+		return NewPoint(lat, lng)
+	}
+
+	var (
+		aux struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+			Geometry    json.RawMessage `json:"geometry"`
+		}
+	)
+
+	if err := json.Unmarshal([]byte(s), &aux); err != nil {
+		return nil, fmt.Errorf("invalid geometry: %w", err)
+	}
+
+	if aux.Type == "Feature" {
+		if len(aux.Geometry) == 0 {
+			return nil, fmt.Errorf("invalid geometry: feature without geometry")
+		}
+
+		return Parse(string(aux.Geometry))
+	}
+
+	var (
+		g = &Geometry{Type: aux.Type}
+
+		// nesting depth of positions for each of the types
+		depth = map[string]int{
+			Point:           0,
+			MultiPoint:      1,
+			LineString:      1,
+			MultiLineString: 2,
+			Polygon:         2,
+			MultiPolygon:    3,
+		}
+	)
+
+	d, has := depth[aux.Type]
+	if !has {
+		return nil, fmt.Errorf("invalid geometry: unsupported type %q", aux.Type)
+	}
+
+	cc, err := decodeCoordinates(aux.Coordinates, d)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateCoordinates(g.Type, cc); err != nil {
+		return nil, err
+	}
+
+	g.Coordinates = cc
+	g.BBox = bbox(cc)
+	return g, nil
+}
+
+// NewPoint creates point geometry from latitude and longitude
+func NewPoint(lat, lng float64) (*Geometry, error) {
+	p := position{lng, lat}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Geometry{Type: Point, Coordinates: p, BBox: [4]float64{lng, lat, lng, lat}}, nil
+}
+
+// Center returns latitude and longitude of the bounding box center
+//
+// For points this is the point itself
+func (g Geometry) Center() (lat, lng float64) {
+	return (g.BBox[1] + g.BBox[3]) / 2, (g.BBox[0] + g.BBox[2]) / 2
+}
+
+// String returns GeoJSON representation of the geometry
+func (g Geometry) String() string {
+	buf, _ := json.Marshal(g)
+	return string(buf)
+}
+
+// Distance calculates great-circle distance (in kilometers) between two points
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	var (
+		rad = func(d float64) float64 { return d * math.Pi / 180 }
+
+		dLat = rad(lat2 - lat1)
+		dLng = rad(lng2 - lng1)
+
+		a = math.Pow(math.Sin(dLat/2), 2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Pow(math.Sin(dLng/2), 2)
+	)
+
+	return earthRadius * 2 * math.Asin(math.Sqrt(a))
+}
+
+// decodeCoordinates decodes positions, nested to the given depth
+func decodeCoordinates(raw json.RawMessage, depth int) (interface{}, error) {
+	if depth == 0 {
+		var p position
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, fmt.Errorf("invalid geometry position: %w", err)
+		}
+
+		return p, p.validate()
+	}
+
+	var nn []json.RawMessage
+	if err := json.Unmarshal(raw, &nn); err != nil {
+		return nil, fmt.Errorf("invalid geometry coordinates: %w", err)
+	}
+
+	if len(nn) == 0 {
+		return nil, fmt.Errorf("invalid geometry: empty coordinates")
+	}
+
+	out := make([]interface{}, len(nn))
+	for i := range nn {
+		c, err := decodeCoordinates(nn[i], depth-1)
+		if err != nil {
+			return nil, err
+		}
+
+		out[i] = c
+	}
+
+	return out, nil
+}
+
+// validateCoordinates checks line strings and polygon rings
+func validateCoordinates(t string, cc interface{}) error {
+	var (
+		lineString = func(c interface{}) error {
+			if len(c.([]interface{})) < 2 {
+				return fmt.Errorf("invalid geometry: line string with less than 2 positions")
+			}
+
+			return nil
+		}
+
+		ring = func(c interface{}) error {
+			pp := c.([]interface{})
+			if len(pp) < 4 {
+				return fmt.Errorf("invalid geometry: polygon ring with less than 4 positions")
+			}
+
+			if !pp[0].(position).equal(pp[len(pp)-1].(position)) {
+				return fmt.Errorf("invalid geometry: polygon ring is not closed")
+			}
+
+			return nil
+		}
+
+		polygon = func(c interface{}) error {
+			for _, r := range c.([]interface{}) {
+				if err := ring(r); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		each = func(c interface{}, fn func(interface{}) error) error {
+			for _, i := range c.([]interface{}) {
+				if err := fn(i); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	)
+
+	switch t {
+	case LineString:
+		return lineString(cc)
+	case MultiLineString:
+		return each(cc, lineString)
+	case Polygon:
+		return polygon(cc)
+	case MultiPolygon:
+		return each(cc, polygon)
+	}
+
+	return nil
+}
+
+// bbox calculates bounding box (minLng, minLat, maxLng, maxLat) of all positions
+func bbox(cc interface{}) (b [4]float64) {
+	b = [4]float64{180, 90, -180, -90}
+
+	var walk func(c interface{})
+	walk = func(c interface{}) {
+		switch c := c.(type) {
+		case position:
+			b[0] = math.Min(b[0], c[0])
+			b[1] = math.Min(b[1], c[1])
+			b[2] = math.Max(b[2], c[0])
+			b[3] = math.Max(b[3], c[1])
+		case []interface{}:
+			for _, i := range c {
+				walk(i)
+			}
+		}
+	}
+
+	walk(cc)
+	return
+}
+
+func (p position) validate() error {
+	// 3rd element (altitude) is allowed but ignored
+	if len(p) < 2 || len(p) > 3 {
+		return fmt.Errorf("invalid geometry position: expecting longitude and latitude")
+	}
+
+	if p[0] < -180 || p[0] > 180 {
+		return fmt.Errorf("invalid geometry position: longitude %v out of range", p[0])
+	}
+
+	if p[1] < -90 || p[1] > 90 {
+		return fmt.Errorf("invalid geometry position: latitude %v out of range", p[1])
+	}
+
+	return nil
+}
+
+func (p position) equal(o position) bool {
+	return len(p) == len(o) && p[0] == o[0] && p[1] == o[1]
+}
@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tcc := []struct {
+		name string
+		in   string
+		out  string
+		err  string
+	}{
+		{
+			name: "lat/lng pair",
+			in:   " 46.0569, 14.5058 ",
+			out:  `{"type":"Point","coordinates":[14.5058,46.0569],"bbox":[14.5058,46.0569,14.5058,46.0569]}`,
+		},
+		{
+			name: "point",
+			in:   `{"type": "Point", "coordinates": [-73.9857, 40.7484]}`,
+			out:  `{"type":"Point","coordinates":[-73.9857,40.7484],"bbox":[-73.9857,40.7484,-73.9857,40.7484]}`,
+		},
+		{
+			name: "polygon",
+			in:   `{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,1],[0,1],[0,0]]]}`,
+			out:  `{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,1],[0,1],[0,0]]],"bbox":[0,0,2,1]}`,
+		},
+		{
+			name: "feature",
+			in:   `{"type":"Feature","properties":{},"geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]}}`,
+			out:  `{"type":"LineString","coordinates":[[1,2],[3,4]],"bbox":[1,2,3,4]}`,
+		},
+		{
+			name: "latitude out of range",
+			in:   "91, 10",
+			err:  "invalid geometry position: latitude 91 out of range",
+		},
+		{
+			name: "unclosed ring",
+			in:   `{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,1],[0,1]]]}`,
+			err:  "invalid geometry: polygon ring is not closed",
+		},
+		{
+			name: "unsupported type",
+			in:   `{"type":"GeometryCollection","geometries":[]}`,
+			err:  `invalid geometry: unsupported type "GeometryCollection"`,
+		},
+		{
+			name: "invalid nesting",
+			in:   `{"type":"Point","coordinates":[[1,2]]}`,
+			err:  "invalid geometry position: json: cannot unmarshal array",
+		},
+		{
+			name: "garbage",
+			in:   "somewhere",
+			err:  "invalid geometry: invalid character 's'",
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			req := require.New(t)
+
+			g, err := Parse(tc.in)
+			if tc.err != "" {
+				req.Error(err)
+				req.Contains(err.Error(), tc.err)
+				return
+			}
+
+			req.NoError(err)
+			req.Equal(tc.out, g.String())
+
+			// output is parsed into the same geometry
+			g, err = Parse(tc.out)
+			req.NoError(err)
+			req.Equal(tc.out, g.String())
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Ljubljana - Zagreb
+	require.InDelta(t, 117.2, Distance(46.0569, 14.5058, 45.8150, 15.9819), 0.5)
+	require.Equal(t, 0.0, Distance(10, 10, 10, 10))
+}
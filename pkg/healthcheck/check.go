@@ -30,6 +30,12 @@ type (
 	checks  struct {
 		cc []*check
 	}
+
+	// info is returned by checks that pass
+	// but report additional information
+	info struct {
+		msg string
+	}
 )
 
 var (
@@ -48,6 +54,16 @@ func New() *checks {
 	return &checks{cc: []*check{}}
 }
 
+// Info can be returned by the check function to report
+// additional information without failing the check
+func Info(f string, aa ...interface{}) error {
+	return &info{msg: fmt.Sprintf(f, aa...)}
+}
+
+func (i info) Error() string {
+	return i.msg
+}
+
 // Add appends new check
 func (c *checks) Add(fn checkFn, label string, description ...string) {
 	c.cc = append(c.cc, &check{fn, &Meta{Label: label, Description: strings.Join(description, "")}})
@@ -65,7 +81,7 @@ func (c checks) Run(ctx context.Context) results {
 
 func (rr results) Healthy() bool {
 	for _, c := range rr {
-		if c.err != nil {
+		if !c.IsHealthy() {
 			return false
 		}
 	}
@@ -97,7 +113,7 @@ func (rr results) WriteTo(w io.Writer) (int64, error) {
 
 		p(" %s", r.Label)
 
-		if !r.IsHealthy() {
+		if r.err != nil {
 			p(": %v", r.Error())
 		}
 
@@ -108,7 +124,12 @@ func (rr results) WriteTo(w io.Writer) (int64, error) {
 }
 
 func (r *result) IsHealthy() bool {
-	return r != nil && r.err == nil
+	if r == nil {
+		return false
+	}
+
+	_, isInfo := r.err.(*info)
+	return r.err == nil || isInfo
 }
 
 func (r *result) Error() string {
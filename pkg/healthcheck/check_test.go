@@ -49,6 +49,15 @@ func Test_Healthy(t *testing.T) {
 			true,
 			"PASS check01\nPASS Pretty check\n",
 		},
+		{
+			"should handle checks with additional info",
+			[]*check{
+				{func(ctx context.Context) error { return Info("info %d", 42) }, &Meta{Label: "check01"}},
+				{func(ctx context.Context) error { return nil }, &Meta{Label: "check02"}},
+			},
+			true,
+			"PASS check01: info 42\nPASS check02\n",
+		},
 		{
 			"should handle empty check list",
 			[]*check{},
@@ -18,6 +18,8 @@ type (
 		LogEnabled               bool          `env:"AUTH_LOG_ENABLED"`
 		Secret                   string        `env:"AUTH_JWT_SECRET"`
 		Expiry                   time.Duration `env:"AUTH_JWT_EXPIRY"`
+		JwtAlgorithm             string        `env:"AUTH_JWT_ALGORITHM"`
+		JwtKeysPath              string        `env:"AUTH_JWT_KEYS_PATH"`
 		ExternalRedirectURL      string        `env:"AUTH_EXTERNAL_REDIRECT_URL"`
 		ExternalCookieSecret     string        `env:"AUTH_EXTERNAL_COOKIE_SECRET"`
 		BaseURL                  string        `env:"AUTH_BASE_URL"`
@@ -44,6 +46,8 @@ func Auth() (o *AuthOpt) {
 	o = &AuthOpt{
 		Secret:                   getSecretFromEnv("jwt secret"),
 		Expiry:                   time.Hour * 24 * 30,
+		JwtAlgorithm:             "HS512",
+		JwtKeysPath:              "",
 		ExternalRedirectURL:      guestBaseURL() + "/auth/external/{provider}/callback",
 		ExternalCookieSecret:     getSecretFromEnv("external cookie secret"),
 		BaseURL:                  guestBaseURL() + "/auth",
@@ -14,16 +14,19 @@ import (
 
 type (
 	EventbusOpt struct {
-		SchedulerEnabled  bool          `env:"EVENTBUS_SCHEDULER_ENABLED"`
-		SchedulerInterval time.Duration `env:"EVENTBUS_SCHEDULER_INTERVAL"`
+		SchedulerEnabled      bool          `env:"EVENTBUS_SCHEDULER_ENABLED"`
+		SchedulerInterval     time.Duration `env:"EVENTBUS_SCHEDULER_INTERVAL"`
+		SchedulerLeaseEnabled bool          `env:"EVENTBUS_SCHEDULER_LEASE_ENABLED"`
+		SchedulerLeaseHolder  string        `env:"EVENTBUS_SCHEDULER_LEASE_HOLDER"`
 	}
 )
 
 // Eventbus initializes and returns a EventbusOpt with default values
 func Eventbus() (o *EventbusOpt) {
 	o = &EventbusOpt{
-		SchedulerEnabled:  true,
-		SchedulerInterval: time.Minute,
+		SchedulerEnabled:      true,
+		SchedulerInterval:     time.Minute,
+		SchedulerLeaseEnabled: true,
 	}
 
 	fill(o)
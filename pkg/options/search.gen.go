@@ -0,0 +1,37 @@
+package options
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// pkg/options/search.yaml
+
+type (
+	SearchOpt struct {
+		Enabled bool   `env:"SEARCH_ENABLED"`
+		Path    string `env:"SEARCH_PATH"`
+	}
+)
+
+// Search initializes and returns a SearchOpt with default values
+func Search() (o *SearchOpt) {
+	o = &SearchOpt{
+		Enabled: false,
+		Path:    "var/search",
+	}
+
+	fill(o)
+
+	// Function that allows access to custom logic inside the parent function.
+	// The custom logic in the other file should be like:
+	// func (o *Search) Defaults() {...}
+	func(o interface{}) {
+		if def, ok := o.(interface{ Defaults() }); ok {
+			def.Defaults()
+		}
+	}(o)
+
+	return
+}
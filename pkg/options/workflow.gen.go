@@ -8,16 +8,34 @@ package options
 // Definitions file that controls how this file is generated:
 // pkg/options/workflow.yaml
 
+import (
+	"time"
+)
+
 type (
 	WorkflowOpt struct {
-		Register bool `env:"WORKFLOW_REGISTER"`
+		Register               bool          `env:"WORKFLOW_REGISTER"`
+		CallStackSize          int           `env:"WORKFLOW_CALL_STACK_SIZE"`
+		SessionRetention       time.Duration `env:"WORKFLOW_SESSION_RETENTION"`
+		SessionCleanupInterval time.Duration `env:"WORKFLOW_SESSION_CLEANUP_INTERVAL"`
+		StepTimeout            time.Duration `env:"WORKFLOW_STEP_TIMEOUT"`
+		StepConcurrency        int           `env:"WORKFLOW_STEP_CONCURRENCY"`
+		DryRunTimeout          time.Duration `env:"WORKFLOW_DRY_RUN_TIMEOUT"`
+		StatsRetention         time.Duration `env:"WORKFLOW_STATS_RETENTION"`
 	}
 )
 
 // Workflow initializes and returns a WorkflowOpt with default values
 func Workflow() (o *WorkflowOpt) {
 	o = &WorkflowOpt{
-		Register: true,
+		Register:               true,
+		CallStackSize:          16,
+		SessionRetention:       0,
+		SessionCleanupInterval: time.Minute * 5,
+		StepTimeout:            0,
+		StepConcurrency:        32,
+		DryRunTimeout:          time.Second * 30,
+		StatsRetention:         time.Hour * 24,
 	}
 
 	fill(o)
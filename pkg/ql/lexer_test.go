@@ -31,6 +31,8 @@ func TestScanner_ScanSimple(t *testing.T) {
 		{s: `'escaped \' quote'`, tok: LSTRING, lit: "escaped ' quote"},
 		{s: `'double \\ escape'`, tok: LSTRING, lit: "double \\ escape"},
 		{s: `12345`, tok: LNUMBER, lit: "12345"},
+		{s: `12.345`, tok: LNUMBER, lit: "12.345"},
+		{s: `12.3.4`, tok: LNUMBER, lit: "12.3"},
 
 		// Identifiers
 		{s: `foo`, tok: IDENT, lit: `foo`},
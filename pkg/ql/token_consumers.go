@@ -132,14 +132,23 @@ func (str TokenConsumerNumber) Test(ch rune) bool {
 }
 
 // Consumes entire number (very naive and simplified)
+//
+// Decimal numbers (with a single decimal point) are supported
 func (str TokenConsumerNumber) Consume(s RuneReader) Token {
 	// Create a buffer and read the current character into it.
-	var buf bytes.Buffer
+	var (
+		buf     bytes.Buffer
+		decimal bool
+	)
+
 	buf.WriteRune(s.read())
 
 	for {
 		if ch := s.read(); ch == eof {
 			break
+		} else if ch == '.' && !decimal {
+			decimal = true
+			_, _ = buf.WriteRune(ch)
 		} else if !isDigit(ch) {
 			s.unread()
 			break
@@ -15,5 +15,9 @@ func Healthcheck(ctx context.Context) error {
 		return fmt.Errorf("stopped")
 	}
 
+	if gScheduler.lease != nil {
+		return gScheduler.lease.healthcheck()
+	}
+
 	return nil
 }
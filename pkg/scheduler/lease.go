@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/healthcheck"
+	"github.com/cortezaproject/corteza-server/pkg/scheduler/types"
+)
+
+type (
+	// lease makes sure only one of the nodes dispatches scheduled events
+	//
+	// Node that holds the lease renews it on every tick; when it stops
+	// (node dies) the lease expires and one of the other nodes takes it over
+	lease struct {
+		store  leaseStore
+		holder string
+		ttl    time.Duration
+
+		mux *sync.RWMutex
+
+		// last known state of the lease and error from the last attempt
+		current *types.Lease
+		err     error
+	}
+
+	leaseStore interface {
+		AcquireSchedulerLease(ctx context.Context, l *types.Lease) (*types.Lease, error)
+	}
+)
+
+const (
+	leaseName = "scheduler"
+)
+
+// acquires or renews the lease and reports if this node holds it
+func (l *lease) acquire(ctx context.Context) bool {
+	var (
+		n = now()
+	)
+
+	cur, err := l.store.AcquireSchedulerLease(ctx, &types.Lease{
+		Name:      leaseName,
+		Holder:    l.holder,
+		ExpiresAt: n.Add(l.ttl),
+		UpdatedAt: n,
+	})
+
+	l.mux.Lock()
+	l.current, l.err = cur, err
+	l.mux.Unlock()
+
+	return err == nil && cur.IsHeldBy(l.holder, n)
+}
+
+// reports the lease holder
+func (l *lease) healthcheck() error {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	switch {
+	case l.err != nil:
+		return fmt.Errorf("could not acquire lease: %w", l.err)
+	case l.current == nil:
+		return healthcheck.Info("lease not acquired yet")
+	case l.current.Holder == l.holder:
+		return healthcheck.Info("lease held by this node (%s) until %s", l.holder, l.current.ExpiresAt.Format(time.RFC3339))
+	default:
+		return healthcheck.Info("lease held by %s until %s", l.current.Holder, l.current.ExpiresAt.Format(time.RFC3339))
+	}
+}
+
+// defaultLeaseHolder identifies node by hostname and process ID
+func defaultLeaseHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/scheduler/types"
+)
+
+type (
+	// testLeaseStore is an in-memory stand-in for the store
+	// that follows the same rules as the RDBMS implementation
+	testLeaseStore struct {
+		mux    sync.Mutex
+		leases map[string]types.Lease
+		err    error
+	}
+
+	countingDispatcher struct {
+		wg  *sync.WaitGroup
+		mux sync.Mutex
+		n   int
+	}
+)
+
+func (s *testLeaseStore) AcquireSchedulerLease(_ context.Context, l *types.Lease) (*types.Lease, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.leases == nil {
+		s.leases = make(map[string]types.Lease)
+	}
+
+	cur, has := s.leases[l.Name]
+	if !has || cur.Holder == l.Holder || cur.ExpiresAt.Before(l.UpdatedAt) {
+		cur = *l
+		s.leases[l.Name] = cur
+	}
+
+	return &cur, nil
+}
+
+func (d *countingDispatcher) Dispatch(context.Context, eventbus.Event) {
+	d.mux.Lock()
+	d.n++
+	d.mux.Unlock()
+	d.wg.Done()
+}
+
+func (d *countingDispatcher) count() int {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.n
+}
+
+func TestLease(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+
+		ls = &testLeaseStore{}
+		wg = &sync.WaitGroup{}
+		d  = &countingDispatcher{wg: wg}
+
+		// nodes sharing the same store
+		node1 = NewService(zap.NewNop(), d, time.Minute)
+		node2 = NewService(zap.NewNop(), d, time.Minute)
+
+		clock = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		tick = func(nn ...*service) {
+			for _, n := range nn {
+				n.dispatch(ctx)
+			}
+
+			wg.Wait()
+		}
+	)
+
+	defer func(orig func() time.Time) { now = orig }(now)
+	now = func() time.Time { return clock }
+
+	node1.UseLease(ls, "node-1")
+	node2.UseLease(ls, "node-2")
+	node1.OnTick(&mockEvent{})
+	node2.OnTick(&mockEvent{})
+
+	// only the first node dispatches
+	wg.Add(1)
+	tick(node1, node2)
+	req.Equal(1, d.count())
+	req.EqualError(node1.lease.healthcheck(), "lease held by this node (node-1) until 2021-01-01T00:01:30Z")
+	req.EqualError(node2.lease.healthcheck(), "lease held by node-1 until 2021-01-01T00:01:30Z")
+
+	// holder renews the lease on the next tick
+	clock = clock.Add(time.Minute)
+	wg.Add(1)
+	tick(node2, node1)
+	req.Equal(2, d.count())
+
+	// first node dies; lease expires and second node takes over
+	clock = clock.Add(time.Minute)
+	tick(node2)
+	req.Equal(2, d.count())
+
+	clock = clock.Add(time.Minute)
+	wg.Add(1)
+	tick(node2)
+	req.Equal(3, d.count())
+	req.EqualError(node2.lease.healthcheck(), "lease held by this node (node-2) until 2021-01-01T00:04:30Z")
+
+	// nothing is dispatched when lease can not be acquired
+	ls.err = fmt.Errorf("store unavailable")
+	clock = clock.Add(time.Minute)
+	tick(node1, node2)
+	req.Equal(3, d.count())
+	req.EqualError(node2.lease.healthcheck(), "could not acquire lease: store unavailable")
+}
@@ -23,6 +23,9 @@ type (
 
 		// Simple chan to control if service is running or not
 		ticker *time.Ticker
+
+		// When set, events are dispatched only when lease is held
+		lease *lease
 	}
 
 	dispatcher interface {
@@ -75,6 +78,31 @@ func NewService(log *zap.Logger, d dispatcher, interval time.Duration) *service
 	return svc
 }
 
+// UseLease makes scheduler dispatch events only while this node holds the lease
+//
+// Use it when running multiple nodes with the same store to prevent
+// scheduled events from being dispatched on every node.
+// Holder identifies the node; hostname and process ID are used when empty
+func (svc *service) UseLease(s leaseStore, holder string) {
+	if holder == "" {
+		holder = defaultLeaseHolder()
+	}
+
+	svc.l.Lock()
+	defer svc.l.Unlock()
+
+	svc.lease = &lease{
+		store:  s,
+		holder: holder,
+
+		// lease is renewed on every tick; let it outlive the interval
+		// so that small delays do not cause a failover but make sure
+		// it expires before the tick after the next one
+		ttl: svc.interval + svc.interval/2,
+		mux: &sync.RWMutex{},
+	}
+}
+
 // Register all events that should fire on tick (interval)
 func (svc *service) OnTick(events ...eventbus.Event) {
 	svc.l.Lock()
@@ -163,6 +191,11 @@ func (svc service) Started() (started bool) {
 }
 
 func (svc service) dispatch(ctx context.Context) {
+	if svc.lease != nil && !svc.lease.acquire(ctx) {
+		svc.log.Debug("scheduler lease not held, skipping dispatch")
+		return
+	}
+
 	svc.l.RLock()
 
 	ee := make([]eventbus.Event, len(svc.events))
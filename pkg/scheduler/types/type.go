@@ -0,0 +1,30 @@
+package types
+
+import (
+	"time"
+)
+
+type (
+	// Lease gives one of the nodes exclusive right to
+	// dispatch scheduled events until it expires
+	Lease struct {
+		Name string
+
+		// Identifies node holding the lease
+		Holder string
+
+		ExpiresAt time.Time
+		UpdatedAt time.Time
+	}
+
+	LeaseSet []*Lease
+
+	LeaseFilter struct {
+		Name []string
+	}
+)
+
+// IsHeldBy checks if lease is held by the holder and not yet expired
+func (l *Lease) IsHeldBy(holder string, now time.Time) bool {
+	return l != nil && l.Holder == holder && l.ExpiresAt.After(now)
+}
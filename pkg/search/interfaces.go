@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+)
+
+type (
+	// Document is a single indexed resource (compose record)
+	//
+	// Fields hold (already formatted) textual values by field name
+	Document struct {
+		ID          uint64              `json:"id,string"`
+		NamespaceID uint64              `json:"namespaceID,string"`
+		ModuleID    uint64              `json:"moduleID,string"`
+		Fields      map[string][]string `json:"fields"`
+	}
+
+	Query struct {
+		NamespaceID uint64
+
+		// Limit search to these modules (all modules when empty)
+		ModuleIDs []uint64
+
+		Text string
+	}
+
+	// Hit is a document that matched the query
+	//
+	// Score is a sum of all field scores; they are provided separately
+	// so that caller can discard matches on fields that user can not read
+	Hit struct {
+		ID          uint64
+		NamespaceID uint64
+		ModuleID    uint64
+		Score       float64
+		Fields      map[string]float64
+	}
+
+	Index interface {
+		// Index adds or replaces documents
+		Index(ctx context.Context, dd ...*Document) error
+
+		// Remove removes documents by ID
+		Remove(ctx context.Context, IDs ...uint64) error
+
+		// Search returns matching documents, ordered by score
+		Search(ctx context.Context, q Query) ([]*Hit, error)
+
+		// Count returns number of indexed documents
+		Count(ctx context.Context) (uint64, error)
+
+		// Close flushes and closes the index
+		Close() error
+	}
+)
@@ -0,0 +1,420 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/cortezaproject/corteza-server/pkg/search"
+	"github.com/spf13/afero"
+)
+
+type (
+	// index is an in-memory inverted index with BM25 ranking
+	//
+	// All changes are appended to an operation log on disk;
+	// log is replayed and compacted when index is opened.
+	index struct {
+		l sync.RWMutex
+
+		fs   afero.Fs
+		path string
+
+		// operation log, nil when index is not persisted
+		log afero.File
+
+		docs map[uint64]*entry
+
+		// documents containing the term (in any of the fields)
+		postings map[string]map[uint64]bool
+
+		// total number of terms and number of documents per field
+		// used to calculate average field length
+		fieldLen  map[string]uint64
+		fieldDocs map[string]uint64
+	}
+
+	entry struct {
+		doc *search.Document
+
+		// term frequencies per field
+		terms map[string]map[string]uint64
+
+		// number of terms per field
+		lens map[string]uint64
+	}
+
+	operation struct {
+		Index  *search.Document `json:"index,omitempty"`
+		Remove uint64           `json:"remove,string,omitempty"`
+	}
+)
+
+const (
+	logFilename = "index.log"
+
+	// BM25 parameters
+	bm25k1 = 1.2
+	bm25b  = 0.75
+
+	// Terms that only start with the query term score lower than exact matches
+	prefixBoost = 0.5
+
+	// Shorter query terms are not expanded with prefix matching
+	minPrefixLen = 2
+)
+
+// New opens (or creates) index on the given path
+//
+// Index is kept only in memory when path is empty
+func New(path string) (*index, error) {
+	return NewWithAfero(afero.NewOsFs(), path)
+}
+
+func NewWithAfero(fs afero.Fs, path string) (*index, error) {
+	idx := &index{
+		fs:        fs,
+		path:      path,
+		docs:      make(map[uint64]*entry),
+		postings:  make(map[string]map[uint64]bool),
+		fieldLen:  make(map[string]uint64),
+		fieldDocs: make(map[string]uint64),
+	}
+
+	if path == "" {
+		return idx, nil
+	}
+
+	if err := idx.open(); err != nil {
+		return nil, fmt.Errorf("could not open search index: %w", err)
+	}
+
+	return idx, nil
+}
+
+func (idx *index) Index(ctx context.Context, dd ...*search.Document) error {
+	idx.l.Lock()
+	defer idx.l.Unlock()
+
+	for _, d := range dd {
+		if err := idx.write(operation{Index: d}); err != nil {
+			return err
+		}
+
+		idx.add(d)
+	}
+
+	return nil
+}
+
+func (idx *index) Remove(ctx context.Context, IDs ...uint64) error {
+	idx.l.Lock()
+	defer idx.l.Unlock()
+
+	for _, ID := range IDs {
+		if idx.docs[ID] == nil {
+			continue
+		}
+
+		if err := idx.write(operation{Remove: ID}); err != nil {
+			return err
+		}
+
+		idx.remove(ID)
+	}
+
+	return nil
+}
+
+func (idx *index) Search(ctx context.Context, q search.Query) ([]*search.Hit, error) {
+	idx.l.RLock()
+	defer idx.l.RUnlock()
+
+	var (
+		hits = make(map[uint64]*search.Hit)
+		mm   = make(map[uint64]bool)
+		n    = float64(len(idx.docs))
+	)
+
+	for _, moduleID := range q.ModuleIDs {
+		mm[moduleID] = true
+	}
+
+	for term, weight := range idx.expand(Tokenize(q.Text)) {
+		var (
+			df  = float64(len(idx.postings[term]))
+			idf = math.Log(1 + (n-df+0.5)/(df+0.5))
+		)
+
+		for ID := range idx.postings[term] {
+			e := idx.docs[ID]
+			if q.NamespaceID > 0 && e.doc.NamespaceID != q.NamespaceID {
+				continue
+			}
+
+			if len(mm) > 0 && !mm[e.doc.ModuleID] {
+				continue
+			}
+
+			for field, tt := range e.terms {
+				tf := float64(tt[term])
+				if tf == 0 {
+					continue
+				}
+
+				var (
+					avg  = float64(idx.fieldLen[field]) / float64(idx.fieldDocs[field])
+					norm = 1 - bm25b + bm25b*float64(e.lens[field])/avg
+				)
+
+				h := hits[ID]
+				if h == nil {
+					h = &search.Hit{
+						ID:          ID,
+						NamespaceID: e.doc.NamespaceID,
+						ModuleID:    e.doc.ModuleID,
+						Fields:      make(map[string]float64),
+					}
+					hits[ID] = h
+				}
+
+				h.Fields[field] += weight * idf * tf * (bm25k1 + 1) / (tf + bm25k1*norm)
+			}
+		}
+	}
+
+	out := make([]*search.Hit, 0, len(hits))
+	for _, h := range hits {
+		for _, s := range h.Fields {
+			h.Score += s
+		}
+
+		out = append(out, h)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+
+		// newer documents first
+		return out[i].ID > out[j].ID
+	})
+
+	return out, nil
+}
+
+func (idx *index) Count(ctx context.Context) (uint64, error) {
+	idx.l.RLock()
+	defer idx.l.RUnlock()
+	return uint64(len(idx.docs)), nil
+}
+
+func (idx *index) Close() error {
+	idx.l.Lock()
+	defer idx.l.Unlock()
+
+	if idx.log == nil {
+		return nil
+	}
+
+	defer func() { idx.log = nil }()
+
+	if err := idx.log.Sync(); err != nil {
+		return err
+	}
+
+	return idx.log.Close()
+}
+
+// Tokenize splits text into lower-cased terms
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// expand returns indexed terms matching the query terms with their weights
+func (idx *index) expand(qq []string) map[string]float64 {
+	var (
+		out = make(map[string]float64)
+
+		set = func(term string, weight float64) {
+			if out[term] < weight {
+				out[term] = weight
+			}
+		}
+	)
+
+	for _, q := range qq {
+		if idx.postings[q] != nil {
+			set(q, 1)
+		}
+
+		if len([]rune(q)) < minPrefixLen {
+			continue
+		}
+
+		for term := range idx.postings {
+			if term != q && strings.HasPrefix(term, q) {
+				set(term, prefixBoost)
+			}
+		}
+	}
+
+	return out
+}
+
+func (idx *index) add(d *search.Document) {
+	idx.remove(d.ID)
+
+	e := &entry{
+		doc:   d,
+		terms: make(map[string]map[string]uint64),
+		lens:  make(map[string]uint64),
+	}
+
+	for field, vv := range d.Fields {
+		tt := make(map[string]uint64)
+		for _, v := range vv {
+			for _, term := range Tokenize(v) {
+				tt[term]++
+				e.lens[field]++
+			}
+		}
+
+		if len(tt) == 0 {
+			continue
+		}
+
+		e.terms[field] = tt
+		idx.fieldLen[field] += e.lens[field]
+		idx.fieldDocs[field]++
+
+		for term := range tt {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[uint64]bool)
+			}
+
+			idx.postings[term][d.ID] = true
+		}
+	}
+
+	idx.docs[d.ID] = e
+}
+
+func (idx *index) remove(ID uint64) {
+	e := idx.docs[ID]
+	if e == nil {
+		return
+	}
+
+	for field, tt := range e.terms {
+		idx.fieldLen[field] -= e.lens[field]
+		idx.fieldDocs[field]--
+
+		for term := range tt {
+			delete(idx.postings[term], ID)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+
+	delete(idx.docs, ID)
+}
+
+// open replays the operation log and compacts it
+func (idx *index) open() (err error) {
+	var (
+		filename = path.Join(idx.path, logFilename)
+		tmp      = filename + ".tmp"
+	)
+
+	if err = idx.fs.MkdirAll(idx.path, 0755); err != nil {
+		return
+	}
+
+	if err = idx.replay(filename); err != nil {
+		return
+	}
+
+	// Write current state into a new log
+	if idx.log, err = idx.fs.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644); err != nil {
+		return
+	}
+
+	for _, e := range idx.docs {
+		if err = idx.write(operation{Index: e.doc}); err != nil {
+			return
+		}
+	}
+
+	if err = idx.log.Close(); err != nil {
+		return
+	}
+
+	if err = idx.fs.Rename(tmp, filename); err != nil {
+		return
+	}
+
+	idx.log, err = idx.fs.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0644)
+	return
+}
+
+func (idx *index) replay(filename string) error {
+	f, err := idx.fs.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	var (
+		scanner = bufio.NewScanner(f)
+		op      operation
+	)
+
+	scanner.Buffer(nil, 1<<24)
+
+	for scanner.Scan() {
+		op = operation{}
+		if err = json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			// Last operation might not be fully written
+			// when process was terminated; ignore it
+			break
+		}
+
+		if op.Index != nil {
+			idx.add(op.Index)
+		} else if op.Remove > 0 {
+			idx.remove(op.Remove)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (idx *index) write(op operation) error {
+	if idx.log == nil {
+		return nil
+	}
+
+	buf, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+
+	_, err = idx.log.Write(append(buf, '\n'))
+	return err
+}
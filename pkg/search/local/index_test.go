@@ -0,0 +1,143 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/search"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func doc(ID, moduleID uint64, ff ...string) *search.Document {
+	d := &search.Document{ID: ID, NamespaceID: 1, ModuleID: moduleID, Fields: make(map[string][]string)}
+	for i := 0; i < len(ff); i += 2 {
+		d.Fields[ff[i]] = append(d.Fields[ff[i]], ff[i+1])
+	}
+
+	return d
+}
+
+func hitIDs(hh []*search.Hit) []uint64 {
+	out := make([]uint64, len(hh))
+	for i, h := range hh {
+		out[i] = h.ID
+	}
+
+	return out
+}
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t,
+		[]string{"hello", "world", "42", "čšž"},
+		Tokenize("Hello, World! <42> ČŠŽ"),
+	)
+}
+
+func TestIndex_Search(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+	)
+
+	idx, err := New("")
+	req.NoError(err)
+
+	req.NoError(idx.Index(ctx,
+		doc(1, 10, "name", "Acme corporation", "notes", "big customer"),
+		doc(2, 10, "name", "Globex", "notes", "acme competitor, acme acme"),
+		doc(3, 20, "title", "Acme anvil"),
+		doc(4, 20, "title", "Rocket skates"),
+	))
+
+	t.Run("ranked", func(t *testing.T) {
+		hh, err := idx.Search(ctx, search.Query{Text: "acme"})
+		req.NoError(err)
+		req.Len(hh, 3)
+		req.NotContains(hitIDs(hh), uint64(4))
+		for i := 1; i < len(hh); i++ {
+			req.True(hh[i-1].Score >= hh[i].Score)
+		}
+
+		req.Contains(hh[0].Fields, "notes")
+		req.NotContains(hh[0].Fields, "name")
+	})
+
+	t.Run("field scores", func(t *testing.T) {
+		hh, err := idx.Search(ctx, search.Query{Text: "acme customer"})
+		req.NoError(err)
+
+		for _, h := range hh {
+			if h.ID == 1 {
+				req.Len(h.Fields, 2)
+				req.Equal(h.Fields["name"]+h.Fields["notes"], h.Score)
+			}
+		}
+	})
+
+	t.Run("modules", func(t *testing.T) {
+		hh, err := idx.Search(ctx, search.Query{Text: "acme", ModuleIDs: []uint64{20}})
+		req.NoError(err)
+		req.Equal([]uint64{3}, hitIDs(hh))
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		hh, err := idx.Search(ctx, search.Query{NamespaceID: 2, Text: "acme"})
+		req.NoError(err)
+		req.Empty(hh)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		hh, err := idx.Search(ctx, search.Query{Text: "rock"})
+		req.NoError(err)
+		req.Equal([]uint64{4}, hitIDs(hh))
+	})
+
+	t.Run("update and remove", func(t *testing.T) {
+		req.NoError(idx.Index(ctx, doc(4, 20, "title", "Acme rocket")))
+		req.NoError(idx.Remove(ctx, 1, 2))
+
+		hh, err := idx.Search(ctx, search.Query{Text: "acme"})
+		req.NoError(err)
+		req.ElementsMatch([]uint64{3, 4}, hitIDs(hh))
+
+		hh, err = idx.Search(ctx, search.Query{Text: "skates"})
+		req.NoError(err)
+		req.Empty(hh)
+
+		c, err := idx.Count(ctx)
+		req.NoError(err)
+		req.Equal(uint64(2), c)
+	})
+}
+
+func TestIndex_Persistence(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		fs  = afero.NewMemMapFs()
+	)
+
+	idx, err := NewWithAfero(fs, "search")
+	req.NoError(err)
+	req.NoError(idx.Index(ctx, doc(1, 10, "name", "foo"), doc(2, 10, "name", "bar")))
+	req.NoError(idx.Index(ctx, doc(1, 10, "name", "baz")))
+	req.NoError(idx.Remove(ctx, 2))
+	req.NoError(idx.Close())
+
+	idx, err = NewWithAfero(fs, "search")
+	req.NoError(err)
+
+	c, err := idx.Count(ctx)
+	req.NoError(err)
+	req.Equal(uint64(1), c)
+
+	hh, err := idx.Search(ctx, search.Query{Text: "baz"})
+	req.NoError(err)
+	req.Equal([]uint64{1}, hitIDs(hh))
+
+	// log is compacted on open
+	buf, err := afero.ReadFile(fs, "search/"+logFilename)
+	req.NoError(err)
+	req.Equal("{\"index\":{\"id\":\"1\",\"namespaceID\":\"1\",\"moduleID\":\"10\",\"fields\":{\"name\":[\"baz\"]}}}\n", string(buf))
+}
@@ -0,0 +1,99 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	// parsed authenticator data
+	// https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data
+	authenticatorData struct {
+		rpIDHash   []byte
+		flags      byte
+		signCount  uint32
+		credential *Credential
+	}
+)
+
+const (
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+	flagExtensionData          byte = 0x80
+
+	// rpIdHash (32) + flags (1) + signCount (4)
+	authDataMinLength = 37
+
+	// aaguid (16) + credentialIdLength (2)
+	attestedCredentialMinLength = 18
+)
+
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < authDataMinLength {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+
+	ad := &authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+
+	if ad.flags&flagAttestedCredentialData == 0 {
+		return ad, nil
+	}
+
+	rest := raw[authDataMinLength:]
+	if len(rest) < attestedCredentialMinLength {
+		return nil, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+	}
+
+	var (
+		aaguid = rest[:16]
+		idLen  = int(binary.BigEndian.Uint16(rest[16:18]))
+	)
+
+	rest = rest[attestedCredentialMinLength:]
+	if len(rest) < idLen {
+		return nil, fmt.Errorf("%w: credential ID too short", ErrInvalidResponse)
+	}
+
+	credID := rest[:idLen]
+	rest = rest[idLen:]
+
+	// public key is followed by (optional) extensions;
+	// decode it just to find out where it ends
+	_, n, err := cborDecode(rest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	pubKey := rest[:n]
+	if _, _, err = parsePublicKey(pubKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	ad.credential = &Credential{
+		ID:        append([]byte{}, credID...),
+		PublicKey: append([]byte{}, pubKey...),
+		SignCount: ad.signCount,
+		AAGUID:    append([]byte{}, aaguid...),
+	}
+
+	return ad, nil
+}
+
+func (ad authenticatorData) userPresent() bool {
+	return ad.flags&flagUserPresent != 0
+}
+
+func sha256sum(data []byte) [32]byte {
+	return sha256.Sum256(data)
+}
+
+func constantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -0,0 +1,190 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Minimal CBOR (RFC 7049) implementation
+//
+// Supports only what WebAuthn relying party needs (decoding attestation objects and COSE keys):
+// integers, byte & text strings, arrays, maps and simple values, all with
+// definite lengths. Floats, tags and indefinite lengths are rejected.
+//
+// Decoded values are one of:
+// int64, []byte, string, []interface{}, map[interface{}]interface{}, bool or nil
+
+const (
+	cborUint byte = iota
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+const (
+	// protects against maliciously large length headers
+	cborMaxItems = 1 << 16
+)
+
+type (
+	cborDecoder struct {
+		data []byte
+		pos  int
+	}
+)
+
+// cborDecode decodes the first CBOR item from data
+//
+// Returns decoded value and the number of bytes consumed
+// (authenticator data contains a CBOR encoded key followed by extensions)
+func cborDecode(data []byte) (v interface{}, n int, err error) {
+	d := &cborDecoder{data: data}
+	if v, err = d.decode(0); err != nil {
+		return nil, 0, err
+	}
+
+	return v, d.pos, nil
+}
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > 16 {
+		return nil, fmt.Errorf("cbor: nesting too deep")
+	}
+
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		if arg > 1<<63-1 {
+			return nil, fmt.Errorf("cbor: integer overflow")
+		}
+		return int64(arg), nil
+
+	case cborNegInt:
+		if arg > 1<<63-1 {
+			return nil, fmt.Errorf("cbor: integer overflow")
+		}
+		return -1 - int64(arg), nil
+
+	case cborBytes, cborText:
+		b, err := d.read(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if major == cborText {
+			return string(b), nil
+		}
+
+		return append([]byte{}, b...), nil
+
+	case cborArray:
+		if arg > cborMaxItems {
+			return nil, fmt.Errorf("cbor: array too large")
+		}
+
+		aa := make([]interface{}, arg)
+		for i := range aa {
+			if aa[i], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return aa, nil
+
+	case cborMap:
+		if arg > cborMaxItems {
+			return nil, fmt.Errorf("cbor: map too large")
+		}
+
+		mm := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			k, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			switch k.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("cbor: unsupported map key type %T", k)
+			}
+
+			if mm[k], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return mm, nil
+
+	case cborSimple:
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cbor: unsupported major type %d (%d)", major, arg)
+}
+
+// reads item head (major type and argument)
+func (d *cborDecoder) head() (major byte, arg uint64, err error) {
+	b, err := d.read(1)
+	if err != nil {
+		return
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		b, err = d.read(1)
+		if err != nil {
+			return
+		}
+		return major, uint64(b[0]), nil
+	case info == 25:
+		b, err = d.read(2)
+		if err != nil {
+			return
+		}
+		return major, uint64(binary.BigEndian.Uint16(b)), nil
+	case info == 26:
+		b, err = d.read(4)
+		if err != nil {
+			return
+		}
+		return major, uint64(binary.BigEndian.Uint32(b)), nil
+	case info == 27:
+		b, err = d.read(8)
+		if err != nil {
+			return
+		}
+		return major, binary.BigEndian.Uint64(b), nil
+	}
+
+	return 0, 0, fmt.Errorf("cbor: indefinite length or reserved value not supported")
+}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, fmt.Errorf("cbor: unexpected end of data")
+	}
+
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
@@ -0,0 +1,42 @@
+package webauthn
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBOR(t *testing.T) {
+	req := require.New(t)
+
+	var (
+		exp = map[interface{}]interface{}{
+			int64(1):  int64(-7),
+			int64(-1): []byte{1, 2, 3},
+			"text":    "value",
+			"list":    []interface{}{int64(1000), int64(-100000), true, nil},
+			"big":     int64(1) << 40,
+		}
+
+		enc, _ = hex.DecodeString("a501262043010203636269671b0000010000000000646c697374841903e83a0001869ff5f664746578746576616c7565")
+	)
+
+	// trailing data is not consumed
+	out, n, err := cborDecode(append(enc, 0xff))
+	req.NoError(err)
+	req.Equal(len(enc), n)
+	req.Equal(exp, out)
+
+	// truncated input
+	_, _, err = cborDecode(enc[:len(enc)-1])
+	req.Error(err)
+
+	// indefinite length array
+	_, _, err = cborDecode([]byte{0x9f, 0x01, 0xff})
+	req.Error(err)
+
+	// huge length header
+	_, _, err = cborDecode([]byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	req.Error(err)
+}
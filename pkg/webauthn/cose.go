@@ -0,0 +1,119 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// COSE (RFC 8152) key parameters and algorithms we support
+const (
+	coseKeyType      int64 = 1
+	coseKeyAlgorithm int64 = 3
+
+	coseEC2Curve int64 = -1
+	coseEC2X     int64 = -2
+	coseEC2Y     int64 = -3
+
+	coseRSAModulus  int64 = -1
+	coseRSAExponent int64 = -2
+
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256 int64 = 1
+
+	// AlgES256 is ECDSA w/ SHA-256 on the P-256 curve
+	AlgES256 = -7
+
+	// AlgRS256 is RSASSA-PKCS1-v1_5 w/ SHA-256
+	AlgRS256 = -257
+)
+
+// parsePublicKey parses COSE encoded public key
+func parsePublicKey(raw []byte) (alg int64, pub crypto.PublicKey, err error) {
+	v, _, err := cborDecode(raw)
+	if err != nil {
+		return
+	}
+
+	key, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return 0, nil, fmt.Errorf("public key is not a COSE key")
+	}
+
+	var (
+		kty, _ = key[coseKeyType].(int64)
+		a, _   = key[coseKeyAlgorithm].(int64)
+	)
+
+	switch {
+	case kty == coseKeyTypeEC2 && a == AlgES256:
+		var (
+			crv, _ = key[coseEC2Curve].(int64)
+			x, _   = key[coseEC2X].([]byte)
+			y, _   = key[coseEC2Y].([]byte)
+		)
+
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return 0, nil, fmt.Errorf("invalid EC2 public key")
+		}
+
+		k := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		if !k.Curve.IsOnCurve(k.X, k.Y) {
+			return 0, nil, fmt.Errorf("invalid EC2 public key")
+		}
+
+		return a, k, nil
+
+	case kty == coseKeyTypeRSA && a == AlgRS256:
+		var (
+			n, _ = key[coseRSAModulus].([]byte)
+			e, _ = key[coseRSAExponent].([]byte)
+		)
+
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return 0, nil, fmt.Errorf("invalid RSA public key")
+		}
+
+		return a, &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+
+	return 0, nil, fmt.Errorf("unsupported public key type %d with algorithm %d", kty, a)
+}
+
+// verifySignature verifies signature over data with COSE encoded public key
+func verifySignature(rawKey, data, sig []byte) error {
+	_, pub, err := parsePublicKey(rawKey)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(data)
+
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], sig) {
+			return ErrInvalidSignature
+		}
+
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) != nil {
+			return ErrInvalidSignature
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package webauthn
+
+type (
+	// Policy describes who can (enabled) and who must (enforced) use security keys
+	Policy struct {
+		// Security keys can be used by everyone
+		Enabled bool
+
+		// Roles that can use security keys when not enabled for everyone
+		EnabledRoles []uint64
+
+		// Security keys are required for everyone (when enabled)
+		Enforced bool
+
+		// Roles that are required to use security keys
+		// (security keys are enabled for them as well)
+		EnforcedRoles []uint64
+	}
+)
+
+// Resolve checks if security keys can be used (enabled)
+// and if they are required (enforced) for a member of the given roles
+func (p Policy) Resolve(roles []uint64) (enabled, enforced bool) {
+	isMember := func(rr []uint64) bool {
+		for _, r := range rr {
+			for _, m := range roles {
+				if r == m {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	enforced = (p.Enabled && p.Enforced) || isMember(p.EnforcedRoles)
+	enabled = p.Enabled || enforced || isMember(p.EnabledRoles)
+	return
+}
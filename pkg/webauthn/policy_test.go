@@ -0,0 +1,34 @@
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Resolve(t *testing.T) {
+	tcc := []struct {
+		name     string
+		policy   Policy
+		roles    []uint64
+		enabled  bool
+		enforced bool
+	}{
+		{"disabled", Policy{}, []uint64{1}, false, false},
+		{"enforced without enabled", Policy{Enforced: true}, []uint64{1}, false, false},
+		{"enabled", Policy{Enabled: true}, nil, true, false},
+		{"enforced", Policy{Enabled: true, Enforced: true}, nil, true, true},
+		{"enabled for role", Policy{EnabledRoles: []uint64{1}}, []uint64{2, 1}, true, false},
+		{"enabled for other role", Policy{EnabledRoles: []uint64{1}}, []uint64{2}, false, false},
+		{"enforced for role", Policy{EnforcedRoles: []uint64{1}}, []uint64{1}, true, true},
+		{"enforced for other role", Policy{Enabled: true, EnforcedRoles: []uint64{1}}, []uint64{2}, true, false},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			enabled, enforced := tc.policy.Resolve(tc.roles)
+			require.Equal(t, tc.enabled, enabled)
+			require.Equal(t, tc.enforced, enforced)
+		})
+	}
+}
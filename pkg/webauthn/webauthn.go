@@ -0,0 +1,376 @@
+// Package webauthn implements server side of the Web Authentication
+// (https://www.w3.org/TR/webauthn-2/) registration and assertion ceremonies
+//
+// Attestation statements are not verified; relying party requests "none"
+// attestation conveyance and treats all authenticators as self-attested.
+package webauthn
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type (
+	// RelyingParty describes the server that registers and verifies credentials
+	RelyingParty struct {
+		// Relying party ID, effective domain of the origin
+		ID string
+
+		// Human-palatable name displayed by the browser
+		Name string
+
+		// Origin (scheme, host and port) of the pages that invoke WebAuthn API
+		Origin string
+	}
+
+	// Credential holds public part of the registered credential
+	Credential struct {
+		ID        Base64 `json:"id"`
+		PublicKey Base64 `json:"publicKey"`
+		SignCount uint32 `json:"signCount"`
+		AAGUID    Base64 `json:"aaguid,omitempty"`
+	}
+
+	// Base64 is a byte slice encoded as URL-safe base64 without padding
+	// as used by WebAuthn
+	Base64 []byte
+
+	Entity struct {
+		ID          Base64 `json:"id,omitempty"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName,omitempty"`
+	}
+
+	RelyingPartyEntity struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	CredentialParameter struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	}
+
+	CredentialDescriptor struct {
+		Type string `json:"type"`
+		ID   Base64 `json:"id"`
+	}
+
+	AuthenticatorSelection struct {
+		UserVerification string `json:"userVerification,omitempty"`
+	}
+
+	// CreationOptions are passed to navigator.credentials.create()
+	CreationOptions struct {
+		Challenge              Base64                 `json:"challenge"`
+		RP                     RelyingPartyEntity     `json:"rp"`
+		User                   Entity                 `json:"user"`
+		PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+		Timeout                uint                   `json:"timeout,omitempty"`
+		ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+		Attestation            string                 `json:"attestation"`
+	}
+
+	// RequestOptions are passed to navigator.credentials.get()
+	RequestOptions struct {
+		Challenge        Base64                 `json:"challenge"`
+		Timeout          uint                   `json:"timeout,omitempty"`
+		RPID             string                 `json:"rpId"`
+		AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+		UserVerification string                 `json:"userVerification,omitempty"`
+	}
+
+	// RegistrationResponse is serialized result of navigator.credentials.create()
+	RegistrationResponse struct {
+		ID       string `json:"id"`
+		RawID    Base64 `json:"rawId"`
+		Type     string `json:"type"`
+		Response struct {
+			ClientDataJSON    Base64 `json:"clientDataJSON"`
+			AttestationObject Base64 `json:"attestationObject"`
+		} `json:"response"`
+	}
+
+	// AssertionResponse is serialized result of navigator.credentials.get()
+	AssertionResponse struct {
+		ID       string `json:"id"`
+		RawID    Base64 `json:"rawId"`
+		Type     string `json:"type"`
+		Response struct {
+			ClientDataJSON    Base64 `json:"clientDataJSON"`
+			AuthenticatorData Base64 `json:"authenticatorData"`
+			Signature         Base64 `json:"signature"`
+			UserHandle        Base64 `json:"userHandle,omitempty"`
+		} `json:"response"`
+	}
+
+	clientData struct {
+		Type        string `json:"type"`
+		Challenge   string `json:"challenge"`
+		Origin      string `json:"origin"`
+		CrossOrigin bool   `json:"crossOrigin,omitempty"`
+	}
+)
+
+const (
+	credentialType = "public-key"
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	challengeLength = 32
+
+	// 5 minutes, in milliseconds
+	ceremonyTimeout = 300000
+)
+
+var (
+	ErrInvalidResponse      = errors.New("invalid WebAuthn response")
+	ErrChallengeMismatch    = errors.New("challenge mismatch")
+	ErrOriginMismatch       = errors.New("origin mismatch")
+	ErrRelyingPartyMismatch = errors.New("relying party ID mismatch")
+	ErrUserNotPresent       = errors.New("user not present")
+	ErrUnknownCredential    = errors.New("unknown credential")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrSignCount            = errors.New("signature counter did not increase, authenticator might be cloned")
+)
+
+// NewRelyingParty configures relying party from the base URL of the auth server
+func NewRelyingParty(baseURL, name string) (*RelyingParty, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("can not determine relying party from %q", baseURL)
+	}
+
+	return &RelyingParty{
+		ID:     u.Hostname(),
+		Name:   name,
+		Origin: u.Scheme + "://" + u.Host,
+	}, nil
+}
+
+// CreationOptions prepares options for a new registration ceremony
+//
+// Credentials in exclude list are already registered and
+// authenticators holding them will refuse to register again
+func (rp RelyingParty) CreationOptions(user Entity, exclude ...[]byte) (*CreationOptions, error) {
+	c, err := challenge()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreationOptions{
+		Challenge: c,
+		RP:        RelyingPartyEntity{ID: rp.ID, Name: rp.Name},
+		User:      user,
+		PubKeyCredParams: []CredentialParameter{
+			{Type: credentialType, Alg: AlgES256},
+			{Type: credentialType, Alg: AlgRS256},
+		},
+		Timeout:                ceremonyTimeout,
+		ExcludeCredentials:     descriptors(exclude),
+		AuthenticatorSelection: AuthenticatorSelection{UserVerification: "discouraged"},
+		Attestation:            "none",
+	}, nil
+}
+
+// RequestOptions prepares options for a new assertion ceremony
+func (rp RelyingParty) RequestOptions(allow ...[]byte) (*RequestOptions, error) {
+	c, err := challenge()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestOptions{
+		Challenge:        c,
+		Timeout:          ceremonyTimeout,
+		RPID:             rp.ID,
+		AllowCredentials: descriptors(allow),
+		UserVerification: "discouraged",
+	}, nil
+}
+
+// VerifyRegistration verifies response of the registration ceremony
+// and returns the new credential
+func (rp RelyingParty) VerifyRegistration(challenge []byte, r *RegistrationResponse) (*Credential, error) {
+	if r == nil || r.Type != credentialType {
+		return nil, ErrInvalidResponse
+	}
+
+	if err := rp.verifyClientData(r.Response.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	v, _, err := cborDecode(r.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	att, _ := v.(map[interface{}]interface{})
+	raw, _ := att["authData"].([]byte)
+	if raw == nil {
+		return nil, fmt.Errorf("%w: missing authenticator data", ErrInvalidResponse)
+	}
+
+	ad, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = rp.verifyAuthenticatorData(ad); err != nil {
+		return nil, err
+	}
+
+	if ad.credential == nil {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrInvalidResponse)
+	}
+
+	return ad.credential, nil
+}
+
+// VerifyAssertion verifies response of the assertion ceremony
+// against the given credential
+//
+// On success, credential's signature counter is updated
+func (rp RelyingParty) VerifyAssertion(challenge []byte, r *AssertionResponse, c *Credential) error {
+	if r == nil || r.Type != credentialType {
+		return ErrInvalidResponse
+	}
+
+	if c == nil || string(r.RawID) != string(c.ID) {
+		return ErrUnknownCredential
+	}
+
+	if err := rp.verifyClientData(r.Response.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return err
+	}
+
+	ad, err := parseAuthenticatorData(r.Response.AuthenticatorData)
+	if err != nil {
+		return err
+	}
+
+	if err = rp.verifyAuthenticatorData(ad); err != nil {
+		return err
+	}
+
+	clientDataHash := sha256sum(r.Response.ClientDataJSON)
+	signed := append(append([]byte{}, r.Response.AuthenticatorData...), clientDataHash[:]...)
+	if err = verifySignature(c.PublicKey, signed, r.Response.Signature); err != nil {
+		return err
+	}
+
+	// authenticators that do not implement counter always send 0
+	if (ad.signCount != 0 || c.SignCount != 0) && ad.signCount <= c.SignCount {
+		return ErrSignCount
+	}
+
+	c.SignCount = ad.signCount
+	return nil
+}
+
+func (rp RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	cd := clientData{}
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	if cd.Type != ceremony {
+		return fmt.Errorf("%w: unexpected client data type %q", ErrInvalidResponse, cd.Type)
+	}
+
+	if c, err := decodeBase64(cd.Challenge); err != nil || len(challenge) == 0 || !constantTimeEqual(c, challenge) {
+		return ErrChallengeMismatch
+	}
+
+	if cd.Origin != rp.Origin {
+		return ErrOriginMismatch
+	}
+
+	return nil
+}
+
+func (rp RelyingParty) verifyAuthenticatorData(ad *authenticatorData) error {
+	if h := sha256sum([]byte(rp.ID)); !constantTimeEqual(ad.rpIDHash, h[:]) {
+		return ErrRelyingPartyMismatch
+	}
+
+	if !ad.userPresent() {
+		return ErrUserNotPresent
+	}
+
+	return nil
+}
+
+// ParseRegistrationResponse parses JSON encoded registration response
+func ParseRegistrationResponse(data []byte) (*RegistrationResponse, error) {
+	r := &RegistrationResponse{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	return r, nil
+}
+
+// ParseAssertionResponse parses JSON encoded assertion response
+func ParseAssertionResponse(data []byte) (*AssertionResponse, error) {
+	r := &AssertionResponse{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	return r, nil
+}
+
+func (b Base64) String() string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (b Base64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+func (b *Base64) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return
+	}
+
+	*b, err = decodeBase64(s)
+	return
+}
+
+// decodes base64 string, with or without padding and in both alphabets
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func descriptors(ids [][]byte) []CredentialDescriptor {
+	dd := make([]CredentialDescriptor, len(ids))
+	for i := range ids {
+		dd[i] = CredentialDescriptor{Type: credentialType, ID: ids[i]}
+	}
+
+	return dd
+}
+
+func challenge() ([]byte, error) {
+	c := make([]byte, challengeLength)
+	if _, err := rand.Read(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
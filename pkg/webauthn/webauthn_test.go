@@ -0,0 +1,157 @@
+package webauthn_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/internal/webauthntest"
+	. "github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCeremonies(t *testing.T) {
+	var (
+		rp = RelyingParty{ID: "corteza.example.tld", Name: "Corteza", Origin: "https://corteza.example.tld"}
+
+		register = func(t *testing.T, a *webauthntest.SoftAuthenticator) *Credential {
+			opt, err := rp.CreationOptions(Entity{ID: []byte{42}, Name: "user"})
+			require.NoError(t, err)
+
+			r, err := a.Create(opt)
+			require.NoError(t, err)
+
+			c, err := rp.VerifyRegistration(opt.Challenge, r)
+			require.NoError(t, err)
+			return c
+		}
+
+		assert = func(t *testing.T, a *webauthntest.SoftAuthenticator, c *Credential) ([]byte, *AssertionResponse) {
+			opt, err := rp.RequestOptions(c.ID)
+			require.NoError(t, err)
+
+			r, err := a.Get(opt)
+			require.NoError(t, err)
+			return opt.Challenge, r
+		}
+	)
+
+	t.Run("registration and assertion", func(t *testing.T) {
+		req := require.New(t)
+
+		a, err := webauthntest.NewSoftAuthenticator(rp.Origin)
+		req.NoError(err)
+
+		c := register(t, a)
+		req.Equal(a.CredentialID(), []byte(c.ID))
+		req.Equal(uint32(0), c.SignCount)
+
+		// response survives JSON serialization (as sent from the browser)
+		challenge, r := assert(t, a, c)
+		enc, err := json.Marshal(r)
+		req.NoError(err)
+		r, err = ParseAssertionResponse(enc)
+		req.NoError(err)
+
+		req.NoError(rp.VerifyAssertion(challenge, r, c))
+		req.Equal(uint32(1), c.SignCount)
+
+		// replay is detected by the counter
+		req.True(errors.Is(rp.VerifyAssertion(challenge, r, c), ErrSignCount))
+	})
+
+	t.Run("RS256 key", func(t *testing.T) {
+		req := require.New(t)
+
+		a, err := webauthntest.NewSoftRSAAuthenticator(rp.Origin)
+		req.NoError(err)
+
+		c := register(t, a)
+		challenge, r := assert(t, a, c)
+		req.NoError(rp.VerifyAssertion(challenge, r, c))
+
+		// signature over different data
+		challenge, r = assert(t, a, c)
+		r.Response.AuthenticatorData[36]++
+		req.True(errors.Is(rp.VerifyAssertion(challenge, r, c), ErrInvalidSignature))
+	})
+
+	t.Run("registration mismatches", func(t *testing.T) {
+		req := require.New(t)
+
+		a, err := webauthntest.NewSoftAuthenticator(rp.Origin)
+		req.NoError(err)
+
+		opt, err := rp.CreationOptions(Entity{ID: []byte{42}, Name: "user"})
+		req.NoError(err)
+
+		r, err := a.Create(opt)
+		req.NoError(err)
+
+		_, err = rp.VerifyRegistration([]byte("other challenge"), r)
+		req.True(errors.Is(err, ErrChallengeMismatch))
+
+		other := rp
+		other.Origin = "https://evil.tld"
+		_, err = other.VerifyRegistration(opt.Challenge, r)
+		req.True(errors.Is(err, ErrOriginMismatch))
+
+		other = rp
+		other.ID = "evil.tld"
+		_, err = other.VerifyRegistration(opt.Challenge, r)
+		req.True(errors.Is(err, ErrRelyingPartyMismatch))
+
+		// already registered credentials are excluded
+		opt, err = rp.CreationOptions(Entity{ID: []byte{42}, Name: "user"}, a.CredentialID())
+		req.NoError(err)
+		_, err = a.Create(opt)
+		req.Error(err)
+	})
+
+	t.Run("assertion mismatches", func(t *testing.T) {
+		req := require.New(t)
+
+		a, err := webauthntest.NewSoftAuthenticator(rp.Origin)
+		req.NoError(err)
+		b, err := webauthntest.NewSoftAuthenticator(rp.Origin)
+		req.NoError(err)
+
+		ca, cb := register(t, a), register(t, b)
+
+		// assertion of a different credential
+		challenge, r := assert(t, a, ca)
+		req.True(errors.Is(rp.VerifyAssertion(challenge, r, cb), ErrUnknownCredential))
+
+		// public key of a different credential
+		cb.ID = ca.ID
+		req.True(errors.Is(rp.VerifyAssertion(challenge, r, cb), ErrInvalidSignature))
+
+		// tampered authenticator data
+		challenge, r = assert(t, a, ca)
+		r.Response.AuthenticatorData[36]++
+		req.True(errors.Is(rp.VerifyAssertion(challenge, r, ca), ErrInvalidSignature))
+
+		challenge, r = assert(t, a, ca)
+		req.True(errors.Is(rp.VerifyAssertion(challenge[1:], r, ca), ErrChallengeMismatch))
+
+		// registration response can not be used for assertion
+		opt, err := rp.CreationOptions(Entity{ID: []byte{42}, Name: "user"})
+		req.NoError(err)
+		reg, err := b.Create(opt)
+		req.NoError(err)
+		r.Response.ClientDataJSON = reg.Response.ClientDataJSON
+		req.True(errors.Is(rp.VerifyAssertion(opt.Challenge, r, ca), ErrInvalidResponse))
+	})
+}
+
+func TestNewRelyingParty(t *testing.T) {
+	req := require.New(t)
+
+	rp, err := NewRelyingParty("https://corteza.example.tld:8443/auth", "Corteza")
+	req.NoError(err)
+	req.Equal("corteza.example.tld", rp.ID)
+	req.Equal("https://corteza.example.tld:8443", rp.Origin)
+
+	_, err = NewRelyingParty("/auth", "Corteza")
+	req.Error(err)
+}
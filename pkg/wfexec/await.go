@@ -0,0 +1,53 @@
+package wfexec
+
+import (
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"time"
+)
+
+type (
+	awaiting struct {
+		// reference; what kind of signal state is waiting for
+		ref string
+
+		// signal details (for example event type and constraints)
+		// meaning of the payload is defined by the step and whoever resumes the state
+		payload *expr.Vars
+
+		// when set, state is resumed with timeout flag
+		// on the input if signal is not received in time
+		timeoutAt *time.Time
+
+		// state to be resumed
+		state *State
+	}
+
+	PendingAwait struct {
+		Ref       string     `json:"ref"`
+		SessionID uint64     `json:"sessionID,string"`
+		CreatedAt time.Time  `json:"createdAt"`
+		StateID   uint64     `json:"stateID,string"`
+		Payload   *expr.Vars `json:"payload"`
+		TimeoutAt *time.Time `json:"timeoutAt,omitempty"`
+	}
+)
+
+// Await suspends the state until it is resumed with ResumeAwaiting or
+// until timeout
+func Await(ref string, payload *expr.Vars, timeoutAt *time.Time) *awaiting {
+	return &awaiting{ref: ref, payload: payload, timeoutAt: timeoutAt}
+}
+
+func (a *awaiting) toPending() *PendingAwait {
+	return &PendingAwait{
+		Ref:       a.ref,
+		CreatedAt: a.state.created,
+		StateID:   a.state.stateId,
+		Payload:   a.payload,
+		TimeoutAt: a.timeoutAt,
+	}
+}
+
+func (a *awaiting) timedOut(t time.Time) bool {
+	return a.timeoutAt != nil && !a.timeoutAt.After(t)
+}
@@ -2,6 +2,7 @@ package wfexec
 
 import (
 	"context"
+	"time"
 )
 
 type (
@@ -11,6 +12,12 @@ type (
 		children map[Step][]Step
 		parents  map[Step][]Step
 		index    map[uint64]Step
+
+		// max duration of step execution
+		timeouts map[Step]time.Duration
+
+		// how failed step execution is retried
+		retries map[Step]*RetryPolicy
 	}
 )
 
@@ -20,6 +27,8 @@ func NewGraph() *Graph {
 		children: make(map[Step][]Step),
 		parents:  make(map[Step][]Step),
 		index:    make(map[uint64]Step),
+		timeouts: make(map[Step]time.Duration),
+		retries:  make(map[Step]*RetryPolicy),
 	}
 
 	return wf
@@ -47,6 +56,38 @@ func (g *Graph) StepByID(ID uint64) Step {
 	return g.index[ID]
 }
 
+// SetStepTimeout limits duration of step execution
+//
+// Zero value removes the limit
+func (g *Graph) SetStepTimeout(s Step, d time.Duration) {
+	if d > 0 {
+		g.timeouts[s] = d
+	} else {
+		delete(g.timeouts, s)
+	}
+}
+
+// StepTimeout returns max duration of step execution
+func (g *Graph) StepTimeout(s Step) time.Duration {
+	return g.timeouts[s]
+}
+
+// SetStepRetry sets retry policy for the step
+//
+// Nil value removes the policy
+func (g *Graph) SetStepRetry(s Step, p *RetryPolicy) {
+	if p != nil {
+		g.retries[s] = p
+	} else {
+		delete(g.retries, s)
+	}
+}
+
+// StepRetry returns retry policy of the step
+func (g *Graph) StepRetry(s Step) *RetryPolicy {
+	return g.retries[s]
+}
+
 func (g *Graph) AddParent(c, p Step) {
 	g.parents[c] = append(g.parents[c], p)
 	g.children[p] = append(g.children[p], c)
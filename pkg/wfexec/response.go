@@ -1,6 +1,7 @@
 package wfexec
 
 import (
+	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"time"
 )
 
@@ -20,11 +21,22 @@ type (
 
 		// state to be resumed
 		state *State
+
+		// state is re-executed after failure
+		// (resumed without setting the input)
+		retry bool
 	}
 
 	// when session is resumed from a delay we'll replace
 	// delay step on state with the a generic step that will return resumed{}
 	resumed struct{}
+
+	// results with explicitly selected step to continue with
+	// (one of step's children)
+	continued struct {
+		next    Step
+		results *expr.Vars
+	}
 )
 
 func Delay(until time.Time) *delayed {
@@ -35,6 +47,10 @@ func Resume() *resumed {
 	return &resumed{}
 }
 
+func ContinueWith(next Step, results *expr.Vars) *continued {
+	return &continued{next: next, results: results}
+}
+
 func ErrorHandler(h Step) *errHandler {
 	return &errHandler{handler: h}
 }
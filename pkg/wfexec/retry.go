@@ -0,0 +1,161 @@
+package wfexec
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+type (
+	// RetryPolicy configures how failed step execution is retried
+	//
+	// State of the failed step is delayed and re-executed with the same
+	// scope and input when the time comes. Delayed retries are suspended
+	// and restored like delayed states
+	RetryPolicy struct {
+		// max number of attempts (including the first one)
+		MaxAttempts int
+
+		// delay before the first retry
+		Delay time.Duration
+
+		// max delay between two attempts; maxRetryDelay when zero
+		MaxDelay time.Duration
+
+		// how delay grows with each attempt
+		Backoff Backoff
+
+		// random portion (0-1) of the delay that is added to it
+		Jitter float64
+
+		// kinds of errors (see ErrorKind) that are retried
+		//
+		// all errors, except session timeouts, are retried when empty
+		ErrorKinds []string
+	}
+
+	Backoff string
+)
+
+const (
+	BackoffConstant    Backoff = "constant"
+	BackoffLinear      Backoff = "linear"
+	BackoffExponential Backoff = "exponential"
+)
+
+const (
+	// upper limit of the delay between two attempts
+	// applies even when policy's max delay is not set
+	maxRetryDelay = time.Hour * 24 * 7
+)
+
+var (
+	// wrapper around rand.Float64() that will aid testing
+	jitter = rand.Float64
+)
+
+// Valid returns true for known backoff strategies (or empty value, constant)
+func (b Backoff) Valid() bool {
+	switch b {
+	case "", BackoffConstant, BackoffLinear, BackoffExponential:
+		return true
+	}
+
+	return false
+}
+
+// Retryable returns true when step can be retried after given attempt failed with the error
+func (p *RetryPolicy) Retryable(attempt int, err error) bool {
+	if p == nil || err == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	kind := ErrorKind(err)
+
+	if len(p.ErrorKinds) == 0 {
+		return !errors.Is(err, ErrSessionTimeout)
+	}
+
+	for _, k := range p.ErrorKinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Wait returns duration of the delay before next attempt
+//
+// Delay is computed in floating point to avoid overflows with large attempt
+// numbers; jitter is added before the delay is capped to max delay
+// (or maxRetryDelay when not set)
+func (p *RetryPolicy) Wait(attempt int) time.Duration {
+	var (
+		d   float64
+		max = maxRetryDelay
+	)
+
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	if p.MaxDelay > 0 && p.MaxDelay < max {
+		max = p.MaxDelay
+	}
+
+	switch p.Backoff {
+	case BackoffLinear:
+		d = float64(p.Delay) * float64(attempt)
+	case BackoffExponential:
+		d = float64(p.Delay) * math.Pow(2, float64(attempt-1))
+	default:
+		d = float64(p.Delay)
+	}
+
+	if d <= 0 || math.IsNaN(d) {
+		return 0
+	}
+
+	if p.Jitter > 0 {
+		d += d * math.Min(p.Jitter, 1) * jitter()
+	}
+
+	if d > float64(max) {
+		d = float64(max)
+	}
+
+	return time.Duration(d)
+}
+
+// scheduleRetry delays re-execution of the failed state when step's retry policy allows it
+//
+// Returns false when state is not retried
+func (s *Session) scheduleRetry(st *State) bool {
+	var (
+		p       = s.g.StepRetry(st.step)
+		attempt = st.attempt
+	)
+
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	if !p.Retryable(attempt, st.err) {
+		return false
+	}
+
+	rst := st.retry()
+
+	defer s.mux.Unlock()
+	s.mux.Lock()
+
+	s.delayed[rst.stateId] = &delayed{
+		resumeAt: now().Add(p.Wait(attempt)),
+		state:    rst,
+		retry:    true,
+	}
+
+	return true
+}
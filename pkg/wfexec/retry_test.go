@@ -0,0 +1,187 @@
+package wfexec
+
+import (
+	"context"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/stretchr/testify/require"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Wait(t *testing.T) {
+	defer func(fn func() float64) { jitter = fn }(jitter)
+	jitter = func() float64 { return 0.5 }
+
+	tcc := []struct {
+		name    string
+		p       RetryPolicy
+		attempt int
+		exp     time.Duration
+	}{
+		{"constant", RetryPolicy{Delay: time.Second}, 3, time.Second},
+		{"linear", RetryPolicy{Delay: time.Second, Backoff: BackoffLinear}, 3, time.Second * 3},
+		{"exponential", RetryPolicy{Delay: time.Second, Backoff: BackoffExponential}, 4, time.Second * 8},
+		{"max delay", RetryPolicy{Delay: time.Second, Backoff: BackoffExponential, MaxDelay: time.Second * 5}, 4, time.Second * 5},
+		{"jitter", RetryPolicy{Delay: time.Second, Jitter: 0.5}, 1, time.Second + time.Millisecond*250},
+		{"exponential overflow", RetryPolicy{Delay: time.Second, Backoff: BackoffExponential}, 100, maxRetryDelay},
+		{"exponential overflow with max delay", RetryPolicy{Delay: time.Second, Backoff: BackoffExponential, MaxDelay: time.Minute}, 1000, time.Minute},
+		{"linear overflow", RetryPolicy{Delay: time.Hour, Backoff: BackoffLinear}, math.MaxInt64, maxRetryDelay},
+		{"max delay over the limit", RetryPolicy{Delay: time.Hour * 24 * 365, MaxDelay: time.Hour * 24 * 365}, 1, maxRetryDelay},
+		{"negative delay", RetryPolicy{Delay: -time.Second, Backoff: BackoffExponential}, 3, 0},
+		{"capped with jitter", RetryPolicy{Delay: time.Second, Backoff: BackoffExponential, MaxDelay: time.Second * 4, Jitter: 0.5}, 64, time.Second * 4},
+		{"jitter over max delay", RetryPolicy{Delay: time.Second * 3, MaxDelay: time.Second * 4, Jitter: 1}, 1, time.Second * 4},
+		{"jitter under max delay", RetryPolicy{Delay: time.Second * 2, MaxDelay: time.Second * 4, Jitter: 0.5}, 1, time.Millisecond * 2500},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, tc.p.Wait(tc.attempt))
+		})
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	var (
+		req = require.New(t)
+		p   = &RetryPolicy{MaxAttempts: 3}
+	)
+
+	req.True(p.Retryable(1, fmt.Errorf("failed")))
+	req.True(p.Retryable(2, fmt.Errorf("failed")))
+	req.False(p.Retryable(3, fmt.Errorf("failed")))
+	req.False(p.Retryable(1, nil))
+	req.False(p.Retryable(1, fmt.Errorf("%w", ErrSessionTimeout)))
+
+	p.ErrorKinds = []string{ErrorKindTimeout}
+	req.True(p.Retryable(1, fmt.Errorf("%w", ErrStepTimeout)))
+	req.False(p.Retryable(1, errors.Automation("failed")))
+
+	req.False((*RetryPolicy)(nil).Retryable(1, fmt.Errorf("failed")))
+}
+
+func TestSession_Retry(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		calls int
+
+		mux    sync.Mutex
+		frames []*Frame
+
+		flaky = &sesTestStep{name: "flaky", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if calls++; calls < 3 {
+				return nil, fmt.Errorf("unavailable")
+			}
+
+			return expr.RVars{"calls": expr.Must(expr.NewInteger(calls))}.Vars(), nil
+		}}
+	)
+
+	wf.AddStep(flaky)
+	wf.SetStepRetry(flaky, &RetryPolicy{MaxAttempts: 3, Delay: time.Millisecond})
+
+	ses := NewSession(ctx, wf,
+		SetWorkerInterval(time.Millisecond),
+		SetHandler(func(_ SessionStatus, st *State, _ *Session) {
+			mux.Lock()
+			defer mux.Unlock()
+			frames = append(frames, st.MakeFrame())
+		}),
+	)
+
+	req.NoError(ses.Exec(ctx, flaky, nil))
+	req.NoError(ses.WaitUntil(ctx, SessionCompleted, SessionFailed))
+	req.NoError(ses.Error())
+	req.Equal(int64(3), expr.Must(expr.Select(ses.Result(), "calls")).Get())
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	var attempts, failed []int
+	for _, f := range frames {
+		if f.Error != "" {
+			failed = append(failed, f.Attempt)
+		}
+
+		attempts = append(attempts, f.Attempt)
+	}
+
+	req.Contains(attempts, 3)
+	req.Equal([]int{0, 2}, failed)
+}
+
+func TestSession_RetryExhausted(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		calls int
+
+		failing = &sesTestStep{name: "failing", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			calls++
+			return nil, fmt.Errorf("unavailable")
+		}}
+	)
+
+	wf.AddStep(failing)
+	wf.SetStepRetry(failing, &RetryPolicy{MaxAttempts: 2, Delay: time.Millisecond})
+
+	ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+	req.NoError(ses.Exec(ctx, failing, nil))
+	req.Error(ses.WaitUntil(ctx, SessionCompleted, SessionFailed))
+	req.Equal(2, calls)
+}
+
+func TestSession_RetrySuspendAndRestore(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		fail = true
+
+		flaky = &sesTestStep{name: "flaky", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if fail {
+				return nil, fmt.Errorf("unavailable")
+			}
+
+			return expr.RVars{"done": expr.Must(expr.NewBoolean(true))}.Vars(), nil
+		}}
+
+		suspended []*SuspendedState
+	)
+
+	flaky.SetID(1)
+	wf.AddStep(flaky)
+	wf.SetStepRetry(flaky, &RetryPolicy{MaxAttempts: 3, Delay: time.Hour})
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+		req.NoError(ses.Exec(ctx, flaky, nil))
+		req.NoError(ses.WaitUntil(ctx, SessionDelayed))
+
+		suspended = ses.SuspendedStates()
+		req.Len(suspended, 1)
+		req.True(suspended[0].Retry)
+		req.Equal(2, suspended[0].Attempt)
+	}
+
+	{
+		fail = false
+		past := now().Add(-time.Second)
+		suspended[0].ResumeAt = &past
+
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+		req.NoError(ses.Restore(suspended...))
+		req.NoError(ses.WaitUntil(ctx, SessionCompleted, SessionFailed))
+		req.Equal(true, expr.Must(expr.Select(ses.Result(), "done")).Get())
+		req.False(ses.Result().Has("resumed"))
+	}
+}
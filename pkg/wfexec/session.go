@@ -38,6 +38,9 @@ type (
 		// prompted
 		prompted map[uint64]*prompted
 
+		// awaiting (waiting for an external signal)
+		awaiting map[uint64]*awaiting
+
 		// how often we check for delayed states and how often idle stat is checked in Wait()
 		workerInterval time.Duration
 
@@ -58,6 +61,24 @@ type (
 		log *zap.Logger
 
 		eventHandler StateChangeHandler
+
+		// max duration of the session and the time when it times out
+		timeout  time.Duration
+		deadline time.Time
+
+		// default max duration of step execution
+		// (used for steps without a timeout set on graph)
+		stepTimeout time.Duration
+
+		// max number of concurrently executed states
+		concurrency int
+
+		// cancels session's context (and all running steps)
+		cancel context.CancelFunc
+
+		// number of step executions that did not stop after timeout
+		// and are still running in the background
+		abandoned int64
 	}
 
 	StateChangeHandler func(SessionStatus, *State, *Session)
@@ -73,6 +94,11 @@ type (
 		ParentID  uint64        `json:"parentID"`
 		StepID    uint64        `json:"stepID"`
 		LeadTime  time.Duration `json:"leadTime"`
+
+		// number of the execution attempt and error of the
+		// failed attempt; set when step is retried
+		Attempt int    `json:"attempt,omitempty"`
+		Error   string `json:"error,omitempty"`
 	}
 
 	// ExecRequest is passed to Exec() functions and contains all information
@@ -145,9 +171,9 @@ func NewSession(ctx context.Context, g *Graph, oo ...sessionOpt) *Session {
 		started:  *now(),
 		qState:   make(chan *State, sessionStateChanBuf),
 		qErr:     make(chan error, 1),
-		execLock: make(chan struct{}, sessionConcurrentExec),
 		delayed:  make(map[uint64]*delayed),
 		prompted: make(map[uint64]*prompted),
+		awaiting: make(map[uint64]*awaiting),
 
 		//workerInterval: time.Millisecond,
 		workerInterval: time.Millisecond * 250, // debug mode rate
@@ -155,6 +181,8 @@ func NewSession(ctx context.Context, g *Graph, oo ...sessionOpt) *Session {
 
 		mux: &sync.RWMutex{},
 
+		concurrency: sessionConcurrentExec,
+
 		log: zap.NewNop(),
 		eventHandler: func(SessionStatus, *State, *Session) {
 			// noop
@@ -165,10 +193,17 @@ func NewSession(ctx context.Context, g *Graph, oo ...sessionOpt) *Session {
 		o(s)
 	}
 
+	s.execLock = make(chan struct{}, s.concurrency)
+
+	if s.timeout > 0 {
+		s.deadline = s.started.Add(s.timeout)
+	}
+
 	s.log = s.log.
 		WithOptions(zap.AddStacktrace(zap.ErrorLevel)).
 		With(zap.Uint64("sessionID", s.id))
 
+	ctx, s.cancel = context.WithCancel(ctx)
 	go s.worker(ctx)
 
 	return s
@@ -185,7 +220,7 @@ func (s Session) Status() SessionStatus {
 	case len(s.prompted) > 0:
 		return SessionPrompted
 
-	case len(s.delayed) > 0:
+	case len(s.delayed) > 0, len(s.awaiting) > 0:
 		return SessionDelayed
 
 	case s.result == nil:
@@ -217,6 +252,19 @@ func (s *Session) Result() *expr.Vars {
 	return s.result
 }
 
+// Cancel stops the session and all running steps
+//
+// Context passed to steps is canceled; session fails with ErrCanceled
+func (s *Session) Cancel() {
+	s.mux.Lock()
+	if s.err == nil && s.result == nil {
+		s.err = ErrCanceled
+	}
+	s.mux.Unlock()
+
+	s.cancel()
+}
+
 func (s *Session) Exec(ctx context.Context, step Step, scope *expr.Vars) error {
 	if s.g.Len() == 0 {
 		return fmt.Errorf("refusing to execute without steps")
@@ -282,6 +330,161 @@ func (s *Session) Resume(ctx context.Context, stateId uint64, input *expr.Vars)
 	return s.enqueue(ctx, p.state)
 }
 
+// PendingAwaits returns all states that are waiting for an external signal
+func (s *Session) PendingAwaits() (out []*PendingAwait) {
+	defer s.mux.RUnlock()
+	s.mux.RLock()
+
+	out = make([]*PendingAwait, 0, len(s.awaiting))
+
+	for _, a := range s.awaiting {
+		pending := a.toPending()
+		pending.SessionID = s.id
+		out = append(out, pending)
+	}
+
+	return
+}
+
+// ResumeAwaiting resumes state that is waiting for an external signal
+//
+// Unlike Resume, it does not check the state owner; it's up
+// to the caller to verify that the signal matches the awaited one
+func (s *Session) ResumeAwaiting(ctx context.Context, stateId uint64, input *expr.Vars) error {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+
+	a, has := s.awaiting[stateId]
+	if !has {
+		return fmt.Errorf("unexisting state")
+	}
+
+	delete(s.awaiting, stateId)
+
+	if input == nil {
+		input = &expr.Vars{}
+	}
+
+	_ = input.AssignFieldValue("resumed", expr.Must(expr.NewBoolean(true)))
+	a.state.input = input
+
+	return s.enqueue(ctx, a.state)
+}
+
+// StateSuspended reports if state is delayed, prompted or awaiting
+//
+// States that are waiting to be retried are not considered suspended
+func (s *Session) StateSuspended(stateId uint64) bool {
+	defer s.mux.RUnlock()
+	s.mux.RLock()
+
+	if d, has := s.delayed[stateId]; has {
+		return !d.retry
+	}
+
+	_, prompted := s.prompted[stateId]
+	_, awaiting := s.awaiting[stateId]
+	return prompted || awaiting
+}
+
+// SuspendedStates returns all delayed and prompted states
+//
+// States that can not be suspended (ones inside loops) are omitted
+func (s *Session) SuspendedStates() (out []*SuspendedState) {
+	defer s.mux.RUnlock()
+	s.mux.RLock()
+
+	out = make([]*SuspendedState, 0, len(s.delayed)+len(s.prompted)+len(s.awaiting))
+
+	for _, d := range s.delayed {
+		ss := d.state.suspend()
+		if ss == nil {
+			s.log.Warn("delayed state can not be suspended", zap.Uint64("stateID", d.state.stateId))
+			continue
+		}
+
+		resumeAt := d.resumeAt
+		ss.ResumeAt = &resumeAt
+		ss.Retry = d.retry
+		out = append(out, ss)
+	}
+
+	for _, p := range s.prompted {
+		ss := p.state.suspend()
+		if ss == nil {
+			s.log.Warn("prompted state can not be suspended", zap.Uint64("stateID", p.state.stateId))
+			continue
+		}
+
+		ss.Prompted = true
+		ss.PromptRef = p.ref
+		ss.PromptPayload = p.payload
+		out = append(out, ss)
+	}
+
+	for _, a := range s.awaiting {
+		ss := a.state.suspend()
+		if ss == nil {
+			s.log.Warn("awaiting state can not be suspended", zap.Uint64("stateID", a.state.stateId))
+			continue
+		}
+
+		ss.ResumeAt = a.timeoutAt
+		ss.Awaiting = true
+		ss.AwaitRef = a.ref
+		ss.AwaitPayload = a.payload
+		out = append(out, ss)
+	}
+
+	return
+}
+
+// Restore adds suspended states back to the session
+//
+// Delayed states are resumed by the session worker when the time comes,
+// prompted states wait for the input as before
+func (s *Session) Restore(ss ...*SuspendedState) error {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+
+	for _, sus := range ss {
+		st, err := restoreState(s, sus)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case sus.Prompted:
+			s.prompted[st.stateId] = &prompted{
+				payload: sus.PromptPayload,
+				ownerId: sus.OwnerID,
+				state:   st,
+				ref:     sus.PromptRef,
+			}
+
+		case sus.Awaiting:
+			s.awaiting[st.stateId] = &awaiting{
+				ref:       sus.AwaitRef,
+				payload:   sus.AwaitPayload,
+				timeoutAt: sus.ResumeAt,
+				state:     st,
+			}
+
+		case sus.ResumeAt != nil:
+			s.delayed[st.stateId] = &delayed{
+				resumeAt: *sus.ResumeAt,
+				state:    st,
+				retry:    sus.Retry,
+			}
+
+		default:
+			return fmt.Errorf("can not restore state %d, state is neither delayed, prompted nor awaiting", sus.StateID)
+		}
+	}
+
+	return nil
+}
+
 func (s *Session) enqueue(ctx context.Context, st *State) error {
 	if st == nil {
 		return fmt.Errorf("state is nil")
@@ -393,6 +596,9 @@ func (s *Session) worker(ctx context.Context) {
 			s.execLock <- struct{}{}
 
 			go func() {
+				// states are executed more than once
+				// when session is resumed
+				st.completed = nil
 				s.exec(ctx, st)
 				st.completed = now()
 
@@ -442,6 +648,12 @@ func (s *Session) queueScheduledSuspended() {
 
 		delete(s.delayed, id)
 
+		if sus.retry {
+			// failed state is re-executed as it was
+			s.qState <- sus.state
+			continue
+		}
+
 		// Set state input when step is resumed
 		sus.state.input = expr.RVars{
 			"resumed":  expr.Must(expr.NewBoolean(true)),
@@ -449,6 +661,21 @@ func (s *Session) queueScheduledSuspended() {
 		}.Vars()
 		s.qState <- sus.state
 	}
+
+	for id, a := range s.awaiting {
+		if !a.timedOut(*now()) {
+			continue
+		}
+
+		delete(s.awaiting, id)
+
+		// Set state input when step is resumed after timeout
+		a.state.input = expr.RVars{
+			"resumed": expr.Must(expr.NewBoolean(true)),
+			"timeout": expr.Must(expr.NewBoolean(true)),
+		}.Vars()
+		s.qState <- a.state
+	}
 }
 
 // executes single step, resolves response and schedule following steps for execution
@@ -500,7 +727,7 @@ func (s *Session) exec(ctx context.Context, st *State) {
 			// that is set to step exec function
 			ctxWithIdentity := auth.SetIdentityToContext(ctx, st.owner)
 
-			result, st.err = st.step.Exec(ctxWithIdentity, st.MakeRequest())
+			result, st.err = s.execStep(ctxWithIdentity, st)
 
 			if iterator, isIterator := result.(Iterator); isIterator && st.err == nil {
 				// Exec fn returned an iterator, adding loop to stack
@@ -511,6 +738,21 @@ func (s *Session) exec(ctx context.Context, st *State) {
 			}
 		}
 
+		if st.err != nil && ctx.Err() != nil {
+			// session was canceled, nothing to handle or report
+			log.Debug("step execution canceled", zap.Error(st.err))
+			return
+		}
+
+		if st.err != nil && s.scheduleRetry(st) {
+			log.Warn("step execution failed, retrying",
+				zap.Int("attempt", st.attempt),
+				zap.Error(st.err),
+			)
+
+			return
+		}
+
 		if st.err != nil {
 			if st.errHandler != nil {
 				// handling error with error handling
@@ -521,6 +763,13 @@ func (s *Session) exec(ctx context.Context, st *State) {
 				)
 
 				_ = expr.Assign(scope, "error", expr.Must(expr.NewString(st.err.Error())))
+				_ = expr.Assign(scope, "errorKind", expr.Must(expr.NewString(ErrorKind(st.err))))
+
+				if errors.Is(st.err, ErrSessionTimeout) {
+					// session timeout is handled, let the
+					// error handling branch complete
+					s.liftDeadline()
+				}
 
 				// copy error handler & disable it on state to prevent inf. loop
 				// in case of another error in the error-handling branch
@@ -576,6 +825,17 @@ func (s *Session) exec(ctx context.Context, st *State) {
 				return nil
 			})
 
+		case *continued:
+			// results are merged into scope and session continues
+			// with the selected step (when set) instead of all child steps
+			if result.results != nil {
+				scope = scope.Merge(result.results)
+			}
+
+			if result.next != nil {
+				next = Steps{result.next}
+			}
+
 		case *errHandler:
 			// this step sets error handling step on current state
 			// and continues on the current path
@@ -617,8 +877,14 @@ func (s *Session) exec(ctx context.Context, st *State) {
 
 		case *termination:
 			// terminate all activities, all delayed tasks and exit right away
-			log.Debug("termination", zap.Int("delayed", len(s.delayed)))
-			s.delayed = nil
+			log.Debug("termination", zap.Int("delayed", len(s.delayed)), zap.Int("awaiting", len(s.awaiting)))
+
+			// maps are reset (not nil-ed) since states of concurrently
+			// executed branches might still be registered
+			s.mux.Lock()
+			s.delayed = make(map[uint64]*delayed)
+			s.awaiting = make(map[uint64]*awaiting)
+			s.mux.Unlock()
 			s.qState <- FinalState(s, scope)
 			return
 
@@ -631,6 +897,15 @@ func (s *Session) exec(ctx context.Context, st *State) {
 			s.mux.Unlock()
 			return
 
+		case *awaiting:
+			log.Debug("session awaiting", zap.String("ref", result.ref))
+
+			result.state = st
+			s.mux.Lock()
+			s.awaiting[st.stateId] = result
+			s.mux.Unlock()
+			return
+
 		case *resumed:
 			log.Debug("session resumed")
 
@@ -706,6 +981,61 @@ func SetWorkerInterval(i time.Duration) sessionOpt {
 	}
 }
 
+// SetSessionID overrides generated session ID
+//
+// Used when session is restored; zero value is ignored
+func SetSessionID(ID uint64) sessionOpt {
+	return func(s *Session) {
+		if ID > 0 {
+			s.id = ID
+		}
+	}
+}
+
+// SetTimeout limits duration of the session
+//
+// Steps executed after session timed out fail with ErrSessionTimeout.
+// Timeout is counted from the time session was started (see SetStarted).
+// Zero value (default) means no limit
+func SetTimeout(d time.Duration) sessionOpt {
+	return func(s *Session) {
+		s.timeout = d
+	}
+}
+
+// SetStarted overrides the time session was started
+//
+// Used when session is restored so that session timeout is counted from
+// the original start and not from the time it was restored; zero value is ignored
+func SetStarted(t time.Time) sessionOpt {
+	return func(s *Session) {
+		if !t.IsZero() {
+			s.started = t
+		}
+	}
+}
+
+// SetStepTimeout sets default limit for duration of step execution
+//
+// Used for steps without a timeout set on the graph.
+// Zero value (default) means no limit
+func SetStepTimeout(d time.Duration) sessionOpt {
+	return func(s *Session) {
+		s.stepTimeout = d
+	}
+}
+
+// SetConcurrency limits number of concurrently executed states (fork branches)
+//
+// Zero value is ignored
+func SetConcurrency(n int) sessionOpt {
+	return func(s *Session) {
+		if n > 0 {
+			s.concurrency = n
+		}
+	}
+}
+
 func SetHandler(fn StateChangeHandler) sessionOpt {
 	return func(s *Session) {
 		s.eventHandler = fn
@@ -2,6 +2,8 @@ package wfexec
 
 import (
 	"context"
+	"errors"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -184,6 +186,323 @@ func TestSession_Delays(t *testing.T) {
 
 }
 
+func TestSession_SuspendAndRestore(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		owner = auth.NewIdentity(42, 1, 2)
+
+		start        = &sesTestStep{name: "start"}
+		waitForInput = &sesTestStep{name: "waitForInput", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if r.Input == nil {
+				return Prompt(auth.GetIdentityFromContext(ctx).Identity(), "ref", nil), nil
+			}
+
+			return r.Input, nil
+		}}
+
+		suspended []*SuspendedState
+	)
+
+	start.SetID(1)
+	waitForInput.SetID(2)
+
+	wf.AddStep(start, waitForInput)
+	wf.AddStep(waitForInput)
+
+	{
+		ses := NewSession(ctx, wf)
+		req.NoError(ses.Exec(auth.SetIdentityToContext(ctx, owner), start, nil))
+		req.NoError(ses.WaitUntil(ctx, SessionPrompted))
+
+		suspended = ses.SuspendedStates()
+		req.Len(suspended, 1)
+		req.True(suspended[0].Prompted)
+		req.Equal("ref", suspended[0].PromptRef)
+		req.Equal(uint64(2), suspended[0].StepID)
+		req.Equal(uint64(1), suspended[0].ParentID)
+		req.Equal(owner.Identity(), suspended[0].OwnerID)
+		req.Equal(owner.Roles(), suspended[0].OwnerRoles)
+	}
+
+	{
+		// restore on a new session and resume
+		ses := NewSession(ctx, wf, SetSessionID(1234))
+		req.Equal(uint64(1234), ses.ID())
+
+		req.NoError(ses.Restore(suspended...))
+		req.Equal(SessionPrompted, ses.Status())
+		req.Len(ses.PendingPrompts(owner.Identity()), 1)
+
+		input := expr.RVars{"input": expr.Must(expr.NewString("foo"))}.Vars()
+		req.NoError(ses.Resume(auth.SetIdentityToContext(ctx, owner), suspended[0].StateID, input))
+		req.NoError(ses.WaitUntil(ctx, SessionCompleted))
+		req.Equal("foo", expr.Must(expr.Select(ses.Result(), "input")).Get())
+	}
+
+	{
+		// restore must fail when step can not be found in the graph
+		ses := NewSession(ctx, NewGraph())
+		req.Error(ses.Restore(suspended...))
+	}
+}
+
+func TestSession_Await(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		owner = auth.NewIdentity(42)
+
+		timeout time.Duration
+
+		start    = &sesTestStep{name: "start"}
+		awaitSig = &sesTestStep{name: "awaitSig", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if r.Input == nil {
+				var timeoutAt *time.Time
+				if timeout > 0 {
+					at := now().Add(timeout)
+					timeoutAt = &at
+				}
+
+				return Await("ref", expr.RVars{"foo": expr.Must(expr.NewString("bar"))}.Vars(), timeoutAt), nil
+			}
+
+			return r.Input, nil
+		}}
+	)
+
+	start.SetID(1)
+	awaitSig.SetID(2)
+
+	wf.AddStep(start, awaitSig)
+	wf.AddStep(awaitSig)
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+		req.NoError(ses.Exec(auth.SetIdentityToContext(ctx, owner), start, nil))
+		req.NoError(ses.WaitUntil(ctx, SessionDelayed))
+
+		pending := ses.PendingAwaits()
+		req.Len(pending, 1)
+		req.Equal("ref", pending[0].Ref)
+		req.Equal(ses.ID(), pending[0].SessionID)
+		req.Nil(pending[0].TimeoutAt)
+		req.Equal("bar", expr.Must(expr.Select(pending[0].Payload, "foo")).Get())
+
+		suspended := ses.SuspendedStates()
+		req.Len(suspended, 1)
+		req.True(suspended[0].Awaiting)
+		req.Equal("ref", suspended[0].AwaitRef)
+
+		req.Error(ses.ResumeAwaiting(ctx, 0, nil))
+
+		input := expr.RVars{"signal": expr.Must(expr.NewString("received"))}.Vars()
+		req.NoError(ses.ResumeAwaiting(ctx, pending[0].StateID, input))
+		req.NoError(ses.WaitUntil(ctx, SessionCompleted))
+		req.Equal("received", expr.Must(expr.Select(ses.Result(), "signal")).Get())
+		req.Equal(true, expr.Must(expr.Select(ses.Result(), "resumed")).Get())
+	}
+
+	{
+		timeout = time.Millisecond
+
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+		req.NoError(ses.Exec(auth.SetIdentityToContext(ctx, owner), start, nil))
+		req.NoError(ses.WaitUntil(ctx, SessionCompleted))
+		req.Equal(true, expr.Must(expr.Select(ses.Result(), "timeout")).Get())
+	}
+}
+
+func TestSession_StepTimeout(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		hang = &sesTestStep{name: "hang", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}}
+
+		ignore = &sesTestStep{name: "ignore", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			// does not respect context
+			time.Sleep(time.Millisecond * 50)
+			return nil, nil
+		}}
+	)
+
+	wf.AddStep(hang)
+	wf.AddStep(ignore)
+	wf.SetStepTimeout(hang, time.Millisecond)
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+		req.NoError(ses.Exec(ctx, hang, nil))
+		req.Error(ses.Wait(ctx))
+		req.True(errors.Is(ses.Error(), ErrStepTimeout))
+	}
+
+	{
+		// default step timeout
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond), SetStepTimeout(time.Millisecond))
+		req.NoError(ses.Exec(ctx, ignore, nil))
+		req.Error(ses.Wait(ctx))
+		req.True(errors.Is(ses.Error(), ErrStepTimeout))
+
+		// step that ignores the context is abandoned until it returns
+		req.Equal(int64(1), ses.abandonedSteps())
+		req.Eventually(func() bool { return ses.abandonedSteps() == 0 }, time.Second, time.Millisecond)
+	}
+}
+
+func TestSession_TimeoutFromStart(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		step = &sesTestStep{name: "step"}
+	)
+
+	wf.AddStep(step)
+
+	// restored session that was started before the timeout
+	ses := NewSession(ctx, wf,
+		SetWorkerInterval(time.Millisecond),
+		SetTimeout(time.Minute),
+		SetStarted(now().Add(-time.Hour)),
+	)
+
+	req.NoError(ses.Exec(ctx, step, nil))
+	req.Error(ses.Wait(ctx))
+	req.True(errors.Is(ses.Error(), ErrSessionTimeout))
+}
+
+func TestSession_TimeoutHandled(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		handler = &sesTestStep{name: "handler", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			return expr.RVars{
+				"handled": expr.Must(expr.NewString(expr.Must(expr.Select(r.Scope, "errorKind")).Get().(string))),
+			}.Vars(), nil
+		}}
+
+		start = &sesTestStep{name: "start", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			return ErrorHandler(handler), nil
+		}}
+
+		slow = &sesTestStep{name: "slow", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}}
+	)
+
+	wf.AddStep(start, slow, handler)
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond), SetStepTimeout(time.Millisecond))
+		req.NoError(ses.Exec(ctx, start, nil))
+		req.NoError(ses.Wait(ctx))
+		req.Equal(ErrorKindTimeout, expr.Must(expr.Select(ses.Result(), "handled")).Get())
+	}
+
+	{
+		ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond), SetTimeout(time.Millisecond*10))
+		req.NoError(ses.Exec(ctx, start, nil))
+		req.NoError(ses.Wait(ctx))
+		req.Equal(ErrorKindSessionTimeout, expr.Must(expr.Select(ses.Result(), "handled")).Get())
+	}
+}
+
+func TestSession_Cancel(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		started = make(chan struct{})
+		hang    = &sesTestStep{name: "hang", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}}
+	)
+
+	wf.AddStep(hang)
+
+	ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+	req.NoError(ses.Exec(ctx, hang, nil))
+	<-started
+	ses.Cancel()
+	req.Equal(ErrCanceled, ses.Wait(ctx))
+	req.Equal(SessionFailed, ses.Status())
+}
+
+func TestSession_Concurrency(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		running, max = atomic.NewInt32(0), atomic.NewInt32(0)
+
+		fork     = ForkGateway()
+		branches = make([]Step, 6)
+	)
+
+	for i := range branches {
+		branches[i] = &sesTestStep{name: "branch", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if n := running.Inc(); n > max.Load() {
+				max.Store(n)
+			}
+
+			time.Sleep(time.Millisecond * 5)
+			running.Dec()
+			return Termination(), nil
+		}}
+	}
+
+	wf.AddStep(fork, branches...)
+
+	ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond), SetConcurrency(2))
+	req.NoError(ses.Exec(ctx, fork, nil))
+	req.NoError(ses.Wait(ctx))
+	req.LessOrEqual(max.Load(), int32(2))
+}
+
+func TestSession_TerminationWithConcurrentAwait(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+
+		fork      = ForkGateway()
+		terminate = &sesTestStep{name: "terminate", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			return Termination(), nil
+		}}
+		awaitSig = &sesTestStep{name: "awaitSig", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			// give the other branch a chance to terminate the session first
+			time.Sleep(time.Millisecond * 10)
+			return Await("ref", nil, nil), nil
+		}}
+	)
+
+	wf.AddStep(fork, terminate, awaitSig)
+
+	ses := NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+	req.NoError(ses.Exec(ctx, fork, nil))
+	req.NoError(ses.WaitUntil(ctx, SessionDelayed, SessionCompleted))
+	time.Sleep(time.Millisecond * 20)
+	req.NoError(ses.Error())
+}
+
 func bmSessionSimpleStepSequence(c uint64, b *testing.B) {
 	var (
 		ctx = context.Background()
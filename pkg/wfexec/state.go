@@ -1,6 +1,7 @@
 package wfexec
 
 import (
+	"fmt"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"time"
@@ -40,8 +41,48 @@ type (
 		// error handling step
 		errHandler Step
 
+		// number of the execution attempt;
+		// zero unless state is retried
+		attempt int
+
 		loops []Iterator
 	}
+
+	// SuspendedState holds all information needed to restore
+	// delayed or prompted state on a new session
+	SuspendedState struct {
+		StateID   uint64
+		CreatedAt time.Time
+
+		// identity (and roles) of the state owner
+		OwnerID    uint64
+		OwnerRoles []uint64
+
+		ParentID     uint64
+		StepID       uint64
+		ErrHandlerID uint64
+
+		Scope *expr.Vars
+		Input *expr.Vars
+
+		// set when failed state is waiting to be retried
+		Retry   bool
+		Attempt int
+
+		// set when state is delayed
+		// or when awaiting state has a timeout
+		ResumeAt *time.Time
+
+		// set when state is waiting for input
+		Prompted      bool
+		PromptRef     string
+		PromptPayload *expr.Vars
+
+		// set when state is waiting for an external signal
+		Awaiting     bool
+		AwaitRef     string
+		AwaitPayload *expr.Vars
+	}
 )
 
 func NewState(ses *Session, owner auth.Identifiable, caller, current Step, scope *expr.Vars) *State {
@@ -84,6 +125,30 @@ func (s State) Next(current Step, scope *expr.Vars) *State {
 	}
 }
 
+// retry creates a copy of the failed state for the next execution attempt
+func (s State) retry() *State {
+	attempt := s.attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	return &State{
+		stateId: nextID(),
+		created: *now(),
+
+		owner:      s.owner,
+		sessionId:  s.sessionId,
+		parent:     s.parent,
+		errHandler: s.errHandler,
+		loops:      s.loops,
+		attempt:    attempt + 1,
+
+		step:  s.step,
+		scope: s.scope,
+		input: s.input,
+	}
+}
+
 func (s State) MakeRequest() *ExecRequest {
 	return &ExecRequest{
 		SessionID: s.sessionId,
@@ -119,6 +184,13 @@ func (s State) loopCurr() Iterator {
 	return nil
 }
 
+// Executed reports if state's step was executed
+//
+// State change handler is called before and after the step is executed
+func (s State) Executed() bool {
+	return s.completed != nil
+}
+
 func (s State) MakeFrame() *Frame {
 	f := &Frame{
 		CreatedAt: s.created,
@@ -126,6 +198,11 @@ func (s State) MakeFrame() *Frame {
 		StateID:   s.stateId,
 		Input:     s.input,
 		Scope:     s.scope,
+		Attempt:   s.attempt,
+	}
+
+	if s.err != nil {
+		f.Error = s.err.Error()
 	}
 
 	if s.step != nil {
@@ -143,6 +220,77 @@ func (s State) MakeFrame() *Frame {
 	return f
 }
 
+// suspend converts state to SuspendedState
+//
+// States inside loops can not be suspended because
+// iterator's internal state can not be serialized
+func (s State) suspend() *SuspendedState {
+	if len(s.loops) > 0 || s.step == nil {
+		return nil
+	}
+
+	ss := &SuspendedState{
+		StateID:   s.stateId,
+		CreatedAt: s.created,
+		StepID:    s.step.ID(),
+		Scope:     s.scope,
+		Input:     s.input,
+		Attempt:   s.attempt,
+	}
+
+	if s.owner != nil {
+		ss.OwnerID = s.owner.Identity()
+		ss.OwnerRoles = s.owner.Roles()
+	}
+
+	if s.parent != nil {
+		ss.ParentID = s.parent.ID()
+	}
+
+	if s.errHandler != nil {
+		ss.ErrHandlerID = s.errHandler.ID()
+	}
+
+	return ss
+}
+
+// restoreState creates state from SuspendedState and resolves all referenced steps from the graph
+func restoreState(ses *Session, ss *SuspendedState) (*State, error) {
+	st := &State{
+		stateId:   ss.StateID,
+		sessionId: ses.id,
+		created:   ss.CreatedAt,
+		scope:     ss.Scope,
+		input:     ss.Input,
+		attempt:   ss.Attempt,
+		owner:     auth.NewIdentity(ss.OwnerID, ss.OwnerRoles...),
+
+		loops: make([]Iterator, 0, 4),
+	}
+
+	if st.step = ses.g.StepByID(ss.StepID); st.step == nil {
+		return nil, fmt.Errorf("can not restore state %d, step %d not found", ss.StateID, ss.StepID)
+	}
+
+	if ss.ParentID > 0 {
+		if st.parent = ses.g.StepByID(ss.ParentID); st.parent == nil {
+			return nil, fmt.Errorf("can not restore state %d, parent step %d not found", ss.StateID, ss.ParentID)
+		}
+	}
+
+	if ss.ErrHandlerID > 0 {
+		if st.errHandler = ses.g.StepByID(ss.ErrHandlerID); st.errHandler == nil {
+			return nil, fmt.Errorf("can not restore state %d, error handler step %d not found", ss.StateID, ss.ErrHandlerID)
+		}
+	}
+
+	if st.scope == nil {
+		st.scope, _ = expr.NewVars(nil)
+	}
+
+	return st, nil
+}
+
 func (s *State) Error() string {
 	if s.err == nil {
 		return ""
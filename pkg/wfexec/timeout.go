@@ -0,0 +1,191 @@
+package wfexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	autErrors "github.com/cortezaproject/corteza-server/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// ErrorKindFailure is set for all errors that are not classified
+	ErrorKindFailure = "failure"
+
+	// ErrorKindAutomation is set for automation errors (error step and similar)
+	ErrorKindAutomation = "automation"
+
+	// ErrorKindTimeout is set when step does not complete in time
+	ErrorKindTimeout = "timeout"
+
+	// ErrorKindSessionTimeout is set when session does not complete in time
+	ErrorKindSessionTimeout = "sessionTimeout"
+)
+
+var (
+	// ErrStepTimeout is wrapped by errors of steps that exceeded their timeout
+	ErrStepTimeout = errors.New("step execution timed out")
+
+	// ErrSessionTimeout is wrapped by errors of steps that were executed
+	// after session exceeded its timeout
+	ErrSessionTimeout = errors.New("session execution timed out")
+
+	// ErrCanceled is set as session error when session is canceled
+	ErrCanceled = errors.New("session canceled")
+)
+
+const (
+	execRunning int32 = iota
+	execFinished
+	execAbandoned
+)
+
+type (
+	execResult struct {
+		rsp ExecResponse
+		err error
+	}
+)
+
+// ErrorKind classifies step execution error
+//
+// When error is handled by error handler step, kind is
+// set to scope as errorKind, next to the error message
+func ErrorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrSessionTimeout):
+		return ErrorKindSessionTimeout
+	case errors.Is(err, ErrStepTimeout):
+		return ErrorKindTimeout
+	case autErrors.IsAutomation(err):
+		return ErrorKindAutomation
+	default:
+		return ErrorKindFailure
+	}
+}
+
+// execStep executes state's step with step and session timeouts applied
+//
+// Step timeout (set on graph or session default) and session deadline
+// are applied to the context passed to the step's Exec fn.
+//
+// Steps that do not respect context cancellation are abandoned
+// when timeout is reached and session continues without waiting for them.
+// Abandoned step keeps running in the background until its Exec fn returns
+// and its results are discarded; abandoned executions are counted and logged
+func (s *Session) execStep(ctx context.Context, st *State) (ExecResponse, error) {
+	var (
+		timeout  = s.g.StepTimeout(st.step)
+		deadline = s.sessionDeadline()
+
+		// when true, session deadline is closer than step's timeout
+		sessionLimited bool
+
+		cancel context.CancelFunc
+	)
+
+	if timeout == 0 {
+		timeout = s.stepTimeout
+	}
+
+	if !deadline.IsZero() {
+		if !now().Before(deadline) {
+			return nil, fmt.Errorf("%w after %s", ErrSessionTimeout, s.timeout)
+		}
+
+		sessionLimited = timeout == 0 || now().Add(timeout).After(deadline)
+	}
+
+	switch {
+	case sessionLimited:
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	case timeout > 0:
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	default:
+		return st.step.Exec(ctx, st.MakeRequest())
+	}
+
+	defer cancel()
+
+	var (
+		done = make(chan execResult, 1)
+		req  = st.MakeRequest()
+
+		// execRunning, execFinished or execAbandoned
+		exec = execRunning
+	)
+
+	go func() {
+		rsp, err := st.step.Exec(ctx, req)
+		done <- execResult{rsp, err}
+
+		if !atomic.CompareAndSwapInt32(&exec, execRunning, execFinished) {
+			s.log.Info("abandoned step execution finished",
+				zap.Uint64("stepID", st.step.ID()),
+				zap.Int64("abandoned", atomic.AddInt64(&s.abandoned, -1)),
+			)
+		}
+	}()
+
+	timedOut := func() error {
+		if sessionLimited {
+			return fmt.Errorf("%w after %s", ErrSessionTimeout, s.timeout)
+		}
+
+		return fmt.Errorf("%w after %s", ErrStepTimeout, timeout)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, timedOut()
+		}
+
+		return r.rsp, r.err
+
+	case <-ctx.Done():
+		if atomic.CompareAndSwapInt32(&exec, execRunning, execAbandoned) {
+			s.log.Warn("step execution did not stop when its context was done; abandoned",
+				zap.Uint64("stepID", st.step.ID()),
+				zap.Int64("abandoned", atomic.AddInt64(&s.abandoned, 1)),
+			)
+		}
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, timedOut()
+		}
+
+		return nil, ctx.Err()
+	}
+}
+
+// abandonedSteps returns number of abandoned step executions
+// that are still running in the background
+func (s *Session) abandonedSteps() int64 {
+	return atomic.LoadInt64(&s.abandoned)
+}
+
+// sessionDeadline returns time when session times out
+//
+// Zero value is returned when session has no timeout or
+// timeout was already handled by an error handler
+func (s *Session) sessionDeadline() time.Time {
+	defer s.mux.RUnlock()
+	s.mux.RLock()
+
+	return s.deadline
+}
+
+// liftDeadline removes session deadline
+//
+// Called when session timeout error is caught by error handler
+// so that error handling branch can be executed
+func (s *Session) liftDeadline() {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+
+	s.deadline = time.Time{}
+}
@@ -0,0 +1,68 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/auth_signing_keys.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	AuthSigningKeys interface {
+		SearchAuthSigningKeys(ctx context.Context, f types.AuthSigningKeyFilter) (types.AuthSigningKeySet, types.AuthSigningKeyFilter, error)
+		LookupAuthSigningKeyByID(ctx context.Context, id uint64) (*types.AuthSigningKey, error)
+
+		CreateAuthSigningKey(ctx context.Context, rr ...*types.AuthSigningKey) error
+
+		UpdateAuthSigningKey(ctx context.Context, rr ...*types.AuthSigningKey) error
+
+		DeleteAuthSigningKey(ctx context.Context, rr ...*types.AuthSigningKey) error
+		DeleteAuthSigningKeyByID(ctx context.Context, ID uint64) error
+
+		TruncateAuthSigningKeys(ctx context.Context) error
+	}
+)
+
+var _ *types.AuthSigningKey
+var _ context.Context
+
+// SearchAuthSigningKeys returns all matching AuthSigningKeys from store
+func SearchAuthSigningKeys(ctx context.Context, s AuthSigningKeys, f types.AuthSigningKeyFilter) (types.AuthSigningKeySet, types.AuthSigningKeyFilter, error) {
+	return s.SearchAuthSigningKeys(ctx, f)
+}
+
+// LookupAuthSigningKeyByID searches for signing key by ID
+func LookupAuthSigningKeyByID(ctx context.Context, s AuthSigningKeys, id uint64) (*types.AuthSigningKey, error) {
+	return s.LookupAuthSigningKeyByID(ctx, id)
+}
+
+// CreateAuthSigningKey creates one or more AuthSigningKeys in store
+func CreateAuthSigningKey(ctx context.Context, s AuthSigningKeys, rr ...*types.AuthSigningKey) error {
+	return s.CreateAuthSigningKey(ctx, rr...)
+}
+
+// UpdateAuthSigningKey updates one or more (existing) AuthSigningKeys in store
+func UpdateAuthSigningKey(ctx context.Context, s AuthSigningKeys, rr ...*types.AuthSigningKey) error {
+	return s.UpdateAuthSigningKey(ctx, rr...)
+}
+
+// DeleteAuthSigningKey Deletes one or more AuthSigningKeys from store
+func DeleteAuthSigningKey(ctx context.Context, s AuthSigningKeys, rr ...*types.AuthSigningKey) error {
+	return s.DeleteAuthSigningKey(ctx, rr...)
+}
+
+// DeleteAuthSigningKeyByID Deletes AuthSigningKey from store
+func DeleteAuthSigningKeyByID(ctx context.Context, s AuthSigningKeys, ID uint64) error {
+	return s.DeleteAuthSigningKeyByID(ctx, ID)
+}
+
+// TruncateAuthSigningKeys Deletes all AuthSigningKeys from store
+func TruncateAuthSigningKeys(ctx context.Context, s AuthSigningKeys) error {
+	return s.TruncateAuthSigningKeys(ctx)
+}
@@ -28,6 +28,11 @@ type (
 		DeleteAutomationSessionByID(ctx context.Context, ID uint64) error
 
 		TruncateAutomationSessions(ctx context.Context) error
+
+		// Additional custom functions
+
+		// DeleteExpiredAutomationSessions (custom function)
+		DeleteExpiredAutomationSessions(ctx context.Context) error
 	}
 )
 
@@ -75,3 +80,7 @@ func DeleteAutomationSessionByID(ctx context.Context, s AutomationSessions, ID u
 func TruncateAutomationSessions(ctx context.Context, s AutomationSessions) error {
 	return s.TruncateAutomationSessions(ctx)
 }
+
+func DeleteExpiredAutomationSessions(ctx context.Context, s AutomationSessions) error {
+	return s.DeleteExpiredAutomationSessions(ctx)
+}
@@ -0,0 +1,75 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/automation_workflow_revisions.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/automation/types"
+)
+
+type (
+	AutomationWorkflowRevisions interface {
+		SearchAutomationWorkflowRevisions(ctx context.Context, f types.WorkflowRevisionFilter) (types.WorkflowRevisionSet, types.WorkflowRevisionFilter, error)
+		LookupAutomationWorkflowRevisionByID(ctx context.Context, id uint64) (*types.WorkflowRevision, error)
+
+		CreateAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) error
+
+		UpdateAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) error
+
+		UpsertAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) error
+
+		DeleteAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) error
+		DeleteAutomationWorkflowRevisionByID(ctx context.Context, ID uint64) error
+
+		TruncateAutomationWorkflowRevisions(ctx context.Context) error
+	}
+)
+
+var _ *types.WorkflowRevision
+var _ context.Context
+
+// SearchAutomationWorkflowRevisions returns all matching AutomationWorkflowRevisions from store
+func SearchAutomationWorkflowRevisions(ctx context.Context, s AutomationWorkflowRevisions, f types.WorkflowRevisionFilter) (types.WorkflowRevisionSet, types.WorkflowRevisionFilter, error) {
+	return s.SearchAutomationWorkflowRevisions(ctx, f)
+}
+
+// LookupAutomationWorkflowRevisionByID searches for workflow revision by ID
+func LookupAutomationWorkflowRevisionByID(ctx context.Context, s AutomationWorkflowRevisions, id uint64) (*types.WorkflowRevision, error) {
+	return s.LookupAutomationWorkflowRevisionByID(ctx, id)
+}
+
+// CreateAutomationWorkflowRevision creates one or more AutomationWorkflowRevisions in store
+func CreateAutomationWorkflowRevision(ctx context.Context, s AutomationWorkflowRevisions, rr ...*types.WorkflowRevision) error {
+	return s.CreateAutomationWorkflowRevision(ctx, rr...)
+}
+
+// UpdateAutomationWorkflowRevision updates one or more (existing) AutomationWorkflowRevisions in store
+func UpdateAutomationWorkflowRevision(ctx context.Context, s AutomationWorkflowRevisions, rr ...*types.WorkflowRevision) error {
+	return s.UpdateAutomationWorkflowRevision(ctx, rr...)
+}
+
+// UpsertAutomationWorkflowRevision creates new or updates existing one or more AutomationWorkflowRevisions in store
+func UpsertAutomationWorkflowRevision(ctx context.Context, s AutomationWorkflowRevisions, rr ...*types.WorkflowRevision) error {
+	return s.UpsertAutomationWorkflowRevision(ctx, rr...)
+}
+
+// DeleteAutomationWorkflowRevision Deletes one or more AutomationWorkflowRevisions from store
+func DeleteAutomationWorkflowRevision(ctx context.Context, s AutomationWorkflowRevisions, rr ...*types.WorkflowRevision) error {
+	return s.DeleteAutomationWorkflowRevision(ctx, rr...)
+}
+
+// DeleteAutomationWorkflowRevisionByID Deletes AutomationWorkflowRevision from store
+func DeleteAutomationWorkflowRevisionByID(ctx context.Context, s AutomationWorkflowRevisions, ID uint64) error {
+	return s.DeleteAutomationWorkflowRevisionByID(ctx, ID)
+}
+
+// TruncateAutomationWorkflowRevisions Deletes all AutomationWorkflowRevisions from store
+func TruncateAutomationWorkflowRevisions(ctx context.Context, s AutomationWorkflowRevisions) error {
+	return s.TruncateAutomationWorkflowRevisions(ctx)
+}
@@ -0,0 +1,75 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/automation_workflow_stats_buckets.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/automation/types"
+)
+
+type (
+	AutomationWorkflowStatsBuckets interface {
+		SearchAutomationWorkflowStatsBuckets(ctx context.Context, f types.WorkflowStatsBucketFilter) (types.WorkflowStatsBucketSet, types.WorkflowStatsBucketFilter, error)
+		LookupAutomationWorkflowStatsBucketByID(ctx context.Context, id uint64) (*types.WorkflowStatsBucket, error)
+
+		CreateAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) error
+
+		UpdateAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) error
+
+		UpsertAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) error
+
+		DeleteAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) error
+		DeleteAutomationWorkflowStatsBucketByID(ctx context.Context, ID uint64) error
+
+		TruncateAutomationWorkflowStatsBuckets(ctx context.Context) error
+	}
+)
+
+var _ *types.WorkflowStatsBucket
+var _ context.Context
+
+// SearchAutomationWorkflowStatsBuckets returns all matching AutomationWorkflowStatsBuckets from store
+func SearchAutomationWorkflowStatsBuckets(ctx context.Context, s AutomationWorkflowStatsBuckets, f types.WorkflowStatsBucketFilter) (types.WorkflowStatsBucketSet, types.WorkflowStatsBucketFilter, error) {
+	return s.SearchAutomationWorkflowStatsBuckets(ctx, f)
+}
+
+// LookupAutomationWorkflowStatsBucketByID searches for workflow stats bucket by ID
+func LookupAutomationWorkflowStatsBucketByID(ctx context.Context, s AutomationWorkflowStatsBuckets, id uint64) (*types.WorkflowStatsBucket, error) {
+	return s.LookupAutomationWorkflowStatsBucketByID(ctx, id)
+}
+
+// CreateAutomationWorkflowStatsBucket creates one or more AutomationWorkflowStatsBuckets in store
+func CreateAutomationWorkflowStatsBucket(ctx context.Context, s AutomationWorkflowStatsBuckets, rr ...*types.WorkflowStatsBucket) error {
+	return s.CreateAutomationWorkflowStatsBucket(ctx, rr...)
+}
+
+// UpdateAutomationWorkflowStatsBucket updates one or more (existing) AutomationWorkflowStatsBuckets in store
+func UpdateAutomationWorkflowStatsBucket(ctx context.Context, s AutomationWorkflowStatsBuckets, rr ...*types.WorkflowStatsBucket) error {
+	return s.UpdateAutomationWorkflowStatsBucket(ctx, rr...)
+}
+
+// UpsertAutomationWorkflowStatsBucket creates new or updates existing one or more AutomationWorkflowStatsBuckets in store
+func UpsertAutomationWorkflowStatsBucket(ctx context.Context, s AutomationWorkflowStatsBuckets, rr ...*types.WorkflowStatsBucket) error {
+	return s.UpsertAutomationWorkflowStatsBucket(ctx, rr...)
+}
+
+// DeleteAutomationWorkflowStatsBucket Deletes one or more AutomationWorkflowStatsBuckets from store
+func DeleteAutomationWorkflowStatsBucket(ctx context.Context, s AutomationWorkflowStatsBuckets, rr ...*types.WorkflowStatsBucket) error {
+	return s.DeleteAutomationWorkflowStatsBucket(ctx, rr...)
+}
+
+// DeleteAutomationWorkflowStatsBucketByID Deletes AutomationWorkflowStatsBucket from store
+func DeleteAutomationWorkflowStatsBucketByID(ctx context.Context, s AutomationWorkflowStatsBuckets, ID uint64) error {
+	return s.DeleteAutomationWorkflowStatsBucketByID(ctx, ID)
+}
+
+// TruncateAutomationWorkflowStatsBuckets Deletes all AutomationWorkflowStatsBuckets from store
+func TruncateAutomationWorkflowStatsBuckets(ctx context.Context, s AutomationWorkflowStatsBuckets) error {
+	return s.TruncateAutomationWorkflowStatsBuckets(ctx)
+}
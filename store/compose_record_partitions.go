@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+)
+
+type (
+	composeRecordPartitionUpgrader interface {
+		UpgradeComposeRecordPartition(context.Context, *types.Module) error
+		DropComposeRecordPartition(context.Context, *types.Module) error
+	}
+)
+
+// UpgradeComposeRecordPartition creates or upgrades dedicated record table
+// for modules with partitioned records
+//
+// Schema changes can not be (safely) done inside a transaction;
+// make sure store passed here is not wrapped in one
+func UpgradeComposeRecordPartition(ctx context.Context, s Storer, m *types.Module) error {
+	if !m.Config.Partitioned {
+		return nil
+	}
+
+	upgradableStore, ok := s.(composeRecordPartitionUpgrader)
+	if !ok {
+		return fmt.Errorf("store does not support partitioned records")
+	}
+
+	return upgradableStore.UpgradeComposeRecordPartition(ctx, m)
+}
+
+// DropComposeRecordPartition removes dedicated record table (and all records in it)
+// of the module with partitioned records
+//
+// Same as with upgrade, make sure store passed here is not wrapped in a transaction
+func DropComposeRecordPartition(ctx context.Context, s Storer, m *types.Module) error {
+	if !m.Config.Partitioned {
+		return nil
+	}
+
+	upgradableStore, ok := s.(composeRecordPartitionUpgrader)
+	if !ok {
+		return fmt.Errorf("store does not support partitioned records")
+	}
+
+	return upgradableStore.DropComposeRecordPartition(ctx, m)
+}
@@ -0,0 +1,75 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/compose_record_revisions.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/compose/types"
+)
+
+type (
+	ComposeRecordRevisions interface {
+		SearchComposeRecordRevisions(ctx context.Context, f types.RecordRevisionFilter) (types.RecordRevisionSet, types.RecordRevisionFilter, error)
+		LookupComposeRecordRevisionByID(ctx context.Context, id uint64) (*types.RecordRevision, error)
+
+		CreateComposeRecordRevision(ctx context.Context, rr ...*types.RecordRevision) error
+
+		UpdateComposeRecordRevision(ctx context.Context, rr ...*types.RecordRevision) error
+
+		UpsertComposeRecordRevision(ctx context.Context, rr ...*types.RecordRevision) error
+
+		DeleteComposeRecordRevision(ctx context.Context, rr ...*types.RecordRevision) error
+		DeleteComposeRecordRevisionByID(ctx context.Context, ID uint64) error
+
+		TruncateComposeRecordRevisions(ctx context.Context) error
+	}
+)
+
+var _ *types.RecordRevision
+var _ context.Context
+
+// SearchComposeRecordRevisions returns all matching ComposeRecordRevisions from store
+func SearchComposeRecordRevisions(ctx context.Context, s ComposeRecordRevisions, f types.RecordRevisionFilter) (types.RecordRevisionSet, types.RecordRevisionFilter, error) {
+	return s.SearchComposeRecordRevisions(ctx, f)
+}
+
+// LookupComposeRecordRevisionByID searches for compose record revision by ID
+func LookupComposeRecordRevisionByID(ctx context.Context, s ComposeRecordRevisions, id uint64) (*types.RecordRevision, error) {
+	return s.LookupComposeRecordRevisionByID(ctx, id)
+}
+
+// CreateComposeRecordRevision creates one or more ComposeRecordRevisions in store
+func CreateComposeRecordRevision(ctx context.Context, s ComposeRecordRevisions, rr ...*types.RecordRevision) error {
+	return s.CreateComposeRecordRevision(ctx, rr...)
+}
+
+// UpdateComposeRecordRevision updates one or more (existing) ComposeRecordRevisions in store
+func UpdateComposeRecordRevision(ctx context.Context, s ComposeRecordRevisions, rr ...*types.RecordRevision) error {
+	return s.UpdateComposeRecordRevision(ctx, rr...)
+}
+
+// UpsertComposeRecordRevision creates new or updates existing one or more ComposeRecordRevisions in store
+func UpsertComposeRecordRevision(ctx context.Context, s ComposeRecordRevisions, rr ...*types.RecordRevision) error {
+	return s.UpsertComposeRecordRevision(ctx, rr...)
+}
+
+// DeleteComposeRecordRevision Deletes one or more ComposeRecordRevisions from store
+func DeleteComposeRecordRevision(ctx context.Context, s ComposeRecordRevisions, rr ...*types.RecordRevision) error {
+	return s.DeleteComposeRecordRevision(ctx, rr...)
+}
+
+// DeleteComposeRecordRevisionByID Deletes ComposeRecordRevision from store
+func DeleteComposeRecordRevisionByID(ctx context.Context, s ComposeRecordRevisions, ID uint64) error {
+	return s.DeleteComposeRecordRevisionByID(ctx, ID)
+}
+
+// TruncateComposeRecordRevisions Deletes all ComposeRecordRevisions from store
+func TruncateComposeRecordRevisions(ctx context.Context, s ComposeRecordRevisions) error {
+	return s.TruncateComposeRecordRevisions(ctx)
+}
@@ -11,8 +11,11 @@ package store
 //  - store/auth_confirmed_clients.yaml
 //  - store/auth_oa2tokens.yaml
 //  - store/auth_sessions.yaml
+//  - store/auth_signing_keys.yaml
 //  - store/automation_sessions.yaml
 //  - store/automation_triggers.yaml
+//  - store/automation_workflow_revisions.yaml
+//  - store/automation_workflow_stats_buckets.yaml
 //  - store/automation_workflows.yaml
 //  - store/compose_attachments.yaml
 //  - store/compose_charts.yaml
@@ -20,6 +23,8 @@ package store
 //  - store/compose_modules.yaml
 //  - store/compose_namespaces.yaml
 //  - store/compose_pages.yaml
+//  - store/compose_record_import_sessions.yaml
+//  - store/compose_record_revisions.yaml
 //  - store/compose_record_values.yaml
 //  - store/compose_records.yaml
 //  - store/credentials.yaml
@@ -34,6 +39,7 @@ package store
 //  - store/reminders.yaml
 //  - store/role_members.yaml
 //  - store/roles.yaml
+//  - store/scheduler_leases.yaml
 //  - store/settings.yaml
 //  - store/templates.yaml
 //  - store/users.yaml
@@ -52,8 +58,11 @@ type (
 		AuthConfirmedClients
 		AuthOa2tokens
 		AuthSessions
+		AuthSigningKeys
 		AutomationSessions
 		AutomationTriggers
+		AutomationWorkflowRevisions
+		AutomationWorkflowStatsBuckets
 		AutomationWorkflows
 		ComposeAttachments
 		ComposeCharts
@@ -61,6 +70,8 @@ type (
 		ComposeModules
 		ComposeNamespaces
 		ComposePages
+		ComposeRecordImportSessions
+		ComposeRecordRevisions
 		ComposeRecordValues
 		ComposeRecords
 		Credentials
@@ -75,6 +86,7 @@ type (
 		Reminders
 		RoleMembers
 		Roles
+		SchedulerLeases
 		Settings
 		Templates
 		Users
@@ -22,3 +22,13 @@ func TestBuilder(t *testing.T) {
 	req.Contains(sql, "INSERT INTO tbl")
 	req.Equal([]interface{}{"v1", "v2", "v2"}, args)
 }
+
+func TestSqlJsonValueExtractor(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	req.Equal(`JSON_UNQUOTE(JSON_EXTRACT(c, '$."name"[0]'))`, sqlJsonValueExtractor("c", "name", 0))
+	req.Equal(`JSON_UNQUOTE(JSON_EXTRACT(c, '$."a\\"b''c"[1]'))`, sqlJsonValueExtractor("c", `a"b'c`, 1))
+	req.Equal("`v_a``b`", quoteIdent("v_a`b"))
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/ql"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/store/rdbms"
@@ -44,6 +46,9 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 	cfg.ErrorHandler = errorHandler
 	cfg.UpsertBuilder = UpsertBuilder
 	cfg.CastModuleFieldToColumnType = fieldToColumnTypeCaster
+	cfg.SqlJsonValueExtractor = sqlJsonValueExtractor
+	cfg.SqlIdentQuoter = quoteIdent
+	cfg.SqlGeoBBoxExtractor = sqlGeoBBoxExtractor
 	cfg.SqlSortHandler = SqlSortHandler
 
 	if s.Store, err = rdbms.Connect(ctx, cfg); err != nil {
@@ -63,6 +68,16 @@ func (s *Store) Upgrade(ctx context.Context, log *zap.Logger) (err error) {
 	return (&rdbms.Schema{}).Upgrade(ctx, NewUpgrader(log, s))
 }
 
+// UpgradeComposeRecordPartition creates or upgrades dedicated table for partitioned records
+func (s *Store) UpgradeComposeRecordPartition(ctx context.Context, m *types.Module) error {
+	return s.Store.UpgradeComposeRecordPartitionTable(ctx, NewUpgrader(logger.Default(), s), m)
+}
+
+// DropComposeRecordPartition removes dedicated table for partitioned records
+func (s *Store) DropComposeRecordPartition(ctx context.Context, m *types.Module) error {
+	return s.Store.DropComposeRecordPartitionTable(ctx, NewUpgrader(logger.Default(), s), m)
+}
+
 // ProcDataSourceName validates given DSN and ensures
 // params are present and correct
 //
@@ -2,10 +2,16 @@ package mysql
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cortezaproject/corteza-server/store/rdbms"
 )
 
+var (
+	// escapes key inside double quotes of the JSON path
+	jsonPathKeyEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
 // fieldToColumnTypeCaster handles special ComposeModule field query representations
 // @todo Not as elegant as it should be but it'll do the trick until the #2 store iteration
 //
@@ -39,3 +45,24 @@ func fieldToColumnTypeCaster(field rdbms.ModuleFieldTypeDetector, ident string)
 	fc := fmt.Sprintf(fcp, ident)
 	return fmt.Sprintf(tcp, fc), fcp, tcp, nil
 }
+
+// sqlJsonValueExtractor extracts value from the JSON array under the given key
+func sqlJsonValueExtractor(column, key string, index int) string {
+	path := fmt.Sprintf(`$."%s"[%d]`, jsonPathKeyEscaper.Replace(key), index)
+	return fmt.Sprintf(`JSON_UNQUOTE(JSON_EXTRACT(%s, %s))`, column, quoteLiteral(path))
+}
+
+// quoteLiteral quotes string literal; MySQL treats backslash as an escape character
+func quoteLiteral(lit string) string {
+	return rdbms.QuoteLiteral(strings.ReplaceAll(lit, `\`, `\\`))
+}
+
+// quoteIdent quotes identifier with backticks
+func quoteIdent(ident string) string {
+	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+}
+
+// sqlGeoBBoxExtractor extracts bounding box value from GeoJSON
+func sqlGeoBBoxExtractor(column string, index int) string {
+	return fmt.Sprintf(`CAST(JSON_EXTRACT(NULLIF(%s, ''), '$.bbox[%d]') AS DECIMAL(20,10))`, column, index)
+}
@@ -50,6 +50,10 @@ func NewUpgrader(log *zap.Logger, store *Store) *upgrader {
 
 			return "TEXT"
 		case ddl.ColumnTypeBinary:
+			if y, has := ct.Flags["mysqlLongBlob"].(bool); has && y {
+				return "LONGBLOB"
+			}
+
 			return "BLOB"
 		case ddl.ColumnTypeTimestamp:
 			return "DATETIME"
@@ -128,7 +132,7 @@ func (u upgrader) DropTable(ctx context.Context, table string) (dropped bool, er
 		return false, err
 	}
 
-	err = u.Exec(ctx, fmt.Sprintf(`DROP TABLE "%s"`, table))
+	err = u.Exec(ctx, "DROP TABLE "+quoteIdent(table))
 	if err != nil {
 		return false, err
 	}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/ql"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/store/rdbms"
@@ -43,6 +45,8 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 	cfg.ErrorHandler = errorHandler
 	cfg.SqlFunctionHandler = sqlFunctionHandler
 	cfg.CastModuleFieldToColumnType = fieldToColumnTypeCaster
+	cfg.SqlJsonValueExtractor = sqlJsonValueExtractor
+	cfg.SqlGeoBBoxExtractor = sqlGeoBBoxExtractor
 
 	if s.Store, err = rdbms.Connect(ctx, cfg); err != nil {
 		return nil, err
@@ -61,6 +65,16 @@ func (s *Store) Upgrade(ctx context.Context, log *zap.Logger) (err error) {
 	return nil
 }
 
+// UpgradeComposeRecordPartition creates or upgrades dedicated table for partitioned records
+func (s *Store) UpgradeComposeRecordPartition(ctx context.Context, m *types.Module) error {
+	return s.Store.UpgradeComposeRecordPartitionTable(ctx, NewUpgrader(logger.Default(), s), m)
+}
+
+// DropComposeRecordPartition removes dedicated table for partitioned records
+func (s *Store) DropComposeRecordPartition(ctx context.Context, m *types.Module) error {
+	return s.Store.DropComposeRecordPartitionTable(ctx, NewUpgrader(logger.Default(), s), m)
+}
+
 // ProcDataSourceName validates given DSN and ensures
 // params are present and correct
 func ProcDataSourceName(dsn string) (c *rdbms.Config, err error) {
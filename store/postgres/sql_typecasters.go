@@ -40,3 +40,13 @@ func fieldToColumnTypeCaster(field rdbms.ModuleFieldTypeDetector, ident string)
 	fc := fmt.Sprintf(fcp, ident)
 	return fmt.Sprintf(tcp, fc), fcp, tcp, nil
 }
+
+// sqlJsonValueExtractor extracts value from the JSON array under the given key
+func sqlJsonValueExtractor(column, key string, index int) string {
+	return fmt.Sprintf(`(%s->%s->>%d)`, column, rdbms.QuoteLiteral(key), index)
+}
+
+// sqlGeoBBoxExtractor extracts bounding box value from GeoJSON
+func sqlGeoBBoxExtractor(column string, index int) string {
+	return fmt.Sprintf(`(NULLIF(%s, '')::jsonb->'bbox'->>%d)::numeric`, column, index)
+}
@@ -0,0 +1,302 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/auth_signing_keys.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+var _ = errors.Is
+
+// SearchAuthSigningKeys returns all matching rows
+//
+// This function calls convertAuthSigningKeyFilter with the given
+// types.AuthSigningKeyFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchAuthSigningKeys(ctx context.Context, f types.AuthSigningKeyFilter) (types.AuthSigningKeySet, types.AuthSigningKeyFilter, error) {
+	var (
+		err error
+		set []*types.AuthSigningKey
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertAuthSigningKeyFilter(f)
+		if err != nil {
+			return err
+		}
+
+		set, err = s.QueryAuthSigningKeys(ctx, q, f.Check)
+		return err
+	}()
+}
+
+// QueryAuthSigningKeys queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryAuthSigningKeys(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.AuthSigningKey) (bool, error),
+) ([]*types.AuthSigningKey, error) {
+	var (
+		set = make([]*types.AuthSigningKey, 0, DefaultSliceCapacity)
+		res *types.AuthSigningKey
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalAuthSigningKeyRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// check fn set, call it and see if it passed the test
+		// if not, skip the item
+		if check != nil {
+			if chk, err := check(res); err != nil {
+				return nil, err
+			} else if !chk {
+				continue
+			}
+		}
+
+		set = append(set, res)
+	}
+
+	return set, rows.Err()
+}
+
+// LookupAuthSigningKeyByID searches for signing key by ID
+func (s Store) LookupAuthSigningKeyByID(ctx context.Context, id uint64) (*types.AuthSigningKey, error) {
+	return s.execLookupAuthSigningKey(ctx, squirrel.Eq{
+		s.preprocessColumn("ask.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateAuthSigningKey creates one or more rows in auth_signing_keys table
+func (s Store) CreateAuthSigningKey(ctx context.Context, rr ...*types.AuthSigningKey) (err error) {
+	for _, res := range rr {
+		err = s.checkAuthSigningKeyConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateAuthSigningKeys(ctx, s.internalAuthSigningKeyEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateAuthSigningKey updates one or more existing rows in auth_signing_keys
+func (s Store) UpdateAuthSigningKey(ctx context.Context, rr ...*types.AuthSigningKey) error {
+	return s.partialAuthSigningKeyUpdate(ctx, nil, rr...)
+}
+
+// partialAuthSigningKeyUpdate updates one or more existing rows in auth_signing_keys
+func (s Store) partialAuthSigningKeyUpdate(ctx context.Context, onlyColumns []string, rr ...*types.AuthSigningKey) (err error) {
+	for _, res := range rr {
+		err = s.checkAuthSigningKeyConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateAuthSigningKeys(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("ask.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalAuthSigningKeyEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// DeleteAuthSigningKey Deletes one or more rows from auth_signing_keys table
+func (s Store) DeleteAuthSigningKey(ctx context.Context, rr ...*types.AuthSigningKey) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteAuthSigningKeys(ctx, squirrel.Eq{
+			s.preprocessColumn("ask.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAuthSigningKeyByID Deletes row from the auth_signing_keys table
+func (s Store) DeleteAuthSigningKeyByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteAuthSigningKeys(ctx, squirrel.Eq{
+		s.preprocessColumn("ask.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateAuthSigningKeys Deletes all rows from the auth_signing_keys table
+func (s Store) TruncateAuthSigningKeys(ctx context.Context) error {
+	return s.Truncate(ctx, s.authSigningKeyTable())
+}
+
+// execLookupAuthSigningKey prepares AuthSigningKey query and executes it,
+// returning types.AuthSigningKey (or error)
+func (s Store) execLookupAuthSigningKey(ctx context.Context, cnd squirrel.Sqlizer) (res *types.AuthSigningKey, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.authSigningKeysSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalAuthSigningKeyRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateAuthSigningKeys updates all matched (by cnd) rows in auth_signing_keys with given data
+func (s Store) execCreateAuthSigningKeys(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.authSigningKeyTable()).SetMap(payload))
+}
+
+// execUpdateAuthSigningKeys updates all matched (by cnd) rows in auth_signing_keys with given data
+func (s Store) execUpdateAuthSigningKeys(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.authSigningKeyTable("ask")).Where(cnd).SetMap(set))
+}
+
+// execDeleteAuthSigningKeys Deletes all matched (by cnd) rows in auth_signing_keys with given data
+func (s Store) execDeleteAuthSigningKeys(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.authSigningKeyTable("ask")).Where(cnd))
+}
+
+func (s Store) internalAuthSigningKeyRowScanner(row rowScanner) (res *types.AuthSigningKey, err error) {
+	res = &types.AuthSigningKey{}
+
+	if _, has := s.config.RowScanners["authSigningKey"]; has {
+		scanner := s.config.RowScanners["authSigningKey"].(func(_ rowScanner, _ *types.AuthSigningKey) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.Algorithm,
+			&res.PrivateKey,
+			&res.CreatedAt,
+			&res.RetiredAt,
+			&res.ExpiresAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan authSigningKey db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryAuthSigningKeys returns squirrel.SelectBuilder with set table and all columns
+func (s Store) authSigningKeysSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.authSigningKeyTable("ask"), s.authSigningKeyColumns("ask")...)
+}
+
+// authSigningKeyTable name of the db table
+func (Store) authSigningKeyTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "auth_signing_keys" + alias
+}
+
+// AuthSigningKeyColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) authSigningKeyColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "algorithm",
+		alias + "private_key",
+		alias + "created_at",
+		alias + "retired_at",
+		alias + "expires_at",
+	}
+}
+
+// {true true false false false true}
+
+// internalAuthSigningKeyEncoder encodes fields from types.AuthSigningKey to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeAuthSigningKey
+// func when rdbms.customEncoder=true
+func (s Store) internalAuthSigningKeyEncoder(res *types.AuthSigningKey) store.Payload {
+	return store.Payload{
+		"id":          res.ID,
+		"algorithm":   res.Algorithm,
+		"private_key": res.PrivateKey,
+		"created_at":  res.CreatedAt,
+		"retired_at":  res.RetiredAt,
+		"expires_at":  res.ExpiresAt,
+	}
+}
+
+// checkAuthSigningKeyConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we can not rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkAuthSigningKeyConstraints(ctx context.Context, res *types.AuthSigningKey) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+func (s Store) convertAuthSigningKeyFilter(f types.AuthSigningKeyFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.authSigningKeysSelectBuilder().
+		OrderBy("ask.created_at")
+
+	return
+}
@@ -433,12 +433,14 @@ func (s Store) internalAutomationSessionRowScanner(row rowScanner) (res *types.S
 		err = row.Scan(
 			&res.ID,
 			&res.WorkflowID,
+			&res.Revision,
 			&res.EventType,
 			&res.ResourceType,
 			&res.Status,
 			&res.Input,
 			&res.Output,
 			&res.Stacktrace,
+			&res.States,
 			&res.CreatedBy,
 			&res.CreatedAt,
 			&res.PurgeAt,
@@ -486,12 +488,14 @@ func (Store) automationSessionColumns(aa ...string) []string {
 	return []string{
 		alias + "id",
 		alias + "rel_workflow",
+		alias + "revision",
 		alias + "event_type",
 		alias + "resource_type",
 		alias + "status",
 		alias + "input",
 		alias + "output",
 		alias + "stacktrace",
+		alias + "states",
 		alias + "created_by",
 		alias + "created_at",
 		alias + "purge_at",
@@ -520,12 +524,14 @@ func (s Store) internalAutomationSessionEncoder(res *types.Session) store.Payloa
 	return store.Payload{
 		"id":            res.ID,
 		"rel_workflow":  res.WorkflowID,
+		"revision":      res.Revision,
 		"event_type":    res.EventType,
 		"resource_type": res.ResourceType,
 		"status":        res.Status,
 		"input":         res.Input,
 		"output":        res.Output,
 		"stacktrace":    res.Stacktrace,
+		"states":        res.States,
 		"created_by":    res.CreatedBy,
 		"created_at":    res.CreatedAt,
 		"purge_at":      res.PurgeAt,
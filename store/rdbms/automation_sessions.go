@@ -1,9 +1,11 @@
 package rdbms
 
 import (
+	"context"
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/automation/types"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"time"
 )
 
 func (s Store) convertAutomationSessionFilter(f types.SessionFilter) (query squirrel.SelectBuilder, err error) {
@@ -20,7 +22,7 @@ func (s Store) convertAutomationSessionFilter(f types.SessionFilter) (query squi
 	}
 
 	if len(f.WorkflowID) > 0 {
-		query = query.Where(squirrel.Eq{"atms.id": f.WorkflowID})
+		query = query.Where(squirrel.Eq{"atms.rel_workflow": f.WorkflowID})
 	}
 
 	if len(f.EventType) > 0 {
@@ -33,3 +35,8 @@ func (s Store) convertAutomationSessionFilter(f types.SessionFilter) (query squi
 
 	return
 }
+
+// DeleteExpiredAutomationSessions removes all sessions with purge time in the past
+func (s Store) DeleteExpiredAutomationSessions(ctx context.Context) error {
+	return s.execDeleteAutomationSessions(ctx, squirrel.Lt{"atms.purge_at": time.Now()})
+}
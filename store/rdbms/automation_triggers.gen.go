@@ -440,6 +440,7 @@ func (s Store) internalAutomationTriggerRowScanner(row rowScanner) (res *types.T
 			&res.Meta,
 			&res.Constraints,
 			&res.Input,
+			&res.Webhook,
 			&res.OwnedBy,
 			&res.CreatedBy,
 			&res.UpdatedBy,
@@ -495,6 +496,7 @@ func (Store) automationTriggerColumns(aa ...string) []string {
 		alias + "meta",
 		alias + "constraints",
 		alias + "input",
+		alias + "webhook",
 		alias + "owned_by",
 		alias + "created_by",
 		alias + "updated_by",
@@ -531,6 +533,7 @@ func (s Store) internalAutomationTriggerEncoder(res *types.Trigger) store.Payloa
 		"meta":          res.Meta,
 		"constraints":   res.Constraints,
 		"input":         res.Input,
+		"webhook":       res.Webhook,
 		"owned_by":      res.OwnedBy,
 		"created_by":    res.CreatedBy,
 		"updated_by":    res.UpdatedBy,
@@ -0,0 +1,582 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/automation_workflow_revisions.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchAutomationWorkflowRevisions returns all matching rows
+//
+// This function calls convertAutomationWorkflowRevisionFilter with the given
+// types.WorkflowRevisionFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchAutomationWorkflowRevisions(ctx context.Context, f types.WorkflowRevisionFilter) (types.WorkflowRevisionSet, types.WorkflowRevisionFilter, error) {
+	var (
+		err error
+		set []*types.WorkflowRevision
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertAutomationWorkflowRevisionFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("id") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "id",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableAutomationWorkflowRevisionColumns()); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfAutomationWorkflowRevisions(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			f.Check,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfAutomationWorkflowRevisions collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfAutomationWorkflowRevisions(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.WorkflowRevision) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.WorkflowRevision, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.WorkflowRevision
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.WorkflowRevision, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryAutomationWorkflowRevisions(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectAutomationWorkflowRevisionCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectAutomationWorkflowRevisionCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectAutomationWorkflowRevisionCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryAutomationWorkflowRevisions queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryAutomationWorkflowRevisions(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.WorkflowRevision) (bool, error),
+) ([]*types.WorkflowRevision, error) {
+	var (
+		set = make([]*types.WorkflowRevision, 0, DefaultSliceCapacity)
+		res *types.WorkflowRevision
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalAutomationWorkflowRevisionRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// check fn set, call it and see if it passed the test
+		// if not, skip the item
+		if check != nil {
+			if chk, err := check(res); err != nil {
+				return nil, err
+			} else if !chk {
+				continue
+			}
+		}
+
+		set = append(set, res)
+	}
+
+	return set, rows.Err()
+}
+
+// LookupAutomationWorkflowRevisionByID searches for workflow revision by ID
+func (s Store) LookupAutomationWorkflowRevisionByID(ctx context.Context, id uint64) (*types.WorkflowRevision, error) {
+	return s.execLookupAutomationWorkflowRevision(ctx, squirrel.Eq{
+		s.preprocessColumn("atmwfr.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateAutomationWorkflowRevision creates one or more rows in automation_workflow_revisions table
+func (s Store) CreateAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) (err error) {
+	for _, res := range rr {
+		err = s.checkAutomationWorkflowRevisionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateAutomationWorkflowRevisions(ctx, s.internalAutomationWorkflowRevisionEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateAutomationWorkflowRevision updates one or more existing rows in automation_workflow_revisions
+func (s Store) UpdateAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) error {
+	return s.partialAutomationWorkflowRevisionUpdate(ctx, nil, rr...)
+}
+
+// partialAutomationWorkflowRevisionUpdate updates one or more existing rows in automation_workflow_revisions
+func (s Store) partialAutomationWorkflowRevisionUpdate(ctx context.Context, onlyColumns []string, rr ...*types.WorkflowRevision) (err error) {
+	for _, res := range rr {
+		err = s.checkAutomationWorkflowRevisionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateAutomationWorkflowRevisions(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("atmwfr.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalAutomationWorkflowRevisionEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertAutomationWorkflowRevision updates one or more existing rows in automation_workflow_revisions
+func (s Store) UpsertAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) (err error) {
+	for _, res := range rr {
+		err = s.checkAutomationWorkflowRevisionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertAutomationWorkflowRevisions(ctx, s.internalAutomationWorkflowRevisionEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAutomationWorkflowRevision Deletes one or more rows from automation_workflow_revisions table
+func (s Store) DeleteAutomationWorkflowRevision(ctx context.Context, rr ...*types.WorkflowRevision) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteAutomationWorkflowRevisions(ctx, squirrel.Eq{
+			s.preprocessColumn("atmwfr.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAutomationWorkflowRevisionByID Deletes row from the automation_workflow_revisions table
+func (s Store) DeleteAutomationWorkflowRevisionByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteAutomationWorkflowRevisions(ctx, squirrel.Eq{
+		s.preprocessColumn("atmwfr.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateAutomationWorkflowRevisions Deletes all rows from the automation_workflow_revisions table
+func (s Store) TruncateAutomationWorkflowRevisions(ctx context.Context) error {
+	return s.Truncate(ctx, s.automationWorkflowRevisionTable())
+}
+
+// execLookupAutomationWorkflowRevision prepares AutomationWorkflowRevision query and executes it,
+// returning types.WorkflowRevision (or error)
+func (s Store) execLookupAutomationWorkflowRevision(ctx context.Context, cnd squirrel.Sqlizer) (res *types.WorkflowRevision, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.automationWorkflowRevisionsSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalAutomationWorkflowRevisionRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateAutomationWorkflowRevisions updates all matched (by cnd) rows in automation_workflow_revisions with given data
+func (s Store) execCreateAutomationWorkflowRevisions(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.automationWorkflowRevisionTable()).SetMap(payload))
+}
+
+// execUpdateAutomationWorkflowRevisions updates all matched (by cnd) rows in automation_workflow_revisions with given data
+func (s Store) execUpdateAutomationWorkflowRevisions(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.automationWorkflowRevisionTable("atmwfr")).Where(cnd).SetMap(set))
+}
+
+// execUpsertAutomationWorkflowRevisions inserts new or updates matching (by-primary-key) rows in automation_workflow_revisions with given data
+func (s Store) execUpsertAutomationWorkflowRevisions(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.automationWorkflowRevisionTable(),
+		set,
+		s.preprocessColumn("id", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteAutomationWorkflowRevisions Deletes all matched (by cnd) rows in automation_workflow_revisions with given data
+func (s Store) execDeleteAutomationWorkflowRevisions(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.automationWorkflowRevisionTable("atmwfr")).Where(cnd))
+}
+
+func (s Store) internalAutomationWorkflowRevisionRowScanner(row rowScanner) (res *types.WorkflowRevision, err error) {
+	res = &types.WorkflowRevision{}
+
+	if _, has := s.config.RowScanners["automationWorkflowRevision"]; has {
+		scanner := s.config.RowScanners["automationWorkflowRevision"].(func(_ rowScanner, _ *types.WorkflowRevision) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.WorkflowID,
+			&res.Revision,
+			&res.Scope,
+			&res.Steps,
+			&res.Paths,
+			&res.CreatedAt,
+			&res.CreatedBy,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan automationWorkflowRevision db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryAutomationWorkflowRevisions returns squirrel.SelectBuilder with set table and all columns
+func (s Store) automationWorkflowRevisionsSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.automationWorkflowRevisionTable("atmwfr"), s.automationWorkflowRevisionColumns("atmwfr")...)
+}
+
+// automationWorkflowRevisionTable name of the db table
+func (Store) automationWorkflowRevisionTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "automation_workflow_revisions" + alias
+}
+
+// AutomationWorkflowRevisionColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) automationWorkflowRevisionColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "rel_workflow",
+		alias + "revision",
+		alias + "scope",
+		alias + "steps",
+		alias + "paths",
+		alias + "created_at",
+		alias + "created_by",
+	}
+}
+
+// {true true false true true true}
+
+// sortableAutomationWorkflowRevisionColumns returns all AutomationWorkflowRevision columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableAutomationWorkflowRevisionColumns() map[string]string {
+	return map[string]string{
+		"id": "id", "revision": "revision", "created_at": "created_at",
+		"createdat": "created_at",
+	}
+}
+
+// internalAutomationWorkflowRevisionEncoder encodes fields from types.WorkflowRevision to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeAutomationWorkflowRevision
+// func when rdbms.customEncoder=true
+func (s Store) internalAutomationWorkflowRevisionEncoder(res *types.WorkflowRevision) store.Payload {
+	return store.Payload{
+		"id":           res.ID,
+		"rel_workflow": res.WorkflowID,
+		"revision":     res.Revision,
+		"scope":        res.Scope,
+		"steps":        res.Steps,
+		"paths":        res.Paths,
+		"created_at":   res.CreatedAt,
+		"created_by":   res.CreatedBy,
+	}
+}
+
+// collectAutomationWorkflowRevisionCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectAutomationWorkflowRevisionCursorValues(res *types.WorkflowRevision, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkId bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "id":
+					cursor.Set(c.Column, res.ID, c.Descending)
+
+					pkId = true
+				case "revision":
+					cursor.Set(c.Column, res.Revision, c.Descending)
+
+				case "created_at":
+					cursor.Set(c.Column, res.CreatedAt, c.Descending)
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkId && true) {
+		collect(&filter.SortExpr{Column: "id", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkAutomationWorkflowRevisionConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we can not rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkAutomationWorkflowRevisionConstraints(ctx context.Context, res *types.WorkflowRevision) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	return nil
+}
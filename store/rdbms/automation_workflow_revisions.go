@@ -0,0 +1,20 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/automation/types"
+)
+
+func (s Store) convertAutomationWorkflowRevisionFilter(f types.WorkflowRevisionFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.automationWorkflowRevisionsSelectBuilder()
+
+	if f.WorkflowID > 0 {
+		query = query.Where(squirrel.Eq{"atmwfr.rel_workflow": f.WorkflowID})
+	}
+
+	if f.Revision > 0 {
+		query = query.Where(squirrel.Eq{"atmwfr.revision": f.Revision})
+	}
+
+	return
+}
@@ -0,0 +1,570 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/automation_workflow_stats_buckets.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchAutomationWorkflowStatsBuckets returns all matching rows
+//
+// This function calls convertAutomationWorkflowStatsBucketFilter with the given
+// types.WorkflowStatsBucketFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchAutomationWorkflowStatsBuckets(ctx context.Context, f types.WorkflowStatsBucketFilter) (types.WorkflowStatsBucketSet, types.WorkflowStatsBucketFilter, error) {
+	var (
+		err error
+		set []*types.WorkflowStatsBucket
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertAutomationWorkflowStatsBucketFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("id") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "id",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableAutomationWorkflowStatsBucketColumns()); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfAutomationWorkflowStatsBuckets(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			f.Check,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfAutomationWorkflowStatsBuckets collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfAutomationWorkflowStatsBuckets(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.WorkflowStatsBucket) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.WorkflowStatsBucket, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.WorkflowStatsBucket
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.WorkflowStatsBucket, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryAutomationWorkflowStatsBuckets(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectAutomationWorkflowStatsBucketCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectAutomationWorkflowStatsBucketCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectAutomationWorkflowStatsBucketCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryAutomationWorkflowStatsBuckets queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryAutomationWorkflowStatsBuckets(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.WorkflowStatsBucket) (bool, error),
+) ([]*types.WorkflowStatsBucket, error) {
+	var (
+		set = make([]*types.WorkflowStatsBucket, 0, DefaultSliceCapacity)
+		res *types.WorkflowStatsBucket
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalAutomationWorkflowStatsBucketRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// check fn set, call it and see if it passed the test
+		// if not, skip the item
+		if check != nil {
+			if chk, err := check(res); err != nil {
+				return nil, err
+			} else if !chk {
+				continue
+			}
+		}
+
+		set = append(set, res)
+	}
+
+	return set, rows.Err()
+}
+
+// LookupAutomationWorkflowStatsBucketByID searches for workflow stats bucket by ID
+func (s Store) LookupAutomationWorkflowStatsBucketByID(ctx context.Context, id uint64) (*types.WorkflowStatsBucket, error) {
+	return s.execLookupAutomationWorkflowStatsBucket(ctx, squirrel.Eq{
+		s.preprocessColumn("atmwfs.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateAutomationWorkflowStatsBucket creates one or more rows in automation_workflow_stats table
+func (s Store) CreateAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) (err error) {
+	for _, res := range rr {
+		err = s.checkAutomationWorkflowStatsBucketConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateAutomationWorkflowStatsBuckets(ctx, s.internalAutomationWorkflowStatsBucketEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateAutomationWorkflowStatsBucket updates one or more existing rows in automation_workflow_stats
+func (s Store) UpdateAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) error {
+	return s.partialAutomationWorkflowStatsBucketUpdate(ctx, nil, rr...)
+}
+
+// partialAutomationWorkflowStatsBucketUpdate updates one or more existing rows in automation_workflow_stats
+func (s Store) partialAutomationWorkflowStatsBucketUpdate(ctx context.Context, onlyColumns []string, rr ...*types.WorkflowStatsBucket) (err error) {
+	for _, res := range rr {
+		err = s.checkAutomationWorkflowStatsBucketConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateAutomationWorkflowStatsBuckets(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("atmwfs.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalAutomationWorkflowStatsBucketEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertAutomationWorkflowStatsBucket updates one or more existing rows in automation_workflow_stats
+func (s Store) UpsertAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) (err error) {
+	for _, res := range rr {
+		err = s.checkAutomationWorkflowStatsBucketConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertAutomationWorkflowStatsBuckets(ctx, s.internalAutomationWorkflowStatsBucketEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAutomationWorkflowStatsBucket Deletes one or more rows from automation_workflow_stats table
+func (s Store) DeleteAutomationWorkflowStatsBucket(ctx context.Context, rr ...*types.WorkflowStatsBucket) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteAutomationWorkflowStatsBuckets(ctx, squirrel.Eq{
+			s.preprocessColumn("atmwfs.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteAutomationWorkflowStatsBucketByID Deletes row from the automation_workflow_stats table
+func (s Store) DeleteAutomationWorkflowStatsBucketByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteAutomationWorkflowStatsBuckets(ctx, squirrel.Eq{
+		s.preprocessColumn("atmwfs.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateAutomationWorkflowStatsBuckets Deletes all rows from the automation_workflow_stats table
+func (s Store) TruncateAutomationWorkflowStatsBuckets(ctx context.Context) error {
+	return s.Truncate(ctx, s.automationWorkflowStatsBucketTable())
+}
+
+// execLookupAutomationWorkflowStatsBucket prepares AutomationWorkflowStatsBucket query and executes it,
+// returning types.WorkflowStatsBucket (or error)
+func (s Store) execLookupAutomationWorkflowStatsBucket(ctx context.Context, cnd squirrel.Sqlizer) (res *types.WorkflowStatsBucket, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.automationWorkflowStatsBucketsSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalAutomationWorkflowStatsBucketRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateAutomationWorkflowStatsBuckets updates all matched (by cnd) rows in automation_workflow_stats with given data
+func (s Store) execCreateAutomationWorkflowStatsBuckets(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.automationWorkflowStatsBucketTable()).SetMap(payload))
+}
+
+// execUpdateAutomationWorkflowStatsBuckets updates all matched (by cnd) rows in automation_workflow_stats with given data
+func (s Store) execUpdateAutomationWorkflowStatsBuckets(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.automationWorkflowStatsBucketTable("atmwfs")).Where(cnd).SetMap(set))
+}
+
+// execUpsertAutomationWorkflowStatsBuckets inserts new or updates matching (by-primary-key) rows in automation_workflow_stats with given data
+func (s Store) execUpsertAutomationWorkflowStatsBuckets(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.automationWorkflowStatsBucketTable(),
+		set,
+		s.preprocessColumn("id", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteAutomationWorkflowStatsBuckets Deletes all matched (by cnd) rows in automation_workflow_stats with given data
+func (s Store) execDeleteAutomationWorkflowStatsBuckets(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.automationWorkflowStatsBucketTable("atmwfs")).Where(cnd))
+}
+
+func (s Store) internalAutomationWorkflowStatsBucketRowScanner(row rowScanner) (res *types.WorkflowStatsBucket, err error) {
+	res = &types.WorkflowStatsBucket{}
+
+	if _, has := s.config.RowScanners["automationWorkflowStatsBucket"]; has {
+		scanner := s.config.RowScanners["automationWorkflowStatsBucket"].(func(_ rowScanner, _ *types.WorkflowStatsBucket) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.WorkflowID,
+			&res.StartAt,
+			&res.Stats,
+			&res.Steps,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan automationWorkflowStatsBucket db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryAutomationWorkflowStatsBuckets returns squirrel.SelectBuilder with set table and all columns
+func (s Store) automationWorkflowStatsBucketsSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.automationWorkflowStatsBucketTable("atmwfs"), s.automationWorkflowStatsBucketColumns("atmwfs")...)
+}
+
+// automationWorkflowStatsBucketTable name of the db table
+func (Store) automationWorkflowStatsBucketTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "automation_workflow_stats" + alias
+}
+
+// AutomationWorkflowStatsBucketColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) automationWorkflowStatsBucketColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "rel_workflow",
+		alias + "start_at",
+		alias + "stats",
+		alias + "steps",
+	}
+}
+
+// {true true false true true true}
+
+// sortableAutomationWorkflowStatsBucketColumns returns all AutomationWorkflowStatsBucket columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableAutomationWorkflowStatsBucketColumns() map[string]string {
+	return map[string]string{
+		"id": "id", "start_at": "start_at",
+		"startat": "start_at",
+	}
+}
+
+// internalAutomationWorkflowStatsBucketEncoder encodes fields from types.WorkflowStatsBucket to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeAutomationWorkflowStatsBucket
+// func when rdbms.customEncoder=true
+func (s Store) internalAutomationWorkflowStatsBucketEncoder(res *types.WorkflowStatsBucket) store.Payload {
+	return store.Payload{
+		"id":           res.ID,
+		"rel_workflow": res.WorkflowID,
+		"start_at":     res.StartAt,
+		"stats":        res.Stats,
+		"steps":        res.Steps,
+	}
+}
+
+// collectAutomationWorkflowStatsBucketCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectAutomationWorkflowStatsBucketCursorValues(res *types.WorkflowStatsBucket, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkId bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "id":
+					cursor.Set(c.Column, res.ID, c.Descending)
+
+					pkId = true
+				case "start_at":
+					cursor.Set(c.Column, res.StartAt, c.Descending)
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkId && true) {
+		collect(&filter.SortExpr{Column: "id", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkAutomationWorkflowStatsBucketConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we can not rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkAutomationWorkflowStatsBucketConstraints(ctx context.Context, res *types.WorkflowStatsBucket) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	return nil
+}
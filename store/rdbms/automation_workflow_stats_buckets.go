@@ -0,0 +1,24 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/automation/types"
+)
+
+func (s Store) convertAutomationWorkflowStatsBucketFilter(f types.WorkflowStatsBucketFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.automationWorkflowStatsBucketsSelectBuilder()
+
+	if f.WorkflowID > 0 {
+		query = query.Where(squirrel.Eq{"atmwfs.rel_workflow": f.WorkflowID})
+	}
+
+	if f.From != nil {
+		query = query.Where(squirrel.GtOrEq{"atmwfs.start_at": f.From})
+	}
+
+	if f.To != nil {
+		query = query.Where(squirrel.LtOrEq{"atmwfs.start_at": f.To})
+	}
+
+	return
+}
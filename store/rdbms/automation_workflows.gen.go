@@ -448,9 +448,11 @@ func (s Store) internalAutomationWorkflowRowScanner(row rowScanner) (res *types.
 			&res.Enabled,
 			&res.Trace,
 			&res.KeepSessions,
+			&res.Timeout,
 			&res.Scope,
 			&res.Steps,
 			&res.Paths,
+			&res.PublishedRevision,
 			&res.Issues,
 			&res.RunAs,
 			&res.OwnedBy,
@@ -505,9 +507,11 @@ func (Store) automationWorkflowColumns(aa ...string) []string {
 		alias + "enabled",
 		alias + "trace",
 		alias + "keep_sessions",
+		alias + "timeout",
 		alias + "scope",
 		alias + "steps",
 		alias + "paths",
+		alias + "published_revision",
 		alias + "issues",
 		alias + "run_as",
 		alias + "owned_by",
@@ -537,24 +541,26 @@ func (Store) sortableAutomationWorkflowColumns() map[string]string {
 // func when rdbms.customEncoder=true
 func (s Store) internalAutomationWorkflowEncoder(res *types.Workflow) store.Payload {
 	return store.Payload{
-		"id":            res.ID,
-		"handle":        res.Handle,
-		"meta":          res.Meta,
-		"enabled":       res.Enabled,
-		"trace":         res.Trace,
-		"keep_sessions": res.KeepSessions,
-		"scope":         res.Scope,
-		"steps":         res.Steps,
-		"paths":         res.Paths,
-		"issues":        res.Issues,
-		"run_as":        res.RunAs,
-		"owned_by":      res.OwnedBy,
-		"created_by":    res.CreatedBy,
-		"updated_by":    res.UpdatedBy,
-		"deleted_by":    res.DeletedBy,
-		"created_at":    res.CreatedAt,
-		"updated_at":    res.UpdatedAt,
-		"deleted_at":    res.DeletedAt,
+		"id":                 res.ID,
+		"handle":             res.Handle,
+		"meta":               res.Meta,
+		"enabled":            res.Enabled,
+		"trace":              res.Trace,
+		"keep_sessions":      res.KeepSessions,
+		"timeout":            res.Timeout,
+		"scope":              res.Scope,
+		"steps":              res.Steps,
+		"paths":              res.Paths,
+		"published_revision": res.PublishedRevision,
+		"issues":             res.Issues,
+		"run_as":             res.RunAs,
+		"owned_by":           res.OwnedBy,
+		"created_by":         res.CreatedBy,
+		"updated_by":         res.UpdatedBy,
+		"deleted_by":         res.DeletedBy,
+		"created_at":         res.CreatedAt,
+		"updated_at":         res.UpdatedAt,
+		"deleted_at":         res.DeletedAt,
 	}
 }
 
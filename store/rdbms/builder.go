@@ -52,3 +52,17 @@ func UpsertBuilder(cfg *Config, table string, payload store.Payload, columns ...
 		SetMap(payload).
 		Suffix(suffix, args...), nil
 }
+
+// QuoteIdent quotes identifier (table or column name) with double quotes
+//
+// Any double quotes inside the identifier are escaped
+func QuoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// QuoteLiteral quotes string literal with single quotes
+//
+// Any single quotes inside the literal are escaped
+func QuoteLiteral(lit string) string {
+	return `'` + strings.ReplaceAll(lit, `'`, `''`) + `'`
+}
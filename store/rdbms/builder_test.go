@@ -22,3 +22,14 @@ func TestBuilder(t *testing.T) {
 	req.Equal([]interface{}{"v1", "v2", "v2"}, args)
 
 }
+
+func TestQuote(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	req.Equal(`"v_name"`, QuoteIdent("v_name"))
+	req.Equal(`"v_a""b"`, QuoteIdent(`v_a"b`))
+	req.Equal(`'name'`, QuoteLiteral("name"))
+	req.Equal(`'a''b'`, QuoteLiteral("a'b"))
+}
@@ -455,6 +455,7 @@ func (s Store) internalComposeModuleRowScanner(row rowScanner) (res *types.Modul
 			&res.Handle,
 			&res.Name,
 			&res.Meta,
+			&res.Config,
 			&res.NamespaceID,
 			&res.CreatedAt,
 			&res.UpdatedAt,
@@ -502,6 +503,7 @@ func (Store) composeModuleColumns(aa ...string) []string {
 		alias + "handle",
 		alias + "name",
 		alias + "meta",
+		alias + "config",
 		alias + "rel_namespace",
 		alias + "created_at",
 		alias + "updated_at",
@@ -535,6 +537,7 @@ func (s Store) internalComposeModuleEncoder(res *types.Module) store.Payload {
 		"handle":        res.Handle,
 		"name":          res.Name,
 		"meta":          res.Meta,
+		"config":        res.Config,
 		"rel_namespace": res.NamespaceID,
 		"created_at":    res.CreatedAt,
 		"updated_at":    res.UpdatedAt,
@@ -0,0 +1,161 @@
+package rdbms
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/ql"
+)
+
+// Geometry values are stored as GeoJSON with bounding box (minLng, minLat, maxLng, maxLat);
+// filters operate on bounding box and its center.
+const (
+	geoBBoxMinLng = iota
+	geoBBoxMinLat
+	geoBBoxMaxLng
+	geoBBoxMaxLat
+)
+
+// composeRecordGeoFunctionHandler translates geo functions used in record filters
+//
+// Supported functions:
+//  - GEO_DISTANCE(field, lat, lng)
+//    distance (in km) between geometry center and the given point
+//  - GEO_WITHIN(field, minLat, minLng, maxLat, maxLng)
+//    true when geometry's bounding box intersects with the given one
+//
+// geoColumns holds (resolved) columns of geometry fields
+//
+// All other functions are left intact
+func (s Store) composeRecordGeoFunctionHandler(geoColumns map[string]bool) ql.FunctionHandler {
+	return func(f ql.Function) (ql.ASTNode, error) {
+		name := strings.ToUpper(f.Name)
+		if (name == "GEO_DISTANCE" || name == "GEO_WITHIN") && s.config.SqlGeoBBoxExtractor == nil {
+			return nil, fmt.Errorf("%s function is not supported by this store backend", name)
+		}
+
+		switch name {
+		case "GEO_DISTANCE":
+			col, nn, err := geoFunctionArgs(f, geoColumns, 2)
+			if err != nil {
+				return nil, err
+			}
+
+			if err = geoValidateLatLng(f, nn[0], nn[1]); err != nil {
+				return nil, err
+			}
+
+			return ql.MakeFormattedNode(s.sqlGeoDistance(
+				fmt.Sprintf("((%s + %s) / 2)", s.sqlGeoBBox(col, geoBBoxMinLat), s.sqlGeoBBox(col, geoBBoxMaxLat)),
+				fmt.Sprintf("((%s + %s) / 2)", s.sqlGeoBBox(col, geoBBoxMinLng), s.sqlGeoBBox(col, geoBBoxMaxLng)),
+				geoFormatFloat(nn[0]),
+				geoFormatFloat(nn[1]),
+			)), nil
+
+		case "GEO_WITHIN":
+			col, nn, err := geoFunctionArgs(f, geoColumns, 4)
+			if err != nil {
+				return nil, err
+			}
+
+			if err = geoValidateLatLng(f, nn[0], nn[1]); err != nil {
+				return nil, err
+			}
+
+			if err = geoValidateLatLng(f, nn[2], nn[3]); err != nil {
+				return nil, err
+			}
+
+			return ql.MakeFormattedNode(fmt.Sprintf(
+				"(%s <= %s AND %s >= %s AND %s <= %s AND %s >= %s)",
+				s.sqlGeoBBox(col, geoBBoxMinLng), geoFormatFloat(nn[3]),
+				s.sqlGeoBBox(col, geoBBoxMaxLng), geoFormatFloat(nn[1]),
+				s.sqlGeoBBox(col, geoBBoxMinLat), geoFormatFloat(nn[2]),
+				s.sqlGeoBBox(col, geoBBoxMaxLat), geoFormatFloat(nn[0]),
+			)), nil
+		}
+
+		return f, nil
+	}
+}
+
+func (s Store) sqlGeoBBox(column string, index int) string {
+	return s.config.SqlGeoBBoxExtractor(column, index)
+}
+
+// sqlGeoDistance uses configured distance function or
+// falls back to haversine formula
+func (s Store) sqlGeoDistance(lat1, lng1, lat2, lng2 string) string {
+	if s.config.SqlGeoDistance != nil {
+		return s.config.SqlGeoDistance(lat1, lng1, lat2, lng2)
+	}
+
+	return fmt.Sprintf(
+		"(6371.0088 * 2 * ASIN(SQRT("+
+			"POWER(SIN(RADIANS(%[3]s - %[1]s) / 2), 2) + "+
+			"COS(RADIANS(%[1]s)) * COS(RADIANS(%[3]s)) * POWER(SIN(RADIANS(%[4]s - %[2]s) / 2), 2))))",
+		lat1, lng1, lat2, lng2,
+	)
+}
+
+// geoFunctionArgs verifies that the 1st argument is a geometry field
+// and the rest of them are numbers
+func geoFunctionArgs(f ql.Function, geoColumns map[string]bool, numbers int) (col string, nn []float64, err error) {
+	if len(f.Arguments) == 0 {
+		return "", nil, fmt.Errorf("expecting geometry field as first argument for %s function", f.Name)
+	}
+
+	if i, ok := f.Arguments[0].(ql.Ident); !ok || !geoColumns[i.Value] {
+		return "", nil, fmt.Errorf("expecting geometry field as first argument for %s function", f.Name)
+	} else {
+		col = i.Value
+	}
+
+	var neg bool
+	for _, a := range f.Arguments[1:] {
+		switch a := a.(type) {
+		case ql.Operator:
+			if a.Kind != "-" || neg {
+				return "", nil, fmt.Errorf("unexpected operator %q in %s function", a.Kind, f.Name)
+			}
+
+			neg = true
+			continue
+
+		case ql.LNumber:
+			n, err := strconv.ParseFloat(a.Value, 64)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid number %q in %s function", a.Value, f.Name)
+			}
+
+			if neg {
+				n = -n
+				neg = false
+			}
+
+			nn = append(nn, n)
+
+		default:
+			return "", nil, fmt.Errorf("expecting numeric arguments for %s function", f.Name)
+		}
+	}
+
+	if neg || len(nn) != numbers {
+		return "", nil, fmt.Errorf("expecting geometry field and exactly %d numeric arguments for %s function", numbers, f.Name)
+	}
+
+	return
+}
+
+func geoValidateLatLng(f ql.Function, lat, lng float64) error {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return fmt.Errorf("latitude or longitude out of range in %s function", f.Name)
+	}
+
+	return nil
+}
+
+func geoFormatFloat(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
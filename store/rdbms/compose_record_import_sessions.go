@@ -0,0 +1,63 @@
+package rdbms
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+func (s Store) convertComposeRecordImportSessionFilter(f types.RecordImportSessionFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.composeRecordImportSessionsSelectBuilder()
+
+	if f.NamespaceID > 0 {
+		query = query.Where(squirrel.Eq{"cris.rel_namespace": f.NamespaceID})
+	}
+
+	if f.ModuleID > 0 {
+		query = query.Where(squirrel.Eq{"cris.rel_module": f.ModuleID})
+	}
+
+	if f.OwnedBy > 0 {
+		query = query.Where(squirrel.Eq{"cris.owned_by": f.OwnedBy})
+	}
+
+	if f.UpdatedBefore != nil {
+		query = query.Where(squirrel.Lt{"cris.updated_at": f.UpdatedBefore})
+	}
+
+	return
+}
+
+// ClaimComposeRecordImportSession claims the import session for the caller
+//
+// Session is updated only when it was not changed since it was loaded (compared by updatedAt);
+// this way only one of the nodes that found the same interrupted session can resume it.
+func (s Store) ClaimComposeRecordImportSession(ctx context.Context, res *types.RecordImportSession) (bool, error) {
+	var (
+		claimedAt = time.Now().Round(time.Second)
+
+		q = s.UpdateBuilder(s.composeRecordImportSessionTable()).
+			Set("updated_at", claimedAt).
+			Where(squirrel.Eq{"id": res.ID, "updated_at": res.UpdatedAt})
+	)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	rsp, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, store.HandleError(err, s.config.ErrorHandler)
+	}
+
+	if n, err := rsp.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	res.UpdatedAt = claimedAt
+	return true, nil
+}
@@ -0,0 +1,474 @@
+package rdbms
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/ddl"
+)
+
+// Partitioned records
+//
+// Records of modules with partitioned storage are kept in a dedicated table (one per module)
+// with all values encoded as JSON (field name => list of values) in a single column.
+//
+// Single-value fields can (via field options) be stored in physical columns as well;
+// these are kept in sync with JSON and used (instead of JSON) for filtering & sorting.
+
+const (
+	composeRecordPartitionTablePfx     = "compose_record_"
+	composeRecordPartitionValuesColumn = "record_values"
+	composeRecordPartitionPhysicalPfx  = "v_"
+)
+
+type (
+	composeRecordPartitionUpgrader interface {
+		CreateTable(context.Context, *ddl.Table) error
+		AddColumn(context.Context, string, *ddl.Column) (bool, error)
+		Exec(context.Context, string, ...interface{}) error
+	}
+
+	composeRecordPartitionDropper interface {
+		DropTable(context.Context, string) (bool, error)
+	}
+
+	// partitioned record values, encoded as JSON
+	composeRecordPartitionValues map[string][]string
+
+	// Ref field values are stored as text and need to be
+	// casted as numbers when compared
+	composeRecordPartitionRefField struct{}
+)
+
+func isPartitionedModule(m *types.Module) bool {
+	return m != nil && m.Config.Partitioned
+}
+
+// composeRecordPartitionSchema returns definition of the dedicated record table for the module
+func (s Store) composeRecordPartitionSchema(m *types.Module) *ddl.Table {
+	t := ddl.TableDef(composeRecordPartitionTable(m),
+		ddl.ID,
+		ddl.ColumnDef("rel_namespace", ddl.ColumnTypeIdentifier),
+		ddl.ColumnDef("module_id", ddl.ColumnTypeIdentifier),
+		ddl.ColumnDef("owned_by", ddl.ColumnTypeIdentifier),
+		ddl.CUDTimestamps,
+		ddl.CUDUsers,
+		ddl.ColumnDef(composeRecordPartitionValuesColumn, ddl.ColumnTypeJson),
+
+		ddl.AddIndex("owner", ddl.IColumn("owned_by")),
+	)
+
+	for _, f := range m.PhysicalFields() {
+		t.Apply(ddl.ColumnDef(s.composeRecordPartitionPhysicalColumn(f), ddl.ColumnTypeText, ddl.Null))
+	}
+
+	return t
+}
+
+// UpgradeComposeRecordPartitionTable creates dedicated record table for the module
+// or adds missing physical columns to an existing one
+//
+// Newly added physical columns are populated with values from JSON
+func (s Store) UpgradeComposeRecordPartitionTable(ctx context.Context, u composeRecordPartitionUpgrader, m *types.Module) (err error) {
+	if !isPartitionedModule(m) {
+		return nil
+	}
+
+	var (
+		t     = s.composeRecordPartitionSchema(m)
+		added bool
+	)
+
+	if err = u.CreateTable(ctx, t); err != nil {
+		return fmt.Errorf("could not create partitioned record table %s: %w", t.Name, err)
+	}
+
+	for _, f := range m.PhysicalFields() {
+		col := &ddl.Column{
+			Name:   s.composeRecordPartitionPhysicalColumn(f),
+			Type:   ddl.ColumnType{Type: ddl.ColumnTypeText},
+			IsNull: true,
+		}
+
+		if added, err = u.AddColumn(ctx, t.Name, col); err != nil {
+			return err
+		} else if !added {
+			continue
+		}
+
+		err = u.Exec(ctx, fmt.Sprintf(
+			"UPDATE %s SET %s = %s",
+			t.Name,
+			col.Name,
+			s.config.SqlJsonValueExtractor(composeRecordPartitionValuesColumn, f.Name, 0),
+		))
+
+		if err != nil {
+			return fmt.Errorf("could not populate physical column %s: %w", col.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// DropComposeRecordPartitionTable removes dedicated record table of the module
+// together with all records stored in it
+func (s Store) DropComposeRecordPartitionTable(ctx context.Context, d composeRecordPartitionDropper, m *types.Module) (err error) {
+	if !isPartitionedModule(m) {
+		return nil
+	}
+
+	if _, err = d.DropTable(ctx, composeRecordPartitionTable(m)); err != nil {
+		return fmt.Errorf("could not drop partitioned record table: %w", err)
+	}
+
+	return nil
+}
+
+func composeRecordPartitionTable(m *types.Module, aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return fmt.Sprintf("%s%d%s", composeRecordPartitionTablePfx, m.ID, alias)
+}
+
+// composeRecordPartitionPhysicalColumn returns quoted name of the physical column
+//
+// Field names are validated by the module service but they still come from the user
+// and are quoted to keep them from being interpreted as SQL
+func (s Store) composeRecordPartitionPhysicalColumn(f *types.ModuleField) string {
+	return s.config.SqlIdentQuoter(composeRecordPartitionPhysicalPfx + f.Name)
+}
+
+// composeRecordModuleTable returns partitioned or the default record table
+func (s Store) composeRecordModuleTable(m *types.Module, aa ...string) string {
+	if isPartitionedModule(m) {
+		return composeRecordPartitionTable(m, aa...)
+	}
+
+	return s.composeRecordTable(aa...)
+}
+
+// composeRecordModuleSelectBuilder selects records from the partitioned or the default table
+//
+// Values are not selected; they are loaded by the post-load processor
+func (s Store) composeRecordModuleSelectBuilder(m *types.Module) squirrel.SelectBuilder {
+	return s.SelectBuilder(s.composeRecordModuleTable(m, "crd"), s.composeRecordColumns("crd")...)
+}
+
+// castComposeRecordField returns full cast, field column and type cast template for the module field
+func (s Store) castComposeRecordField(m *types.Module, f *types.ModuleField) (string, string, string, error) {
+	if !isPartitionedModule(m) {
+		full, fcp, tcp, err := s.config.CastModuleFieldToColumnType(f, f.Name)
+		return full, fmt.Sprintf(fcp, f.Name), tcp, err
+	}
+
+	var (
+		fc  string
+		det ModuleFieldTypeDetector = f
+	)
+
+	if f.IsRef() {
+		det = composeRecordPartitionRefField{}
+	}
+
+	if f.IsPhysical() {
+		fc = "crd." + s.composeRecordPartitionPhysicalColumn(f)
+	} else if s.config.SqlJsonValueExtractor != nil {
+		// multi-value fields are filtered & sorted by the first value
+		fc = s.config.SqlJsonValueExtractor("crd."+composeRecordPartitionValuesColumn, f.Name, 0)
+	} else {
+		return "", "", "", fmt.Errorf("partitioned records not supported by the store")
+	}
+
+	_, _, tcp, err := s.config.CastModuleFieldToColumnType(det, f.Name)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return fmt.Sprintf(tcp, fc), fc, tcp, nil
+}
+
+func (s Store) lookupPartitionedComposeRecordByID(ctx context.Context, m *types.Module, id uint64) (res *types.Record, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.composeRecordModuleSelectBuilder(m).Where(squirrel.Eq{"crd.id": id}))
+	if err != nil {
+		return
+	}
+
+	if res, err = s.internalComposeRecordRowScanner(m, row); err != nil {
+		return
+	}
+
+	if err = s.composeRecordPostLoadProcessor(ctx, m, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s Store) createPartitionedComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
+	for _, res := range rr {
+		payload, err := s.internalComposeRecordPartitionEncoder(m, res)
+		if err != nil {
+			return err
+		}
+
+		if err = s.Exec(ctx, s.InsertBuilder(composeRecordPartitionTable(m)).SetMap(payload)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Store) updatePartitionedComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
+	for _, res := range rr {
+		payload, err := s.internalComposeRecordPartitionEncoder(m, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.Exec(ctx, s.UpdateBuilder(composeRecordPartitionTable(m)).
+			Where(squirrel.Eq{"id": res.ID}).
+			SetMap(payload.Skip("id")))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Store) upsertPartitionedComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
+	for _, res := range rr {
+		payload, err := s.internalComposeRecordPartitionEncoder(m, res)
+		if err != nil {
+			return err
+		}
+
+		upsert, err := s.config.UpsertBuilder(s.config, composeRecordPartitionTable(m), payload, "id")
+		if err != nil {
+			return err
+		}
+
+		if err = s.Exec(ctx, upsert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Store) deletePartitionedComposeRecordByID(ctx context.Context, m *types.Module, ID uint64) error {
+	return s.Exec(ctx, s.DeleteBuilder(composeRecordPartitionTable(m)).Where(squirrel.Eq{"id": ID}))
+}
+
+func (s Store) truncatePartitionedComposeRecords(ctx context.Context, m *types.Module) error {
+	return s.Exec(ctx, s.DeleteBuilder(composeRecordPartitionTable(m)))
+}
+
+// loads values from JSON column and decodes them into record value sets
+func (s Store) partitionedComposeRecordPostLoadProcessor(ctx context.Context, m *types.Module, set ...*types.Record) (err error) {
+	var (
+		rows *sql.Rows
+		vv   = make(map[uint64]types.RecordValueSet, len(set))
+
+		q = s.SelectBuilder(composeRecordPartitionTable(m, "crd"), "crd.id", "crd."+composeRecordPartitionValuesColumn).
+			Where(squirrel.Eq{"crd.id": types.RecordSet(set).IDs()})
+	)
+
+	if rows, err = s.Query(ctx, q); err != nil {
+		return
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ID  uint64
+			raw []byte
+			pv  = composeRecordPartitionValues{}
+		)
+
+		if err = rows.Scan(&ID, &raw); err != nil {
+			return errors.Store("could not scan partitioned composeRecord values: %s", err).Wrap(err)
+		}
+
+		if len(raw) > 0 {
+			if err = json.Unmarshal(raw, &pv); err != nil {
+				return errors.Store("could not decode partitioned composeRecord values: %s", err).Wrap(err)
+			}
+		}
+
+		vv[ID] = pv.decode(m, ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, r := range set {
+		r.Values = vv[r.ID]
+	}
+
+	return nil
+}
+
+// searches for a record that has a (first) value of the field set to ref
+func (s Store) partitionedComposeRecordValueRefLookup(ctx context.Context, m *types.Module, field string, ref uint64) (uint64, error) {
+	f := m.Fields.FindByName(field)
+	if f == nil {
+		return 0, fmt.Errorf("unknown field %q", field)
+	}
+
+	full, _, _, err := s.castComposeRecordField(m, f)
+	if err != nil {
+		return 0, err
+	}
+
+	q := s.SelectBuilder(composeRecordPartitionTable(m, "crd"), "crd.id").
+		Where(squirrel.Eq{"crd.deleted_at": nil}).
+		Where(squirrel.Expr(full+" = ?", ref)).
+		Limit(1)
+
+	row, err := s.QueryRow(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	var recordID uint64
+	if err = row.Scan(&recordID); errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return recordID, nil
+}
+
+// updates values of the partitioned records one by one
+func (s Store) partialPartitionedComposeRecordValueUpdate(ctx context.Context, m *types.Module, vv ...*types.RecordValue) (err error) {
+	var r *types.Record
+
+	for _, v := range vv {
+		if r, err = s.lookupPartitionedComposeRecordByID(ctx, m, v.RecordID); err != nil {
+			return
+		}
+
+		r.Values = r.Values.Set(v)
+		if err = s.updatePartitionedComposeRecord(ctx, m, r); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+func (s Store) internalComposeRecordPartitionEncoder(m *types.Module, res *types.Record) (store.Payload, error) {
+	var (
+		payload = s.internalComposeRecordEncoder(res)
+		pv      = encodeComposeRecordPartitionValues(res.Values)
+	)
+
+	enc, err := json.Marshal(pv)
+	if err != nil {
+		return nil, err
+	}
+
+	payload[composeRecordPartitionValuesColumn] = string(enc)
+
+	for _, f := range m.PhysicalFields() {
+		if len(pv[f.Name]) > 0 {
+			payload[s.composeRecordPartitionPhysicalColumn(f)] = pv[f.Name][0]
+		} else {
+			payload[s.composeRecordPartitionPhysicalColumn(f)] = nil
+		}
+	}
+
+	return payload, nil
+}
+
+// encodes all (non-deleted) values, ordered by place
+func encodeComposeRecordPartitionValues(vv types.RecordValueSet) composeRecordPartitionValues {
+	var (
+		pv    = composeRecordPartitionValues{}
+		clean = vv.GetClean()
+	)
+
+	sort.SliceStable(clean, func(i, j int) bool {
+		return clean[i].Place < clean[j].Place
+	})
+
+	for _, v := range clean {
+		pv[v.Name] = append(pv[v.Name], v.Value)
+	}
+
+	return pv
+}
+
+// decodes values into record value set
+//
+// Values of known fields are ordered as fields on the module,
+// followed by values of unknown fields (ordered by name)
+func (pv composeRecordPartitionValues) decode(m *types.Module, recordID uint64) (out types.RecordValueSet) {
+	var (
+		names = make([]string, 0, len(pv))
+		known = make(map[string]bool)
+	)
+
+	for _, f := range m.Fields {
+		known[f.Name] = true
+		names = append(names, f.Name)
+	}
+
+	unknown := make([]string, 0)
+	for name := range pv {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+	names = append(names, unknown...)
+
+	out = types.RecordValueSet{}
+	for _, name := range names {
+		f := m.Fields.FindByName(name)
+
+		for place, value := range pv[name] {
+			v := &types.RecordValue{
+				RecordID: recordID,
+				Name:     name,
+				Value:    value,
+				Place:    uint(place),
+			}
+
+			if f != nil && f.IsRef() {
+				v.Ref, _ = strconv.ParseUint(value, 10, 64)
+			}
+
+			out = append(out, v)
+		}
+	}
+
+	return
+}
+
+func (composeRecordPartitionRefField) IsBoolean() bool  { return false }
+func (composeRecordPartitionRefField) IsNumeric() bool  { return true }
+func (composeRecordPartitionRefField) IsDateTime() bool { return false }
+func (composeRecordPartitionRefField) IsRef() bool      { return false }
@@ -40,6 +40,9 @@ type (
 		Query(context.Context, squirrel.Sqlizer) (*sql.Rows, error)
 		SqlFunctionHandler(f ql.Function) (ql.ASTNode, error)
 		FieldToColumnTypeCaster(f ModuleFieldTypeDetector, i ql.Ident) (ql.Ident, error)
+
+		composeRecordModuleTable(m *types.Module, aa ...string) string
+		castComposeRecordField(m *types.Module, f *types.ModuleField) (string, string, string, error)
 	}
 )
 
@@ -135,7 +138,7 @@ func (b *recordReportBuilder) Build() (sb squirrel.SelectBuilder, err error) {
 	var (
 		joinTpl = "compose_record_value AS rv_%s ON (rv_%s.record_id = crd.id AND rv_%s.name = '%s' AND rv_%s.deleted_at IS NULL)"
 
-		report = b.store.SelectBuilder("compose_record AS crd").
+		report = b.store.SelectBuilder(b.store.composeRecordModuleTable(b.module, "crd")).
 			Column(squirrel.Alias(squirrel.Expr("COUNT(*)"), "count")).
 			Where("crd.deleted_at IS NULL").
 			Where("crd.module_id = ?", b.module.ID)
@@ -168,6 +171,12 @@ func (b *recordReportBuilder) Build() (sb squirrel.SelectBuilder, err error) {
 			return i, fmt.Errorf("invalid field name: %q", i.Value)
 		}
 
+		if b.module.Config.Partitioned {
+			var err error
+			i.Value, _, _, err = b.store.castComposeRecordField(b.module, b.module.Fields.FindByName(i.Value))
+			return i, err
+		}
+
 		if !alreadyJoined(i.Value) {
 			report = report.LeftJoin(strings.ReplaceAll(joinTpl, "%s", i.Value))
 		}
@@ -0,0 +1,24 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+)
+
+func (s Store) convertComposeRecordRevisionFilter(f types.RecordRevisionFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.composeRecordRevisionsSelectBuilder()
+
+	if f.RecordID > 0 {
+		query = query.Where(squirrel.Eq{"crr.rel_record": f.RecordID})
+	}
+
+	if f.ModuleID > 0 {
+		query = query.Where(squirrel.Eq{"crr.rel_module": f.ModuleID})
+	}
+
+	if f.NamespaceID > 0 {
+		query = query.Where(squirrel.Eq{"crr.rel_namespace": f.NamespaceID})
+	}
+
+	return
+}
@@ -18,6 +18,10 @@ func (s Store) convertComposeRecordValueFilter(_ *types.Module, f types.RecordVa
 }
 
 func (s Store) ComposeRecordValueRefLookup(ctx context.Context, m *types.Module, field string, ref uint64) (uint64, error) {
+	if isPartitionedModule(m) {
+		return s.partitionedComposeRecordValueRefLookup(ctx, m, field, ref)
+	}
+
 	q := s.composeRecordValuesSelectBuilder().
 		Join(s.composeRecordTable("crd"), "crv.record_id = crd.id").
 		Where(squirrel.Eq{
@@ -47,6 +51,10 @@ func (s Store) ComposeRecordValueRefLookup(ctx context.Context, m *types.Module,
 
 // PartialComposeRecordValueUpdate updates specific record values across multiple records
 func (s Store) PartialComposeRecordValueUpdate(ctx context.Context, m *types.Module, vv ...*types.RecordValue) (err error) {
+	if isPartitionedModule(m) {
+		return s.partialPartitionedComposeRecordValueUpdate(ctx, m, vv...)
+	}
+
 	{
 		// handle standard record-value storage
 		for _, v := range vv {
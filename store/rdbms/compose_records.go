@@ -28,11 +28,11 @@ type (
 	}
 )
 
-func buildComposeRecordsCursor(cfg *Config, m *types.Module) func(cur *filter.PagingCursor) squirrel.Sqlizer {
+func buildComposeRecordsCursor(s Store, m *types.Module) func(cur *filter.PagingCursor) squirrel.Sqlizer {
 	return func(cur *filter.PagingCursor) squirrel.Sqlizer {
 		return builders.CursorCondition(cur, func(key string) (builders.KeyMap, error) {
 			if col, fd, is := isRealRecordCol(key); is {
-				_, _, tcp, _ := cfg.CastModuleFieldToColumnType(fd, key)
+				_, _, tcp, _ := s.config.CastModuleFieldToColumnType(fd, key)
 				// These values here won't be casted
 				return builders.KeyMap{
 					FieldCast:    col,
@@ -46,12 +46,11 @@ func buildComposeRecordsCursor(cfg *Config, m *types.Module) func(cur *filter.Pa
 				return builders.KeyMap{}, fmt.Errorf("unknown module field %q used in a cursor", key)
 			}
 
-			_, fcp, tcp, err := cfg.CastModuleFieldToColumnType(f, f.Name)
+			_, fc, tcp, err := s.castComposeRecordField(m, f)
 			if err != nil {
 				return builders.KeyMap{}, err
 			}
 
-			fc := fmt.Sprintf(fcp, key)
 			tc := fmt.Sprintf(tcp, fc)
 			rr := builders.KeyMap{
 				FieldCast:    fc,
@@ -64,12 +63,9 @@ func buildComposeRecordsCursor(cfg *Config, m *types.Module) func(cur *filter.Pa
 	}
 }
 
-// @todo support for partitioned records (records are partitioned into multiple record tables
-//       in this case, values are no longer separated into record_value (key-value) table but encoded as JSON
-// @todo support for partitioned record with (optional) physical columns for record values
-//       physical columns are part of module-field configuration
-
 // SearchComposeRecords returns all matching ComposeRecords from store
+//
+// Records of partitioned modules are searched in the dedicated table (see compose_record_partitions.go)
 func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f types.RecordFilter) (types.RecordSet, types.RecordFilter, error) {
 	var (
 		set []*types.Record
@@ -137,7 +133,7 @@ func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f type
 			ctx, m, q,
 			f.Sort, f.PageCursor, f.Limit,
 			f.Check,
-			buildComposeRecordsCursor(s.config, m),
+			buildComposeRecordsCursor(s, m),
 		)
 
 		if err != nil {
@@ -153,7 +149,7 @@ func (s Store) SearchComposeRecords(ctx context.Context, m *types.Module, f type
 			if f.Limit > 0 && uint(len(set)) == f.Limit {
 				// Build page navigation ONLY when limit is set and
 				// there are less items fetched then requested limit
-				if nav, err := s.composeRecordsPageNavigation(ctx, m, q, f, f.Sort, buildComposeRecordsCursor(s.config, m)); err != nil {
+				if nav, err := s.composeRecordsPageNavigation(ctx, m, q, f, f.Sort, buildComposeRecordsCursor(s, m)); err != nil {
 					return err
 				} else {
 					f.Total = nav.Total
@@ -300,7 +296,11 @@ func (s Store) composeRecordsPageNavigation(
 
 // LookupComposeRecordByID searches for compose record by ID
 // It returns compose record even if deleted
-func (s Store) LookupComposeRecordByID(ctx context.Context, _ *types.Module, id uint64) (res *types.Record, err error) {
+func (s Store) LookupComposeRecordByID(ctx context.Context, m *types.Module, id uint64) (res *types.Record, err error) {
+	if isPartitionedModule(m) {
+		return s.lookupPartitionedComposeRecordByID(ctx, m, id)
+	}
+
 	res, err = s.lookupComposeRecordByID(ctx, nil, id)
 	if err != nil {
 		return
@@ -315,6 +315,10 @@ func (s Store) CreateComposeRecord(ctx context.Context, m *types.Module, rr ...*
 		return
 	}
 
+	if isPartitionedModule(m) {
+		return s.createPartitionedComposeRecord(ctx, m, rr...)
+	}
+
 	for _, res := range rr {
 
 		err = s.createComposeRecord(ctx, nil, res)
@@ -340,6 +344,10 @@ func (s Store) UpdateComposeRecord(ctx context.Context, m *types.Module, rr ...*
 		return
 	}
 
+	if isPartitionedModule(m) {
+		return s.updatePartitionedComposeRecord(ctx, m, rr...)
+	}
+
 	for _, res := range rr {
 		err = s.updateComposeRecord(ctx, nil, res)
 		if err != nil {
@@ -397,12 +405,16 @@ func (s Store) DeleteComposeRecord(ctx context.Context, m *types.Module, rr ...*
 	return
 }
 
-// DeleteComposeRecord Deletes one or more ComposeRecords from store
+// UpsertComposeRecord updates or creates one or more ComposeRecords in store
 func (s Store) UpsertComposeRecord(ctx context.Context, m *types.Module, rr ...*types.Record) (err error) {
 	if err = validateRecordModule(m, rr...); err != nil {
 		return
 	}
 
+	if isPartitionedModule(m) {
+		return s.upsertPartitionedComposeRecord(ctx, m, rr...)
+	}
+
 	for _, res := range rr {
 		err = s.upsertComposeRecord(ctx, m, res)
 		if err != nil {
@@ -414,7 +426,11 @@ func (s Store) UpsertComposeRecord(ctx context.Context, m *types.Module, rr ...*
 }
 
 // DeleteComposeRecordByID Deletes ComposeRecord from store
-func (s Store) DeleteComposeRecordByID(ctx context.Context, _ *types.Module, ID uint64) (err error) {
+func (s Store) DeleteComposeRecordByID(ctx context.Context, m *types.Module, ID uint64) (err error) {
+	if isPartitionedModule(m) {
+		return s.deletePartitionedComposeRecordByID(ctx, m, ID)
+	}
+
 	err = s.deleteComposeRecordByID(ctx, nil, ID)
 	if err != nil {
 		return
@@ -429,7 +445,13 @@ func (s Store) DeleteComposeRecordByID(ctx context.Context, _ *types.Module, ID
 }
 
 // TruncateComposeRecords Deletes all ComposeRecords from store
-func (s Store) TruncateComposeRecords(ctx context.Context, _ *types.Module) (err error) {
+//
+// When partitioned module is given, only records of that module are removed
+func (s Store) TruncateComposeRecords(ctx context.Context, m *types.Module) (err error) {
+	if isPartitionedModule(m) {
+		return s.truncatePartitionedComposeRecords(ctx, m)
+	}
+
 	err = s.truncateComposeRecords(ctx, nil)
 	if err != nil {
 		return
@@ -493,6 +515,13 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 				return i, fmt.Errorf("unknown field %q", i.Value)
 			}
 
+			if isPartitionedModule(m) {
+				// partitioned records keep values in the same table, no joins needed
+				var castErr error
+				i.Value, _, _, castErr = s.castComposeRecordField(m, m.Fields.FindByName(i.Value))
+				return i, castErr
+			}
+
 			if !alreadyJoined(i.Value) {
 				join := composeRecordValueJoinTpl
 				join = strings.ReplaceAll(join, "{alias}", composeRecordValueAliasPfx+i.Value)
@@ -505,7 +534,7 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 	)
 
 	// Create query for fetching and counting records.
-	query = s.composeRecordsSelectBuilder().
+	query = s.composeRecordModuleSelectBuilder(m).
 		Where("crd.module_id = ?", m.ID).
 		Where("crd.rel_namespace = ?", m.NamespaceID)
 
@@ -524,11 +553,27 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 
 			// Filter node
 			fn ql.ASTNode
+
+			// Columns of geometry fields used in geo functions
+			geoColumns = make(map[string]bool)
 		)
 
 		// Resolve all identifiers found in the query
 		// into their table/column counterparts
-		fp.OnIdent = identResolver
+		fp.OnIdent = func(i ql.Ident) (ql.Ident, error) {
+			var (
+				mf       = m.Fields.FindByName(i.Value)
+				out, err = identResolver(i)
+			)
+
+			if err == nil && mf != nil && mf.IsGeometry() {
+				geoColumns[out.Value] = true
+			}
+
+			return out, err
+		}
+
+		fp.OnFunction = s.composeRecordGeoFunctionHandler(geoColumns)
 
 		if fn, err = fp.ParseExpression(f.Query); err != nil {
 			return
@@ -594,6 +639,10 @@ func (s Store) convertComposeRecordFilter(m *types.Module, f types.RecordFilter)
 //}
 
 func (s Store) composeRecordPostLoadProcessor(ctx context.Context, m *types.Module, set ...*types.Record) (err error) {
+	if len(set) > 0 && isPartitionedModule(m) {
+		return s.partitionedComposeRecordPostLoadProcessor(ctx, m, set...)
+	}
+
 	if len(set) > 0 {
 		// Load all related record values and append them to each record
 		var (
@@ -626,8 +675,7 @@ func (s Store) composeRecordsSorter(m *types.Module, q squirrel.SelectBuilder, s
 		if col, has := sortable[strings.ToLower(c.Column)]; has {
 			sqlSort[i] = col
 		} else if f := m.Fields.FindByName(c.Column); f != nil {
-
-			sqlSort[i], _, _, err = s.config.CastModuleFieldToColumnType(f, c.Column)
+			sqlSort[i], _, _, err = s.castComposeRecordField(m, f)
 		} else {
 			err = fmt.Errorf("could not sort by unknown column: %s", c.Column)
 		}
@@ -221,12 +221,26 @@ func IWhere(cnd string) indexManipulator {
 	}
 }
 
+// Get returns column by name
+//
+// Quoted names are matched by their unquoted value
 func (cc Columns) Get(name string) *Column {
+	name = unquote(name)
 	for c := range cc {
-		if cc[c].Name == name {
+		if unquote(cc[c].Name) == name {
 			return cc[c]
 		}
 	}
 
 	return nil
 }
+
+// unquote removes double quotes or backticks around the identifier
+func unquote(ident string) string {
+	if l := len(ident); l > 1 && (ident[0] == '"' || ident[0] == '`') && ident[l-1] == ident[0] {
+		q := ident[:1]
+		return strings.ReplaceAll(ident[1:l-1], q+q, q)
+	}
+
+	return ident
+}
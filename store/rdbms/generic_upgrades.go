@@ -72,11 +72,26 @@ func (g genericUpgrades) Upgrade(ctx context.Context, t *ddl.Table) error {
 	case "compose_module":
 		return g.all(ctx,
 			g.AlterComposeModuleRenameJsonToMeta,
+			g.AlterComposeModuleAddConfig,
 		)
 	case "compose_module_field":
 		return g.all(ctx,
 			g.AlterComposeModuleFieldAddExpresions,
 		)
+	case "automation_sessions":
+		return g.all(ctx,
+			g.AlterAutomationSessionsAddStates,
+			g.AlterAutomationSessionsAddRevision,
+		)
+	case "automation_workflows":
+		return g.all(ctx,
+			g.AlterAutomationWorkflowsAddPublishedRevision,
+			g.AlterAutomationWorkflowsAddTimeout,
+		)
+	case "automation_triggers":
+		return g.all(ctx,
+			g.AlterAutomationTriggersAddWebhook,
+		)
 		//case "compose_attachment_binds":
 		//	return g.all(ctx,
 		//		g.MigrateComposeAttachmentsToBindsTable,
@@ -377,3 +392,87 @@ func (g genericUpgrades) AlterComposeModuleFieldAddExpresions(ctx context.Contex
 	_, err = g.u.AddColumn(ctx, "compose_module_field", col)
 	return
 }
+
+func (g genericUpgrades) AlterComposeModuleAddConfig(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "config",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeJson},
+			IsNull:       false,
+			DefaultValue: "'{}'",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "compose_module", col)
+	return
+}
+
+func (g genericUpgrades) AlterAutomationSessionsAddStates(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "states",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeJson},
+			IsNull:       false,
+			DefaultValue: "'[]'",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "automation_sessions", col)
+	return
+}
+
+func (g genericUpgrades) AlterAutomationSessionsAddRevision(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "revision",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeInteger},
+			IsNull:       false,
+			DefaultValue: "0",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "automation_sessions", col)
+	return
+}
+
+func (g genericUpgrades) AlterAutomationWorkflowsAddPublishedRevision(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "published_revision",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeInteger},
+			IsNull:       false,
+			DefaultValue: "0",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "automation_workflows", col)
+	return
+}
+
+func (g genericUpgrades) AlterAutomationWorkflowsAddTimeout(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "timeout",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeInteger},
+			IsNull:       false,
+			DefaultValue: "0",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "automation_workflows", col)
+	return
+}
+
+func (g genericUpgrades) AlterAutomationTriggersAddWebhook(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "webhook",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeJson},
+			IsNull:       false,
+			DefaultValue: "'{}'",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "automation_triggers", col)
+	return
+}
@@ -339,8 +339,9 @@ func (s Store) FieldToColumnTypeCaster(f ModuleFieldTypeDetector, i ql.Ident) (q
 // It utilizes configured transaction error handlers and max-retry limits
 // to determine if and how many times transaction should be retried
 //
+// Disabled transactions are still started when required by the context (see store.TxRequired)
 func tx(ctx context.Context, dbCandidate interface{}, cfg *Config, txOpt *sql.TxOptions, task func(context.Context, dbLayer) error) error {
-	if cfg.TxDisabled {
+	if cfg.TxDisabled && !store.IsTxRequired(ctx) {
 		return task(ctx, dbCandidate.(dbLayer))
 	}
 
@@ -115,6 +115,29 @@ type (
 		SqlSortHandler func(exp string, desc bool) string
 
 		CastModuleFieldToColumnType func(ModuleFieldTypeDetector, string) (string, string, string, error)
+
+		// SqlIdentQuoter quotes identifiers that are not known in advance
+		// (physical columns of partitioned compose records)
+		SqlIdentQuoter func(string) string
+
+		// SqlJsonValueExtractor returns expression that extracts (text) value
+		// at the given index from the array under the key in the JSON column
+		//
+		// Key is escaped by the extractor
+		// Used for querying partitioned compose records
+		SqlJsonValueExtractor func(column, key string, index int) string
+
+		// SqlGeoBBoxExtractor returns numeric expression that extracts bounding box
+		// value at the given index from the GeoJSON stored in the column
+		//
+		// Used for filtering records by geometry fields
+		SqlGeoBBoxExtractor func(column string, index int) string
+
+		// SqlGeoDistance returns expression that calculates distance (in km)
+		// between two points
+		//
+		// When not set, haversine formula with standard SQL math functions is used
+		SqlGeoDistance func(lat1, lng1, lat2, lng2 string) string
 	}
 )
 
@@ -149,6 +172,10 @@ func (c *Config) SetDefaults() {
 		c.UpsertBuilder = UpsertBuilder
 	}
 
+	if c.SqlIdentQuoter == nil {
+		c.SqlIdentQuoter = QuoteIdent
+	}
+
 	// ** ** ** ** ** ** ** ** ** ** ** ** ** **
 
 	if c.MaxIdleConns == 0 {
@@ -53,6 +53,7 @@ func (s Schema) Tables() []*Table {
 		s.AuthConfirmedClients(),
 		s.AuthSessions(),
 		s.AuthOA2Tokens(),
+		s.AuthSigningKeys(),
 		s.Roles(),
 		s.RoleMembers(),
 		s.Applications(),
@@ -63,6 +64,7 @@ func (s Schema) Tables() []*Table {
 		s.Settings(),
 		s.Labels(),
 		s.Flags(),
+		s.SchedulerLeases(),
 		s.Templates(),
 		s.ComposeAttachment(),
 		s.ComposeChart(),
@@ -72,14 +74,18 @@ func (s Schema) Tables() []*Table {
 		s.ComposePage(),
 		s.ComposeRecord(),
 		s.ComposeRecordValue(),
+		s.ComposeRecordRevision(),
+		s.ComposeRecordImportSession(),
 		s.FederationModuleShared(),
 		s.FederationModuleExposed(),
 		s.FederationModuleMapping(),
 		s.FederationNodes(),
 		s.FederationNodesSync(),
 		s.AutomationWorkflows(),
+		s.AutomationWorkflowRevisions(),
 		s.AutomationTriggers(),
 		s.AutomationSessions(),
+		s.AutomationWorkflowStats(),
 		//s.AutomationState(),
 	}
 }
@@ -192,6 +198,18 @@ func (Schema) AuthOA2Tokens() *Table {
 	)
 }
 
+func (Schema) AuthSigningKeys() *Table {
+	return TableDef(`auth_signing_keys`,
+		ID,
+
+		ColumnDef("algorithm", ColumnTypeVarchar, ColumnTypeLength(16)),
+		ColumnDef("private_key", ColumnTypeText),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("retired_at", ColumnTypeTimestamp, Null),
+		ColumnDef("expires_at", ColumnTypeTimestamp, Null),
+	)
+}
+
 func (Schema) Roles() *Table {
 	return TableDef(`roles`,
 		ID,
@@ -326,6 +344,17 @@ func (Schema) Flags() *Table {
 	)
 }
 
+func (Schema) SchedulerLeases() *Table {
+	return TableDef("scheduler_leases",
+		ColumnDef("name", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("holder", ColumnTypeVarchar, ColumnTypeLength(resourceLength)),
+		ColumnDef("expires_at", ColumnTypeTimestamp),
+		ColumnDef("updated_at", ColumnTypeTimestamp),
+
+		PrimaryKey(IColumn("name")),
+	)
+}
+
 func (Schema) Templates() *Table {
 	return TableDef("templates",
 		ID,
@@ -410,6 +439,7 @@ func (Schema) ComposeModule() *Table {
 		ColumnDef("handle", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
 		ColumnDef("name", ColumnTypeText),
 		ColumnDef("meta", ColumnTypeJson),
+		ColumnDef("config", ColumnTypeJson),
 		CUDTimestamps,
 
 		AddIndex("namespace", IColumn("rel_namespace")),
@@ -504,6 +534,44 @@ func (Schema) ComposeRecordValue() *Table {
 	)
 }
 
+func (Schema) ComposeRecordRevision() *Table {
+	return TableDef("compose_record_revision",
+		ID,
+		ColumnDef("rel_record", ColumnTypeIdentifier),
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("rel_namespace", ColumnTypeIdentifier),
+		ColumnDef("revision", ColumnTypeInteger),
+		ColumnDef("operation", ColumnTypeVarchar, ColumnTypeLength(16)),
+		ColumnDef("snapshot", ColumnTypeJson),
+		ColumnDef("owned_by", ColumnTypeIdentifier),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("created_by", ColumnTypeIdentifier),
+
+		AddIndex("unique_record_revision", IColumn("rel_record", "revision")),
+		AddIndex("module", IColumn("rel_module")),
+	)
+}
+
+func (Schema) ComposeRecordImportSession() *Table {
+	return TableDef("compose_record_import_session",
+		ID,
+		ColumnDef("rel_namespace", ColumnTypeIdentifier),
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("name", ColumnTypeText),
+		ColumnDef("content_type", ColumnTypeText),
+		ColumnDef("source", ColumnTypeBinary, ColumnTypeFlag("mysqlLongBlob", true)),
+		ColumnDef("on_error", ColumnTypeVarchar, ColumnTypeLength(16)),
+		ColumnDef("fields", ColumnTypeJson),
+		ColumnDef("progress", ColumnTypeJson),
+		ColumnDef("owned_by", ColumnTypeIdentifier),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("updated_at", ColumnTypeTimestamp),
+
+		AddIndex("owner", IColumn("owned_by")),
+		AddIndex("updated_at", IColumn("updated_at")),
+	)
+}
+
 func (Schema) FederationModuleShared() *Table {
 	return TableDef("federation_module_shared",
 		ID,
@@ -578,9 +646,11 @@ func (Schema) AutomationWorkflows() *Table {
 		ColumnDef("enabled", ColumnTypeBoolean),
 		ColumnDef("trace", ColumnTypeBoolean),
 		ColumnDef("keep_sessions", ColumnTypeInteger),
+		ColumnDef("timeout", ColumnTypeInteger),
 		ColumnDef("scope", ColumnTypeJson),
 		ColumnDef("steps", ColumnTypeJson),
 		ColumnDef("paths", ColumnTypeJson),
+		ColumnDef("published_revision", ColumnTypeInteger),
 		ColumnDef("issues", ColumnTypeJson),
 		ColumnDef("run_as", ColumnTypeIdentifier),
 		ColumnDef("owned_by", ColumnTypeIdentifier),
@@ -591,16 +661,45 @@ func (Schema) AutomationWorkflows() *Table {
 	)
 }
 
+func (Schema) AutomationWorkflowRevisions() *Table {
+	return TableDef("automation_workflow_revisions",
+		ID,
+		ColumnDef("rel_workflow", ColumnTypeIdentifier),
+		ColumnDef("revision", ColumnTypeInteger),
+		ColumnDef("scope", ColumnTypeJson),
+		ColumnDef("steps", ColumnTypeJson),
+		ColumnDef("paths", ColumnTypeJson),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("created_by", ColumnTypeIdentifier),
+
+		AddIndex("unique_workflow_revision", IColumn("rel_workflow", "revision")),
+	)
+}
+
+func (Schema) AutomationWorkflowStats() *Table {
+	return TableDef("automation_workflow_stats",
+		ID,
+		ColumnDef("rel_workflow", ColumnTypeIdentifier),
+		ColumnDef("start_at", ColumnTypeTimestamp),
+		ColumnDef("stats", ColumnTypeJson),
+		ColumnDef("steps", ColumnTypeJson),
+
+		AddIndex("workflow", IColumn("rel_workflow", "start_at")),
+	)
+}
+
 func (Schema) AutomationSessions() *Table {
 	return TableDef("automation_sessions",
 		ID,
 		ColumnDef("rel_workflow", ColumnTypeIdentifier),
+		ColumnDef("revision", ColumnTypeInteger),
 		ColumnDef("status", ColumnTypeInteger),
 		ColumnDef("event_type", ColumnTypeText, ColumnTypeLength(handleLength)),
 		ColumnDef("resource_type", ColumnTypeText, ColumnTypeLength(handleLength)),
 		ColumnDef("input", ColumnTypeJson),
 		ColumnDef("output", ColumnTypeJson),
 		ColumnDef("stacktrace", ColumnTypeJson),
+		ColumnDef("states", ColumnTypeJson),
 		ColumnDef("created_by", ColumnTypeIdentifier),
 		ColumnDef("created_at", ColumnTypeTimestamp),
 		ColumnDef("purge_at", ColumnTypeTimestamp, Null),
@@ -623,6 +722,7 @@ func (Schema) AutomationTriggers() *Table {
 		ColumnDef("event_type", ColumnTypeText, ColumnTypeLength(handleLength)),
 		ColumnDef("constraints", ColumnTypeJson),
 		ColumnDef("input", ColumnTypeJson),
+		ColumnDef("webhook", ColumnTypeJson),
 		ColumnDef("owned_by", ColumnTypeIdentifier),
 		CUDTimestamps,
 		CUDUsers,
@@ -0,0 +1,297 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/scheduler_leases.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/scheduler/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+var _ = errors.Is
+
+// QuerySchedulerLeases queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QuerySchedulerLeases(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.Lease) (bool, error),
+) ([]*types.Lease, error) {
+	var (
+		set = make([]*types.Lease, 0, DefaultSliceCapacity)
+		res *types.Lease
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalSchedulerLeaseRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		set = append(set, res)
+	}
+
+	return set, rows.Err()
+}
+
+// LookupSchedulerLeaseByName searches for lease by name
+func (s Store) LookupSchedulerLeaseByName(ctx context.Context, name string) (*types.Lease, error) {
+	return s.execLookupSchedulerLease(ctx, squirrel.Eq{
+		s.preprocessColumn("sls.name", ""): store.PreprocessValue(name, ""),
+	})
+}
+
+// CreateSchedulerLease creates one or more rows in scheduler_leases table
+func (s Store) CreateSchedulerLease(ctx context.Context, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+		err = s.checkSchedulerLeaseConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateSchedulerLeases(ctx, s.internalSchedulerLeaseEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateSchedulerLease updates one or more existing rows in scheduler_leases
+func (s Store) UpdateSchedulerLease(ctx context.Context, rr ...*types.Lease) error {
+	return s.partialSchedulerLeaseUpdate(ctx, nil, rr...)
+}
+
+// partialSchedulerLeaseUpdate updates one or more existing rows in scheduler_leases
+func (s Store) partialSchedulerLeaseUpdate(ctx context.Context, onlyColumns []string, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+		err = s.checkSchedulerLeaseConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateSchedulerLeases(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("sls.name", ""): store.PreprocessValue(res.Name, ""),
+			},
+			s.internalSchedulerLeaseEncoder(res).Skip("name").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertSchedulerLease updates one or more existing rows in scheduler_leases
+func (s Store) UpsertSchedulerLease(ctx context.Context, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+		err = s.checkSchedulerLeaseConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertSchedulerLeases(ctx, s.internalSchedulerLeaseEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteSchedulerLease Deletes one or more rows from scheduler_leases table
+func (s Store) DeleteSchedulerLease(ctx context.Context, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteSchedulerLeases(ctx, squirrel.Eq{
+			s.preprocessColumn("sls.name", ""): store.PreprocessValue(res.Name, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteSchedulerLeaseByName Deletes row from the scheduler_leases table
+func (s Store) DeleteSchedulerLeaseByName(ctx context.Context, name string) error {
+	return s.execDeleteSchedulerLeases(ctx, squirrel.Eq{
+		s.preprocessColumn("sls.name", ""): store.PreprocessValue(name, ""),
+	})
+}
+
+// TruncateSchedulerLeases Deletes all rows from the scheduler_leases table
+func (s Store) TruncateSchedulerLeases(ctx context.Context) error {
+	return s.Truncate(ctx, s.schedulerLeaseTable())
+}
+
+// execLookupSchedulerLease prepares SchedulerLease query and executes it,
+// returning types.Lease (or error)
+func (s Store) execLookupSchedulerLease(ctx context.Context, cnd squirrel.Sqlizer) (res *types.Lease, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.schedulerLeasesSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalSchedulerLeaseRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateSchedulerLeases updates all matched (by cnd) rows in scheduler_leases with given data
+func (s Store) execCreateSchedulerLeases(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.schedulerLeaseTable()).SetMap(payload))
+}
+
+// execUpdateSchedulerLeases updates all matched (by cnd) rows in scheduler_leases with given data
+func (s Store) execUpdateSchedulerLeases(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.schedulerLeaseTable("sls")).Where(cnd).SetMap(set))
+}
+
+// execUpsertSchedulerLeases inserts new or updates matching (by-primary-key) rows in scheduler_leases with given data
+func (s Store) execUpsertSchedulerLeases(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.schedulerLeaseTable(),
+		set,
+		s.preprocessColumn("name", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteSchedulerLeases Deletes all matched (by cnd) rows in scheduler_leases with given data
+func (s Store) execDeleteSchedulerLeases(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.schedulerLeaseTable("sls")).Where(cnd))
+}
+
+func (s Store) internalSchedulerLeaseRowScanner(row rowScanner) (res *types.Lease, err error) {
+	res = &types.Lease{}
+
+	if _, has := s.config.RowScanners["schedulerLease"]; has {
+		scanner := s.config.RowScanners["schedulerLease"].(func(_ rowScanner, _ *types.Lease) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.Name,
+			&res.Holder,
+			&res.ExpiresAt,
+			&res.UpdatedAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan schedulerLease db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QuerySchedulerLeases returns squirrel.SelectBuilder with set table and all columns
+func (s Store) schedulerLeasesSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.schedulerLeaseTable("sls"), s.schedulerLeaseColumns("sls")...)
+}
+
+// schedulerLeaseTable name of the db table
+func (Store) schedulerLeaseTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "scheduler_leases" + alias
+}
+
+// SchedulerLeaseColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) schedulerLeaseColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "name",
+		alias + "holder",
+		alias + "expires_at",
+		alias + "updated_at",
+	}
+}
+
+// {false true false false false false}
+
+// internalSchedulerLeaseEncoder encodes fields from types.Lease to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeSchedulerLease
+// func when rdbms.customEncoder=true
+func (s Store) internalSchedulerLeaseEncoder(res *types.Lease) store.Payload {
+	return store.Payload{
+		"name":       res.Name,
+		"holder":     res.Holder,
+		"expires_at": res.ExpiresAt,
+		"updated_at": res.UpdatedAt,
+	}
+}
+
+// checkSchedulerLeaseConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we can not rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkSchedulerLeaseConstraints(ctx context.Context, res *types.Lease) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package rdbms
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/scheduler/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// AcquireSchedulerLease acquires, renews or takes over an expired lease
+//
+// Lease is modified only when held by the same holder or when expired
+// and created when it does not exist yet. Update is done with a single
+// conditional statement so that only one of the competing nodes can succeed.
+//
+// Current state of the lease is returned; caller should check the holder
+func (s Store) AcquireSchedulerLease(ctx context.Context, l *types.Lease) (*types.Lease, error) {
+	var (
+		upd = s.UpdateBuilder(s.schedulerLeaseTable()).
+			SetMap(store.Payload{
+				"holder":     l.Holder,
+				"expires_at": l.ExpiresAt,
+				"updated_at": l.UpdatedAt,
+			}).
+			Where(squirrel.And{
+				squirrel.Eq{"name": l.Name},
+				squirrel.Or{
+					squirrel.Eq{"holder": l.Holder},
+					squirrel.Lt{"expires_at": l.UpdatedAt},
+				},
+			})
+	)
+
+	query, args, err := upd.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := s.db.ExecContext(ctx, query, args...)
+	if err = store.HandleError(err, s.config.ErrorHandler); err != nil {
+		return nil, err
+	}
+
+	if affected, err := rsp.RowsAffected(); err == nil && affected == 0 {
+		// lease does not exist yet or someone else holds it;
+		// failing with duplicate error means that another node was faster
+		err = s.execCreateSchedulerLeases(ctx, s.internalSchedulerLeaseEncoder(l))
+		if err != nil && !errors.IsDuplicateData(err) {
+			return nil, err
+		}
+	}
+
+	return s.LookupSchedulerLeaseByName(ctx, l.Name)
+}
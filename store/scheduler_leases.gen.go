@@ -0,0 +1,78 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/scheduler_leases.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/scheduler/types"
+)
+
+type (
+	SchedulerLeases interface {
+		LookupSchedulerLeaseByName(ctx context.Context, name string) (*types.Lease, error)
+
+		CreateSchedulerLease(ctx context.Context, rr ...*types.Lease) error
+
+		UpdateSchedulerLease(ctx context.Context, rr ...*types.Lease) error
+
+		UpsertSchedulerLease(ctx context.Context, rr ...*types.Lease) error
+
+		DeleteSchedulerLease(ctx context.Context, rr ...*types.Lease) error
+		DeleteSchedulerLeaseByName(ctx context.Context, name string) error
+
+		TruncateSchedulerLeases(ctx context.Context) error
+
+		// Additional custom functions
+
+		// AcquireSchedulerLease (custom function)
+		AcquireSchedulerLease(ctx context.Context, _lease *types.Lease) (*types.Lease, error)
+	}
+)
+
+var _ *types.Lease
+var _ context.Context
+
+// LookupSchedulerLeaseByName searches for lease by name
+func LookupSchedulerLeaseByName(ctx context.Context, s SchedulerLeases, name string) (*types.Lease, error) {
+	return s.LookupSchedulerLeaseByName(ctx, name)
+}
+
+// CreateSchedulerLease creates one or more SchedulerLeases in store
+func CreateSchedulerLease(ctx context.Context, s SchedulerLeases, rr ...*types.Lease) error {
+	return s.CreateSchedulerLease(ctx, rr...)
+}
+
+// UpdateSchedulerLease updates one or more (existing) SchedulerLeases in store
+func UpdateSchedulerLease(ctx context.Context, s SchedulerLeases, rr ...*types.Lease) error {
+	return s.UpdateSchedulerLease(ctx, rr...)
+}
+
+// UpsertSchedulerLease creates new or updates existing one or more SchedulerLeases in store
+func UpsertSchedulerLease(ctx context.Context, s SchedulerLeases, rr ...*types.Lease) error {
+	return s.UpsertSchedulerLease(ctx, rr...)
+}
+
+// DeleteSchedulerLease Deletes one or more SchedulerLeases from store
+func DeleteSchedulerLease(ctx context.Context, s SchedulerLeases, rr ...*types.Lease) error {
+	return s.DeleteSchedulerLease(ctx, rr...)
+}
+
+// DeleteSchedulerLeaseByName Deletes SchedulerLease from store
+func DeleteSchedulerLeaseByName(ctx context.Context, s SchedulerLeases, name string) error {
+	return s.DeleteSchedulerLeaseByName(ctx, name)
+}
+
+// TruncateSchedulerLeases Deletes all SchedulerLeases from store
+func TruncateSchedulerLeases(ctx context.Context, s SchedulerLeases) error {
+	return s.TruncateSchedulerLeases(ctx)
+}
+
+func AcquireSchedulerLease(ctx context.Context, s SchedulerLeases, _lease *types.Lease) (*types.Lease, error) {
+	return s.AcquireSchedulerLease(ctx, _lease)
+}
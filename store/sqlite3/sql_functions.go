@@ -1,8 +1,12 @@
 package sqlite3
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/geo"
 	"github.com/cortezaproject/corteza-server/pkg/ql"
+	"github.com/mattn/go-sqlite3"
+	"strconv"
 	"strings"
 )
 
@@ -28,3 +32,92 @@ func sqlFunctionHandler(f ql.Function) (ql.ASTNode, error) {
 
 	return f, nil
 }
+
+// registerFunctions adds custom functions to each new connection
+//
+// JSON1 extension is not compiled into the SQLite driver by default
+// so we provide our own function for extracting values from JSON
+//
+// Same goes for the math functions used in geo filters
+func registerFunctions(conn *sqlite3.SQLiteConn) (err error) {
+	if err = conn.RegisterFunc("json_value_at", jsonValueAt, true); err != nil {
+		return
+	}
+
+	if err = conn.RegisterFunc("geo_bbox", geoBBox, true); err != nil {
+		return
+	}
+
+	return conn.RegisterFunc("geo_distance", geoDistance, true)
+}
+
+// jsonValueAt returns value from the JSON array (under key) at the given index
+//
+// Returned as blob since it is the only type that can be converted to NULL
+func jsonValueAt(doc, key string, index int) ([]byte, error) {
+	var (
+		vv = make(map[string][]interface{})
+	)
+
+	if err := json.Unmarshal([]byte(doc), &vv); err != nil || index < 0 || index >= len(vv[key]) {
+		return nil, nil
+	}
+
+	switch v := vv[key][index].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// geoBBox returns bounding box value (at the given index) of the GeoJSON
+//
+// Returns NULL for empty or invalid values
+func geoBBox(doc interface{}, index int) ([]byte, error) {
+	var (
+		s string
+	)
+
+	switch d := doc.(type) {
+	case string:
+		s = d
+	case []byte:
+		s = string(d)
+	default:
+		return nil, nil
+	}
+
+	g, err := geo.Parse(s)
+	if err != nil || index < 0 || index >= len(g.BBox) {
+		return nil, nil
+	}
+
+	return []byte(strconv.FormatFloat(g.BBox[index], 'f', -1, 64)), nil
+}
+
+// geoDistance calculates distance between two points
+//
+// Returns NULL when any of the coordinates is NULL (no value)
+func geoDistance(lat1, lng1, lat2, lng2 interface{}) ([]byte, error) {
+	var (
+		ff = make([]float64, 4)
+	)
+
+	for i, v := range []interface{}{lat1, lng1, lat2, lng2} {
+		switch c := v.(type) {
+		case int64:
+			ff[i] = float64(c)
+		case float64:
+			ff[i] = c
+		case string:
+			ff[i], _ = strconv.ParseFloat(c, 64)
+		default:
+			return nil, nil
+		}
+	}
+
+	return []byte(strconv.FormatFloat(geo.Distance(ff[0], ff[1], ff[2], ff[3]), 'f', -1, 64)), nil
+}
@@ -36,3 +36,20 @@ func fieldToColumnTypeCaster(field rdbms.ModuleFieldTypeDetector, ident string)
 	fc := fmt.Sprintf(fcp, ident)
 	return fmt.Sprintf(tcp, fc), fcp, tcp, nil
 }
+
+// sqlJsonValueExtractor extracts value from the JSON array under the given key
+func sqlJsonValueExtractor(column, key string, index int) string {
+	return fmt.Sprintf(`CAST(json_value_at(%s, %s, %d) AS TEXT)`, column, rdbms.QuoteLiteral(key), index)
+}
+
+// sqlGeoBBoxExtractor extracts bounding box value from GeoJSON
+func sqlGeoBBoxExtractor(column string, index int) string {
+	return fmt.Sprintf(`CAST(geo_bbox(%s, %d) AS REAL)`, column, index)
+}
+
+// sqlGeoDistance calculates distance between two points
+//
+// SQLite does not provide trigonometric functions by default
+func sqlGeoDistance(lat1, lng1, lat2, lng2 string) string {
+	return fmt.Sprintf(`CAST(geo_distance(%s, %s, %s, %s) AS REAL)`, lat1, lng1, lat2, lng2)
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/ql"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/store/rdbms"
@@ -22,9 +24,16 @@ type (
 	}
 )
 
+const (
+	// driver with custom functions registered,
+	// used instead of the default "sqlite3" driver
+	sqlite3DriverName = "sqlite3+functions"
+)
+
 func init() {
 	store.Register(Connect, "sqlite3", "sqlite3+debug")
-	sql.Register("sqlite3+debug", sqlmw.Driver(new(sqlite3.SQLiteDriver), instrumentation.Debug()))
+	sql.Register(sqlite3DriverName, &sqlite3.SQLiteDriver{ConnectHook: registerFunctions})
+	sql.Register("sqlite3+debug", sqlmw.Driver(&sqlite3.SQLiteDriver{ConnectHook: registerFunctions}, instrumentation.Debug()))
 }
 
 func Connect(ctx context.Context, dsn string) (store.Storer, error) {
@@ -38,6 +47,10 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 		return nil, err
 	}
 
+	if cfg.DriverName == "sqlite3" {
+		cfg.DriverName = sqlite3DriverName
+	}
+
 	cfg.PlaceholderFormat = squirrel.Dollar
 	cfg.TxRetryErrHandler = txRetryErrHandler
 	cfg.ErrorHandler = errorHandler
@@ -47,6 +60,9 @@ func Connect(ctx context.Context, dsn string) (store.Storer, error) {
 	cfg.TxDisabled = true
 	cfg.SqlFunctionHandler = sqlFunctionHandler
 	cfg.CastModuleFieldToColumnType = fieldToColumnTypeCaster
+	cfg.SqlJsonValueExtractor = sqlJsonValueExtractor
+	cfg.SqlGeoBBoxExtractor = sqlGeoBBoxExtractor
+	cfg.SqlGeoDistance = sqlGeoDistance
 
 	if s.Store, err = rdbms.Connect(ctx, cfg); err != nil {
 		return nil, err
@@ -73,6 +89,16 @@ func (s *Store) Upgrade(ctx context.Context, log *zap.Logger) (err error) {
 	return nil
 }
 
+// UpgradeComposeRecordPartition creates or upgrades dedicated table for partitioned records
+func (s *Store) UpgradeComposeRecordPartition(ctx context.Context, m *types.Module) error {
+	return s.Store.UpgradeComposeRecordPartitionTable(ctx, NewUpgrader(logger.Default(), s), m)
+}
+
+// DropComposeRecordPartition removes dedicated table for partitioned records
+func (s *Store) DropComposeRecordPartition(ctx context.Context, m *types.Module) error {
+	return s.Store.DropComposeRecordPartitionTable(ctx, NewUpgrader(logger.Default(), s), m)
+}
+
 // ProcDataSourceName validates given DSN and ensures
 // params are present and correct
 func ProcDataSourceName(in string) (*rdbms.Config, error) {
@@ -119,7 +145,7 @@ func errorHandler(err error) error {
 	if err != nil {
 		if implErr, ok := err.(sqlite3.Error); ok {
 			switch implErr.ExtendedCode {
-			case sqlite3.ErrConstraintUnique:
+			case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
 				return store.ErrNotUnique.Wrap(implErr)
 			}
 		}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func testAutomationWorkflowRevisions(t *testing.T, s store.Storer) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		workflowID = id.Next()
+
+		makeNew = func(workflowID uint64, rev uint, stepIDs ...uint64) *types.WorkflowRevision {
+			res := &types.WorkflowRevision{
+				ID:         id.Next(),
+				WorkflowID: workflowID,
+				Revision:   rev,
+				CreatedAt:  time.Now(),
+			}
+
+			for _, stepID := range stepIDs {
+				res.Steps = append(res.Steps, &types.WorkflowStep{ID: stepID, Kind: types.WorkflowStepKindExpressions})
+			}
+
+			return res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req.NoError(s.CreateAutomationWorkflowRevision(ctx, makeNew(workflowID, 1, 1)))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		rev := makeNew(workflowID, 1, 1, 2)
+		req.NoError(s.CreateAutomationWorkflowRevision(ctx, rev))
+
+		fetched, err := s.LookupAutomationWorkflowRevisionByID(ctx, rev.ID)
+		req.NoError(err)
+		req.Equal(rev.ID, fetched.ID)
+		req.Equal(rev.WorkflowID, fetched.WorkflowID)
+		req.Len(fetched.Steps, 2)
+		req.Equal(uint64(2), fetched.Steps[1].ID)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req.NoError(s.TruncateAutomationWorkflowRevisions(ctx))
+		req.NoError(s.CreateAutomationWorkflowRevision(ctx,
+			makeNew(workflowID, 1),
+			makeNew(workflowID, 2),
+			makeNew(workflowID, 3),
+			makeNew(id.Next(), 1),
+		))
+
+		f := types.WorkflowRevisionFilter{WorkflowID: workflowID}
+		req.NoError(f.Sort.Set("revision DESC"))
+		f.Limit = 2
+
+		set, _, err := s.SearchAutomationWorkflowRevisions(ctx, f)
+		req.NoError(err)
+		req.Len(set, 2)
+		req.Equal(uint(3), set[0].Revision)
+		req.Equal(uint(2), set[1].Revision)
+
+		set, _, err = s.SearchAutomationWorkflowRevisions(ctx, types.WorkflowRevisionFilter{WorkflowID: workflowID, Revision: 2})
+		req.NoError(err)
+		req.Len(set, 1)
+	})
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func testAutomationWorkflowStatsBuckets(t *testing.T, s store.Storer) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		workflowID = id.Next()
+		startAt    = time.Now().Truncate(time.Minute)
+
+		makeNew = func(workflowID uint64, startAt time.Time) *types.WorkflowStatsBucket {
+			return &types.WorkflowStatsBucket{
+				ID:         id.Next(),
+				WorkflowID: workflowID,
+				StartAt:    startAt,
+				Stats:      types.ExecutionStats{Executions: 2, Failures: 1},
+				Steps:      types.WorkflowStatsBucketSteps{10: {Executions: 3}},
+			}
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req.NoError(s.CreateAutomationWorkflowStatsBucket(ctx, makeNew(workflowID, startAt)))
+	})
+
+	t.Run("upsert and lookup by ID", func(t *testing.T) {
+		b := makeNew(workflowID, startAt)
+		req.NoError(s.UpsertAutomationWorkflowStatsBucket(ctx, b))
+
+		b.Stats.Executions = 5
+		req.NoError(s.UpsertAutomationWorkflowStatsBucket(ctx, b))
+
+		fetched, err := s.LookupAutomationWorkflowStatsBucketByID(ctx, b.ID)
+		req.NoError(err)
+		req.Equal(uint(5), fetched.Stats.Executions)
+		req.Equal(uint(1), fetched.Stats.Failures)
+		req.Equal(uint(3), fetched.Steps[10].Executions)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req.NoError(s.TruncateAutomationWorkflowStatsBuckets(ctx))
+		req.NoError(s.CreateAutomationWorkflowStatsBucket(ctx,
+			makeNew(workflowID, startAt.Add(-2*time.Minute)),
+			makeNew(workflowID, startAt.Add(-time.Minute)),
+			makeNew(workflowID, startAt),
+			makeNew(id.Next(), startAt),
+		))
+
+		from := startAt.Add(-time.Minute)
+		set, _, err := s.SearchAutomationWorkflowStatsBuckets(ctx, types.WorkflowStatsBucketFilter{WorkflowID: workflowID, From: &from})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		to := startAt.Add(-time.Minute)
+		set, _, err = s.SearchAutomationWorkflowStatsBuckets(ctx, types.WorkflowStatsBucketFilter{To: &to})
+		req.NoError(err)
+		req.Len(set, 2)
+	})
+}
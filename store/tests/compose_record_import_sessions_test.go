@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func testComposeRecordImportSessions(t *testing.T, s store.Storer) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		makeNew = func(updatedAt time.Time) *types.RecordImportSession {
+			return &types.RecordImportSession{
+				ID:          id.Next(),
+				NamespaceID: 1,
+				ModuleID:    2,
+				Name:        "import.csv",
+				Source:      []byte("name\nfoo\n"),
+				Fields:      types.RecordImportFields{"name": "name"},
+				Progress:    types.RecordImportProgress{EntryCount: 1},
+				OwnedBy:     3,
+				CreatedAt:   updatedAt,
+				UpdatedAt:   updatedAt,
+			}
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req.NoError(s.CreateComposeRecordImportSession(ctx, makeNew(time.Now().Round(time.Second))))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		ses := makeNew(time.Now().Round(time.Second))
+		req.NoError(s.CreateComposeRecordImportSession(ctx, ses))
+
+		fetched, err := s.LookupComposeRecordImportSessionByID(ctx, ses.ID)
+		req.NoError(err)
+		req.Equal(ses.ID, fetched.ID)
+		req.Equal("name\nfoo\n", string(fetched.Source))
+		req.Equal("name", fetched.Fields["name"])
+		req.Equal(uint64(1), fetched.Progress.EntryCount)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req.NoError(s.TruncateComposeRecordImportSessions(ctx))
+
+		var (
+			old = time.Now().Add(-time.Hour).Round(time.Second)
+			cut = time.Now().Add(-time.Minute)
+		)
+
+		req.NoError(s.CreateComposeRecordImportSession(ctx,
+			makeNew(old),
+			makeNew(time.Now().Round(time.Second)),
+		))
+
+		set, _, err := s.SearchComposeRecordImportSessions(ctx, types.RecordImportSessionFilter{UpdatedBefore: &cut})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.True(set[0].UpdatedAt.Equal(old))
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		ses := makeNew(time.Now().Add(-time.Hour).Round(time.Second))
+		req.NoError(s.CreateComposeRecordImportSession(ctx, ses))
+
+		stale, err := s.LookupComposeRecordImportSessionByID(ctx, ses.ID)
+		req.NoError(err)
+		other := *stale
+
+		claimed, err := s.ClaimComposeRecordImportSession(ctx, stale)
+		req.NoError(err)
+		req.True(claimed)
+
+		// session was updated in the meantime
+		claimed, err = s.ClaimComposeRecordImportSession(ctx, &other)
+		req.NoError(err)
+		req.False(claimed)
+	})
+}
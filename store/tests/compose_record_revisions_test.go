@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func testComposeRecordRevisions(t *testing.T, s store.Storer) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		recordID = id.Next()
+
+		makeNew = func(recordID uint64, rev uint, vv ...string) *types.RecordRevision {
+			res := &types.RecordRevision{
+				ID:          id.Next(),
+				RecordID:    recordID,
+				ModuleID:    1,
+				NamespaceID: 2,
+				Revision:    rev,
+				Operation:   types.RecordRevisionUpdate,
+				CreatedAt:   time.Now(),
+			}
+
+			for _, v := range vv {
+				res.Values = append(res.Values, &types.RecordValue{Name: "name", Value: v})
+			}
+
+			return res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req.NoError(s.CreateComposeRecordRevision(ctx, makeNew(recordID, 1, "foo")))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		rev := makeNew(recordID, 1, "foo", "bar")
+		req.NoError(s.CreateComposeRecordRevision(ctx, rev))
+
+		fetched, err := s.LookupComposeRecordRevisionByID(ctx, rev.ID)
+		req.NoError(err)
+		req.Equal(rev.ID, fetched.ID)
+		req.Equal(rev.RecordID, fetched.RecordID)
+		req.Equal(types.RecordRevisionUpdate, fetched.Operation)
+		req.Len(fetched.Values, 2)
+		req.Equal("bar", fetched.Values[1].Value)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req.NoError(s.TruncateComposeRecordRevisions(ctx))
+		req.NoError(s.CreateComposeRecordRevision(ctx,
+			makeNew(recordID, 1, "one"),
+			makeNew(recordID, 2, "two"),
+			makeNew(recordID, 3, "three"),
+			makeNew(id.Next(), 1, "other"),
+		))
+
+		f := types.RecordRevisionFilter{RecordID: recordID}
+		req.NoError(f.Sort.Set("revision DESC"))
+		f.Limit = 2
+
+		set, _, err := s.SearchComposeRecordRevisions(ctx, f)
+		req.NoError(err)
+		req.Len(set, 2)
+		req.Equal(uint(3), set[0].Revision)
+		req.Equal(uint(2), set[1].Revision)
+
+		set, _, err = s.SearchComposeRecordRevisions(ctx, types.RecordRevisionFilter{ModuleID: 1})
+		req.NoError(err)
+		req.Len(set, 4)
+	})
+}
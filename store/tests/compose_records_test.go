@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func testComposeRecords(t *testing.T, s store.ComposeRecords) {
+func testComposeRecords(t *testing.T, s store.Storer) {
 	var (
 		ctx = context.Background()
 
@@ -1618,4 +1618,110 @@ func testComposeRecords(t *testing.T, s store.ComposeRecords) {
 		req.Equal("1st,1;2nd,22;3rd,3", stringifyValues(set, "str1", "num1"))
 
 	})
+
+	t.Run("partitioned", func(t *testing.T) {
+		var (
+			err error
+			set types.RecordSet
+			f   types.RecordFilter
+
+			req = require.New(t)
+
+			pmod = &types.Module{
+				ID:          id.Next(),
+				NamespaceID: mod.NamespaceID,
+				Name:        "testComposeRecordsPartitioned",
+				Config:      types.ModuleConfig{Partitioned: true},
+				CreatedAt:   time.Now(),
+				Fields: types.ModuleFieldSet{
+					&types.ModuleField{Kind: "String", Name: "str1", Options: types.ModuleFieldOptions{}},
+					&types.ModuleField{Kind: "Number", Name: "num1", Options: types.ModuleFieldOptions{}},
+					&types.ModuleField{Kind: "String", Name: "strMulti", Multi: true},
+				},
+			}
+
+			makeNewPartitioned = func(vv ...*types.RecordValue) *types.Record {
+				r := makeNew(vv...)
+				r.ModuleID = pmod.ID
+				return r
+			}
+		)
+
+		req.NoError(s.TruncateComposeRecords(ctx, mod))
+
+		pmod.Fields[1].Options.SetIsPhysical(true)
+		req.NoError(store.UpgradeComposeRecordPartition(ctx, s, pmod))
+
+		req.NoError(s.CreateComposeRecord(ctx, pmod,
+			makeNewPartitioned(&types.RecordValue{Name: "str1", Value: "a"}, &types.RecordValue{Name: "num1", Value: "10"}),
+			makeNewPartitioned(&types.RecordValue{Name: "str1", Value: "b"}, &types.RecordValue{Name: "num1", Value: "2"}),
+			makeNewPartitioned(
+				&types.RecordValue{Name: "str1", Value: "c"},
+				&types.RecordValue{Name: "num1", Value: "3"},
+				&types.RecordValue{Name: "strMulti", Value: "m1"},
+				&types.RecordValue{Name: "strMulti", Value: "m2", Place: 1},
+			),
+		))
+
+		// records are not stored in the default table
+		set, _, err = s.SearchComposeRecords(ctx, mod, types.RecordFilter{})
+		req.NoError(err)
+		req.Len(set, 0)
+
+		f = types.RecordFilter{Query: "num1 > 2"}
+		req.NoError(f.Sort.Set("num1 DESC"))
+		set, _, err = s.SearchComposeRecords(ctx, pmod, f)
+		req.NoError(err)
+		req.Equal("a,10;c,3", stringifyValues(set, "str1", "num1"))
+
+		set, _, err = s.SearchComposeRecords(ctx, pmod, types.RecordFilter{Query: "str1 = 'c'"})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Len(set[0].Values, 4)
+		req.Equal("m2", set[0].Values.Get("strMulti", 1).Value)
+
+		fetched, err := s.LookupComposeRecordByID(ctx, pmod, set[0].ID)
+		req.NoError(err)
+		req.Equal(set[0].ID, fetched.ID)
+
+		fetched.Values = types.RecordValueSet{{RecordID: fetched.ID, Name: "str1", Value: "d"}, {RecordID: fetched.ID, Name: "num1", Value: "1"}}
+		req.NoError(s.UpdateComposeRecord(ctx, pmod, fetched))
+		req.NoError(s.PartialComposeRecordValueUpdate(ctx, pmod, &types.RecordValue{RecordID: fetched.ID, Name: "num1", Value: "4"}))
+
+		f = types.RecordFilter{}
+		req.NoError(f.Sort.Set("num1"))
+		set, _, err = s.SearchComposeRecords(ctx, pmod, f)
+		req.NoError(err)
+		req.Equal("b,2;d,4;a,10", stringifyValues(set, "str1", "num1"))
+
+		report, err := s.ComposeRecordReport(ctx, pmod, "MAX(num1)", "str1", "")
+		req.NoError(err)
+		req.Len(report, 3)
+
+		// adding physical column to an existing table populates it from JSON values
+		pmod.Fields[0].Options.SetIsPhysical(true)
+		req.NoError(store.UpgradeComposeRecordPartition(ctx, s, pmod))
+
+		set, _, err = s.SearchComposeRecords(ctx, pmod, types.RecordFilter{Query: "str1 = 'd'"})
+		req.NoError(err)
+		req.Equal("d,4", stringifyValues(set, "str1", "num1"))
+
+		req.NoError(s.DeleteComposeRecordByID(ctx, pmod, set[0].ID))
+
+		// upsert creates missing and updates existing records
+		upserted := makeNewPartitioned(&types.RecordValue{Name: "str1", Value: "e"}, &types.RecordValue{Name: "num1", Value: "5"})
+		req.NoError(s.UpsertComposeRecord(ctx, pmod, upserted))
+		upserted.Values = types.RecordValueSet{{RecordID: upserted.ID, Name: "str1", Value: "e"}, {RecordID: upserted.ID, Name: "num1", Value: "6"}}
+		req.NoError(s.UpsertComposeRecord(ctx, pmod, upserted))
+
+		set, _, err = s.SearchComposeRecords(ctx, pmod, types.RecordFilter{Query: "str1 = 'e'"})
+		req.NoError(err)
+		req.Equal("e,6", stringifyValues(set, "str1", "num1"))
+
+		req.NoError(s.TruncateComposeRecords(ctx, pmod))
+
+		set, _, err = s.SearchComposeRecords(ctx, pmod, types.RecordFilter{})
+		req.NoError(err)
+		req.Len(set, 0)
+	})
 }
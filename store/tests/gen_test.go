@@ -11,8 +11,11 @@ package tests
 //  - store/auth_confirmed_clients.yaml
 //  - store/auth_oa2tokens.yaml
 //  - store/auth_sessions.yaml
+//  - store/auth_signing_keys.yaml
 //  - store/automation_sessions.yaml
 //  - store/automation_triggers.yaml
+//  - store/automation_workflow_revisions.yaml
+//  - store/automation_workflow_stats_buckets.yaml
 //  - store/automation_workflows.yaml
 //  - store/compose_attachments.yaml
 //  - store/compose_charts.yaml
@@ -20,6 +23,8 @@ package tests
 //  - store/compose_modules.yaml
 //  - store/compose_namespaces.yaml
 //  - store/compose_pages.yaml
+//  - store/compose_record_import_sessions.yaml
+//  - store/compose_record_revisions.yaml
 //  - store/credentials.yaml
 //  - store/federation_exposed_modules.yaml
 //  - store/federation_module_mappings.yaml
@@ -32,6 +37,7 @@ package tests
 //  - store/reminders.yaml
 //  - store/role_members.yaml
 //  - store/roles.yaml
+//  - store/scheduler_leases.yaml
 //  - store/settings.yaml
 //  - store/templates.yaml
 //  - store/users.yaml
@@ -82,6 +88,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testAuthSessions(t, s)
 	})
 
+	// Run generated tests for AuthSigningKeys
+	t.Run("AuthSigningKeys", func(t *testing.T) {
+		testAuthSigningKeys(t, s)
+	})
+
 	// Run generated tests for AutomationSessions
 	t.Run("AutomationSessions", func(t *testing.T) {
 		testAutomationSessions(t, s)
@@ -92,6 +103,16 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testAutomationTriggers(t, s)
 	})
 
+	// Run generated tests for AutomationWorkflowRevisions
+	t.Run("AutomationWorkflowRevisions", func(t *testing.T) {
+		testAutomationWorkflowRevisions(t, s)
+	})
+
+	// Run generated tests for AutomationWorkflowStatsBuckets
+	t.Run("AutomationWorkflowStatsBuckets", func(t *testing.T) {
+		testAutomationWorkflowStatsBuckets(t, s)
+	})
+
 	// Run generated tests for AutomationWorkflows
 	t.Run("AutomationWorkflows", func(t *testing.T) {
 		testAutomationWorkflows(t, s)
@@ -127,6 +148,16 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testComposePages(t, s)
 	})
 
+	// Run generated tests for ComposeRecordImportSessions
+	t.Run("ComposeRecordImportSessions", func(t *testing.T) {
+		testComposeRecordImportSessions(t, s)
+	})
+
+	// Run generated tests for ComposeRecordRevisions
+	t.Run("ComposeRecordRevisions", func(t *testing.T) {
+		testComposeRecordRevisions(t, s)
+	})
+
 	// Run generated tests for ComposeRecordValues
 	t.Run("ComposeRecordValues", func(t *testing.T) {
 		testComposeRecordValues(t, s)
@@ -197,6 +228,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testRoles(t, s)
 	})
 
+	// Run generated tests for SchedulerLeases
+	t.Run("SchedulerLeases", func(t *testing.T) {
+		testSchedulerLeases(t, s)
+	})
+
 	// Run generated tests for Settings
 	t.Run("Settings", func(t *testing.T) {
 		testSettings(t, s)
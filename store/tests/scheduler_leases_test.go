@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/scheduler/types"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchedulerLeases(t *testing.T, s store.SchedulerLeases) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(holder string, at time.Time) *types.Lease {
+			return &types.Lease{
+				Name:      "scheduler",
+				Holder:    holder,
+				ExpiresAt: at.Add(time.Minute),
+				UpdatedAt: at,
+			}
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateSchedulerLeases(ctx))
+		req.NoError(s.CreateSchedulerLease(ctx, makeNew("node-1", *now())))
+	})
+
+	t.Run("lookup by name", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateSchedulerLeases(ctx))
+		req.NoError(s.CreateSchedulerLease(ctx, makeNew("node-1", *now())))
+
+		l, err := s.LookupSchedulerLeaseByName(ctx, "scheduler")
+		req.NoError(err)
+		req.Equal("node-1", l.Holder)
+	})
+
+	t.Run("acquire", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			n   = *now()
+		)
+
+		req.NoError(s.TruncateSchedulerLeases(ctx))
+
+		// first one gets it
+		l, err := s.AcquireSchedulerLease(ctx, makeNew("node-1", n))
+		req.NoError(err)
+		req.Equal("node-1", l.Holder)
+
+		// others can not take it over while it is valid
+		l, err = s.AcquireSchedulerLease(ctx, makeNew("node-2", n.Add(time.Second)))
+		req.NoError(err)
+		req.Equal("node-1", l.Holder)
+
+		// holder can renew it
+		l, err = s.AcquireSchedulerLease(ctx, makeNew("node-1", n.Add(time.Second*30)))
+		req.NoError(err)
+		req.Equal("node-1", l.Holder)
+		req.True(l.ExpiresAt.Equal(n.Add(time.Second * 90)))
+
+		// expired lease can be taken over
+		l, err = s.AcquireSchedulerLease(ctx, makeNew("node-2", n.Add(time.Minute*2)))
+		req.NoError(err)
+		req.Equal("node-2", l.Holder)
+	})
+}
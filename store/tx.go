@@ -2,6 +2,25 @@ package store
 
 import "context"
 
+type (
+	txRequiredCtxKey struct{}
+)
+
 func Tx(ctx context.Context, s Storer, fn func(context.Context, Storer) error) error {
 	return s.Tx(ctx, fn)
 }
+
+// TxRequired marks the context so that the transaction is started
+// even on stores with disabled transactions (SQLite)
+//
+// Use it only for short tasks that must be committed together;
+// on SQLite transaction locks tables for other connections
+func TxRequired(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txRequiredCtxKey{}, true)
+}
+
+// IsTxRequired returns true if the transaction is required by the context
+func IsTxRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(txRequiredCtxKey{}).(bool)
+	return required
+}
@@ -0,0 +1,620 @@
+package automation
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// system/automation/email_handler.yaml
+
+import (
+	"context"
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	gomail "gopkg.in/mail.v2"
+	"io"
+)
+
+var _ wfexec.ExecResponse
+
+type (
+	emailHandlerRegistry interface {
+		AddFunctions(ff ...*atypes.Function)
+		Type(ref string) expr.Type
+	}
+)
+
+func (h emailHandler) register() {
+	h.reg.AddFunctions(
+		h.Message(),
+		h.Attach(),
+		h.SendMessage(),
+		h.Send(),
+	)
+}
+
+type (
+	emailMessageArgs struct {
+		hasSubject bool
+		Subject    string
+
+		hasFrom bool
+		From    string
+
+		hasReplyTo bool
+		ReplyTo    string
+
+		hasTo    bool
+		To       interface{}
+		toString string
+		toKV     map[string]string
+
+		hasCc    bool
+		Cc       interface{}
+		ccString string
+		ccKV     map[string]string
+
+		hasBcc    bool
+		Bcc       interface{}
+		bccString string
+		bccKV     map[string]string
+
+		hasHtml      bool
+		Html         interface{}
+		htmlString   string
+		htmlDocument *RenderedDocument
+		htmlStream   io.Reader
+
+		hasPlain      bool
+		Plain         interface{}
+		plainString   string
+		plainDocument *RenderedDocument
+		plainStream   io.Reader
+	}
+
+	emailMessageResults struct {
+		Message *gomail.Message
+	}
+)
+
+func (a emailMessageArgs) GetTo() (bool, string, map[string]string) {
+	return a.hasTo, a.toString, a.toKV
+}
+
+func (a emailMessageArgs) GetCc() (bool, string, map[string]string) {
+	return a.hasCc, a.ccString, a.ccKV
+}
+
+func (a emailMessageArgs) GetBcc() (bool, string, map[string]string) {
+	return a.hasBcc, a.bccString, a.bccKV
+}
+
+func (a emailMessageArgs) GetHtml() (bool, string, *RenderedDocument, io.Reader) {
+	return a.hasHtml, a.htmlString, a.htmlDocument, a.htmlStream
+}
+
+func (a emailMessageArgs) GetPlain() (bool, string, *RenderedDocument, io.Reader) {
+	return a.hasPlain, a.plainString, a.plainDocument, a.plainStream
+}
+
+// Message function Builds email message
+//
+// expects implementation of message function:
+// func (h emailHandler) message(ctx context.Context, args *emailMessageArgs) (results *emailMessageResults, err error) {
+//    return
+// }
+func (h emailHandler) Message() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "emailMessage",
+		Kind:   "function",
+		Labels: map[string]string{"email": "step,workflow"},
+		Meta: &atypes.FunctionMeta{
+			Short:       "Builds email message",
+			Description: "Message can be extended with attachments before it is sent",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "subject",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "from",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "replyTo",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "to",
+				Types: []string{"String", "KV"},
+			},
+			{
+				Name:  "cc",
+				Types: []string{"String", "KV"},
+			},
+			{
+				Name:  "bcc",
+				Types: []string{"String", "KV"},
+			},
+			{
+				Name:  "html",
+				Types: []string{"String", "Document", "Reader"},
+			},
+			{
+				Name:  "plain",
+				Types: []string{"String", "Document", "Reader"},
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "message",
+				Types: []string{"EmailMessage"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &emailMessageArgs{
+					hasSubject: in.Has("subject"),
+					hasFrom:    in.Has("from"),
+					hasReplyTo: in.Has("replyTo"),
+					hasTo:      in.Has("to"),
+					hasCc:      in.Has("cc"),
+					hasBcc:     in.Has("bcc"),
+					hasHtml:    in.Has("html"),
+					hasPlain:   in.Has("plain"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			// Converting To argument
+			if args.hasTo {
+				aux := expr.Must(expr.Select(in, "to"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.toString = aux.Get().(string)
+				case h.reg.Type("KV").Type():
+					args.toKV = aux.Get().(map[string]string)
+				}
+			}
+
+			// Converting Cc argument
+			if args.hasCc {
+				aux := expr.Must(expr.Select(in, "cc"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.ccString = aux.Get().(string)
+				case h.reg.Type("KV").Type():
+					args.ccKV = aux.Get().(map[string]string)
+				}
+			}
+
+			// Converting Bcc argument
+			if args.hasBcc {
+				aux := expr.Must(expr.Select(in, "bcc"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.bccString = aux.Get().(string)
+				case h.reg.Type("KV").Type():
+					args.bccKV = aux.Get().(map[string]string)
+				}
+			}
+
+			// Converting Html argument
+			if args.hasHtml {
+				aux := expr.Must(expr.Select(in, "html"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.htmlString = aux.Get().(string)
+				case h.reg.Type("Document").Type():
+					args.htmlDocument = aux.Get().(*RenderedDocument)
+				case h.reg.Type("Reader").Type():
+					args.htmlStream = aux.Get().(io.Reader)
+				}
+			}
+
+			// Converting Plain argument
+			if args.hasPlain {
+				aux := expr.Must(expr.Select(in, "plain"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.plainString = aux.Get().(string)
+				case h.reg.Type("Document").Type():
+					args.plainDocument = aux.Get().(*RenderedDocument)
+				case h.reg.Type("Reader").Type():
+					args.plainStream = aux.Get().(io.Reader)
+				}
+			}
+
+			var results *emailMessageResults
+			if results, err = h.message(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Message (*gomail.Message) to EmailMessage
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("EmailMessage").Cast(results.Message); err != nil {
+					return
+				} else if err = expr.Assign(out, "message", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
+
+type (
+	emailAttachArgs struct {
+		hasMessage bool
+		Message    *gomail.Message
+
+		hasContent      bool
+		Content         interface{}
+		contentDocument *RenderedDocument
+		contentStream   io.Reader
+		contentString   string
+
+		hasName bool
+		Name    string
+
+		hasType bool
+		Type    string
+	}
+
+	emailAttachResults struct {
+		Message *gomail.Message
+	}
+)
+
+func (a emailAttachArgs) GetContent() (bool, *RenderedDocument, io.Reader, string) {
+	return a.hasContent, a.contentDocument, a.contentStream, a.contentString
+}
+
+// Attach function Adds attachment to email message
+//
+// expects implementation of attach function:
+// func (h emailHandler) attach(ctx context.Context, args *emailAttachArgs) (results *emailAttachResults, err error) {
+//    return
+// }
+func (h emailHandler) Attach() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "emailAttach",
+		Kind:   "function",
+		Labels: map[string]string{"email": "step,workflow"},
+		Meta: &atypes.FunctionMeta{
+			Short:       "Adds attachment to email message",
+			Description: "Rendered documents, compose attachment contents, streams and strings can be attached",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "message",
+				Types: []string{"EmailMessage"}, Required: true,
+			},
+			{
+				Name:  "content",
+				Types: []string{"Document", "Reader", "String"}, Required: true,
+			},
+			{
+				Name:  "name",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "type",
+				Types: []string{"String"},
+			},
+		},
+
+		Results: []*atypes.Param{
+
+			{
+				Name:  "message",
+				Types: []string{"EmailMessage"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &emailAttachArgs{
+					hasMessage: in.Has("message"),
+					hasContent: in.Has("content"),
+					hasName:    in.Has("name"),
+					hasType:    in.Has("type"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			// Converting Content argument
+			if args.hasContent {
+				aux := expr.Must(expr.Select(in, "content"))
+				switch aux.Type() {
+				case h.reg.Type("Document").Type():
+					args.contentDocument = aux.Get().(*RenderedDocument)
+				case h.reg.Type("Reader").Type():
+					args.contentStream = aux.Get().(io.Reader)
+				case h.reg.Type("String").Type():
+					args.contentString = aux.Get().(string)
+				}
+			}
+
+			var results *emailAttachResults
+			if results, err = h.attach(ctx, args); err != nil {
+				return
+			}
+
+			out = &expr.Vars{}
+
+			{
+				// converting results.Message (*gomail.Message) to EmailMessage
+				var (
+					tval expr.TypedValue
+				)
+
+				if tval, err = h.reg.Type("EmailMessage").Cast(results.Message); err != nil {
+					return
+				} else if err = expr.Assign(out, "message", tval); err != nil {
+					return
+				}
+			}
+
+			return
+		},
+	}
+}
+
+type (
+	emailSendMessageArgs struct {
+		hasMessage bool
+		Message    *gomail.Message
+	}
+)
+
+// SendMessage function Sends email message
+//
+// expects implementation of sendMessage function:
+// func (h emailHandler) sendMessage(ctx context.Context, args *emailSendMessageArgs) (err error) {
+//    return
+// }
+func (h emailHandler) SendMessage() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "emailSendMessage",
+		Kind:   "function",
+		Labels: map[string]string{"email": "step,workflow", "send": "step"},
+		Meta: &atypes.FunctionMeta{
+			Short: "Sends email message",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "message",
+				Types: []string{"EmailMessage"}, Required: true,
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &emailSendMessageArgs{
+					hasMessage: in.Has("message"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			return out, h.sendMessage(ctx, args)
+		},
+	}
+}
+
+type (
+	emailSendArgs struct {
+		hasSubject bool
+		Subject    string
+
+		hasFrom bool
+		From    string
+
+		hasReplyTo bool
+		ReplyTo    string
+
+		hasTo    bool
+		To       interface{}
+		toString string
+		toKV     map[string]string
+
+		hasCc    bool
+		Cc       interface{}
+		ccString string
+		ccKV     map[string]string
+
+		hasBcc    bool
+		Bcc       interface{}
+		bccString string
+		bccKV     map[string]string
+
+		hasHtml      bool
+		Html         interface{}
+		htmlString   string
+		htmlDocument *RenderedDocument
+		htmlStream   io.Reader
+
+		hasPlain      bool
+		Plain         interface{}
+		plainString   string
+		plainDocument *RenderedDocument
+		plainStream   io.Reader
+	}
+)
+
+func (a emailSendArgs) GetTo() (bool, string, map[string]string) {
+	return a.hasTo, a.toString, a.toKV
+}
+
+func (a emailSendArgs) GetCc() (bool, string, map[string]string) {
+	return a.hasCc, a.ccString, a.ccKV
+}
+
+func (a emailSendArgs) GetBcc() (bool, string, map[string]string) {
+	return a.hasBcc, a.bccString, a.bccKV
+}
+
+func (a emailSendArgs) GetHtml() (bool, string, *RenderedDocument, io.Reader) {
+	return a.hasHtml, a.htmlString, a.htmlDocument, a.htmlStream
+}
+
+func (a emailSendArgs) GetPlain() (bool, string, *RenderedDocument, io.Reader) {
+	return a.hasPlain, a.plainString, a.plainDocument, a.plainStream
+}
+
+// Send function Builds and sends email message
+//
+// expects implementation of send function:
+// func (h emailHandler) send(ctx context.Context, args *emailSendArgs) (err error) {
+//    return
+// }
+func (h emailHandler) Send() *atypes.Function {
+	return &atypes.Function{
+		Ref:    "emailSend",
+		Kind:   "function",
+		Labels: map[string]string{"email": "step,workflow", "send": "step"},
+		Meta: &atypes.FunctionMeta{
+			Short: "Builds and sends email message",
+		},
+
+		Parameters: []*atypes.Param{
+			{
+				Name:  "subject",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "from",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "replyTo",
+				Types: []string{"String"},
+			},
+			{
+				Name:  "to",
+				Types: []string{"String", "KV"},
+			},
+			{
+				Name:  "cc",
+				Types: []string{"String", "KV"},
+			},
+			{
+				Name:  "bcc",
+				Types: []string{"String", "KV"},
+			},
+			{
+				Name:  "html",
+				Types: []string{"String", "Document", "Reader"},
+			},
+			{
+				Name:  "plain",
+				Types: []string{"String", "Document", "Reader"},
+			},
+		},
+
+		Handler: func(ctx context.Context, in *expr.Vars) (out *expr.Vars, err error) {
+			var (
+				args = &emailSendArgs{
+					hasSubject: in.Has("subject"),
+					hasFrom:    in.Has("from"),
+					hasReplyTo: in.Has("replyTo"),
+					hasTo:      in.Has("to"),
+					hasCc:      in.Has("cc"),
+					hasBcc:     in.Has("bcc"),
+					hasHtml:    in.Has("html"),
+					hasPlain:   in.Has("plain"),
+				}
+			)
+
+			if err = in.Decode(args); err != nil {
+				return
+			}
+
+			// Converting To argument
+			if args.hasTo {
+				aux := expr.Must(expr.Select(in, "to"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.toString = aux.Get().(string)
+				case h.reg.Type("KV").Type():
+					args.toKV = aux.Get().(map[string]string)
+				}
+			}
+
+			// Converting Cc argument
+			if args.hasCc {
+				aux := expr.Must(expr.Select(in, "cc"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.ccString = aux.Get().(string)
+				case h.reg.Type("KV").Type():
+					args.ccKV = aux.Get().(map[string]string)
+				}
+			}
+
+			// Converting Bcc argument
+			if args.hasBcc {
+				aux := expr.Must(expr.Select(in, "bcc"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.bccString = aux.Get().(string)
+				case h.reg.Type("KV").Type():
+					args.bccKV = aux.Get().(map[string]string)
+				}
+			}
+
+			// Converting Html argument
+			if args.hasHtml {
+				aux := expr.Must(expr.Select(in, "html"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.htmlString = aux.Get().(string)
+				case h.reg.Type("Document").Type():
+					args.htmlDocument = aux.Get().(*RenderedDocument)
+				case h.reg.Type("Reader").Type():
+					args.htmlStream = aux.Get().(io.Reader)
+				}
+			}
+
+			// Converting Plain argument
+			if args.hasPlain {
+				aux := expr.Must(expr.Select(in, "plain"))
+				switch aux.Type() {
+				case h.reg.Type("String").Type():
+					args.plainString = aux.Get().(string)
+				case h.reg.Type("Document").Type():
+					args.plainDocument = aux.Get().(*RenderedDocument)
+				case h.reg.Type("Reader").Type():
+					args.plainStream = aux.Get().(io.Reader)
+				}
+			}
+
+			return out, h.send(ctx, args)
+		},
+	}
+}
@@ -0,0 +1,271 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	netmail "net/mail"
+	"path/filepath"
+	"strings"
+
+	atypes "github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/mail"
+	gomail "gopkg.in/mail.v2"
+)
+
+type (
+	emailHandler struct {
+		reg emailHandlerRegistry
+
+		// when set, dialers are used before the default (configured) dialer
+		dialers []mail.Dialer
+	}
+)
+
+func EmailHandler(reg emailHandlerRegistry) *emailHandler {
+	h := &emailHandler{
+		reg: reg,
+	}
+
+	h.register()
+	return h
+}
+
+func (h emailHandler) message(ctx context.Context, args *emailMessageArgs) (results *emailMessageResults, err error) {
+	results = &emailMessageResults{}
+	results.Message, err = h.build(args)
+	return
+}
+
+func (h emailHandler) attach(ctx context.Context, args *emailAttachArgs) (results *emailAttachResults, err error) {
+	var (
+		name, ctype = args.Name, args.Type
+		content     []byte
+	)
+
+	if args.Message == nil {
+		return nil, fmt.Errorf("email message not set")
+	}
+
+	switch {
+	case args.contentDocument != nil:
+		if name == "" {
+			name = args.contentDocument.Name
+		}
+
+		if ctype == "" {
+			ctype = args.contentDocument.Type
+		}
+
+		content, err = readContent(args.contentDocument.Document)
+	case args.contentStream != nil:
+		if f, ok := args.contentStream.(atypes.File); ok {
+			if name == "" {
+				name = f.FileName()
+			}
+
+			if ctype == "" {
+				ctype = f.ContentType()
+			}
+		}
+
+		content, err = readContent(args.contentStream)
+	default:
+		content = []byte(args.contentString)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read attachment content: %w", err)
+	}
+
+	if name == "" {
+		name = "attachment"
+	}
+
+	if ctype == "" {
+		if ctype = mime.TypeByExtension(filepath.Ext(name)); ctype == "" {
+			ctype = "application/octet-stream"
+		}
+	}
+
+	// Content is buffered so that the message
+	// can be (re)sent more than once
+	args.Message.Attach(
+		name,
+		gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(content)
+			return err
+		}),
+		gomail.SetHeader(map[string][]string{"Content-Type": {ctype}}),
+	)
+
+	return &emailAttachResults{Message: args.Message}, nil
+}
+
+func (h emailHandler) sendMessage(ctx context.Context, args *emailSendMessageArgs) error {
+	if args.Message == nil {
+		return fmt.Errorf("email message not set")
+	}
+
+	return mail.Send(args.Message, h.dialers...)
+}
+
+func (h emailHandler) send(ctx context.Context, args *emailSendArgs) error {
+	msg, err := h.build((*emailMessageArgs)(args))
+	if err != nil {
+		return err
+	}
+
+	return mail.Send(msg, h.dialers...)
+}
+
+// build creates new message from the given arguments
+//
+// Plain text part is added before the HTML part so that
+// clients that support HTML prefer it
+func (h emailHandler) build(args *emailMessageArgs) (msg *gomail.Message, err error) {
+	msg = mail.New()
+
+	if args.hasFrom {
+		if err = setEmailAddresses(msg, "From", args.From, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.hasReplyTo {
+		if err = setEmailAddresses(msg, "Reply-To", args.ReplyTo, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rcpt := range []struct {
+		field string
+		get   func() (bool, string, map[string]string)
+	}{
+		{"To", args.GetTo},
+		{"Cc", args.GetCc},
+		{"Bcc", args.GetBcc},
+	} {
+		if has, str, kv := rcpt.get(); has {
+			if err = setEmailAddresses(msg, rcpt.field, str, kv); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Message can be sent to Cc or Bcc recipients only
+	if len(msg.GetHeader("To"))+len(msg.GetHeader("Cc"))+len(msg.GetHeader("Bcc")) == 0 {
+		return nil, fmt.Errorf("email message without recipients")
+	}
+
+	msg.SetHeader("Subject", args.Subject)
+
+	var (
+		plain, html string
+	)
+
+	if plain, err = readEmailBody(args.GetPlain()); err != nil {
+		return nil, fmt.Errorf("could not read plain text body: %w", err)
+	}
+
+	if html, err = readEmailBody(args.GetHtml()); err != nil {
+		return nil, fmt.Errorf("could not read HTML body: %w", err)
+	}
+
+	switch {
+	case html == "":
+		// Make sure plain body is always set, even if empty
+		msg.SetBody("text/plain", plain)
+	case plain == "":
+		msg.SetBody("text/html", html)
+	default:
+		msg.SetBody("text/plain", plain)
+		msg.AddAlternative("text/html", html)
+	}
+
+	return msg, nil
+}
+
+// setEmailAddresses parses, validates and sets addresses to the message header
+//
+// String can contain comma separated list of addresses ("foo@bar.baz, Foo Baz <foo@baz.bar>");
+// KV maps email addresses to names
+func setEmailAddresses(msg *gomail.Message, field string, str string, kv map[string]string) error {
+	var (
+		aa = make([]string, 0, len(kv))
+	)
+
+	if str = strings.TrimSpace(str); str != "" {
+		list, err := netmail.ParseAddressList(str)
+		if err != nil {
+			return fmt.Errorf("invalid %s address: %w", field, err)
+		}
+
+		for _, a := range list {
+			if !mail.IsValidAddress(a.Address) {
+				return fmt.Errorf("invalid %s address: %s", field, a.Address)
+			}
+
+			aa = append(aa, msg.FormatAddress(a.Address, a.Name))
+		}
+	}
+
+	for email, name := range kv {
+		if !mail.IsValidAddress(email) {
+			return fmt.Errorf("invalid %s address: %s", field, email)
+		}
+
+		aa = append(aa, msg.FormatAddress(email, name))
+	}
+
+	if len(aa) > 0 {
+		msg.SetHeader(field, aa...)
+	}
+
+	return nil
+}
+
+func readEmailBody(has bool, str string, doc *RenderedDocument, stream io.Reader) (string, error) {
+	var (
+		buf []byte
+		err error
+	)
+
+	switch {
+	case !has:
+		return "", nil
+	case doc != nil:
+		buf, err = readContent(doc.Document)
+	case stream != nil:
+		buf, err = readContent(stream)
+	default:
+		return str, nil
+	}
+
+	return string(buf), err
+}
+
+// readContent reads all content from the reader
+//
+// Seekable readers (like rendered documents) are rewound first
+// so that they can be used more than once
+func readContent(r io.Reader) ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	if s, ok := r.(io.Seeker); ok {
+		if _, err := s.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	if b, ok := r.(*bytes.Buffer); ok {
+		return b.Bytes(), nil
+	}
+
+	return ioutil.ReadAll(r)
+}
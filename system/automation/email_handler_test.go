@@ -0,0 +1,156 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/mail"
+	"github.com/stretchr/testify/require"
+	gomail "gopkg.in/mail.v2"
+)
+
+type (
+	testDialer struct {
+		sent []*gomail.Message
+		err  error
+	}
+)
+
+func (d *testDialer) DialAndSend(mm ...*gomail.Message) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	d.sent = append(d.sent, mm...)
+	return nil
+}
+
+func renderMessage(req *require.Assertions, msg *gomail.Message) string {
+	buf := &bytes.Buffer{}
+	_, err := msg.WriteTo(buf)
+	req.NoError(err)
+	return buf.String()
+}
+
+func TestEmailHandler_message(t *testing.T) {
+	t.Run("recipients and body parts", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			h   = emailHandler{}
+		)
+
+		rr, err := h.message(context.Background(), &emailMessageArgs{
+			hasSubject:  true,
+			Subject:     "Hello",
+			hasReplyTo:  true,
+			ReplyTo:     "reply@example.tld",
+			hasTo:       true,
+			toString:    "to1@example.tld, Second <to2@example.tld>",
+			hasCc:       true,
+			ccKV:        map[string]string{"cc@example.tld": "Cc"},
+			hasPlain:    true,
+			plainString: "plain",
+			hasHtml:     true,
+			htmlDocument: &RenderedDocument{
+				Document: strings.NewReader("<b>html</b>"),
+				Type:     "text/html",
+			},
+		})
+
+		req.NoError(err)
+		req.Equal([]string{"to1@example.tld", `"Second" <to2@example.tld>`}, rr.Message.GetHeader("To"))
+		req.Equal([]string{`"Cc" <cc@example.tld>`}, rr.Message.GetHeader("Cc"))
+		req.Equal([]string{"reply@example.tld"}, rr.Message.GetHeader("Reply-To"))
+		req.Equal([]string{"Hello"}, rr.Message.GetHeader("Subject"))
+
+		out := renderMessage(req, rr.Message)
+		req.Contains(out, "multipart/alternative")
+		req.Contains(out, "<b>html</b>")
+		req.True(strings.Index(out, "text/plain") < strings.Index(out, "text/html"))
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		_, err := emailHandler{}.message(context.Background(), &emailMessageArgs{})
+		require.EqualError(t, err, "email message without recipients")
+	})
+
+	t.Run("bcc recipients only", func(t *testing.T) {
+		rr, err := emailHandler{}.message(context.Background(), &emailMessageArgs{
+			hasBcc:      true,
+			bccString:   "bcc@example.tld",
+			hasPlain:    true,
+			plainString: "plain",
+		})
+		require.NoError(t, err)
+		require.Empty(t, rr.Message.GetHeader("To"))
+		require.Equal(t, []string{"bcc@example.tld"}, rr.Message.GetHeader("Bcc"))
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		_, err := emailHandler{}.message(context.Background(), &emailMessageArgs{
+			hasTo: true,
+			toKV:  map[string]string{"not-an-email": "Foo"},
+		})
+		require.EqualError(t, err, "invalid To address: not-an-email")
+	})
+}
+
+func TestEmailHandler_attach(t *testing.T) {
+	var (
+		req = require.New(t)
+		h   = emailHandler{}
+		doc = &RenderedDocument{
+			Document: strings.NewReader("%PDF"),
+			Name:     "invoice.pdf",
+			Type:     "application/pdf",
+		}
+	)
+
+	mr, err := h.message(context.Background(), &emailMessageArgs{hasTo: true, toString: "to@example.tld"})
+	req.NoError(err)
+
+	ar, err := h.attach(context.Background(), &emailAttachArgs{Message: mr.Message, contentDocument: doc})
+	req.NoError(err)
+
+	ar, err = h.attach(context.Background(), &emailAttachArgs{Message: ar.Message, contentString: "a,b", Name: "data.csv"})
+	req.NoError(err)
+
+	out := renderMessage(req, ar.Message)
+	req.Contains(out, `filename="invoice.pdf"`)
+	req.Contains(out, "Content-Type: application/pdf")
+	req.Contains(out, `filename="data.csv"`)
+	req.Contains(out, "Content-Type: text/csv")
+
+	// buffered attachments survive repeated rendering (e.g. when retried)
+	req.Contains(out, "JVBERg==")
+	req.Contains(renderMessage(req, ar.Message), "JVBERg==")
+}
+
+func TestEmailHandler_send(t *testing.T) {
+	t.Run("delivered", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			d   = &testDialer{}
+			h   = emailHandler{dialers: []mail.Dialer{d}}
+		)
+
+		req.NoError(h.send(context.Background(), &emailSendArgs{hasTo: true, toString: "to@example.tld"}))
+		req.Len(d.sent, 1)
+	})
+
+	t.Run("delivery error", func(t *testing.T) {
+		var (
+			d = &testDialer{err: fmt.Errorf("connection refused")}
+			h = emailHandler{dialers: []mail.Dialer{d}}
+		)
+
+		mr, err := h.message(context.Background(), &emailMessageArgs{hasTo: true, toString: "to@example.tld"})
+		require.NoError(t, err)
+
+		err = h.sendMessage(context.Background(), &emailSendMessageArgs{Message: mr.Message})
+		require.EqualError(t, err, "could not send email: connection refused")
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	. "github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/system/types"
+	gomail "gopkg.in/mail.v2"
 )
 
 var _ = context.Background
@@ -186,6 +187,44 @@ func (t *DocumentType) Assign(val interface{}) error {
 	}
 }
 
+// EmailMessage is an expression type, wrapper for *gomail.Message type
+type EmailMessage struct{ value *gomail.Message }
+
+// NewEmailMessage creates new instance of EmailMessage expression type
+func NewEmailMessage(val interface{}) (*EmailMessage, error) {
+	if c, err := CastToEmailMessage(val); err != nil {
+		return nil, fmt.Errorf("unable to create EmailMessage: %w", err)
+	} else {
+		return &EmailMessage{value: c}, nil
+	}
+}
+
+// Return underlying value on EmailMessage
+func (t EmailMessage) Get() interface{} { return t.value }
+
+// Return underlying value on EmailMessage
+func (t EmailMessage) GetValue() *gomail.Message { return t.value }
+
+// Return type name
+func (EmailMessage) Type() string { return "EmailMessage" }
+
+// Convert value to *gomail.Message
+func (EmailMessage) Cast(val interface{}) (TypedValue, error) {
+	return NewEmailMessage(val)
+}
+
+// Assign new value to EmailMessage
+//
+// value is first passed through CastToEmailMessage
+func (t *EmailMessage) Assign(val interface{}) error {
+	if c, err := CastToEmailMessage(val); err != nil {
+		return err
+	} else {
+		t.value = c
+		return nil
+	}
+}
+
 // RenderOptions is an expression type, wrapper for map[string]string type
 type RenderOptions struct{ value map[string]string }
 
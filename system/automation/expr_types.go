@@ -7,6 +7,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/spf13/cast"
+	gomail "gopkg.in/mail.v2"
 )
 
 type (
@@ -17,6 +18,21 @@ type (
 	}
 )
 
+// Read reads rendered document content
+//
+// Together with FileName and ContentType it allows rendered documents
+// to be used as files (email and HTTP request attachments, uploads)
+func (doc *RenderedDocument) Read(p []byte) (int, error) {
+	if doc.Document == nil {
+		return 0, io.EOF
+	}
+
+	return doc.Document.Read(p)
+}
+
+func (doc *RenderedDocument) FileName() string    { return doc.Name }
+func (doc *RenderedDocument) ContentType() string { return doc.Type }
+
 func CastToUser(val interface{}) (out *types.User, err error) {
 	switch val := val.(type) {
 	case expr.Iterator:
@@ -144,3 +160,12 @@ func CastToRenderVariables(val interface{}) (out map[string]interface{}, err err
 		return out, nil
 	}
 }
+
+func CastToEmailMessage(val interface{}) (out *gomail.Message, err error) {
+	switch val := expr.UntypedValue(val).(type) {
+	case *gomail.Message:
+		return val, nil
+	default:
+		return nil, fmt.Errorf("unable to cast type %T to %T", val, out)
+	}
+}
@@ -1,13 +1,16 @@
 package commands
 
 import (
+	"fmt"
 	"github.com/cortezaproject/corteza-server/auth/external"
+	"github.com/cortezaproject/corteza-server/auth/keys"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/cli"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/spf13/cobra"
+	"time"
 )
 
 // Will perform OpenID connect auto-configuration
@@ -107,10 +110,61 @@ func Auth(app serviceInitializer, opt options.AuthOpt) *cobra.Command {
 		},
 	}
 
+	keysCmd := &cobra.Command{
+		Use:   "keys",
+		Short: "JWT signing keys",
+	}
+
+	keysListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "Lists JWT signing keys",
+		PreRunE: commandPreRunInitService(app),
+		Run: func(cmd *cobra.Command, args []string) {
+			if keys.DefaultManager == nil {
+				cli.HandleError(fmt.Errorf("JWT signing keys are not used with %s algorithm", opt.JwtAlgorithm))
+			}
+
+			kk, err := keys.DefaultManager.Keys(cli.Context())
+			cli.HandleError(err)
+
+			for _, k := range kk {
+				status := "active"
+				if k.RetiredAt != nil {
+					status = "retired, valid until " + k.ExpiresAt.Format(time.RFC3339)
+				}
+
+				cmd.Printf("%s\t%s\t%s\t%s\n", k.ID, k.Method.Alg(), k.CreatedAt.Format(time.RFC3339), status)
+			}
+		},
+	}
+
+	keysRotateCmd := &cobra.Command{
+		Use:     "rotate",
+		Short:   "Generates new JWT signing key",
+		Long:    "Generates new JWT signing key and retires current keys; retired keys remain valid until tokens signed with them expire",
+		PreRunE: commandPreRunInitService(app),
+		Run: func(cmd *cobra.Command, args []string) {
+			if keys.DefaultManager == nil {
+				cli.HandleError(fmt.Errorf("JWT signing keys are not used with %s algorithm", opt.JwtAlgorithm))
+			}
+
+			k, err := keys.DefaultManager.Rotate(cli.Context())
+			cli.HandleError(err)
+
+			cmd.Printf("New %s signing key %s created.\n", k.Method.Alg(), k.ID)
+		},
+	}
+
+	keysCmd.AddCommand(
+		keysListCmd,
+		keysRotateCmd,
+	)
+
 	cmd.AddCommand(
 		autoDiscoverCmd,
 		testEmails,
 		jwtCmd,
+		keysCmd,
 	)
 
 	return cmd
@@ -169,10 +169,40 @@ func Users(app serviceInitializer) *cobra.Command {
 		},
 	}
 
+	resetMfaCmd := &cobra.Command{
+		Use:     "reset-mfa [email]",
+		Short:   "Reset multi-factor authentication for user",
+		Long:    "Removes all multi-factor authentication factors (TOTP, recovery codes, security keys) from the user",
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: commandPreRunInitService(app),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				ctx = auth.SetSuperUserContext(cli.Context())
+
+				user *types.User
+				err  error
+			)
+
+			// Update current settings to be sure that we do not have outdated values
+			cli.HandleError(service.DefaultSettings.UpdateCurrent(ctx))
+
+			if user, err = service.DefaultUser.FindByEmail(ctx, args[0]); err != nil {
+				cli.HandleError(err)
+			}
+
+			if err = service.DefaultUser.ResetMFA(ctx, user.ID); err != nil {
+				cli.HandleError(err)
+			}
+
+			cmd.Printf("Multi-factor authentication reset for user [%d].\n", user.ID)
+		},
+	}
+
 	cmd.AddCommand(
 		listCmd,
 		addCmd,
 		pwdCmd,
+		resetMfaCmd,
 	)
 
 	return cmd
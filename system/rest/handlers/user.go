@@ -29,6 +29,7 @@ type (
 		Unsuspend(context.Context, *request.UserUnsuspend) (interface{}, error)
 		Undelete(context.Context, *request.UserUndelete) (interface{}, error)
 		SetPassword(context.Context, *request.UserSetPassword) (interface{}, error)
+		ResetMFA(context.Context, *request.UserResetMFA) (interface{}, error)
 		MembershipList(context.Context, *request.UserMembershipList) (interface{}, error)
 		MembershipAdd(context.Context, *request.UserMembershipAdd) (interface{}, error)
 		MembershipRemove(context.Context, *request.UserMembershipRemove) (interface{}, error)
@@ -47,6 +48,7 @@ type (
 		Unsuspend        func(http.ResponseWriter, *http.Request)
 		Undelete         func(http.ResponseWriter, *http.Request)
 		SetPassword      func(http.ResponseWriter, *http.Request)
+		ResetMFA         func(http.ResponseWriter, *http.Request)
 		MembershipList   func(http.ResponseWriter, *http.Request)
 		MembershipAdd    func(http.ResponseWriter, *http.Request)
 		MembershipRemove func(http.ResponseWriter, *http.Request)
@@ -216,6 +218,22 @@ func NewUser(h UserAPI) *User {
 
 			api.Send(w, r, value)
 		},
+		ResetMFA: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewUserResetMFA()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.ResetMFA(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		MembershipList: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewUserMembershipList()
@@ -296,6 +314,7 @@ func (h User) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http.H
 		r.Post("/users/{userID}/unsuspend", h.Unsuspend)
 		r.Post("/users/{userID}/undelete", h.Undelete)
 		r.Post("/users/{userID}/password", h.SetPassword)
+		r.Post("/users/{userID}/mfa/reset", h.ResetMFA)
 		r.Get("/users/{userID}/membership", h.MembershipList)
 		r.Post("/users/{userID}/membership/{roleID}", h.MembershipAdd)
 		r.Delete("/users/{userID}/membership/{roleID}", h.MembershipRemove)
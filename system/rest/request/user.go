@@ -225,6 +225,13 @@ type (
 		Password string
 	}
 
+	UserResetMFA struct {
+		// UserID PATH parameter
+		//
+		// User ID
+		UserID uint64 `json:",string"`
+	}
+
 	UserMembershipList struct {
 		// UserID PATH parameter
 		//
@@ -993,6 +1000,41 @@ func (r *UserSetPassword) Fill(req *http.Request) (err error) {
 	return err
 }
 
+// NewUserResetMFA request
+func NewUserResetMFA() *UserResetMFA {
+	return &UserResetMFA{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserResetMFA) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"userID": r.UserID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r UserResetMFA) GetUserID() uint64 {
+	return r.UserID
+}
+
+// Fill processes request and fills internal variables
+func (r *UserResetMFA) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "userID")
+		r.UserID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
 // NewUserMembershipList request
 func NewUserMembershipList() *UserMembershipList {
 	return &UserMembershipList{}
@@ -197,6 +197,10 @@ func (ctrl User) SetPassword(ctx context.Context, r *request.UserSetPassword) (i
 	return api.OK(), ctrl.user.SetPassword(ctx, r.UserID, r.Password)
 }
 
+func (ctrl User) ResetMFA(ctx context.Context, r *request.UserResetMFA) (interface{}, error) {
+	return api.OK(), ctrl.user.ResetMFA(ctx, r.UserID)
+}
+
 func (ctrl User) MembershipList(ctx context.Context, r *request.UserMembershipList) (interface{}, error) {
 	if mm, err := ctrl.role.Membership(ctx, r.UserID); err != nil {
 		return nil, err
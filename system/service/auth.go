@@ -15,6 +15,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/rand"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
 	"github.com/cortezaproject/corteza-server/store"
@@ -22,11 +23,14 @@ import (
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/dgryski/dgoogauth"
 	"github.com/markbates/goth"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type (
 	auth struct {
+		logger    *zap.Logger
 		actionlog actionlog.Recorder
 		ac        authAccessController
 		eventbus  eventDispatcher
@@ -67,6 +71,7 @@ func defaultProviderValidator(provider string) error {
 
 func Auth() *auth {
 	return &auth{
+		logger:        DefaultLogger,
 		eventbus:      eventbus.Service(),
 		ac:            DefaultAccessControl,
 		settings:      CurrentSettings,
@@ -79,6 +84,14 @@ func Auth() *auth {
 	}
 }
 
+func (svc auth) log(ctx context.Context, fields ...zapcore.Field) *zap.Logger {
+	if svc.logger == nil {
+		return zap.NewNop()
+	}
+
+	return logger.AddRequestID(ctx, svc.logger.Named("auth")).With(fields...)
+}
+
 // External func performs login/signup procedures
 //
 // We fully trust external auth sources (see system/auth/external) to provide a valid & validates
@@ -1068,7 +1081,8 @@ func (svc auth) ConfigureTOTP(ctx context.Context, secret string, code string) (
 
 // RemoveTOTP removes TOTP secret from user's credentials
 //
-// If user is removing own TOTP code is required
+// If user is removing own TOTP code is required;
+// recovery code can be used instead of the TOTP code
 // When removing TOTP for another user, remover shou
 //
 // It returns the user with security policy changes
@@ -1079,6 +1093,10 @@ func (svc auth) RemoveTOTP(ctx context.Context, userID uint64, code string) (u *
 		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
 		i    = internalAuth.GetIdentityFromContext(ctx)
 		self = i != nil && i.Identity() == userID
+
+		// set when recovery code is used instead of TOTP code
+		rcam      *authActionProps
+		remaining int
 	)
 
 	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
@@ -1101,8 +1119,12 @@ func (svc auth) RemoveTOTP(ctx context.Context, userID uint64, code string) (u *
 				return err
 			}
 
-			if err = svc.validateTOTP(c.Credentials, code); err != nil {
-				return err
+			if terr := svc.validateTOTP(c.Credentials, code); terr != nil {
+				// device might be lost, try with recovery code
+				rcam = &authActionProps{user: u, credentials: &types.Credentials{Kind: credentialsTypeMfaRecoveryCode}}
+				if remaining, err = svc.useRecoveryCode(ctx, s, u, code, rcam); err != nil {
+					return terr
+				}
 			}
 		} else if !svc.ac.CanUpdateUser(ctx, u) {
 			return AuthErrNotAllowedToRemoveTOTP()
@@ -1112,11 +1134,21 @@ func (svc auth) RemoveTOTP(ctx context.Context, userID uint64, code string) (u *
 			return err
 		}
 
+		// recovery codes are useless without TOTP
+		if err = svc.revokeCredentials(ctx, s, u.ID, credentialsTypeMfaRecoveryCode); err != nil {
+			return err
+		}
+
 		u.Meta.SecurityPolicy.MFA.EnforcedTOTP = false
 		return store.UpdateUser(ctx, s, u)
 
 	})
 
+	if err == nil && rcam != nil {
+		_ = svc.recordAction(ctx, rcam, AuthActionRecoveryCodeValidate, nil)
+		svc.notifyRecoveryCodeUsed(ctx, u, remaining)
+	}
+
 	return u, svc.recordAction(ctx, aam, AuthActionTotpConfigure, err)
 }
 
@@ -1162,22 +1194,9 @@ func (auth) validateTOTP(secret string, code string) error {
 }
 
 // Revokes all existing user's TOTPs
-func (auth) revokeAllTOTP(ctx context.Context, s store.Credentials, userID uint64) error {
+func (svc auth) revokeAllTOTP(ctx context.Context, s store.Credentials, userID uint64) error {
 	// revoke (soft-delete) all existing secrets
-	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
-		OwnerID: userID,
-		Kind:    credentialsTypeMfaTotpSecret,
-		Deleted: filter.StateExcluded,
-	})
-
-	if err != nil {
-		return err
-	}
-
-	return cc.Walk(func(c *types.Credentials) error {
-		c.DeletedAt = now()
-		return store.UpdateCredentials(ctx, s, c)
-	})
+	return svc.revokeCredentials(ctx, s, userID, credentialsTypeMfaTotpSecret)
 }
 
 func (svc auth) SendEmailOTP(ctx context.Context) (err error) {
@@ -615,6 +615,126 @@ func AuthActionEmailOtpVerify(props ...*authActionProps) *authAction {
 	return a
 }
 
+// AuthActionWebAuthnConfigure returns "system:auth.webAuthnConfigure" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebAuthnConfigure(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webAuthnConfigure",
+		log:       "security key {credentials.label} for {user} registered",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebAuthnRemove returns "system:auth.webAuthnRemove" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebAuthnRemove(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webAuthnRemove",
+		log:       "security key {credentials.label} for {user} removed",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebAuthnValidate returns "system:auth.webAuthnValidate" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebAuthnValidate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webAuthnValidate",
+		log:       "security key {credentials.label} for {user} validated",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionRecoveryCodesGenerate returns "system:auth.recoveryCodesGenerate" action
+//
+// This function is auto-generated.
+//
+func AuthActionRecoveryCodesGenerate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "recoveryCodesGenerate",
+		log:       "recovery codes for {user} generated",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionRecoveryCodeValidate returns "system:auth.recoveryCodeValidate" action
+//
+// This function is auto-generated.
+//
+func AuthActionRecoveryCodeValidate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "recoveryCodeValidate",
+		log:       "recovery code for {user} used",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionMfaReset returns "system:auth.mfaReset" action
+//
+// This function is auto-generated.
+//
+func AuthActionMfaReset(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "mfaReset",
+		log:       "multi-factor authentication for {user} reset",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -1487,6 +1607,190 @@ func AuthErrInvalidEmailOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
+// AuthErrDisabledMFAWithWebAuthn returns "system:auth.disabledMFAWithWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledMFAWithWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("multi factor authentication with security keys is disabled", nil),
+
+		errors.Meta("type", "disabledMFAWithWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrEnforcedMFAWithWebAuthn returns "system:auth.enforcedMFAWithWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrEnforcedMFAWithWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("security keys are enforced and the last one can not be removed", nil),
+
+		errors.Meta("type", "enforcedMFAWithWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrUnconfiguredWebAuthn returns "system:auth.unconfiguredWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrUnconfiguredWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("no security keys registered", nil),
+
+		errors.Meta("type", "unconfiguredWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrNotAllowedToRemoveWebAuthn returns "system:auth.notAllowedToRemoveWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrNotAllowedToRemoveWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to remove security key", nil),
+
+		errors.Meta("type", "notAllowedToRemoveWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidWebAuthn returns "system:auth.invalidWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("security key verification failed", nil),
+
+		errors.Meta("type", "invalidWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "security key verification for {user} failed"),
+		errors.Meta(authPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidRecoveryCode returns "system:auth.invalidRecoveryCode" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidRecoveryCode(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid recovery code", nil),
+
+		errors.Meta("type", "invalidRecoveryCode"),
+		errors.Meta("resource", "system:auth"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "recovery code validation for {user} failed"),
+		errors.Meta(authPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
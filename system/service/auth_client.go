@@ -24,6 +24,7 @@ type (
 		CanReadAuthClient(context.Context, *types.AuthClient) bool
 		CanUpdateAuthClient(context.Context, *types.AuthClient) bool
 		CanDeleteAuthClient(context.Context, *types.AuthClient) bool
+		CanImpersonateUser(context.Context, *types.User) bool
 	}
 )
 
@@ -188,6 +189,10 @@ func (svc *authClient) Create(ctx context.Context, new *types.AuthClient) (app *
 			new.Meta = &types.AuthClientMeta{}
 		}
 
+		if err = svc.checkImpersonation(ctx, new.Security, nil); err != nil {
+			return
+		}
+
 		if err = store.CreateAuthClient(ctx, svc.store, new); err != nil {
 			return
 		}
@@ -245,6 +250,10 @@ func (svc *authClient) Update(ctx context.Context, upd *types.AuthClient) (app *
 		}
 
 		if upd.Security != nil {
+			if err = svc.checkImpersonation(ctx, upd.Security, app.Security); err != nil {
+				return
+			}
+
 			app.Security = upd.Security
 		}
 
@@ -357,3 +366,28 @@ func toLabeledAuthClients(set []*types.AuthClient) []label.LabeledResource {
 
 	return ll
 }
+
+// checkImpersonation verifies that the current user can impersonate
+// the user that is set on the client's security settings
+//
+// Check is skipped when impersonated user is not changed
+func (svc *authClient) checkImpersonation(ctx context.Context, new, old *types.AuthClientSecurity) error {
+	if new == nil || new.ImpersonateUser == 0 {
+		return nil
+	}
+
+	if old != nil && old.ImpersonateUser == new.ImpersonateUser {
+		return nil
+	}
+
+	u, err := store.LookupUserByID(ctx, svc.store, new.ImpersonateUser)
+	if err != nil {
+		return err
+	}
+
+	if !svc.ac.CanImpersonateUser(ctx, u) {
+		return AuthClientErrNotAllowedToImpersonateUser()
+	}
+
+	return nil
+}
@@ -690,6 +690,38 @@ func AuthClientErrNotAllowedToUndelete(mm ...*authClientActionProps) *errors.Err
 	return e
 }
 
+// AuthClientErrNotAllowedToImpersonateUser returns "system:auth-client.notAllowedToImpersonateUser" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthClientErrNotAllowedToImpersonateUser(mm ...*authClientActionProps) *errors.Error {
+	var p = &authClientActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to impersonate this user", nil),
+
+		errors.Meta("type", "notAllowedToImpersonateUser"),
+		errors.Meta("resource", "system:auth-client"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authClientLogMetaKey{}, "failed to set impersonated user on {authClient}; insufficient permissions"),
+		errors.Meta(authClientPropsMetaKey{}, p),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
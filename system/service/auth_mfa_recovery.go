@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"regexp"
+	"strings"
+
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
+)
+
+// Multi-factor authentication recovery
+//
+// Recovery codes are generated when TOTP is configured and can be used
+// instead of the TOTP code when user loses the device. Each code is stored
+// (bcrypt hashed) as a separate credentials entry and can be used only once.
+//
+// When all else fails, MFA factors can be reset by an administrator.
+
+const (
+	credentialsTypeMfaRecoveryCode = "mfa-recovery-code"
+
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	reRecoveryCodeCleanup = regexp.MustCompile(`[^a-z0-9]`)
+)
+
+// GenerateRecoveryCodes generates new set of recovery codes for the current user
+//
+// All existing recovery codes are revoked. Generated codes are returned
+// in plain text and can not be retrieved later.
+func (svc auth) GenerateRecoveryCodes(ctx context.Context) (codes []string, err error) {
+	var (
+		u    *types.User
+		kind = credentialsTypeMfaRecoveryCode
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.TOTP.Enabled {
+			return AuthErrDisabledMFAWithTOTP()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if !u.Meta.SecurityPolicy.MFA.EnforcedTOTP {
+			return AuthErrUnconfiguredTOTP()
+		}
+
+		if err = svc.revokeCredentials(ctx, s, u.ID, kind); err != nil {
+			return err
+		}
+
+		codes = make([]string, recoveryCodeCount)
+		for c := range codes {
+			if codes[c], err = makeRecoveryCode(); err != nil {
+				return err
+			}
+
+			hash, err := svc.hashPassword(normalizeRecoveryCode(codes[c]))
+			if err != nil {
+				return err
+			}
+
+			err = store.CreateCredentials(ctx, s, &types.Credentials{
+				ID:          nextID(),
+				CreatedAt:   *now(),
+				OwnerID:     u.ID,
+				Kind:        kind,
+				Credentials: string(hash),
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		codes = nil
+	}
+
+	return codes, svc.recordAction(ctx, aam, AuthActionRecoveryCodesGenerate, err)
+}
+
+// ValidateRecoveryCode checks given recovery code for the current user
+//
+// Matching code is revoked and user is notified about its use
+func (svc auth) ValidateRecoveryCode(ctx context.Context, code string) (err error) {
+	var (
+		u         *types.User
+		remaining int
+		kind      = credentialsTypeMfaRecoveryCode
+		aam       = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i         = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+		remaining, err = svc.useRecoveryCode(ctx, s, u, code, aam)
+		return err
+	})
+
+	if err == nil {
+		svc.notifyRecoveryCodeUsed(ctx, u, remaining)
+	}
+
+	return svc.recordAction(ctx, aam, AuthActionRecoveryCodeValidate, err)
+}
+
+// ResetMFA removes all user's MFA factors (TOTP, security keys, recovery codes)
+// and disables MFA enforcement on the user
+//
+// User is notified about the reset; if MFA is enforced by the settings
+// user will be asked to configure it again on the next login.
+//
+// This function does not check permissions, that is the caller's responsibility
+func (svc auth) ResetMFA(ctx context.Context, userID uint64) (err error) {
+	var (
+		u   *types.User
+		aam = &authActionProps{}
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		u, err = store.LookupUserByID(ctx, s, userID)
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		for _, kind := range []string{
+			credentialsTypeMfaTotpSecret,
+			credentialsTypeMfaRecoveryCode,
+			credentialsTypeMfaWebAuthn,
+			credentialsTypeMFAEmailOTP,
+		} {
+			if err = svc.revokeCredentials(ctx, s, u.ID, kind); err != nil {
+				return err
+			}
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedEmailOTP = false
+		u.Meta.SecurityPolicy.MFA.EnforcedTOTP = false
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = false
+
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	if err == nil {
+		// user should know about the reset even when it was not requested by them
+		// but failed notification must not undo or fail the reset
+		if nErr := svc.notifications.MFAReset(ctx, u.Email); nErr != nil {
+			svc.log(ctx, zap.Uint64("userID", u.ID)).Warn("could not send MFA reset notification", zap.Error(nErr))
+		}
+	}
+
+	return svc.recordAction(ctx, aam, AuthActionMfaReset, err)
+}
+
+// Finds and revokes matching recovery code
+//
+// Returns number of remaining recovery codes
+func (svc auth) useRecoveryCode(ctx context.Context, s store.Credentials, u *types.User, code string, aam *authActionProps) (int, error) {
+	if code = normalizeRecoveryCode(code); len(code) != recoveryCodeLength {
+		return 0, AuthErrInvalidRecoveryCode(aam)
+	}
+
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: u.ID,
+		Kind:    credentialsTypeMfaRecoveryCode,
+		Deleted: filter.StateExcluded,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	c := cc.CompareHashAndPassword(code)
+	if c == nil {
+		return 0, AuthErrInvalidRecoveryCode(aam)
+	}
+
+	c.LastUsedAt = now()
+	c.DeletedAt = c.LastUsedAt
+	if err = store.UpdateCredentials(ctx, s, c); err != nil {
+		return 0, err
+	}
+
+	return len(cc) - 1, nil
+}
+
+// Notifies the user about used recovery code
+//
+// Called after the code is revoked; failed notification must not
+// prevent user from signing in with a valid recovery code
+func (svc auth) notifyRecoveryCodeUsed(ctx context.Context, u *types.User, remaining int) {
+	if err := svc.notifications.RecoveryCodeUsed(ctx, u.Email, remaining); err != nil {
+		svc.log(ctx, zap.Uint64("userID", u.ID)).Warn("could not send recovery code notification", zap.Error(err))
+	}
+}
+
+// Revokes (soft-deletes) all user's credentials of a specific kind
+func (auth) revokeCredentials(ctx context.Context, s store.Credentials, userID uint64, kind string) error {
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    kind,
+		Deleted: filter.StateExcluded,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return cc.Walk(func(c *types.Credentials) error {
+		c.DeletedAt = now()
+		return store.UpdateCredentials(ctx, s, c)
+	})
+}
+
+// Generates random recovery code in xxxxx-xxxxx format
+func makeRecoveryCode() (string, error) {
+	var (
+		code = make([]byte, recoveryCodeLength)
+		max  = big.NewInt(int64(len(recoveryCodeAlphabet)))
+	)
+
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		code[i] = recoveryCodeAlphabet[n.Int64()]
+	}
+
+	return string(code[:recoveryCodeLength/2]) + "-" + string(code[recoveryCodeLength/2:]), nil
+}
+
+// Removes formatting (dashes, spaces) and normalizes case
+func normalizeRecoveryCode(code string) string {
+	return reRecoveryCodeCleanup.ReplaceAllString(strings.ToLower(code), "")
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mockAuthNotification struct {
+		AuthNotificationService
+
+		recoveryCodeUsed []int
+		mfaReset         []string
+
+		// mimics failing (or unconfigured) SMTP
+		err error
+	}
+)
+
+func (m *mockAuthNotification) RecoveryCodeUsed(_ context.Context, _ string, remaining int) error {
+	m.recoveryCodeUsed = append(m.recoveryCodeUsed, remaining)
+	return m.err
+}
+
+func (m *mockAuthNotification) MFAReset(_ context.Context, emailAddress string) error {
+	m.mfaReset = append(m.mfaReset, emailAddress)
+	return m.err
+}
+
+func TestAuth_RecoveryCodes(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+		ntf = &mockAuthNotification{}
+
+		user = &types.User{Email: "recovery@test.cortezaproject.org", ID: nextID(), CreatedAt: *now(), Meta: &types.UserMeta{}}
+
+		uctx = internalAuth.SetIdentityToContext(ctx, user)
+	)
+
+	svc.notifications = ntf
+	svc.settings.Auth.MultiFactor.TOTP.Enabled = true
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, user))
+
+	// TOTP must be configured first
+	_, err := svc.GenerateRecoveryCodes(uctx)
+	req.EqualError(err, "TOTP not configured")
+
+	user.Meta.SecurityPolicy.MFA.EnforcedTOTP = true
+	req.NoError(store.UpdateUser(ctx, svc.store, user))
+	req.NoError(store.CreateCredentials(ctx, svc.store, &types.Credentials{
+		ID:          nextID(),
+		CreatedAt:   *now(),
+		OwnerID:     user.ID,
+		Kind:        credentialsTypeMfaTotpSecret,
+		Credentials: "JBSWY3DPEHPK3PXP",
+	}))
+
+	old, err := svc.GenerateRecoveryCodes(uctx)
+	req.NoError(err)
+	req.Len(old, recoveryCodeCount)
+
+	codes, err := svc.GenerateRecoveryCodes(uctx)
+	req.NoError(err)
+	req.Len(codes, recoveryCodeCount)
+	req.Regexp(`^[a-z0-9]{5}-[a-z0-9]{5}$`, codes[0])
+
+	// codes from the previous set are revoked
+	req.EqualError(svc.ValidateRecoveryCode(uctx, old[0]), "invalid recovery code")
+
+	// formatting does not matter
+	req.NoError(svc.ValidateRecoveryCode(uctx, " "+strings.ToUpper(strings.Replace(codes[0], "-", "", 1))))
+	req.Equal([]int{recoveryCodeCount - 1}, ntf.recoveryCodeUsed)
+
+	// each code can be used only once
+	req.EqualError(svc.ValidateRecoveryCode(uctx, codes[0]), "invalid recovery code")
+	req.EqualError(svc.ValidateRecoveryCode(uctx, ""), "invalid recovery code")
+
+	// failed notification does not reject valid code
+	ntf.err = fmt.Errorf("unable to find configured and working SMTP dialer")
+	req.NoError(svc.ValidateRecoveryCode(uctx, codes[1]))
+	req.EqualError(svc.ValidateRecoveryCode(uctx, codes[1]), "invalid recovery code")
+
+	// recovery code can be used instead of the TOTP code
+	_, err = svc.RemoveTOTP(uctx, user.ID, "000000")
+	req.EqualError(err, "invalid code")
+
+	u, err := svc.RemoveTOTP(uctx, user.ID, codes[2])
+	req.NoError(err)
+	req.False(u.Meta.SecurityPolicy.MFA.EnforcedTOTP)
+	req.Equal([]int{recoveryCodeCount - 1, recoveryCodeCount - 2, recoveryCodeCount - 3}, ntf.recoveryCodeUsed)
+
+	// remaining codes are revoked with TOTP
+	req.EqualError(svc.ValidateRecoveryCode(uctx, codes[3]), "invalid recovery code")
+}
+
+func TestAuth_ResetMFA(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+		ntf = &mockAuthNotification{}
+
+		user = &types.User{Email: "reset-mfa@test.cortezaproject.org", ID: nextID(), CreatedAt: *now(), Meta: &types.UserMeta{}}
+	)
+
+	svc.notifications = ntf
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+
+	user.Meta.SecurityPolicy.MFA.EnforcedEmailOTP = true
+	user.Meta.SecurityPolicy.MFA.EnforcedTOTP = true
+	user.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+	req.NoError(store.CreateUser(ctx, svc.store, user))
+
+	for _, kind := range []string{credentialsTypeMfaTotpSecret, credentialsTypeMfaRecoveryCode, credentialsTypeMfaWebAuthn, credentialsTypePassword} {
+		req.NoError(store.CreateCredentials(ctx, svc.store, &types.Credentials{
+			ID:          nextID(),
+			CreatedAt:   *now(),
+			OwnerID:     user.ID,
+			Kind:        kind,
+			Credentials: kind,
+		}))
+	}
+
+	// reset is not undone when notification can not be sent
+	ntf.err = fmt.Errorf("unable to find configured and working SMTP dialer")
+	req.NoError(svc.ResetMFA(ctx, user.ID))
+	req.Equal([]string{user.Email}, ntf.mfaReset)
+
+	u, err := store.LookupUserByID(ctx, svc.store, user.ID)
+	req.NoError(err)
+	req.False(u.Meta.SecurityPolicy.MFA.EnforcedEmailOTP)
+	req.False(u.Meta.SecurityPolicy.MFA.EnforcedTOTP)
+	req.False(u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+
+	// only password is left
+	cc, _, err := store.SearchCredentials(ctx, svc.store, types.CredentialsFilter{OwnerID: user.ID})
+	req.NoError(err)
+	req.Len(cc, 1)
+	req.Equal(credentialsTypePassword, cc[0].Kind)
+
+	req.Error(svc.ResetMFA(ctx, nextID()))
+}
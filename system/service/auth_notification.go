@@ -28,6 +28,8 @@ type (
 		EmailOTP(ctx context.Context, emailAddress string, otp string) error
 		EmailConfirmation(ctx context.Context, emailAddress string, url string) error
 		PasswordReset(ctx context.Context, emailAddress string, url string) error
+		RecoveryCodeUsed(ctx context.Context, emailAddress string, remaining int) error
+		MFAReset(ctx context.Context, emailAddress string) error
 	}
 )
 
@@ -62,6 +64,19 @@ func (svc authNotification) PasswordReset(ctx context.Context, emailAddress stri
 	})
 }
 
+func (svc authNotification) RecoveryCodeUsed(ctx context.Context, emailAddress string, remaining int) error {
+	return svc.send(ctx, "auth_email_mfa_recovery_code_used", emailAddress, map[string]interface{}{
+		"Remaining": remaining,
+		"URL":       fmt.Sprintf("%s/security", svc.opt.BaseURL),
+	})
+}
+
+func (svc authNotification) MFAReset(ctx context.Context, emailAddress string) error {
+	return svc.send(ctx, "auth_email_mfa_reset", emailAddress, map[string]interface{}{
+		"URL": fmt.Sprintf("%s/security", svc.opt.BaseURL),
+	})
+}
+
 func (svc authNotification) newMail() *gomail.Message {
 	var (
 		m    = mail.New()
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+// Multi-factor authentication with security keys (WebAuthn)
+//
+// Each registered security key is stored as a separate credentials entry:
+// credential ID (base64url encoded) is kept in Credentials field
+// and the public key with the signature counter in Meta.
+//
+// Challenges are generated when options are prepared and must be
+// kept by the caller (in the session) until the response is verified.
+
+const (
+	credentialsTypeMfaWebAuthn = "mfa-webauthn"
+)
+
+// WebAuthnCreationOptions prepares options for registration of a new security key
+//
+// Already registered keys are excluded
+func (svc auth) WebAuthnCreationOptions(ctx context.Context, rp *webauthn.RelyingParty) (opt *webauthn.CreationOptions, err error) {
+	var (
+		u    *types.User
+		cc   types.CredentialsSet
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = func() error {
+		if u, err = svc.loadWebAuthnUser(ctx, i.Identity(), aam); err != nil {
+			return err
+		}
+
+		if enabled, _ := svc.webAuthnPolicy(u); !enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		if cc, err = svc.searchWebAuthnCredentials(ctx, svc.store, u.ID); err != nil {
+			return err
+		}
+
+		var (
+			user = webauthn.Entity{
+				ID:          make([]byte, 8),
+				Name:        u.Email,
+				DisplayName: u.Name,
+			}
+
+			exclude = make([][]byte, 0, len(cc))
+		)
+
+		binary.BigEndian.PutUint64(user.ID, u.ID)
+		if user.DisplayName == "" {
+			user.DisplayName = u.Email
+		}
+
+		for _, c := range cc {
+			if wc, err := decodeWebAuthnCredential(c); err == nil {
+				exclude = append(exclude, wc.ID)
+			}
+		}
+
+		opt, err = rp.CreationOptions(user, exclude...)
+		return err
+	}()
+
+	return opt, svc.recordAction(ctx, aam, nil, err)
+}
+
+// ConfigureWebAuthn verifies registration response and stores the new security key
+//
+// It returns the user with security policy changes
+func (svc auth) ConfigureWebAuthn(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, label, response string) (u *types.User, err error) {
+	var (
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind, Label: label}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if u, err = svc.loadWebAuthnUser(ctx, i.Identity(), aam); err != nil {
+			return err
+		}
+
+		if enabled, _ := svc.webAuthnPolicy(u); !enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		r, err := webauthn.ParseRegistrationResponse([]byte(response))
+		if err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		wc, err := rp.VerifyRegistration(challenge, r)
+		if err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		if label = strings.TrimSpace(label); label == "" {
+			label = "Security key"
+		}
+
+		cred := &types.Credentials{
+			ID:          nextID(),
+			CreatedAt:   *now(),
+			OwnerID:     u.ID,
+			Kind:        kind,
+			Label:       label,
+			Credentials: wc.ID.String(),
+		}
+
+		if cred.Meta, err = json.Marshal(wc); err != nil {
+			return err
+		}
+
+		aam.setCredentials(cred)
+		if err = store.CreateCredentials(ctx, s, cred); err != nil {
+			return err
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionWebAuthnConfigure, err)
+}
+
+// WebAuthnRequestOptions prepares options for verification with one of the registered security keys
+func (svc auth) WebAuthnRequestOptions(ctx context.Context, rp *webauthn.RelyingParty) (opt *webauthn.RequestOptions, err error) {
+	var (
+		u    *types.User
+		cc   types.CredentialsSet
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = func() error {
+		if u, err = svc.loadWebAuthnUser(ctx, i.Identity(), aam); err != nil {
+			return err
+		}
+
+		if enabled, _ := svc.webAuthnPolicy(u); !enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		if cc, err = svc.searchWebAuthnCredentials(ctx, svc.store, u.ID); err != nil {
+			return err
+		}
+
+		allow := make([][]byte, 0, len(cc))
+		for _, c := range cc {
+			if wc, err := decodeWebAuthnCredential(c); err == nil {
+				allow = append(allow, wc.ID)
+			}
+		}
+
+		if len(allow) == 0 {
+			return AuthErrUnconfiguredWebAuthn()
+		}
+
+		opt, err = rp.RequestOptions(allow...)
+		return err
+	}()
+
+	return opt, svc.recordAction(ctx, aam, nil, err)
+}
+
+// ValidateWebAuthn verifies assertion response with one of the registered security keys
+func (svc auth) ValidateWebAuthn(ctx context.Context, rp *webauthn.RelyingParty, challenge []byte, response string) (err error) {
+	var (
+		u    *types.User
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if u, err = svc.loadWebAuthnUser(ctx, i.Identity(), aam); err != nil {
+			return err
+		}
+
+		if enabled, _ := svc.webAuthnPolicy(u); !enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		if !u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn {
+			return AuthErrUnconfiguredWebAuthn()
+		}
+
+		r, err := webauthn.ParseAssertionResponse([]byte(response))
+		if err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+			OwnerID:     u.ID,
+			Kind:        kind,
+			Credentials: r.RawID.String(),
+			Deleted:     filter.StateExcluded,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if len(cc) != 1 {
+			return AuthErrInvalidWebAuthn(aam).Wrap(webauthn.ErrUnknownCredential)
+		}
+
+		c := cc[0]
+		aam.setCredentials(c)
+
+		wc, err := decodeWebAuthnCredential(c)
+		if err != nil {
+			return err
+		}
+
+		if err = rp.VerifyAssertion(challenge, r, wc); err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		// store updated signature counter
+		if c.Meta, err = json.Marshal(wc); err != nil {
+			return err
+		}
+
+		c.LastUsedAt = now()
+		return store.UpdateCredentials(ctx, s, c)
+	})
+
+	return svc.recordAction(ctx, aam, AuthActionWebAuthnValidate, err)
+}
+
+// RemoveWebAuthn removes registered security key
+//
+// All user's security keys are removed when credentialsID is 0.
+// Users can not remove their last security key when security keys are enforced,
+// user with update permissions can remove any key (e.g. when key is lost)
+//
+// It returns the user with security policy changes
+func (svc auth) RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error) {
+	var (
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+		self = i != nil && i.Identity() == userID
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if u, err = svc.loadWebAuthnUser(ctx, userID, aam); err != nil {
+			return err
+		}
+
+		if !self && !svc.ac.CanUpdateUser(ctx, u) {
+			return AuthErrNotAllowedToRemoveWebAuthn()
+		}
+
+		cc, err := svc.searchWebAuthnCredentials(ctx, s, u.ID)
+		if err != nil {
+			return err
+		}
+
+		var removed types.CredentialsSet
+		for _, c := range cc {
+			if credentialsID == 0 || c.ID == credentialsID {
+				removed = append(removed, c)
+			}
+		}
+
+		if len(removed) == 0 {
+			return AuthErrUnconfiguredWebAuthn()
+		}
+
+		last := len(removed) == len(cc)
+		if _, enforced := svc.webAuthnPolicy(u); last && enforced && self {
+			return AuthErrEnforcedMFAWithWebAuthn()
+		}
+
+		for _, c := range removed {
+			aam.setCredentials(c)
+			c.DeletedAt = now()
+			if err = store.UpdateCredentials(ctx, s, c); err != nil {
+				return err
+			}
+		}
+
+		if !last {
+			return nil
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = false
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionWebAuthnRemove, err)
+}
+
+// WebAuthnCredentials returns all registered security keys
+func (svc auth) WebAuthnCredentials(ctx context.Context, userID uint64) (cc types.CredentialsSet, err error) {
+	var (
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = func() error {
+		u, err := store.LookupUserByID(ctx, svc.store, userID)
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		} else if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if (i == nil || i.Identity() != userID) && !svc.ac.CanUpdateUser(ctx, u) {
+			return AuthErrNotAllowedToRemoveWebAuthn()
+		}
+
+		cc, err = svc.searchWebAuthnCredentials(ctx, svc.store, userID)
+		return err
+	}()
+
+	return cc, svc.recordAction(ctx, aam, nil, err)
+}
+
+// loads user with role memberships (needed to resolve security key policy)
+func (svc auth) loadWebAuthnUser(ctx context.Context, userID uint64, aam *authActionProps) (u *types.User, err error) {
+	u, err = store.LookupUserByID(ctx, svc.store, userID)
+	if errors.IsNotFound(err) {
+		return nil, AuthErrFailedForUnknownUser(aam)
+	} else if err != nil {
+		return nil, err
+	}
+
+	aam.setUser(u)
+	return u, svc.LoadRoleMemberships(ctx, u)
+}
+
+// webAuthnPolicy resolves if security keys are enabled and enforced
+// for the user, globally or for one of user's roles
+func (svc auth) webAuthnPolicy(u *types.User) (enabled, enforced bool) {
+	wa := svc.settings.Auth.MultiFactor.WebAuthn
+	return webauthn.Policy{
+		Enabled:       wa.Enabled,
+		EnabledRoles:  payload.ParseUint64s(wa.EnabledRoles),
+		Enforced:      wa.Enforced,
+		EnforcedRoles: payload.ParseUint64s(wa.EnforcedRoles),
+	}.Resolve(u.Roles())
+}
+
+// Searches for all valid security keys
+func (auth) searchWebAuthnCredentials(ctx context.Context, s store.Credentials, userID uint64) (types.CredentialsSet, error) {
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeMfaWebAuthn,
+		Deleted: filter.StateExcluded,
+	})
+
+	return cc, err
+}
+
+func decodeWebAuthnCredential(c *types.Credentials) (*webauthn.Credential, error) {
+	wc := &webauthn.Credential{}
+	return wc, json.Unmarshal(c.Meta, wc)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/internal/webauthntest"
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth_WebAuthn(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		svc = makeMockAuthService()
+
+		rp   = &webauthn.RelyingParty{ID: "corteza.example.tld", Name: "Corteza", Origin: "https://corteza.example.tld"}
+		user = &types.User{Email: "webauthn@test.cortezaproject.org", ID: nextID(), CreatedAt: *now()}
+		role = &types.Role{Handle: "admins", ID: nextID(), CreatedAt: *now()}
+
+		uctx = internalAuth.SetIdentityToContext(ctx, user)
+
+		register = func(a *webauthntest.SoftAuthenticator, label string) (*types.User, error) {
+			opt, err := svc.WebAuthnCreationOptions(uctx, rp)
+			req.NoError(err)
+
+			r, err := a.Create(opt)
+			req.NoError(err)
+
+			rsp, _ := json.Marshal(r)
+			return svc.ConfigureWebAuthn(uctx, rp, opt.Challenge, label, string(rsp))
+		}
+
+		verify = func(a *webauthntest.SoftAuthenticator) error {
+			opt, err := svc.WebAuthnRequestOptions(uctx, rp)
+			req.NoError(err)
+
+			r, err := a.Get(opt)
+			req.NoError(err)
+
+			rsp, _ := json.Marshal(r)
+			return svc.ValidateWebAuthn(uctx, rp, opt.Challenge, string(rsp))
+		}
+	)
+
+	req.NoError(svc.store.TruncateUsers(ctx))
+	req.NoError(svc.store.TruncateCredentials(ctx))
+	req.NoError(svc.store.TruncateRoles(ctx))
+	req.NoError(svc.store.TruncateRoleMembers(ctx))
+	req.NoError(store.CreateUser(ctx, svc.store, user))
+	req.NoError(store.CreateRole(ctx, svc.store, role))
+	req.NoError(store.CreateRoleMember(ctx, svc.store, &types.RoleMember{RoleID: role.ID, UserID: user.ID}))
+
+	a, err := webauthntest.NewSoftAuthenticator(rp.Origin)
+	req.NoError(err)
+	b, err := webauthntest.NewSoftAuthenticator(rp.Origin)
+	req.NoError(err)
+
+	// disabled by default
+	_, err = svc.WebAuthnCreationOptions(uctx, rp)
+	req.EqualError(err, "multi factor authentication with security keys is disabled")
+
+	// enforced for user's role
+	svc.settings.Auth.MultiFactor.WebAuthn.EnforcedRoles = []string{strconv.FormatUint(role.ID, 10)}
+
+	u, err := register(a, "primary")
+	req.NoError(err)
+	req.True(u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+
+	_, err = register(b, "")
+	req.NoError(err)
+
+	cc, err := svc.WebAuthnCredentials(uctx, user.ID)
+	req.NoError(err)
+	req.Len(cc, 2)
+	req.Equal("primary", cc[0].Label)
+	req.Equal("Security key", cc[1].Label)
+
+	// already registered key can not be registered twice
+	opt, err := svc.WebAuthnCreationOptions(uctx, rp)
+	req.NoError(err)
+	_, err = a.Create(opt)
+	req.Error(err)
+
+	req.NoError(verify(a))
+	req.NoError(verify(b))
+
+	// replayed response is rejected
+	ro, err := svc.WebAuthnRequestOptions(uctx, rp)
+	req.NoError(err)
+	r, err := a.Get(ro)
+	req.NoError(err)
+	rsp, _ := json.Marshal(r)
+	req.NoError(svc.ValidateWebAuthn(uctx, rp, ro.Challenge, string(rsp)))
+	req.EqualError(svc.ValidateWebAuthn(uctx, rp, ro.Challenge, string(rsp)), "security key verification failed")
+
+	// last key can not be removed while enforced
+	u, err = svc.RemoveWebAuthn(uctx, user.ID, cc[0].ID)
+	req.NoError(err)
+	req.True(u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+
+	// removed key is no longer allowed
+	ro, err = svc.WebAuthnRequestOptions(uctx, rp)
+	req.NoError(err)
+	req.Len(ro.AllowCredentials, 1)
+	r, err = a.Get(&webauthn.RequestOptions{Challenge: ro.Challenge, RPID: ro.RPID})
+	req.NoError(err)
+	rsp, _ = json.Marshal(r)
+	req.EqualError(svc.ValidateWebAuthn(uctx, rp, ro.Challenge, string(rsp)), "security key verification failed")
+	req.NoError(verify(b))
+
+	_, err = svc.RemoveWebAuthn(uctx, user.ID, cc[1].ID)
+	req.EqualError(err, "security keys are enforced and the last one can not be removed")
+
+	svc.settings.Auth.MultiFactor.WebAuthn.EnforcedRoles = nil
+	svc.settings.Auth.MultiFactor.WebAuthn.Enabled = true
+
+	u, err = svc.RemoveWebAuthn(uctx, user.ID, cc[1].ID)
+	req.NoError(err)
+	req.False(u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+
+	_, err = svc.WebAuthnRequestOptions(uctx, rp)
+	req.EqualError(err, "no security keys registered")
+}
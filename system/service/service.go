@@ -176,6 +176,7 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 		automation.RenderVariables{},
 		automation.RenderOptions{},
 		automation.Document{},
+		automation.EmailMessage{},
 	)
 
 	automation.UsersHandler(
@@ -193,6 +194,10 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 		DefaultRole,
 	)
 
+	automation.EmailHandler(
+		automationService.Registry(),
+	)
+
 	return
 }
 
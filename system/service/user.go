@@ -42,6 +42,7 @@ type (
 	userAuth interface {
 		CheckPasswordStrength(string) bool
 		SetPasswordCredentials(context.Context, uint64, string) error
+		ResetMFA(context.Context, uint64) error
 	}
 
 	userAccessController interface {
@@ -80,6 +81,7 @@ type (
 		Undelete(ctx context.Context, id uint64) error
 
 		SetPassword(ctx context.Context, userID uint64, password string) error
+		ResetMFA(ctx context.Context, userID uint64) error
 
 		Preloader(context.Context, userIdGetter, types.UserFilter, userSetter) error
 	}
@@ -673,6 +675,33 @@ func (svc user) SetPassword(ctx context.Context, userID uint64, newPassword stri
 
 }
 
+// ResetMFA removes all multi-factor authentication factors from the user
+//
+// Used when user can no longer authenticate with the configured factors
+// (lost TOTP device and recovery codes, lost security keys)
+func (svc user) ResetMFA(ctx context.Context, userID uint64) (err error) {
+	var (
+		u       *types.User
+		uaProps = &userActionProps{user: &types.User{ID: userID}}
+	)
+
+	err = func() error {
+		if u, err = store.LookupUserByID(ctx, svc.store, userID); err != nil {
+			return err
+		}
+
+		uaProps.setUser(u)
+
+		if !svc.ac.CanUpdateUser(ctx, u) {
+			return UserErrNotAllowedToUpdate()
+		}
+
+		return svc.auth.ResetMFA(ctx, userID)
+	}()
+
+	return svc.recordAction(ctx, uaProps, UserActionResetMFA, err)
+}
+
 // Masks (or leaves as-is) private data on user
 func (svc user) handlePrivateData(ctx context.Context, u *types.User) {
 	if svc.maskEmail(ctx, u) {
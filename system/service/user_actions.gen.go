@@ -506,6 +506,26 @@ func UserActionSetPassword(props ...*userActionProps) *userAction {
 	return a
 }
 
+// UserActionResetMFA returns "system:user.resetMFA" action
+//
+// This function is auto-generated.
+//
+func UserActionResetMFA(props ...*userActionProps) *userAction {
+	a := &userAction{
+		timestamp: time.Now(),
+		resource:  "system:user",
+		action:    "resetMFA",
+		log:       "multi-factor authentication reset for {user}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
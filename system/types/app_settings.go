@@ -82,6 +82,24 @@ type (
 					// TOTP issuer, defaults to "Corteza"
 					Issuer string
 				} `kv:"totp"`
+
+				WebAuthn struct {
+					// Can users use security keys (WebAuthn) for MFA
+					Enabled bool
+
+					// IDs of roles that can use security keys
+					// when they are not enabled for everyone
+					EnabledRoles []string `kv:"enabled-roles"`
+
+					// Is MFA with security keys enforced?
+					Enforced bool
+
+					// IDs of roles that are required to use security keys
+					EnforcedRoles []string `kv:"enforced-roles"`
+
+					// Relying party name displayed by the browser, defaults to "Corteza"
+					RelyingPartyName string `kv:"relying-party-name"`
+				} `kv:"webauthn"`
 			} `kv:"multi-factor"`
 
 			Mail struct {
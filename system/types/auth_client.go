@@ -70,7 +70,7 @@ type (
 	AuthClientSecurity struct {
 		// Impersonates a specific user;
 		// ignored when non client-credentials grant is used
-		//ImpersonateUser uint64 `json:"impersonateUser,string,omitempty"`
+		ImpersonateUser uint64 `json:"impersonateUser,string,omitempty"`
 
 		// Subset of roles, permitted to be used with this client
 		// IDs are intentionally stored as strings to support JS (int64 only)
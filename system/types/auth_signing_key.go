@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+)
+
+type (
+	// AuthSigningKey holds private key used for signing JWTs
+	//
+	// Key is retired when it is replaced with a new one (rotated); retired key is
+	// no longer used for signing but it remains valid (tokens signed with it can be verified)
+	// until it expires
+	AuthSigningKey struct {
+		ID         uint64     `json:"keyID,string"`
+		Algorithm  string     `json:"algorithm"`
+		PrivateKey string     `json:"-"`
+		CreatedAt  time.Time  `json:"createdAt,omitempty"`
+		RetiredAt  *time.Time `json:"retiredAt,omitempty"`
+		ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	}
+
+	AuthSigningKeyFilter struct {
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*AuthSigningKey) (bool, error) `json:"-"`
+	}
+)
@@ -40,6 +40,11 @@ type (
 	// This type is auto-generated.
 	AuthSessionSet []*AuthSession
 
+	// AuthSigningKeySet slice of AuthSigningKey
+	//
+	// This type is auto-generated.
+	AuthSigningKeySet []*AuthSigningKey
+
 	// CredentialsSet slice of Credentials
 	//
 	// This type is auto-generated.
@@ -360,6 +365,62 @@ func (set AuthSessionSet) Filter(f func(*AuthSession) (bool, error)) (out AuthSe
 	return
 }
 
+// Walk iterates through every slice item and calls w(AuthSigningKey) err
+//
+// This function is auto-generated.
+func (set AuthSigningKeySet) Walk(w func(*AuthSigningKey) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(AuthSigningKey) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set AuthSigningKeySet) Filter(f func(*AuthSigningKey) (bool, error)) (out AuthSigningKeySet, err error) {
+	var ok bool
+	out = AuthSigningKeySet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set AuthSigningKeySet) FindByID(ID uint64) *AuthSigningKey {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set AuthSigningKeySet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(Credentials) err
 //
 // This function is auto-generated.
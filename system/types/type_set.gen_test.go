@@ -486,6 +486,96 @@ func TestAuthSessionSetFilter(t *testing.T) {
 	}
 }
 
+func TestAuthSigningKeySetWalk(t *testing.T) {
+	var (
+		value = make(AuthSigningKeySet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*AuthSigningKey) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*AuthSigningKey) error { return fmt.Errorf("walk error") }))
+}
+
+func TestAuthSigningKeySetFilter(t *testing.T) {
+	var (
+		value = make(AuthSigningKeySet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*AuthSigningKey) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*AuthSigningKey) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*AuthSigningKey) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestAuthSigningKeySetIDs(t *testing.T) {
+	var (
+		value = make(AuthSigningKeySet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(AuthSigningKey)
+	value[1] = new(AuthSigningKey)
+	value[2] = new(AuthSigningKey)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
 func TestCredentialsSetWalk(t *testing.T) {
 	var (
 		value = make(CredentialsSet, 3)
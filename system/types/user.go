@@ -58,6 +58,9 @@ type (
 
 				// Require OTP to be entered every time client is authorized
 				//StrictTOTP bool `json:"strictTOTP"`
+
+				// Are security keys (WebAuthn) registered & enforced?
+				EnforcedWebAuthn bool `json:"enforcedWebAuthn"`
 			} `json:"mfa"`
 		} `json:"securityPolicy"`
 	}
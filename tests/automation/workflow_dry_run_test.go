@@ -0,0 +1,107 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/automation/service"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) repoMakeDryRunWorkflow() *types.Workflow {
+	wf := h.repoMakeWorkflow()
+	wf.Steps = types.WorkflowStepSet{
+		{
+			ID:   1,
+			Kind: types.WorkflowStepKindFunction,
+			Ref:  "httpRequestSend",
+			Arguments: types.ExprSet{
+				{Target: "url", Type: "String", Value: "http://example.tld"},
+				{Target: "method", Type: "String", Value: "GET"},
+			},
+			Results: types.ExprSet{
+				{Target: "code", Expr: "statusCode"},
+			},
+		},
+	}
+
+	h.noError(store.UpdateAutomationWorkflow(context.Background(), service.DefaultStore, wf))
+	return wf
+}
+
+func TestWorkflowDryRun(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+
+	wf := h.repoMakeDryRunWorkflow()
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "execute")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/test", wf.ID)).
+		Header("Accept", "application/json").
+		FormData("mocks", `[{"stepID":"1","results":{"statusCode":{"@type":"Integer","@value":201}}}]`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.status`, types.SessionCompleted.String())).
+		Assert(jsonpath.Present(`$.response.stacktrace`)).
+		Assert(jsonpath.Present(`$.response.scope.code`)).
+		End()
+}
+
+func TestWorkflowDryRunMockedError(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+
+	wf := h.repoMakeDryRunWorkflow()
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "execute")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/test", wf.ID)).
+		Header("Accept", "application/json").
+		FormData("mocks", `[{"stepID":"1","error":"service unavailable"}]`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.status`, types.SessionFailed.String())).
+		Assert(jsonpath.Contains(`$.response.error`, "service unavailable")).
+		End()
+}
+
+func TestWorkflowDryRunNotMocked(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+
+	wf := h.repoMakeDryRunWorkflow()
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "execute")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/test", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.status`, types.SessionFailed.String())).
+		Assert(jsonpath.Contains(`$.response.error`, "step 1 is not mocked")).
+		End()
+}
+
+func TestWorkflowDryRunForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+
+	wf := h.repoMakeDryRunWorkflow()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/test", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("not allowed to execute this workflow")).
+		End()
+}
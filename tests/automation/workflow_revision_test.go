@@ -0,0 +1,196 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/service"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) clearWorkflowRevisions() {
+	h.noError(store.TruncateAutomationWorkflowRevisions(context.Background(), service.DefaultStore))
+}
+
+func (h helper) repoMakeWorkflowRevision(wf *types.Workflow, rev uint, ss ...*types.WorkflowStep) *types.WorkflowRevision {
+	var r = &types.WorkflowRevision{
+		ID:         id.Next(),
+		WorkflowID: wf.ID,
+		Revision:   rev,
+		Steps:      ss,
+		CreatedAt:  time.Now(),
+	}
+
+	h.noError(store.CreateAutomationWorkflowRevision(context.Background(), service.DefaultStore, r))
+	return r
+}
+
+func TestWorkflowPublish(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+	h.clearWorkflowRevisions()
+
+	wf := h.repoMakeWorkflow()
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/publish", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.publishedRevision`, float64(1))).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/publish", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.publishedRevision`, float64(2))).
+		End()
+
+	h.a.Equal(uint(2), h.lookupWorkflowByID(wf.ID).PublishedRevision)
+}
+
+func TestWorkflowPublishForbidden(t *testing.T) {
+	h := newHelper(t)
+	wf := h.repoMakeWorkflow()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/publish", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("not allowed to publish this workflow")).
+		End()
+}
+
+func TestWorkflowUpdateKeepsPublishedRevision(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+	h.clearWorkflowRevisions()
+
+	wf := h.repoMakeWorkflow()
+	wf.PublishedRevision = 1
+	h.noError(store.UpdateAutomationWorkflow(context.Background(), service.DefaultStore, wf))
+	h.repoMakeWorkflowRevision(wf, 1)
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "update")
+
+	h.apiInit().
+		Put(fmt.Sprintf("/workflows/%d", wf.ID)).
+		Header("Accept", "application/json").
+		JSON(helpers.JSON(&types.Workflow{
+			Handle:  wf.Handle,
+			Enabled: true,
+			Steps:   types.WorkflowStepSet{{ID: 1, Kind: types.WorkflowStepKindVisual}},
+		})).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.steps`, 1)).
+		Assert(jsonpath.Equal(`$.response.publishedRevision`, float64(1))).
+		End()
+}
+
+func TestWorkflowRevisionList(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+	h.clearWorkflowRevisions()
+
+	wf := h.repoMakeWorkflow()
+	h.repoMakeWorkflowRevision(wf, 1)
+	h.repoMakeWorkflowRevision(wf, 2)
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "read")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/workflows/%d/revisions", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 2)).
+		Assert(jsonpath.Equal(`$.response.set[0].revision`, float64(2))).
+		End()
+}
+
+func TestWorkflowRevisionDiff(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+	h.clearWorkflowRevisions()
+
+	wf := h.repoMakeWorkflow()
+	h.repoMakeWorkflowRevision(wf, 1, &types.WorkflowStep{ID: 1, Kind: types.WorkflowStepKindVisual})
+	h.repoMakeWorkflowRevision(wf, 2, &types.WorkflowStep{ID: 2, Kind: types.WorkflowStepKindVisual})
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "read")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/workflows/%d/revisions/1/diff", wf.ID)).
+		Query("compareTo", "2").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.steps`, 2)).
+		Assert(jsonpath.Equal(`$.response.steps[0].change`, "added")).
+		Assert(jsonpath.Equal(`$.response.steps[1].change`, "removed")).
+		End()
+}
+
+func TestWorkflowRollback(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+	h.clearWorkflowRevisions()
+
+	wf := h.repoMakeWorkflow()
+	h.repoMakeWorkflowRevision(wf, 1, &types.WorkflowStep{ID: 1, Kind: types.WorkflowStepKindVisual})
+	h.repoMakeWorkflowRevision(wf, 2)
+	h.allow(types.WorkflowRBACResource.AppendID(wf.ID), "update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/revisions/1/rollback", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.publishedRevision`, float64(1))).
+		End()
+
+	// draft definition is kept
+	h.a.Empty(h.lookupWorkflowByID(wf.ID).Steps)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/workflows/%d/revisions/42/rollback", wf.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("workflow revision not found")).
+		End()
+}
+
+func TestWorkflowRevisionUnique(t *testing.T) {
+	h := newHelper(t)
+	h.clearWorkflows()
+	h.clearWorkflowRevisions()
+
+	wf := h.repoMakeWorkflow()
+	h.repoMakeWorkflowRevision(wf, 1)
+
+	err := store.CreateAutomationWorkflowRevision(context.Background(), service.DefaultStore, &types.WorkflowRevision{
+		ID:         id.Next(),
+		WorkflowID: wf.ID,
+		Revision:   1,
+		CreatedAt:  time.Now(),
+	})
+
+	h.a.True(errors.Is(err, store.ErrNotUnique))
+}
@@ -218,6 +218,9 @@ func TestWorkflowCreateFull(t *testing.T) {
 	input.CreatedAt = output.CreatedAt
 	h.a.NoError(output.Scope.ResolveTypes(service.Registry().Type))
 
+	// initial definition is published on create
+	input.PublishedRevision = 1
+
 	h.a.Equal(input, output)
 
 	h.allow(types.WorkflowRBACResource.AppendID(output.ID), "read")
@@ -14,6 +14,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
+	"github.com/cortezaproject/corteza-server/pkg/search/local"
 	sysTypes "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/cortezaproject/corteza-server/tests/helpers"
 	"github.com/go-chi/chi"
@@ -59,6 +60,11 @@ func InitTestApp() {
 				return err
 			}
 
+			service.DefaultSearchIndex, err = local.New("")
+			if err != nil {
+				return err
+			}
+
 			eventbus.Set(eventBus)
 			return nil
 		})
@@ -233,6 +233,72 @@ func TestModuleUpdate(t *testing.T) {
 	h.a.Equal("changed-name", m.Name)
 }
 
+func TestModulePartitionedRecords(t *testing.T) {
+	h := newHelper(t)
+	h.clearModules()
+
+	h.allow(types.NamespaceRBACResource.AppendWildcard(), "read")
+	h.allow(types.NamespaceRBACResource.AppendWildcard(), "module.create")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "read")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.read")
+	h.allow(types.ModuleFieldRBACResource.AppendWildcard(), "record.value.read")
+	h.allow(types.ModuleFieldRBACResource.AppendWildcard(), "record.value.update")
+
+	ns := h.makeNamespace("some-namespace")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/", ns.ID)).
+		JSON(`{"name":"partitioned","config":{"partitioned":true},"fields":[{"name":"name","kind":"String","options":{"isPhysical":true}}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.config.partitioned`, true)).
+		End()
+
+	m, err := store.LookupComposeModuleByNamespaceIDName(context.Background(), service.DefaultStore, ns.ID, "partitioned")
+	h.noError(err)
+	h.a.True(m.Config.Partitioned)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", ns.ID, m.ID)).
+		JSON(`{"values": [{"name": "name", "value": "partitioned value"}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/", ns.ID, m.ID)).
+		Query("query", "name = 'partitioned value'").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		Assert(jsonpath.Equal(`$.response.set[0].values[0].value`, "partitioned value")).
+		End()
+}
+
+func TestModuleUpdateRecordStorageWithRecords(t *testing.T) {
+	h := newHelper(t)
+	h.clearModules()
+
+	h.allow(types.NamespaceRBACResource.AppendWildcard(), "read")
+	ns := h.makeNamespace("some-namespace")
+	m := h.makeModule(ns, "some-module")
+	h.makeRecord(m)
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d", ns.ID, m.ID)).
+		Header("Accept", "application/json").
+		JSON(`{"name":"some-module","config":{"partitioned":true}}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record storage can not be changed on module with records")).
+		End()
+}
+
 func TestModuleFieldsUpdate(t *testing.T) {
 	h := newHelper(t)
 	h.clearModules()
@@ -0,0 +1,139 @@
+package compose
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) apiCreateGeoRecord(m *types.Module, name, location string) {
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "name", "value": %q}, {"name": "location", "value": %q}]}`, name, location)).
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func (h helper) makeGeoModule() *types.Module {
+	module := h.repoMakeRecordModuleWithFields(
+		"geo module",
+		&types.ModuleField{Name: "name", Kind: "String"},
+		&types.ModuleField{Name: "location", Kind: "Geometry"},
+	)
+
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+	return module
+}
+
+func TestRecordGeometry(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.makeGeoModule()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", module.NamespaceID, module.ID)).
+		JSON(`{"values": [{"name": "location", "value": "46.0569, 14.5058"}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.values[0].value`, `{"type":"Point","coordinates":[14.5058,46.0569],"bbox":[14.5058,46.0569,14.5058,46.0569]}`)).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", module.NamespaceID, module.ID)).
+		Header("Accept", "application/json").
+		JSON(`{"values": [{"name": "location", "value": "somewhere"}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("1 issue(s) found")).
+		End()
+}
+
+func TestRecordGeometryFilter(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.makeGeoModule()
+
+	h.apiCreateGeoRecord(module, "Ljubljana", "46.0569, 14.5058")
+	h.apiCreateGeoRecord(module, "Ljubljana Castle", "46.0490, 14.5086")
+	h.apiCreateGeoRecord(module, "Zagreb", "45.8150, 15.9819")
+	h.apiCreateGeoRecord(module, "Manhattan", `{"type":"Polygon","coordinates":[[[-74.02,40.70],[-73.93,40.70],[-73.93,40.88],[-74.02,40.88],[-74.02,40.70]]]}`)
+
+	tcc := []struct {
+		name  string
+		query string
+		names []string
+	}{
+		{
+			name:  "distance",
+			query: "GEO_DISTANCE(location, 46.0569, 14.5058) < 5",
+			names: []string{"Ljubljana", "Ljubljana Castle"},
+		},
+		{
+			name:  "distance with negative coordinates",
+			query: "GEO_DISTANCE(location, 40.7484, -73.9857) < 10",
+			names: []string{"Manhattan"},
+		},
+		{
+			name:  "bounding box",
+			query: "GEO_WITHIN(location, 45, 15, 46, 16)",
+			names: []string{"Zagreb"},
+		},
+		{
+			name:  "bounding box intersection",
+			query: "GEO_WITHIN(location, 40.75, -74, 40.76, -73.98) AND name = 'Manhattan'",
+			names: []string{"Manhattan"},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			test := h.apiInit().
+				Get(fmt.Sprintf("/namespace/%d/module/%d/record/", module.NamespaceID, module.ID)).
+				Query("query", tc.query).
+				Expect(t).
+				Status(http.StatusOK).
+				Assert(helpers.AssertNoErrors).
+				Assert(jsonpath.Len(`$.response.set`, len(tc.names)))
+
+			for _, n := range tc.names {
+				test = test.Assert(jsonpath.Present(fmt.Sprintf(`$.response.set[*].values[? @.value==%q]`, n)))
+			}
+
+			test.End()
+		})
+	}
+}
+
+func TestRecordGeometryFilter_invalid(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.makeGeoModule()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/", module.NamespaceID, module.ID)).
+		Header("Accept", "application/json").
+		Query("query", "GEO_DISTANCE(name, 46.0569, 14.5058) < 5").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("expecting geometry field as first argument for GEO_DISTANCE function")).
+		End()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/", module.NamespaceID, module.ID)).
+		Header("Accept", "application/json").
+		Query("query", "GEO_WITHIN(location, 45, 15)").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("expecting geometry field and exactly 4 numeric arguments for GEO_WITHIN function")).
+		End()
+}
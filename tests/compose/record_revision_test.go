@@ -0,0 +1,170 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/rest/request"
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) apiUpdateRecordName(record *types.Record, name string) {
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", record.NamespaceID, record.ModuleID, record.ID)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "name", "value": %q}]}`, name)).
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func (h helper) lookupRecordRevisions(record *types.Record) types.RecordRevisionSet {
+	f := types.RecordRevisionFilter{RecordID: record.ID}
+	h.noError(f.Sort.Set("revision"))
+
+	set, _, err := store.SearchComposeRecordRevisions(context.Background(), service.DefaultStore, f)
+	h.noError(err)
+	return set
+}
+
+func TestRecordRevisionList(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module)
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.update")
+
+	h.apiUpdateRecordName(record, "first")
+	h.apiUpdateRecordName(record, "second")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions", module.NamespaceID, module.ID, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 2)).
+		Assert(jsonpath.Equal(`$.response.set[0].revision`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.set[0].values[0].value`, "second")).
+		End()
+}
+
+func TestRecordRevisionDiff(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module)
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.update")
+
+	h.apiUpdateRecordName(record, "first")
+	h.apiUpdateRecordName(record, "second")
+
+	rr := h.lookupRecordRevisions(record)
+	h.a.Len(rr, 2)
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/%d/diff", module.NamespaceID, module.ID, record.ID, rr[0].ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response`, 1)).
+		Assert(jsonpath.Equal(`$.response[0].name`, "name")).
+		Assert(jsonpath.Equal(`$.response[0].old[0]`, "first")).
+		Assert(jsonpath.Equal(`$.response[0].new[0]`, "second")).
+		End()
+}
+
+func TestRecordRevisionRestore(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module)
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.update")
+
+	h.apiUpdateRecordName(record, "first")
+	h.apiUpdateRecordName(record, "second")
+
+	rr := h.lookupRecordRevisions(record)
+	h.a.Len(rr, 2)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/%d/restore", module.NamespaceID, module.ID, record.ID, rr[0].ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.values[0].value`, "first")).
+		End()
+
+	rr = h.lookupRecordRevisions(record)
+	h.a.Len(rr, 3)
+	h.a.Equal(types.RecordRevisionRestore, rr[2].Operation)
+}
+
+func TestRecordRevisionRestoreForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module)
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.update")
+
+	h.apiUpdateRecordName(record, "first")
+	rr := h.lookupRecordRevisions(record)
+	h.a.Len(rr, 1)
+
+	h.deny(types.ModuleRBACResource.AppendWildcard(), "record.update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/%d/restore", module.NamespaceID, module.ID, record.ID, rr[0].ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("not allowed to update this record")).
+		End()
+}
+
+func TestRecordRevisionOrganize(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.update")
+
+	module := h.repoMakeRecordModuleWithFields(
+		"record testing module",
+		&types.ModuleField{Name: "position", Kind: "Number"},
+	)
+
+	var (
+		aRec = h.makeRecord(module, &types.RecordValue{Name: "position", Value: "1"})
+		bRec = h.makeRecord(module, &types.RecordValue{Name: "position", Value: "2"})
+	)
+
+	// move a to position 2; records from that position on are shifted as well
+	h.apiSendRecordExec(module.NamespaceID, module.ID, "organize", request.ProcedureArgs{
+		{Name: "recordID", Value: strconv.FormatUint(aRec.ID, 10)},
+		{Name: "positionField", Value: "position"},
+		{Name: "position", Value: "2"}}).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	// each reordered record gets exactly one revision holding its stored position
+	for _, r := range []*types.Record{aRec, bRec} {
+		rr := h.lookupRecordRevisions(r)
+		h.a.Len(rr, 1)
+		h.a.Equal(types.RecordRevisionUpdate, rr[0].Operation)
+		h.a.Equal(
+			h.lookupRecordByID(module, r.ID).Values.Get("position", 0).Value,
+			rr[0].Values.Get("position", 0).Value,
+		)
+	}
+}
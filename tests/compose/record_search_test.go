@@ -0,0 +1,165 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) apiCreateRecord(m *types.Module, name, email string) {
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "name", "value": %q}, {"name": "email", "value": %q}]}`, name, email)).
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func TestRecordSearch(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record search module")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+
+	h.apiCreateRecord(module, "Wile E. Coyote", "wile@acme.test")
+	h.apiCreateRecord(module, "Road Runner", "beep@desert.test")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "coyote").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		Assert(jsonpath.Present(`$.response.set[0].record.values[? @.value=="Wile E. Coyote"]`)).
+		End()
+
+	// prefix match
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "acm").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		End()
+}
+
+func TestRecordSearch_imported(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record search module")
+
+	// Records stored directly are not indexed until module is reindexed
+	h.makeRecord(module, &types.RecordValue{Name: "name", Value: "Marvin the Martian"})
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "martian").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.response.set[0]`)).
+		End()
+
+	h.noError(service.DefaultRecordSearch.ReindexModule(context.Background(), module.ID))
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "martian").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		End()
+}
+
+func TestRecordSearch_deleted(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record search module")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.delete")
+
+	h.apiCreateRecord(module, "Elmer Fudd", "elmer@hunt.test")
+	rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 1)
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, rr[0].ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "fudd").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.response.set[0]`)).
+		End()
+}
+
+func TestRecordSearch_fieldPermissions(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record search module")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+
+	h.apiCreateRecord(module, "Daffy Duck", "daffy@pond.test")
+	h.deny(module.Fields.FindByName("email").RBACResource(), "record.value.read")
+
+	// match on unreadable field only
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "pond").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.NotPresent(`$.response.set[0]`)).
+		End()
+
+	// record is returned without unreadable values
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "daffy").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		Assert(jsonpath.NotPresent(`$.response.set[0].record.values[? @.name=="email"]`)).
+		End()
+}
+
+func TestRecordSearch_forbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record search module")
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+	h.apiCreateRecord(module, "Yosemite Sam", "sam@west.test")
+
+	h.deny(module.RBACResource(), "record.read")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/search", module.NamespaceID)).
+		Query("query", "yosemite").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.NotPresent(`$.response.set[0]`)).
+		End()
+}
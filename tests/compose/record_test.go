@@ -13,6 +13,9 @@ import (
 
 	"github.com/cortezaproject/corteza-server/compose/service"
 	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/xlsx"
 	"github.com/cortezaproject/corteza-server/pkg/id"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/tests/helpers"
@@ -25,12 +28,14 @@ func (h helper) clearRecords() {
 	h.clearNamespaces()
 	h.clearModules()
 	h.noError(store.TruncateComposeRecords(context.Background(), service.DefaultStore, nil))
+	h.noError(store.TruncateComposeRecordRevisions(context.Background(), service.DefaultStore))
 }
 
 type (
 	rImportSession struct {
 		Response struct {
-			SessionID string `json:"sessionID"`
+			SessionID string                     `json:"sessionID"`
+			Progress  types.RecordImportProgress `json:"progress"`
 		} `json:"response"`
 	}
 )
@@ -336,6 +341,53 @@ func TestRecordExport(t *testing.T) {
 	h.a.Equal(expected, string(b))
 }
 
+func TestRecordExportImportXlsx(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields(
+		"record export module",
+		&types.ModuleField{Name: "name", Kind: "String"},
+		&types.ModuleField{Name: "options", Kind: "String", Multi: true},
+		&types.ModuleField{Name: "num", Kind: "Number"},
+	)
+
+	r := h.makeRecord(module,
+		&types.RecordValue{Name: "name", Value: "n1"},
+		&types.RecordValue{Name: "options", Value: "a"},
+		&types.RecordValue{Name: "options", Value: "b", Place: 1},
+		&types.RecordValue{Name: "num", Value: "42"},
+	)
+
+	rsp := h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/export.xlsx", module.NamespaceID, module.ID)).
+		Query("fields", "name,options,num").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	b, err := ioutil.ReadAll(rsp.Response.Body)
+	h.noError(err)
+
+	ii, err := xlsx.Decoder().Decode(context.Background(), bytes.NewReader(b), &envoy.DecoderOpts{Name: "export"})
+	h.noError(err)
+
+	ds := ii[0].(*resource.ResourceDataset)
+	h.a.Equal([]string{"id", "name", "options", "num"}, ds.P.Fields())
+
+	row, err := ds.P.Next()
+	h.noError(err)
+	h.a.Equal(map[string]string{"id": fmt.Sprintf("%d", r.ID), "name": "n1", "options": "a\nb", "num": "42"}, row)
+
+	// exported document can be imported back
+	url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+	h.apiInitRecordImport(h.apiInit(), url, "export.xlsx", b).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Present(`$.response.fields.options==""`)).
+		Assert(jsonpath.Present("$.response.progress.entryCount==1")).
+		End()
+}
+
 func (h helper) apiInitRecordImport(api *apitest.APITest, url, f string, file []byte) *apitest.Response {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -364,6 +416,30 @@ func (h helper) apiRunRecordImport(api *apitest.APITest, url, b string) *apitest
 		Status(http.StatusOK)
 }
 
+// apiWaitRecordImport polls import progress until the import is finished
+func (h helper) apiWaitRecordImport(url string) types.RecordImportProgress {
+	rsp := &rImportSession{}
+
+	for i := 0; i < 100; i++ {
+		h.apiInit().Get(url).
+			Header("Accept", "application/json").
+			Expect(h.t).
+			Status(http.StatusOK).
+			Assert(helpers.AssertNoErrors).
+			End().
+			JSON(rsp)
+
+		if rsp.Response.Progress.FinishedAt != nil {
+			return rsp.Response.Progress
+		}
+
+		time.Sleep(time.Millisecond * 20)
+	}
+
+	h.t.Fatalf("import %s not finished", url)
+	return rsp.Response.Progress
+}
+
 func TestRecordImportInit(t *testing.T) {
 	h := newHelper(t)
 	h.clearRecords()
@@ -435,14 +511,85 @@ func TestRecordImportRun(t *testing.T) {
 
 			h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail"}`).
 				Assert(helpers.AssertNoErrors).
-				Assert(jsonpath.Present("$.response.progress")).
+				Assert(jsonpath.Present("$.response.progress.startedAt")).
 				Assert(jsonpath.Present(`$.response.fields.fname=="name"`)).
 				Assert(jsonpath.Present(`$.response.fields.femail=="email"`)).
 				End()
+
+			p := h.apiWaitRecordImport(fmt.Sprintf("%s/%s", url, rsp.Response.SessionID))
+			h.a.Empty(p.FailReason)
+			h.a.Equal(uint64(1), p.Completed)
+
+			rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+			h.noError(err)
+			h.a.Len(rr, 1)
+			h.a.Equal("v1", rr[0].Values.FilterByName("name")[0].Value)
+
+			// Imported records are created by the record service
+			rv := h.lookupRecordRevisions(rr[0])
+			h.a.Len(rv, 1)
+			h.a.Equal(types.RecordRevisionCreate, rv[0].Operation)
+			h.a.Equal(h.cUser.ID, rr[0].CreatedBy)
 		})
 	}
 }
 
+func TestRecordImportRun_alreadyStarted(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+
+	module := h.repoMakeRecordModuleWithFields("record import run module")
+	url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+	rsp := &rImportSession{}
+	api := h.apiInit()
+
+	h.apiInitRecordImport(api, url, "f1.csv", []byte("fname,femail\nv1,v2\n")).End().JSON(rsp)
+
+	url = fmt.Sprintf("%s/%s", url, rsp.Response.SessionID)
+	h.apiRunRecordImport(api, url, `{"fields":{"fname":"name"},"onError":"fail"}`).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiRunRecordImport(h.apiInit(), url, `{"fields":{"fname":"name"},"onError":"fail"}`).
+		Assert(helpers.AssertError("import session already active")).
+		End()
+
+	h.apiWaitRecordImport(url)
+}
+
+func TestRecordImportRun_skipFailed(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.allow(types.ModuleRBACResource.AppendWildcard(), "record.create")
+
+	module := h.repoMakeRecordModuleWithFields(
+		"record import run module",
+		&types.ModuleField{Name: "name"},
+		&types.ModuleField{Name: "email", Kind: "Email"},
+	)
+
+	url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+	rsp := &rImportSession{}
+
+	h.apiInitRecordImport(h.apiInit(), url, "f1.csv", []byte("fname,femail\nv1,invalid\nv2,v2@test.tld\n")).End().JSON(rsp)
+
+	url = fmt.Sprintf("%s/%s", url, rsp.Response.SessionID)
+	h.apiRunRecordImport(h.apiInit(), url, `{"fields":{"fname":"name","femail":"email"},"onError":"skip"}`).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	p := h.apiWaitRecordImport(url)
+	h.a.Equal(uint64(2), p.Completed)
+	h.a.Equal(uint64(1), p.Failed)
+	h.a.Contains(p.FailReason, "1 issue(s) found")
+
+	rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID})
+	h.noError(err)
+	h.a.Len(rr, 1)
+	h.a.Equal("v2", rr[0].Values.FilterByName("name")[0].Value)
+}
+
 func TestRecordImportRun_sessionNotFound(t *testing.T) {
 	h := newHelper(t)
 	h.clearRecords()
@@ -479,7 +626,7 @@ func TestRecordImportRunForbidden(t *testing.T) {
 			r.JSON(rsp)
 
 			h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail"}`).
-				Assert(helpers.AssertErrorP("not allowed to create records for module")).
+				Assert(helpers.AssertError("not allowed to create records")).
 				End()
 		})
 	}
@@ -515,8 +662,12 @@ func TestRecordImportRunForbidden_field(t *testing.T) {
 			r.JSON(rsp)
 
 			h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"fname":"name","femail":"email"},"onError":"fail"}`).
-				Assert(helpers.AssertErrorP("1 issue(s) found")).
+				Assert(helpers.AssertNoErrors).
 				End()
+
+			p := h.apiWaitRecordImport(fmt.Sprintf("%s/%s", url, rsp.Response.SessionID))
+			h.a.Contains(p.FailReason, "1 issue(s) found")
+			h.a.Zero(p.Completed)
 		})
 	}
 }
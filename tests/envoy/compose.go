@@ -162,6 +162,12 @@ func sTestComposeModuleFull(ctx context.Context, s store.Storer, t *testing.T, n
 				Kind:     "User",
 				Name:     "User",
 			},
+			{
+				ID:       su.NextID(),
+				ModuleID: modID,
+				Kind:     "Geometry",
+				Name:     "Geometry",
+			},
 		},
 	}
 	err := store.CreateComposeModule(ctx, s, mod)
@@ -266,6 +266,11 @@ func TestStoreJsonl_records_fieldTypes(t *testing.T) {
 							Value:    strconv.FormatUint(usr.ID, 10),
 							Ref:      usr.ID,
 						},
+						{
+							RecordID: recID,
+							Name:     "Geometry",
+							Value:    `{"type":"Point","coordinates":[14.5058,46.0569],"bbox":[14.5058,46.0569,14.5058,46.0569]}`,
+						},
 					},
 				}
 				err := store.CreateComposeRecord(ctx, s, mod, rec)
@@ -306,6 +311,7 @@ func TestStoreJsonl_records_fieldTypes(t *testing.T) {
 				req.Equal("htts://www.testing.tld", rec.Values.FilterByName("Url")[0].Value)
 				req.Equal(strconv.FormatUint(usr.ID, 10), rec.Values.FilterByName("User")[0].Value)
 				req.Equal(usr.ID, rec.Values.FilterByName("User")[0].Ref)
+				req.Equal(`{"type":"Point","coordinates":[14.5058,46.0569],"bbox":[14.5058,46.0569,14.5058,46.0569]}`, rec.Values.FilterByName("Geometry")[0].Value)
 			},
 		},
 	}